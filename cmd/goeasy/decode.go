@@ -0,0 +1,42 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+
+	"github.com/allanchen1214/goeasy/log"
+)
+
+// runDecode 将encoder: msgpack输出的日志转换为每行一条的json，未指定文件或为-时从标准输入读取
+func runDecode(args []string, stdin io.Reader, stdout io.Writer) error {
+	r := stdin
+	if path := argOr(args, 0); path != "" && path != "-" {
+		f, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		r = f
+	}
+
+	w := bufio.NewWriter(stdout)
+	defer w.Flush()
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(false)
+	dec := log.NewMsgpackDecoder(r)
+	for {
+		entry, err := dec.Decode()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if err := enc.Encode(entry); err != nil {
+			return err
+		}
+	}
+}
@@ -0,0 +1,34 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestDecode(t *testing.T) {
+	// {"msg":"login","user":"<bob>"}、{"msg":"logout","user":"<bob>"}
+	var in bytes.Buffer
+	for _, msg := range []string{"login", "logout"} {
+		in.Write([]byte{0x82, 0xa3})
+		in.WriteString("msg")
+		in.WriteByte(0xa0 | byte(len(msg)))
+		in.WriteString(msg)
+		in.WriteByte(0xa4)
+		in.WriteString("user")
+		in.WriteByte(0xa5)
+		in.WriteString("<bob>")
+	}
+
+	var out bytes.Buffer
+	if err := runDecode(nil, &in, &out); err != nil {
+		t.Fatal(err)
+	}
+	lines := bytes.Split(bytes.TrimSpace(out.Bytes()), []byte("\n"))
+	if len(lines) != 2 || !bytes.Contains(lines[0], []byte(`"msg":"login"`)) || !bytes.Contains(lines[1], []byte(`"user":"<bob>"`)) {
+		t.Fatalf("unexpected output %s", out.Bytes())
+	}
+
+	if err := runDecode(nil, bytes.NewReader([]byte{0x81, 0xa1}), &out); err == nil {
+		t.Fatal("expected truncated input error")
+	}
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/allanchen1214/goeasy/log"
+)
+
+const genUsage = `usage: goeasy gen <kind> [-force] [path]
+
+kinds:
+  log-config [path]         write a fully commented log config, default ./log_config.yaml, - for stdout
+`
+
+func runGen(args []string, stdout io.Writer) error {
+	if len(args) == 0 {
+		fmt.Fprint(os.Stderr, genUsage)
+		return errors.New("missing kind")
+	}
+	switch args[0] {
+	case "log-config":
+		return genLogConfig(args[1:], stdout)
+	case "-h", "-help", "--help", "help":
+		fmt.Fprint(stdout, genUsage)
+		return nil
+	default:
+		return fmt.Errorf("unknown kind %q", args[0])
+	}
+}
+
+func genLogConfig(args []string, stdout io.Writer) error {
+	fs := flag.NewFlagSet("gen log-config", flag.ContinueOnError)
+	fs.Usage = func() { fmt.Fprint(fs.Output(), genUsage) }
+	force := fs.Bool("force", false, "overwrite an existing file")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	path := argOr(fs.Args(), 0)
+	switch {
+	case path == "-":
+		_, err := stdout.Write(log.ExampleConfig())
+		return err
+	case path == "":
+		path = "log_config.yaml"
+	}
+	if *force {
+		if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+	}
+	if err := log.WriteExampleConfig(path); err != nil {
+		if errors.Is(err, os.ErrExist) {
+			return fmt.Errorf("%s already exists, use -force to overwrite", path)
+		}
+		return err
+	}
+	fmt.Fprintf(stdout, "wrote %s\n", path)
+	return nil
+}
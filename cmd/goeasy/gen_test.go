@@ -0,0 +1,40 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestGenLogConfig(t *testing.T) {
+	var out bytes.Buffer
+	if err := runGen([]string{"log-config", "-"}, &out); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out.String(), "zaplog:") {
+		t.Fatalf("unexpected output %q", out.String())
+	}
+
+	path := filepath.Join(t.TempDir(), "log_config.yaml")
+	out.Reset()
+	if err := runGen([]string{"log-config", path}, &out); err != nil {
+		t.Fatal(err)
+	}
+	if err := runGen([]string{"log-config", path}, &out); err == nil || !strings.Contains(err.Error(), "-force") {
+		t.Fatalf("expected overwrite hint, got %v", err)
+	}
+	if err := os.WriteFile(path, []byte("old"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := runGen([]string{"log-config", "-force", path}, &out); err != nil {
+		t.Fatal(err)
+	}
+	if data, _ := os.ReadFile(path); !bytes.Contains(data, []byte("zaplog:")) {
+		t.Fatal("-force should overwrite the existing file")
+	}
+	if err := runGen([]string{"nginx"}, &out); err == nil {
+		t.Fatal("expected unknown kind error")
+	}
+}
@@ -0,0 +1,326 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+const logctlUsage = `usage: goeasy logctl [-addr url] <command> [args]
+
+commands:
+  stats                     show level and entry counts of each logger
+  list                      show level, outputs and rotation settings of each logger
+  level <name> <level>      change the level of a logger
+  debug <name> <duration>   switch a logger to debug, restored automatically after duration
+  rotate [name]             rotate the log file of a logger (all if omitted)
+  usage                     show bytes written per logger and projected monthly volume
+  tail [name] [level]       stream new entries of a logger
+  dump [name]               dump entries kept in the in-memory ring buffer
+  inventory                 print the logging inventory as JSON
+`
+
+// logctl 通过服务的管理接口操作日志
+type logctl struct {
+	addr   string
+	client *http.Client
+	out    io.Writer
+}
+
+func runLogctl(args []string) error {
+	fs := flag.NewFlagSet("logctl", flag.ContinueOnError)
+	fs.Usage = func() { fmt.Fprint(fs.Output(), logctlUsage) }
+	addr := fs.String("addr", envOr("GOEASY_ADMIN_ADDR", "http://127.0.0.1:6060"), "admin HTTP address of the service")
+	timeout := fs.Duration("timeout", 10*time.Second, "request timeout, not applied to tail")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() == 0 {
+		fs.Usage()
+		return errors.New("missing command")
+	}
+
+	c := &logctl{
+		addr:   strings.TrimRight(*addr, "/"),
+		client: &http.Client{Timeout: *timeout},
+		out:    os.Stdout,
+	}
+	cmd, rest := fs.Arg(0), fs.Args()[1:]
+	switch cmd {
+	case "stats":
+		return c.stats()
+	case "list":
+		return c.list()
+	case "level":
+		if len(rest) != 2 {
+			return errors.New("usage: level <name> <level>")
+		}
+		return c.setLevel(rest[0], rest[1])
+	case "debug":
+		if len(rest) != 2 {
+			return errors.New("usage: debug <name> <duration>")
+		}
+		return c.debug(rest[0], rest[1])
+	case "rotate":
+		return c.rotate(argOr(rest, 0))
+	case "usage":
+		return c.usage()
+	case "tail":
+		c.client.Timeout = 0
+		return c.tail(argOr(rest, 0), argOr(rest, 1))
+	case "dump":
+		return c.dump(argOr(rest, 0))
+	case "inventory":
+		return c.copy("/debug/log/inventory", nil)
+	default:
+		return fmt.Errorf("unknown command %q", cmd)
+	}
+}
+
+func envOr(key, def string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return def
+}
+
+func argOr(args []string, i int) string {
+	if i < len(args) {
+		return args[i]
+	}
+	return ""
+}
+
+func (c *logctl) do(method, path string, query url.Values) (*http.Response, error) {
+	u := c.addr + path
+	if len(query) > 0 {
+		u += "?" + query.Encode()
+	}
+	req, err := http.NewRequest(method, u, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode >= http.StatusBadRequest {
+		defer resp.Body.Close()
+		var body struct {
+			Error string `json:"error"`
+		}
+		if json.NewDecoder(resp.Body).Decode(&body) == nil && body.Error != "" {
+			return nil, fmt.Errorf("%s %s: %s", method, path, body.Error)
+		}
+		return nil, fmt.Errorf("%s %s: %s", method, path, resp.Status)
+	}
+	return resp, nil
+}
+
+func (c *logctl) stats() error {
+	resp, err := c.do(http.MethodGet, "/debug/log/stats", nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	var stats []struct {
+		Name    string            `json:"name"`
+		Level   string            `json:"level"`
+		Entries map[string]uint64 `json:"entries"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&stats); err != nil {
+		return fmt.Errorf("failed to decode stats: %w", err)
+	}
+
+	tw := tabwriter.NewWriter(c.out, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "NAME\tLEVEL\tENTRIES")
+	for _, s := range stats {
+		levels := make([]string, 0, len(s.Entries))
+		for level, n := range s.Entries {
+			if n > 0 {
+				levels = append(levels, fmt.Sprintf("%s=%d", level, n))
+			}
+		}
+		sort.Strings(levels)
+		fmt.Fprintf(tw, "%s\t%s\t%s\n", s.Name, s.Level, strings.Join(levels, " "))
+	}
+	return tw.Flush()
+}
+
+func (c *logctl) list() error {
+	resp, err := c.do(http.MethodGet, "/debug/log/loggers", nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	var loggers []struct {
+		Name     string   `json:"name"`
+		Level    string   `json:"level"`
+		Outputs  []string `json:"outputs"`
+		Rotation struct {
+			MaxSizeMB  int  `json:"max_size_mb"`
+			MaxAgeDays int  `json:"max_age_days"`
+			MaxBackups int  `json:"max_backups"`
+			Compress   bool `json:"compress"`
+		} `json:"rotation"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&loggers); err != nil {
+		return fmt.Errorf("failed to decode loggers: %w", err)
+	}
+
+	tw := tabwriter.NewWriter(c.out, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "NAME\tLEVEL\tOUTPUTS\tROTATION")
+	for _, l := range loggers {
+		r := l.Rotation
+		rotation := fmt.Sprintf("%dMB/%dd/%d backups", r.MaxSizeMB, r.MaxAgeDays, r.MaxBackups)
+		if r.Compress {
+			rotation += " gz"
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", l.Name, l.Level, strings.Join(l.Outputs, ","), rotation)
+	}
+	return tw.Flush()
+}
+
+func (c *logctl) usage() error {
+	resp, err := c.do(http.MethodGet, "/debug/log/usage", nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	var report struct {
+		Window  time.Duration `json:"window"`
+		Loggers []struct {
+			Name                  string `json:"name"`
+			TotalBytes            int64  `json:"total_bytes"`
+			ProjectedMonthlyBytes int64  `json:"projected_monthly_bytes"`
+		} `json:"loggers"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&report); err != nil {
+		return fmt.Errorf("failed to decode usage: %w", err)
+	}
+
+	fmt.Fprintf(c.out, "window: %s\n", report.Window.Round(time.Second))
+	tw := tabwriter.NewWriter(c.out, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "NAME\tBYTES\tPROJECTED/MONTH")
+	for _, l := range report.Loggers {
+		fmt.Fprintf(tw, "%s\t%s\t%s\n", l.Name, humanBytes(l.TotalBytes), humanBytes(l.ProjectedMonthlyBytes))
+	}
+	return tw.Flush()
+}
+
+func humanBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%dB", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f%ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
+
+func (c *logctl) setLevel(name, level string) error {
+	resp, err := c.do(http.MethodPut, "/debug/log/level", url.Values{"name": {name}, "level": {level}})
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	fmt.Fprintf(c.out, "%s: level set to %s\n", name, level)
+	return nil
+}
+
+func (c *logctl) debug(name, duration string) error {
+	if _, err := time.ParseDuration(duration); err != nil {
+		return err
+	}
+	resp, err := c.do(http.MethodPost, "/debug/log/debug", url.Values{"name": {name}, "duration": {duration}})
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	fmt.Fprintf(c.out, "%s: debug enabled for %s\n", name, duration)
+	return nil
+}
+
+func (c *logctl) rotate(name string) error {
+	query := url.Values{}
+	if name != "" {
+		query.Set("name", name)
+	}
+	resp, err := c.do(http.MethodPost, "/debug/log/rotate", query)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
+
+func (c *logctl) tail(name, level string) error {
+	query := url.Values{}
+	if name != "" {
+		query.Set("name", name)
+	}
+	if level != "" {
+		query.Set("level", level)
+	}
+	resp, err := c.do(http.MethodGet, "/debug/log/tail", query)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	// 解析SSE，只输出事件内容
+	var event string
+	scanner := bufio.NewScanner(resp.Body)
+	scanner.Buffer(make([]byte, 64*1024), 1<<20)
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case line == "":
+			event = ""
+		case strings.HasPrefix(line, "event: "):
+			event = strings.TrimPrefix(line, "event: ")
+		case strings.HasPrefix(line, "data: "):
+			data := strings.TrimPrefix(line, "data: ")
+			if event == "dropped" {
+				fmt.Fprintf(c.out, "... %s entries dropped\n", data)
+				continue
+			}
+			fmt.Fprintln(c.out, data)
+		}
+	}
+	return scanner.Err()
+}
+
+func (c *logctl) dump(name string) error {
+	query := url.Values{}
+	if name != "" {
+		query.Set("name", name)
+	}
+	return c.copy("/debug/log/dump", query)
+}
+
+func (c *logctl) copy(path string, query url.Values) error {
+	resp, err := c.do(http.MethodGet, path, query)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	_, err = io.Copy(c.out, resp.Body)
+	return err
+}
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestLogctl(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /debug/log/stats", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`[{"name":"default","level":"info","entries":{"info":3,"debug":0}}]`))
+	})
+	mux.HandleFunc("GET /debug/log/loggers", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`[{"name":"access","level":"debug","outputs":["file:./logs/access.log","stdout","sink:kafka"],"rotation":{"max_size_mb":100,"max_age_days":7,"max_backups":3,"compress":true}}]`))
+	})
+	mux.HandleFunc("GET /debug/log/tail", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.Write([]byte(": tailing default\n\ndata: {\"msg\":\"first\"}\n\nevent: dropped\ndata: 3\n\ndata: {\"msg\":\"second\"}\n\n"))
+	})
+	mux.HandleFunc("PUT /debug/log/level", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("level") != "debug" {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"error":"invalid level"}`))
+			return
+		}
+		w.Write([]byte(`{}`))
+	})
+	mux.HandleFunc("GET /debug/log/usage", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"window":3600000000000,"loggers":[{"name":"default","total_bytes":2048,"projected_monthly_bytes":1572864}]}`))
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	var out bytes.Buffer
+	c := &logctl{addr: srv.URL, client: srv.Client(), out: &out}
+
+	if err := c.stats(); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out.String(), "default") || !strings.Contains(out.String(), "info=3") {
+		t.Fatalf("unexpected stats output: %q", out.String())
+	}
+	out.Reset()
+	if err := c.list(); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out.String(), "file:./logs/access.log,stdout,sink:kafka") || !strings.Contains(out.String(), "100MB/7d/3 backups gz") {
+		t.Fatalf("unexpected list output: %q", out.String())
+	}
+	out.Reset()
+	if err := c.usage(); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out.String(), "2.0KiB") || !strings.Contains(out.String(), "1.5MiB") {
+		t.Fatalf("unexpected usage output: %q", out.String())
+	}
+	out.Reset()
+	if err := c.tail("", "warn"); err != nil {
+		t.Fatal(err)
+	}
+	if got := out.String(); got != "{\"msg\":\"first\"}\n... 3 entries dropped\n{\"msg\":\"second\"}\n" {
+		t.Fatalf("unexpected tail output: %q", got)
+	}
+	if err := c.setLevel("default", "debug"); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.setLevel("default", "verbose"); err == nil || !strings.Contains(err.Error(), "invalid level") {
+		t.Fatalf("expected server error, got %v", err)
+	}
+	if err := c.dump(""); err == nil {
+		t.Fatal("expected error for missing endpoint")
+	}
+}
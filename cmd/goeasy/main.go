@@ -0,0 +1,48 @@
+// goeasy 命令行工具
+//
+// 用法:
+//
+//	goeasy logctl [-addr url] <command> [args]
+//	goeasy gen log-config [-force] [path]
+//	goeasy decode [file]
+package main
+
+import (
+	"fmt"
+	"os"
+)
+
+const usage = `usage: goeasy <command> [args]
+
+commands:
+  logctl    manage loggers of a running service via its admin HTTP API
+  gen       generate config files, e.g. gen log-config
+  decode    convert msgpack encoded logs from file or stdin to JSON lines
+`
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	var err error
+	switch os.Args[1] {
+	case "logctl":
+		err = runLogctl(os.Args[2:])
+	case "gen":
+		err = runGen(os.Args[2:], os.Stdout)
+	case "decode":
+		err = runDecode(os.Args[2:], os.Stdin, os.Stdout)
+	case "-h", "-help", "--help", "help":
+		fmt.Print(usage)
+		return
+	default:
+		fmt.Fprintf(os.Stderr, "goeasy: unknown command %q\n\n%s", os.Args[1], usage)
+		os.Exit(2)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "goeasy %s: %v\n", os.Args[1], err)
+		os.Exit(1)
+	}
+}
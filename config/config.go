@@ -0,0 +1,239 @@
+// Package config 以统一的方式将配置文件加载到结构体，log等goeasy子系统和业务代码共用：
+//
+//	type AppConfig struct {
+//		Addr    string        `mapstructure:"addr" default:":8080"`
+//		Timeout time.Duration `mapstructure:"timeout" default:"5s"`
+//		DB      struct {
+//			DSN      string `mapstructure:"dsn" validate:"required" env:"APP_DB_DSN"`
+//			MaxConns int    `mapstructure:"max_conns" default:"10" validate:"min=1"`
+//		} `mapstructure:"db"`
+//	}
+//
+//	var cfg AppConfig
+//	err := config.Load("app.yaml", &cfg, config.WithEnvPrefix("APP"))
+//
+// 字段名取自mapstructure标签，未设置时为小写的字段名。加载顺序为：读取文件并合并当前profile的文件(path为目录或glob时合并其中的各文件)，
+// 替换${env:DB_PASS}、${file:/run/secrets/x}、${vault:secret/app#key}等占位符(见Resolver)，
+// 以环境变量覆盖，解析到结构体，以default标签填充零值字段，最后按validate标签及Validate方法校验。
+// 需要热更新时使用Watch，文件变更后按相同的流程重新加载；配置存放在配置中心时使用NewRemote
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+
+	"github.com/spf13/viper"
+)
+
+// Validator 配置结构体可实现此接口，补充字段之间的校验，在validate标签校验通过后调用
+type Validator interface {
+	Validate() error
+}
+
+// Option Load的可选配置
+type Option func(*options)
+
+type options struct {
+	format    string
+	envPrefix string
+	onError   func(error)
+	resolvers map[string]Resolver
+	profile   *string
+	mergeKeys map[string]string
+	hooks     []func(map[string]any) error
+}
+
+// WithFormat 指定配置格式yaml、json或toml，默认根据扩展名判断，无法判断时按yaml解析
+func WithFormat(format string) Option {
+	return func(o *options) { o.format = strings.ToLower(format) }
+}
+
+// WithEnvPrefix 以<PREFIX>_<KEY>形式的环境变量覆盖配置，嵌套字段的键以下划线连接，
+// 如前缀APP时APP_DB_MAX_CONNS覆盖db.max_conns。env标签指定的环境变量不受前缀影响，始终生效
+func WithEnvPrefix(prefix string) Option {
+	return func(o *options) { o.envPrefix = prefix }
+}
+
+func newOptions(opts []Option) *options {
+	o := &options{}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+// Load 读取path中的配置并解析到out，out需为结构体指针。
+// 存在当前profile对应的文件时依次合并，如profile为prod时以app.prod.yaml覆盖app.yaml，见WithProfile。
+// path为目录或glob(如conf/*.yaml)时按文件名顺序读取并合并，可将共用的基础配置与各环境的覆盖配置分开维护，
+// 如00-base.yaml、10-prod.yaml，此时不加载profile文件
+func Load(path string, out any, opts ...Option) error {
+	if err := checkTarget(out); err != nil {
+		return err
+	}
+	return load(path, out, newOptions(opts))
+}
+
+func load(path string, out any, o *options) error {
+	files, err := sourceFiles(path, o)
+	if err != nil {
+		return err
+	}
+	settings, err := readFiles(files, o)
+	if err != nil {
+		return err
+	}
+	if err := decode(settings, out, o); err != nil {
+		return fmt.Errorf("config %s: %w", path, err)
+	}
+	return nil
+}
+
+func checkTarget(out any) error {
+	v := reflect.ValueOf(out)
+	if v.Kind() != reflect.Pointer || v.IsNil() || v.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("config: out must be a non-nil pointer to struct, got %T", out)
+	}
+	return nil
+}
+
+// fileFormat 根据扩展名返回配置格式
+func fileFormat(path string) string {
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".json", ".toml":
+		return ext[1:]
+	}
+	return "yaml"
+}
+
+// readFile 读取配置文件，返回各键的值
+func readFile(path, format string) (map[string]any, error) {
+	if format == "" {
+		format = fileFormat(path)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("config %s: %w", path, err)
+	}
+	settings, err := parse(data, format)
+	if err != nil {
+		return nil, fmt.Errorf("config %s: %w", path, err)
+	}
+	return settings, nil
+}
+
+// parse 按format解析配置内容
+func parse(data []byte, format string) (map[string]any, error) {
+	switch format {
+	case "yaml", "yml", "json", "toml":
+	default:
+		return nil, fmt.Errorf("unsupported format %q", format)
+	}
+	v := viper.New()
+	v.SetConfigType(format)
+	if err := v.ReadConfig(bytes.NewReader(data)); err != nil {
+		return nil, err
+	}
+	return v.AllSettings(), nil
+}
+
+// decode 替换settings中的占位符，调用WithSettingsHook指定的函数，以环境变量覆盖后解析到out，填充默认值并校验
+func decode(settings map[string]any, out any, o *options) error {
+	settings, errs := expand(settings, o)
+	if len(errs) > 0 {
+		return errors.Join(errs...)
+	}
+	for _, hook := range o.hooks {
+		if err := hook(settings); err != nil {
+			return err
+		}
+	}
+	v := viper.New()
+	if err := v.MergeConfigMap(settings); err != nil {
+		return err
+	}
+	bindEnv(v, reflect.TypeOf(out).Elem(), "", o.envPrefix)
+	if err := v.Unmarshal(out); err != nil {
+		return err
+	}
+	rv := reflect.ValueOf(out).Elem()
+	if err := applyDefaults(rv, ""); err != nil {
+		return err
+	}
+	if errs := validate(rv, ""); len(errs) > 0 {
+		return errors.Join(errs...)
+	}
+	if val, ok := out.(Validator); ok {
+		return val.Validate()
+	}
+	return nil
+}
+
+// bindEnv 为结构体的各字段绑定环境变量，切片、map中的结构体不绑定
+func bindEnv(v *viper.Viper, t reflect.Type, path, prefix string) {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		key, ok := fieldKey(f)
+		if !ok {
+			continue
+		}
+		if path != "" {
+			key = path + "." + key
+		}
+		if ft := indirect(f.Type); isStruct(ft) {
+			bindEnv(v, ft, key, prefix)
+			continue
+		}
+		var names []string
+		if name := f.Tag.Get("env"); name != "" {
+			names = append(names, name)
+		}
+		if prefix != "" {
+			names = append(names, envName(prefix, key))
+		}
+		if len(names) > 0 {
+			_ = v.BindEnv(append([]string{key}, names...)...)
+		}
+	}
+}
+
+// envName 返回键对应的环境变量名，非字母数字字符替换为下划线，如APP_DB_MAX_CONNS
+func envName(prefix, key string) string {
+	return strings.ToUpper(strings.Map(func(r rune) rune {
+		if r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' {
+			return r
+		}
+		return '_'
+	}, prefix+"_"+key))
+}
+
+// fieldKey 返回字段在配置中的键名，未导出或标签为-的字段返回false
+func fieldKey(f reflect.StructField) (string, bool) {
+	if !f.IsExported() {
+		return "", false
+	}
+	name, _, _ := strings.Cut(f.Tag.Get("mapstructure"), ",")
+	switch name {
+	case "-":
+		return "", false
+	case "":
+		return strings.ToLower(f.Name), true
+	}
+	return name, true
+}
+
+func indirect(t reflect.Type) reflect.Type {
+	if t.Kind() == reflect.Pointer {
+		return t.Elem()
+	}
+	return t
+}
+
+// isStruct 是否为需要逐字段处理的结构体，time.Time等按值处理
+func isStruct(t reflect.Type) bool {
+	return t.Kind() == reflect.Struct && t.PkgPath() != "time"
+}
@@ -0,0 +1,112 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+type testConfig struct {
+	Addr    string        `mapstructure:"addr" default:":8080"`
+	Timeout time.Duration `mapstructure:"timeout" default:"5s"`
+	Tags    []string      `mapstructure:"tags" default:"a, b"`
+	DB      struct {
+		DSN      string `mapstructure:"dsn" validate:"required" env:"TEST_CONFIG_DSN"`
+		MaxConns int    `mapstructure:"max_conns" default:"10" validate:"min=1,max=100"`
+	} `mapstructure:"db"`
+	Mode string `validate:"oneof=dev prod"`
+}
+
+func writeFile(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoad(t *testing.T) {
+	files := map[string]string{
+		"app.yaml": "timeout: 2s\ndb:\n  dsn: mysql://yaml\nmode: dev\n",
+		"app.json": `{"timeout": "2s", "db": {"dsn": "mysql://json"}, "mode": "dev"}`,
+		"app.toml": "timeout = \"2s\"\nmode = \"dev\"\n[db]\ndsn = \"mysql://toml\"\n",
+	}
+	for name, content := range files {
+		var cfg testConfig
+		if err := Load(writeFile(t, name, content), &cfg); err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		if cfg.Addr != ":8080" || cfg.Timeout != 2*time.Second || cfg.DB.MaxConns != 10 || strings.Join(cfg.Tags, "|") != "a|b" {
+			t.Fatalf("%s: unexpected config %+v", name, cfg)
+		}
+		if want := "mysql://" + strings.TrimPrefix(filepath.Ext(name), "."); cfg.DB.DSN != want {
+			t.Fatalf("%s: expected dsn %s, got %s", name, want, cfg.DB.DSN)
+		}
+	}
+}
+
+func TestLoadEnv(t *testing.T) {
+	path := writeFile(t, "app.yaml", "addr: :9000\ndb:\n  dsn: mysql://file\n  max_conns: 5\nmode: dev\n")
+	t.Setenv("APP_ADDR", ":9100")
+	t.Setenv("APP_DB_MAX_CONNS", "20")
+	t.Setenv("TEST_CONFIG_DSN", "mysql://env")
+
+	var cfg testConfig
+	if err := Load(path, &cfg, WithEnvPrefix("app")); err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Addr != ":9100" || cfg.DB.MaxConns != 20 || cfg.DB.DSN != "mysql://env" {
+		t.Fatalf("unexpected config %+v", cfg)
+	}
+
+	// 未指定前缀时只有env标签生效
+	cfg = testConfig{}
+	if err := Load(path, &cfg); err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Addr != ":9000" || cfg.DB.MaxConns != 5 || cfg.DB.DSN != "mysql://env" {
+		t.Fatalf("unexpected config %+v", cfg)
+	}
+}
+
+type checkedConfig struct {
+	Min int `mapstructure:"min"`
+	Max int `mapstructure:"max"`
+}
+
+func (c *checkedConfig) Validate() error {
+	if c.Min > c.Max {
+		return errors.New("min must not exceed max")
+	}
+	return nil
+}
+
+func TestLoadErrors(t *testing.T) {
+	var cfg testConfig
+	err := Load(writeFile(t, "app.yaml", "db:\n  max_conns: 0\nmode: test\n"), &cfg)
+	if err == nil {
+		t.Fatal("expected validation error")
+	}
+	for _, want := range []string{"db.dsn: is required", "mode: must be one of [dev prod]"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Fatalf("expected %q in %v", want, err)
+		}
+	}
+
+	if err := Load(writeFile(t, "app.yaml", "min: 2\nmax: 1\n"), &checkedConfig{}); err == nil || !strings.Contains(err.Error(), "min must not exceed max") {
+		t.Fatalf("expected Validate error, got %v", err)
+	}
+	if err := Load(filepath.Join(t.TempDir(), "missing.yaml"), &cfg); err == nil {
+		t.Fatal("expected error for missing file")
+	}
+	if err := Load(writeFile(t, "app.ini", "a=1"), &cfg, WithFormat("ini")); err == nil || !strings.Contains(err.Error(), "unsupported format") {
+		t.Fatalf("expected unsupported format error, got %v", err)
+	}
+	if err := Load(writeFile(t, "app.yaml", ""), cfg); err == nil {
+		t.Fatal("expected error for non-pointer target")
+	}
+}
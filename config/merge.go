@@ -0,0 +1,148 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// WithMergeKey 合并多个文件时，列表key中的元素按field的值合并到同名元素，field不同的追加到末尾，
+// 如WithMergeKey("servers", "name")；未指定的列表整体替换。key为点分隔的路径
+func WithMergeKey(key, field string) Option {
+	return func(o *options) {
+		if o.mergeKeys == nil {
+			o.mergeKeys = make(map[string]string)
+		}
+		o.mergeKeys[strings.ToLower(key)] = strings.ToLower(field)
+	}
+}
+
+// WithSettingsHook 在替换占位符之后、环境变量覆盖和解析到结构体之前调用fn，可检查或修改合并后的配置，
+// fn返回错误时加载失败。多次指定时按顺序调用
+func WithSettingsHook(fn func(settings map[string]any) error) Option {
+	return func(o *options) { o.hooks = append(o.hooks, fn) }
+}
+
+// sourceFiles 返回path对应的配置文件：path为目录时为其中扩展名为yaml、yml、json、toml的文件，
+// 为glob时为匹配的文件，均按文件名排序；为普通文件时为该文件及当前profile的文件
+func sourceFiles(path string, o *options) ([]string, error) {
+	var files []string
+	if info, err := os.Stat(path); err == nil && info.IsDir() {
+		entries, err := os.ReadDir(path)
+		if err != nil {
+			return nil, fmt.Errorf("config %s: %w", path, err)
+		}
+		for _, e := range entries {
+			switch strings.ToLower(filepath.Ext(e.Name())) {
+			case ".yaml", ".yml", ".json", ".toml":
+				if !e.IsDir() {
+					files = append(files, filepath.Join(path, e.Name()))
+				}
+			}
+		}
+	} else if strings.ContainsAny(path, "*?[") {
+		matches, err := filepath.Glob(path)
+		if err != nil {
+			return nil, fmt.Errorf("config %s: invalid pattern: %w", path, err)
+		}
+		files = matches
+	} else {
+		return append([]string{path}, profileFiles(path, o.profiles())...), nil
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("config %s: no config files found", path)
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+// readFiles 依次读取files并合并，后面的文件覆盖前面的，第一个之后的文件不存在时跳过
+func readFiles(files []string, o *options) (map[string]any, error) {
+	settings := map[string]any{}
+	for i, file := range files {
+		fileSettings, err := readFile(file, o.format)
+		if i > 0 && errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		mergeSettings(settings, fileSettings, o, "")
+	}
+	return settings, nil
+}
+
+// mergeSettings 将src合并到dst：对象逐键合并，WithMergeKey指定的列表按元素合并，其他值(包括列表)整体替换
+func mergeSettings(dst, src map[string]any, o *options, path string) {
+	for key, value := range src {
+		keyPath := join(path, key)
+		if field, ok := o.mergeKeys[keyPath]; ok {
+			dst[key] = mergeList(settingsList(dst[key]), settingsList(value), field, o, keyPath)
+			continue
+		}
+		srcMap, ok := value.(map[string]any)
+		dstMap, dstOk := dst[key].(map[string]any)
+		if ok && dstOk {
+			mergeSettings(dstMap, srcMap, o, keyPath)
+			continue
+		}
+		if ok {
+			copied := map[string]any{}
+			mergeSettings(copied, srcMap, o, keyPath)
+			value = copied
+		}
+		dst[key] = value
+	}
+}
+
+// mergeList 将overlay中的元素按field合并到base中field相同的元素
+func mergeList(base, overlay []any, field string, o *options, path string) []any {
+	out := append([]any(nil), base...)
+	index := map[string]int{}
+	for i, item := range out {
+		if m, ok := item.(map[string]any); ok {
+			if id, _ := m[field].(string); id != "" {
+				index[id] = i
+			}
+		}
+	}
+	for _, item := range overlay {
+		m, ok := item.(map[string]any)
+		if !ok {
+			out = append(out, item)
+			continue
+		}
+		id, _ := m[field].(string)
+		if i, found := index[id]; found && id != "" {
+			merged := map[string]any{}
+			mergeSettings(merged, out[i].(map[string]any), o, path)
+			mergeSettings(merged, m, o, path)
+			out[i] = merged
+			continue
+		}
+		if id != "" {
+			index[id] = len(out)
+		}
+		out = append(out, item)
+	}
+	return out
+}
+
+// settingsList toml解析的数组为[]map[string]any，统一转换为[]any
+func settingsList(v any) []any {
+	switch list := v.(type) {
+	case []any:
+		return list
+	case []map[string]any:
+		out := make([]any, len(list))
+		for i, m := range list {
+			out[i] = m
+		}
+		return out
+	}
+	return nil
+}
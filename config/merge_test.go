@@ -0,0 +1,93 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+type mergeConfig struct {
+	Servers []struct {
+		Name string `mapstructure:"name"`
+		Addr string `mapstructure:"addr"`
+		Port int    `mapstructure:"port"`
+	} `mapstructure:"servers"`
+	Tags []string `mapstructure:"tags"`
+}
+
+func TestLoadDirMerge(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"00-base.yaml":  "servers:\n  - {name: api, addr: 10.0.0.1, port: 80}\n  - {name: admin, addr: 10.0.0.2}\ntags: [a, b]\n",
+		"10-prod.yaml":  "servers:\n  - {name: api, port: 443}\n  - {name: metrics, addr: 10.0.0.3}\ntags: [c]\n",
+		"20-local.json": `{"servers": [{"name": "admin", "port": 8080}]}`,
+		"README.md":     "not a config file",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var cfg mergeConfig
+	if err := Load(dir, &cfg, WithMergeKey("servers", "name")); err != nil {
+		t.Fatal(err)
+	}
+	if len(cfg.Servers) != 3 || cfg.Servers[2].Name != "metrics" {
+		t.Fatalf("unexpected servers %+v", cfg.Servers)
+	}
+	if s := cfg.Servers[0]; s.Addr != "10.0.0.1" || s.Port != 443 {
+		t.Fatalf("api should merge by name: %+v", s)
+	}
+	if s := cfg.Servers[1]; s.Addr != "10.0.0.2" || s.Port != 8080 {
+		t.Fatalf("admin should merge by name: %+v", s)
+	}
+	if len(cfg.Tags) != 1 || cfg.Tags[0] != "c" {
+		t.Fatalf("lists without merge key should be replaced: %v", cfg.Tags)
+	}
+
+	// 未指定合并键时列表整体替换
+	cfg = mergeConfig{}
+	if err := Load(filepath.Join(dir, "*.yaml"), &cfg); err != nil {
+		t.Fatal(err)
+	}
+	if len(cfg.Servers) != 2 || cfg.Servers[0].Addr != "" {
+		t.Fatalf("unexpected servers %+v", cfg.Servers)
+	}
+
+	if err := Load(filepath.Join(dir, "*.toml"), &cfg); err == nil {
+		t.Fatal("expected error when no files match")
+	}
+}
+
+func TestSettingsHook(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "app.yaml")
+	if err := os.WriteFile(path, []byte("servers:\n  - name: api\n    addr: \"${env:API_ADDR}\"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("API_ADDR", "10.0.0.1")
+
+	var cfg mergeConfig
+	err := Load(path, &cfg, WithSettingsHook(func(settings map[string]any) error {
+		// 占位符已替换
+		server := settings["servers"].([]any)[0].(map[string]any)
+		if server["addr"] != "10.0.0.1" {
+			t.Errorf("placeholders should be resolved before hooks: %v", server)
+		}
+		server["port"] = 8080
+		return nil
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Servers[0].Port != 8080 {
+		t.Fatalf("hook changes should be decoded: %+v", cfg.Servers)
+	}
+
+	errInvalid := errors.New("invalid")
+	if err := Load(path, &cfg, WithSettingsHook(func(map[string]any) error { return errInvalid })); !errors.Is(err, errInvalid) {
+		t.Fatalf("expected hook error, got %v", err)
+	}
+}
@@ -0,0 +1,58 @@
+package config
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+)
+
+// ProfileEnv 指定当前profile的环境变量，多个profile以逗号分隔，如GOEASY_PROFILE=prod,eu
+const ProfileEnv = "GOEASY_PROFILE"
+
+// profileFlag RegisterProfileFlag注册的命令行参数
+var profileFlag atomic.Pointer[string]
+
+// WithProfile 指定当前profile，多个以逗号分隔，优先于命令行参数和GOEASY_PROFILE，为空时不加载profile文件
+func WithProfile(profile string) Option {
+	return func(o *options) { o.profile = &profile }
+}
+
+// RegisterProfileFlag 在fs中注册-profile参数，fs为nil时使用flag.CommandLine。
+// 解析后参数非空时优先于GOEASY_PROFILE生效
+func RegisterProfileFlag(fs *flag.FlagSet) {
+	if fs == nil {
+		fs = flag.CommandLine
+	}
+	profileFlag.Store(fs.String("profile", "", "active config profiles, comma separated, overrides "+ProfileEnv))
+}
+
+// profiles 返回当前生效的profile：WithProfile、-profile参数、GOEASY_PROFILE依次优先
+func (o *options) profiles() []string {
+	profile := os.Getenv(ProfileEnv)
+	if p := profileFlag.Load(); p != nil && *p != "" {
+		profile = *p
+	}
+	if o.profile != nil {
+		profile = *o.profile
+	}
+	var profiles []string
+	for _, p := range strings.Split(profile, ",") {
+		if p = strings.TrimSpace(p); p != "" {
+			profiles = append(profiles, p)
+		}
+	}
+	return profiles
+}
+
+// profileFiles 返回path各profile对应的文件，如app.yaml的prod为app.prod.yaml
+func profileFiles(path string, profiles []string) []string {
+	ext := filepath.Ext(path)
+	base := strings.TrimSuffix(path, ext)
+	files := make([]string, len(profiles))
+	for i, p := range profiles {
+		files[i] = base + "." + p + ext
+	}
+	return files
+}
@@ -0,0 +1,129 @@
+package config
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type profileConfig struct {
+	Addr  string   `mapstructure:"addr"`
+	Level string   `mapstructure:"level" default:"info"`
+	Hosts []string `mapstructure:"hosts"`
+	DB    struct {
+		DSN      string `mapstructure:"dsn"`
+		MaxConns int    `mapstructure:"max_conns"`
+	} `mapstructure:"db"`
+}
+
+func writeProfiles(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	files := map[string]string{
+		"app.yaml":      "addr: :8080\nhosts: [a, b]\ndb:\n  dsn: mysql://dev\n  max_conns: 10\n",
+		"app.prod.yaml": "level: warn\nhosts: [c]\ndb:\n  dsn: mysql://prod\n",
+		"app.eu.yaml":   "db:\n  max_conns: 50\n",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return filepath.Join(dir, "app.yaml")
+}
+
+func TestLoadProfile(t *testing.T) {
+	path := writeProfiles(t)
+	t.Setenv(ProfileEnv, "")
+
+	var cfg profileConfig
+	if err := Load(path, &cfg); err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Level != "info" || cfg.DB.DSN != "mysql://dev" {
+		t.Fatalf("unexpected config without profile %+v", cfg)
+	}
+
+	// 逐个合并，列表整体替换，后面的profile优先
+	t.Setenv(ProfileEnv, "prod, eu, missing")
+	t.Setenv("APP_DB_DSN", "mysql://env")
+	cfg = profileConfig{}
+	if err := Load(path, &cfg, WithEnvPrefix("APP")); err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Addr != ":8080" || cfg.Level != "warn" || !reflect.DeepEqual(cfg.Hosts, []string{"c"}) || cfg.DB.DSN != "mysql://env" || cfg.DB.MaxConns != 50 {
+		t.Fatalf("unexpected merged config %+v", cfg)
+	}
+
+	// WithProfile优先于环境变量，为空时不加载profile文件
+	cfg = profileConfig{}
+	if err := Load(path, &cfg, WithProfile("")); err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Level != "info" || cfg.DB.MaxConns != 10 {
+		t.Fatalf("unexpected config %+v", cfg)
+	}
+}
+
+func TestProfileFlag(t *testing.T) {
+	defer profileFlag.Store(nil)
+	t.Setenv(ProfileEnv, "prod")
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	RegisterProfileFlag(fs)
+
+	o := newOptions(nil)
+	if err := fs.Parse(nil); err != nil {
+		t.Fatal(err)
+	}
+	if got := o.profiles(); !reflect.DeepEqual(got, []string{"prod"}) {
+		t.Fatalf("empty flag should fall back to %s, got %v", ProfileEnv, got)
+	}
+	if err := fs.Parse([]string{"-profile", "test,eu"}); err != nil {
+		t.Fatal(err)
+	}
+	if got := o.profiles(); !reflect.DeepEqual(got, []string{"test", "eu"}) {
+		t.Fatalf("unexpected profiles %v", got)
+	}
+	if got := newOptions([]Option{WithProfile("dev")}).profiles(); !reflect.DeepEqual(got, []string{"dev"}) {
+		t.Fatalf("WithProfile should take precedence, got %v", got)
+	}
+}
+
+func TestWatchProfile(t *testing.T) {
+	path := writeProfiles(t)
+	changes := make(chan profileConfig, 10)
+	var cfg profileConfig
+	w, err := Watch(path, &cfg, func(old, new profileConfig) { changes <- new }, WithProfile("local"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Close()
+
+	local := filepath.Join(filepath.Dir(path), "app.local.yaml")
+	if err := os.WriteFile(local, []byte("level: debug\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case c := <-changes:
+		if c.Level != "debug" {
+			t.Fatalf("unexpected change %+v", c)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected change after creating the profile file")
+	}
+
+	if err := os.Remove(local); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case c := <-changes:
+		if c.Level != "info" {
+			t.Fatalf("unexpected change %+v", c)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected change after removing the profile file")
+	}
+}
@@ -0,0 +1,186 @@
+package config
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// maxRemoteSize 配置内容的大小上限
+const maxRemoteSize = 16 << 20
+
+// errNotFound 配置中心中不存在对应的配置
+var errNotFound = errors.New("not found")
+
+// httpProvider 以HTTP接口读取配置，各配置中心只需构造请求和解析响应
+type httpProvider struct {
+	client  *http.Client
+	headers map[string]string
+	request func(ctx context.Context) (*http.Request, error)
+	extract func(body []byte) ([]byte, error) // 为nil时响应体即配置内容
+}
+
+func newHTTPProvider(opts RemoteOptions) (*httpProvider, error) {
+	if opts.Endpoint == "" {
+		return nil, errors.New("endpoint is required")
+	}
+	if opts.Key == "" {
+		return nil, errors.New("key is required")
+	}
+	return &httpProvider{client: &http.Client{Timeout: opts.Timeout}, headers: opts.Headers}, nil
+}
+
+func (p *httpProvider) Get(ctx context.Context) ([]byte, error) {
+	req, err := p.request(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for k, v := range p.headers {
+		req.Header.Set(k, v)
+	}
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxRemoteSize))
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		return nil, errNotFound
+	case resp.StatusCode != http.StatusOK:
+		return nil, fmt.Errorf("unexpected status %s: %s", resp.Status, bytes.TrimSpace(truncate(body, 1024)))
+	}
+	if p.extract == nil {
+		return body, nil
+	}
+	return p.extract(body)
+}
+
+func truncate(b []byte, n int) []byte {
+	if len(b) > n {
+		return b[:n]
+	}
+	return b
+}
+
+func endpointURL(endpoint, path string, query url.Values) string {
+	u := strings.TrimRight(endpoint, "/") + path
+	if len(query) > 0 {
+		u += "?" + query.Encode()
+	}
+	return u
+}
+
+// newConsulProvider 读取Consul KV中Key的原始值
+func newConsulProvider(opts RemoteOptions) (Provider, error) {
+	p, err := newHTTPProvider(opts)
+	if err != nil {
+		return nil, err
+	}
+	u := endpointURL(opts.Endpoint, "/v1/kv/"+strings.TrimLeft(opts.Key, "/"), url.Values{"raw": {"true"}})
+	p.request = func(ctx context.Context) (*http.Request, error) {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+		if err == nil && opts.Token != "" {
+			req.Header.Set("X-Consul-Token", opts.Token)
+		}
+		return req, err
+	}
+	return p, nil
+}
+
+// newEtcdProvider 通过etcd v3的gRPC网关读取Key的值
+func newEtcdProvider(opts RemoteOptions) (Provider, error) {
+	p, err := newHTTPProvider(opts)
+	if err != nil {
+		return nil, err
+	}
+	u := endpointURL(opts.Endpoint, "/v3/kv/range", nil)
+	body, _ := json.Marshal(map[string]string{"key": base64.StdEncoding.EncodeToString([]byte(opts.Key))})
+	p.request = func(ctx context.Context) (*http.Request, error) {
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, u, bytes.NewReader(body))
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Content-Type", "application/json")
+		if opts.Token != "" {
+			req.Header.Set("Authorization", opts.Token)
+		}
+		return req, nil
+	}
+	p.extract = func(body []byte) ([]byte, error) {
+		var resp struct {
+			Kvs []struct {
+				Value string `json:"value"`
+			} `json:"kvs"`
+		}
+		if err := json.Unmarshal(body, &resp); err != nil {
+			return nil, fmt.Errorf("invalid etcd response: %w", err)
+		}
+		if len(resp.Kvs) == 0 {
+			return nil, errNotFound
+		}
+		return base64.StdEncoding.DecodeString(resp.Kvs[0].Value)
+	}
+	return p, nil
+}
+
+// newNacosProvider 通过Nacos的open API读取dataId为Key的配置
+func newNacosProvider(opts RemoteOptions) (Provider, error) {
+	p, err := newHTTPProvider(opts)
+	if err != nil {
+		return nil, err
+	}
+	query := url.Values{"dataId": {opts.Key}, "group": {cmp.Or(opts.Group, "DEFAULT_GROUP")}}
+	if opts.Namespace != "" {
+		query.Set("tenant", opts.Namespace)
+	}
+	if opts.Token != "" {
+		query.Set("accessToken", opts.Token)
+	}
+	u := endpointURL(opts.Endpoint, "/nacos/v1/cs/configs", query)
+	p.request = func(ctx context.Context) (*http.Request, error) {
+		return http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	}
+	return p, nil
+}
+
+// newApolloProvider 通过Apollo的配置服务读取namespace为Key的配置：
+// yaml、json等文件格式的namespace返回其内容，properties格式的返回各键值组成的json
+func newApolloProvider(opts RemoteOptions) (Provider, error) {
+	p, err := newHTTPProvider(opts)
+	if err != nil {
+		return nil, err
+	}
+	if opts.AppID == "" {
+		return nil, errors.New("app_id is required")
+	}
+	path := "/configs/" + url.PathEscape(opts.AppID) + "/" + url.PathEscape(cmp.Or(opts.Cluster, "default")) + "/" + url.PathEscape(opts.Key)
+	u := endpointURL(opts.Endpoint, path, nil)
+	p.request = func(ctx context.Context) (*http.Request, error) {
+		return http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	}
+	p.extract = func(body []byte) ([]byte, error) {
+		var resp struct {
+			Configurations map[string]string `json:"configurations"`
+		}
+		if err := json.Unmarshal(body, &resp); err != nil {
+			return nil, fmt.Errorf("invalid apollo response: %w", err)
+		}
+		if content, ok := resp.Configurations["content"]; ok && strings.Contains(opts.Key, ".") {
+			return []byte(content), nil
+		}
+		return json.Marshal(resp.Configurations)
+	}
+	return p, nil
+}
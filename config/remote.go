@@ -0,0 +1,197 @@
+package config
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	defaultRemoteTimeout      = 5 * time.Second
+	defaultRemotePollInterval = 10 * time.Second
+)
+
+// Provider 从配置中心读取一份配置的原始内容
+type Provider interface {
+	Get(ctx context.Context) ([]byte, error)
+}
+
+// ProviderFactory 根据RemoteOptions创建Provider
+type ProviderFactory func(opts RemoteOptions) (Provider, error)
+
+// RemoteOptions 配置中心参数，各字段对不同配置中心的含义见注释
+type RemoteOptions struct {
+	Endpoint     string            `yaml:"endpoint" mapstructure:"endpoint"`           // 配置中心地址，如http://127.0.0.1:8500
+	Key          string            `yaml:"key" mapstructure:"key"`                     // consul、etcd为键，nacos为dataId，apollo为namespace
+	Group        string            `yaml:"group" mapstructure:"group"`                 // nacos的group，默认DEFAULT_GROUP
+	Namespace    string            `yaml:"namespace" mapstructure:"namespace"`         // nacos的命名空间ID
+	AppID        string            `yaml:"app_id" mapstructure:"app_id"`               // apollo的appId
+	Cluster      string            `yaml:"cluster" mapstructure:"cluster"`             // apollo的集群，默认default
+	Token        string            `yaml:"token" mapstructure:"token"`                 // consul的ACL token，nacos的accessToken，etcd的Authorization头
+	Headers      map[string]string `yaml:"headers" mapstructure:"headers"`             // 附加的请求头
+	Format       string            `yaml:"format" mapstructure:"format"`               // 配置内容的格式，默认根据Key的扩展名判断，无法判断时按yaml解析
+	CacheFile    string            `yaml:"cache_file" mapstructure:"cache_file"`       // 本地缓存文件，读取成功后写入，配置中心不可用时从中读取
+	Timeout      time.Duration     `yaml:"timeout" mapstructure:"timeout"`             // 单次请求超时，默认5s
+	PollInterval time.Duration     `yaml:"poll_interval" mapstructure:"poll_interval"` // WatchRemote的轮询间隔，默认10s
+	OnError      func(error)       `yaml:"-" mapstructure:"-"`                         // 配置中心不可用而改用缓存时的处理函数，默认输出到stderr
+}
+
+var (
+	providerFactories = map[string]ProviderFactory{
+		"consul": newConsulProvider,
+		"etcd":   newEtcdProvider,
+		"nacos":  newNacosProvider,
+		"apollo": newApolloProvider,
+	}
+	registryMu sync.RWMutex
+)
+
+// RegisterProvider 注册自定义配置中心，NewRemote中通过name引用，重复注册时覆盖原有实现
+func RegisterProvider(name string, factory ProviderFactory) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	providerFactories[strings.ToLower(name)] = factory
+}
+
+func lookupProvider(name string) (ProviderFactory, bool) {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	f, ok := providerFactories[strings.ToLower(name)]
+	return f, ok
+}
+
+// Remote 从配置中心读取配置，读取成功时写入本地缓存，配置中心短暂不可用时从缓存读取
+type Remote struct {
+	name     string
+	provider Provider
+	opts     RemoteOptions
+
+	mu     sync.Mutex
+	cached []byte // 最近写入缓存文件的内容
+}
+
+// NewRemote 创建provider对应的配置中心客户端，provider为内置的consul、etcd、nacos、apollo
+// 或通过RegisterProvider注册的名称
+func NewRemote(provider string, opts RemoteOptions) (*Remote, error) {
+	factory, ok := lookupProvider(provider)
+	if !ok {
+		return nil, fmt.Errorf("config: unknown provider %q", provider)
+	}
+	opts.Timeout = cmp.Or(opts.Timeout, defaultRemoteTimeout)
+	opts.PollInterval = cmp.Or(opts.PollInterval, defaultRemotePollInterval)
+	if opts.Format == "" {
+		opts.Format = fileFormat(opts.Key)
+	}
+	if opts.OnError == nil {
+		opts.OnError = func(err error) { fmt.Fprintln(os.Stderr, err) }
+	}
+	p, err := factory(opts)
+	if err != nil {
+		return nil, fmt.Errorf("config %s: %w", provider, err)
+	}
+	return &Remote{name: provider, provider: p, opts: opts}, nil
+}
+
+// Get 返回配置的原始内容。配置中心读取失败时，如有缓存文件则返回缓存内容，并将错误交给OnError
+func (r *Remote) Get(ctx context.Context) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.opts.Timeout)
+	defer cancel()
+	data, err := r.provider.Get(ctx)
+	if err == nil {
+		r.writeCache(data)
+		return data, nil
+	}
+	err = fmt.Errorf("config %s %s: %w", r.name, r.opts.Key, err)
+	if r.opts.CacheFile == "" || errors.Is(err, context.Canceled) {
+		return nil, err
+	}
+	cached, cacheErr := os.ReadFile(r.opts.CacheFile)
+	if cacheErr != nil {
+		return nil, errors.Join(err, fmt.Errorf("config cache: %w", cacheErr))
+	}
+	r.opts.OnError(fmt.Errorf("%w, using cache %s", err, r.opts.CacheFile))
+	return cached, nil
+}
+
+// Load 读取配置并解析到out，解析流程同Load
+func (r *Remote) Load(ctx context.Context, out any, opts ...Option) error {
+	if err := checkTarget(out); err != nil {
+		return err
+	}
+	return r.load(ctx, out, r.options(opts))
+}
+
+func (r *Remote) options(opts []Option) *options {
+	return newOptions(append([]Option{WithFormat(r.opts.Format)}, opts...))
+}
+
+func (r *Remote) load(ctx context.Context, out any, o *options) error {
+	data, err := r.Get(ctx)
+	if err != nil {
+		return err
+	}
+	settings, err := parse(data, o.format)
+	if err == nil {
+		err = decode(settings, out, o)
+	}
+	if err != nil {
+		return fmt.Errorf("config %s %s: %w", r.name, r.opts.Key, err)
+	}
+	return nil
+}
+
+// writeCache 内容变化时写入缓存文件，先写临时文件再重命名，避免进程退出时留下不完整的缓存
+func (r *Remote) writeCache(data []byte) {
+	if r.opts.CacheFile == "" {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.cached != nil && bytes.Equal(r.cached, data) {
+		return
+	}
+	if err := writeFileAtomic(r.opts.CacheFile, data); err != nil {
+		r.opts.OnError(fmt.Errorf("config cache: %w", err))
+		return
+	}
+	r.cached = bytes.Clone(data)
+}
+
+func writeFileAtomic(path string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// WatchRemote 读取配置到out，然后每隔PollInterval重新读取，变更时调用onChange，行为同Watch
+func WatchRemote[T any](r *Remote, out *T, onChange func(old, new T), opts ...Option) (*Watcher[T], error) {
+	o := r.options(opts)
+	w, err := newWatcher(out, onChange, o, func(ctx context.Context, out any) error {
+		return r.load(ctx, out, o)
+	})
+	if err != nil {
+		return nil, err
+	}
+	go w.poll(r.opts.PollInterval)
+	return w, nil
+}
@@ -0,0 +1,201 @@
+package config
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type remoteConfig struct {
+	Level string `mapstructure:"level" validate:"required"`
+	DB    struct {
+		MaxConns int `mapstructure:"max_conns" default:"10"`
+	} `mapstructure:"db"`
+}
+
+func TestRemoteProviders(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /v1/kv/app/config.yaml", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("raw") != "true" || r.Header.Get("X-Consul-Token") != "secret" {
+			http.Error(w, "bad request", http.StatusBadRequest)
+			return
+		}
+		w.Write([]byte("level: consul\n"))
+	})
+	mux.HandleFunc("POST /v3/kv/range", func(w http.ResponseWriter, r *http.Request) {
+		var req struct{ Key string }
+		json.NewDecoder(r.Body).Decode(&req)
+		if key, _ := base64.StdEncoding.DecodeString(req.Key); string(key) != "/app/config.json" {
+			w.Write([]byte(`{}`))
+			return
+		}
+		value := base64.StdEncoding.EncodeToString([]byte(`{"level": "etcd"}`))
+		w.Write([]byte(`{"kvs": [{"value": "` + value + `"}]}`))
+	})
+	mux.HandleFunc("GET /nacos/v1/cs/configs", func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if q.Get("dataId") != "app.toml" || q.Get("group") != "DEFAULT_GROUP" || q.Get("tenant") != "prod" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte("level = \"nacos\"\n"))
+	})
+	mux.HandleFunc("GET /configs/app/default/app.yaml", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"configurations": {"content": "level: apollo\n"}}`))
+	})
+	mux.HandleFunc("GET /configs/app/default/application", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"configurations": {"level": "apollo-properties", "db.max_conns": "20"}}`))
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	tests := []struct {
+		provider string
+		opts     RemoteOptions
+		level    string
+		maxConns int
+	}{
+		{"consul", RemoteOptions{Key: "app/config.yaml", Token: "secret"}, "consul", 10},
+		{"etcd", RemoteOptions{Key: "/app/config.json"}, "etcd", 10},
+		{"nacos", RemoteOptions{Key: "app.toml", Namespace: "prod"}, "nacos", 10},
+		{"apollo", RemoteOptions{Key: "app.yaml", AppID: "app"}, "apollo", 10},
+		{"apollo", RemoteOptions{Key: "application", AppID: "app"}, "apollo-properties", 20},
+	}
+	for _, tt := range tests {
+		tt.opts.Endpoint = srv.URL
+		r, err := NewRemote(tt.provider, tt.opts)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var cfg remoteConfig
+		if err := r.Load(context.Background(), &cfg); err != nil {
+			t.Fatalf("%s %s: %v", tt.provider, tt.opts.Key, err)
+		}
+		if cfg.Level != tt.level || cfg.DB.MaxConns != tt.maxConns {
+			t.Fatalf("%s %s: unexpected config %+v", tt.provider, tt.opts.Key, cfg)
+		}
+	}
+
+	r, _ := NewRemote("etcd", RemoteOptions{Endpoint: srv.URL, Key: "/missing"})
+	if _, err := r.Get(context.Background()); !errors.Is(err, errNotFound) {
+		t.Fatalf("expected not found, got %v", err)
+	}
+	if _, err := NewRemote("zookeeper", RemoteOptions{}); err == nil {
+		t.Fatal("expected error for unknown provider")
+	}
+	if _, err := NewRemote("apollo", RemoteOptions{Endpoint: srv.URL, Key: "application"}); err == nil || !strings.Contains(err.Error(), "app_id is required") {
+		t.Fatalf("expected app_id error, got %v", err)
+	}
+	if _, err := NewRemote("consul", RemoteOptions{Key: "app"}); err == nil || !strings.Contains(err.Error(), "endpoint is required") {
+		t.Fatalf("expected endpoint error, got %v", err)
+	}
+}
+
+// staticProvider 返回content中的内容，fail时返回错误
+type staticProvider struct {
+	content atomic.Value
+	fail    atomic.Bool
+}
+
+func (p *staticProvider) Get(context.Context) ([]byte, error) {
+	if p.fail.Load() {
+		return nil, errors.New("connection refused")
+	}
+	return []byte(p.content.Load().(string)), nil
+}
+
+func TestRemoteCache(t *testing.T) {
+	p := &staticProvider{}
+	p.content.Store("level: info\n")
+	RegisterProvider("test-static", func(RemoteOptions) (Provider, error) { return p, nil })
+
+	cache := filepath.Join(t.TempDir(), "cache", "app.yaml")
+	var reported []error
+	opts := RemoteOptions{Key: "app.yaml", CacheFile: cache, OnError: func(err error) { reported = append(reported, err) }}
+	r, err := NewRemote("test-static", opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var cfg remoteConfig
+	if err := r.Load(context.Background(), &cfg); err != nil {
+		t.Fatal(err)
+	}
+	if data, _ := os.ReadFile(cache); string(data) != "level: info\n" {
+		t.Fatalf("expected cache to be written, got %q", data)
+	}
+
+	// 配置中心不可用时从缓存读取，新建的Remote同样可用
+	p.fail.Store(true)
+	r, _ = NewRemote("test-static", opts)
+	cfg = remoteConfig{}
+	if err := r.Load(context.Background(), &cfg); err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Level != "info" || len(reported) != 1 || !strings.Contains(reported[0].Error(), "connection refused, using cache") {
+		t.Fatalf("expected config from cache, got %+v %v", cfg, reported)
+	}
+
+	os.Remove(cache)
+	if err := r.Load(context.Background(), &cfg); err == nil || !strings.Contains(err.Error(), "connection refused") {
+		t.Fatalf("expected error without cache, got %v", err)
+	}
+}
+
+func TestWatchRemote(t *testing.T) {
+	p := &staticProvider{}
+	p.content.Store("level: info\n")
+	RegisterProvider("test-watch", func(RemoteOptions) (Provider, error) { return p, nil })
+	r, err := NewRemote("test-watch", RemoteOptions{Key: "app", PollInterval: 10 * time.Millisecond})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	changes := make(chan remoteConfig, 10)
+	errs := make(chan error, 10)
+	var cfg remoteConfig
+	w, err := WatchRemote(r, &cfg, func(old, new remoteConfig) { changes <- new }, WithErrorHandler(func(err error) {
+		select {
+		case errs <- err:
+		default:
+		}
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Close()
+	if cfg.Level != "info" {
+		t.Fatalf("unexpected initial config %+v", cfg)
+	}
+
+	p.fail.Store(true)
+	select {
+	case err := <-errs:
+		if !strings.Contains(err.Error(), "connection refused") {
+			t.Fatalf("unexpected error %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected poll error")
+	}
+	p.content.Store("level: warn\n")
+	p.fail.Store(false)
+	select {
+	case c := <-changes:
+		if c.Level != "warn" || w.Get().Level != "warn" {
+			t.Fatalf("unexpected change %+v", c)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected change callback")
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
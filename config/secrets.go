@@ -0,0 +1,212 @@
+package config
+
+import (
+	"cmp"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// Resolver 解析${scheme:ref}形式的占位符，返回替换后的值
+type Resolver interface {
+	Resolve(ref string) (string, error)
+}
+
+// ResolverFunc 以函数实现Resolver
+type ResolverFunc func(ref string) (string, error)
+
+// Resolve 实现Resolver
+func (f ResolverFunc) Resolve(ref string) (string, error) {
+	return f(ref)
+}
+
+var resolvers = map[string]Resolver{
+	"env":   ResolverFunc(resolveEnv),
+	"file":  ResolverFunc(resolveFile),
+	"vault": &VaultResolver{},
+}
+
+// RegisterResolver 注册占位符解析器，配置中通过${scheme:ref}引用，重复注册时覆盖原有实现
+func RegisterResolver(scheme string, r Resolver) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	resolvers[strings.ToLower(scheme)] = r
+}
+
+// WithResolver 为本次加载指定占位符解析器，优先于RegisterResolver注册的同名解析器
+func WithResolver(scheme string, r Resolver) Option {
+	return func(o *options) {
+		if o.resolvers == nil {
+			o.resolvers = map[string]Resolver{}
+		}
+		o.resolvers[strings.ToLower(scheme)] = r
+	}
+}
+
+func (o *options) resolver(scheme string) (Resolver, bool) {
+	if r, ok := o.resolvers[scheme]; ok {
+		return r, true
+	}
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	r, ok := resolvers[scheme]
+	return r, ok
+}
+
+// placeholder 匹配${scheme:ref}，$${为转义，保留为${
+var placeholder = regexp.MustCompile(`\$?\$\{([a-zA-Z][a-zA-Z0-9_-]*):([^}]*)\}`)
+
+// expand 替换settings中字符串值里的占位符，返回替换后的副本，不修改settings
+func expand(settings map[string]any, o *options) (map[string]any, []error) {
+	var errs []error
+	out := expandValue(settings, "", o, &errs).(map[string]any)
+	return out, errs
+}
+
+func expandValue(v any, path string, o *options, errs *[]error) any {
+	switch v := v.(type) {
+	case string:
+		s, err := expandString(v, o)
+		if err != nil {
+			*errs = append(*errs, fmt.Errorf("%s: %w", path, err))
+		}
+		return s
+	case map[string]any:
+		out := make(map[string]any, len(v))
+		for key, value := range v {
+			out[key] = expandValue(value, join(path, key), o, errs)
+		}
+		return out
+	case []any:
+		out := make([]any, len(v))
+		for i, value := range v {
+			out[i] = expandValue(value, fmt.Sprintf("%s[%d]", path, i), o, errs)
+		}
+		return out
+	case []map[string]any:
+		out := make([]any, len(v))
+		for i, value := range v {
+			out[i] = expandValue(value, fmt.Sprintf("%s[%d]", path, i), o, errs)
+		}
+		return out
+	}
+	return v
+}
+
+func expandString(s string, o *options) (string, error) {
+	if !strings.Contains(s, "${") {
+		return s, nil
+	}
+	var err error
+	out := placeholder.ReplaceAllStringFunc(s, func(m string) string {
+		if strings.HasPrefix(m, "$$") {
+			return m[1:]
+		}
+		sub := placeholder.FindStringSubmatch(m)
+		scheme, ref := strings.ToLower(sub[1]), sub[2]
+		r, ok := o.resolver(scheme)
+		if !ok {
+			err = cmp.Or(err, fmt.Errorf("unknown resolver %q", scheme))
+			return m
+		}
+		value, resolveErr := r.Resolve(ref)
+		if resolveErr != nil {
+			err = cmp.Or(err, fmt.Errorf("resolve ${%s:%s}: %w", scheme, ref, resolveErr))
+			return m
+		}
+		return value
+	})
+	return out, err
+}
+
+func resolveEnv(name string) (string, error) {
+	value, ok := os.LookupEnv(name)
+	if !ok {
+		return "", errors.New("environment variable is not set")
+	}
+	return value, nil
+}
+
+// resolveFile 返回文件内容，去掉末尾的换行，适用于docker、kubernetes挂载的secret
+func resolveFile(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimRight(string(data), "\r\n"), nil
+}
+
+// VaultResolver 从HashiCorp Vault的KV引擎读取secret，占位符形式为${vault:<mount>/<path>#<key>}，
+// 如${vault:secret/app#db_password}
+type VaultResolver struct {
+	Address   string       // Vault地址，默认取VAULT_ADDR
+	Token     string       // 访问token，默认取VAULT_TOKEN
+	KVVersion int          // KV引擎版本1或2，默认2
+	Client    *http.Client // 默认超时5s
+}
+
+// Resolve 实现Resolver
+func (v *VaultResolver) Resolve(ref string) (string, error) {
+	secretPath, key, ok := strings.Cut(ref, "#")
+	mount, rest, _ := strings.Cut(strings.Trim(secretPath, "/"), "/")
+	if !ok || key == "" || mount == "" || rest == "" {
+		return "", errors.New("expected <mount>/<path>#<key>")
+	}
+	addr := cmp.Or(v.Address, os.Getenv("VAULT_ADDR"))
+	if addr == "" {
+		return "", errors.New("vault address is not set, set VAULT_ADDR")
+	}
+	apiPath := "/v1/" + url.PathEscape(mount) + "/data/" + rest
+	if v.KVVersion == 1 {
+		apiPath = "/v1/" + url.PathEscape(mount) + "/" + rest
+	}
+	req, err := http.NewRequest(http.MethodGet, strings.TrimRight(addr, "/")+apiPath, nil)
+	if err != nil {
+		return "", err
+	}
+	if token := cmp.Or(v.Token, os.Getenv("VAULT_TOKEN")); token != "" {
+		req.Header.Set("X-Vault-Token", token)
+	}
+	client := v.Client
+	if client == nil {
+		client = &http.Client{Timeout: 5 * time.Second}
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxRemoteSize))
+	if err != nil {
+		return "", err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("vault: unexpected status %s", resp.Status)
+	}
+	var secret struct {
+		Data map[string]any `json:"data"`
+	}
+	if err := json.Unmarshal(body, &secret); err != nil {
+		return "", fmt.Errorf("vault: invalid response: %w", err)
+	}
+	data := secret.Data
+	if v.KVVersion != 1 {
+		data, _ = data["data"].(map[string]any)
+	}
+	value, ok := data[key]
+	if !ok {
+		return "", fmt.Errorf("vault: key %q not found in %s", key, secretPath)
+	}
+	if s, ok := value.(string); ok {
+		return s, nil
+	}
+	b, err := json.Marshal(value)
+	return string(b), err
+}
@@ -0,0 +1,122 @@
+package config
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type secretsConfig struct {
+	DSN      string   `mapstructure:"dsn"`
+	Password string   `mapstructure:"password"`
+	Port     int      `mapstructure:"port"`
+	Hosts    []string `mapstructure:"hosts"`
+	APIKey   string   `mapstructure:"api_key"`
+	Literal  string   `mapstructure:"literal"`
+}
+
+func TestLoadSecrets(t *testing.T) {
+	secret := filepath.Join(t.TempDir(), "password")
+	if err := os.WriteFile(secret, []byte("s3cret\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	vault := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/secret/data/app" || r.Header.Get("X-Vault-Token") != "root" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte(`{"data": {"data": {"api_key": "vault-key"}, "metadata": {"version": 1}}}`))
+	}))
+	defer vault.Close()
+	t.Setenv("VAULT_ADDR", vault.URL)
+	t.Setenv("VAULT_TOKEN", "root")
+	t.Setenv("TEST_DB_USER", "app")
+	t.Setenv("TEST_PORT", "3306")
+
+	path := writeFile(t, "app.yaml", `
+dsn: mysql://${env:TEST_DB_USER}:${file:`+secret+`}@db:${env:TEST_PORT}/app
+password: ${file:`+secret+`}
+port: ${env:TEST_PORT}
+hosts: ["${env:TEST_DB_USER}.internal", other]
+api_key: ${vault:secret/app#api_key}
+literal: $${env:TEST_PORT} ${HOME}
+`)
+	var cfg secretsConfig
+	if err := Load(path, &cfg); err != nil {
+		t.Fatal(err)
+	}
+	want := secretsConfig{
+		DSN:      "mysql://app:s3cret@db:3306/app",
+		Password: "s3cret",
+		Port:     3306,
+		Hosts:    []string{"app.internal", "other"},
+		APIKey:   "vault-key",
+		Literal:  "${env:TEST_PORT} ${HOME}",
+	}
+	if !reflect.DeepEqual(cfg, want) {
+		t.Fatalf("expected %+v, got %+v", want, cfg)
+	}
+}
+
+func TestLoadSecretsErrors(t *testing.T) {
+	path := writeFile(t, "app.yaml", "dsn: ${env:TEST_MISSING_VAR}\npassword: ${kms:app}\n")
+	var cfg secretsConfig
+	err := Load(path, &cfg)
+	if err == nil {
+		t.Fatal("expected error")
+	}
+	for _, want := range []string{"dsn: resolve ${env:TEST_MISSING_VAR}: environment variable is not set", `password: unknown resolver "kms"`} {
+		if !strings.Contains(err.Error(), want) {
+			t.Fatalf("expected %q in %v", want, err)
+		}
+	}
+
+	// WithResolver为本次加载注册解析器
+	kms := ResolverFunc(func(ref string) (string, error) {
+		if ref == "app" {
+			return "decrypted", nil
+		}
+		return "", errors.New("no such key")
+	})
+	t.Setenv("TEST_MISSING_VAR", "dsn")
+	if err := Load(path, &cfg, WithResolver("kms", kms)); err != nil || cfg.Password != "decrypted" {
+		t.Fatalf("unexpected result %+v %v", cfg, err)
+	}
+	if err := Load(path, &cfg); err == nil {
+		t.Fatal("WithResolver should not register the resolver globally")
+	}
+}
+
+func TestVaultResolver(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v1/kv/app":
+			w.Write([]byte(`{"data": {"password": "v1-secret", "port": 5432}}`))
+		default:
+			http.Error(w, "forbidden", http.StatusForbidden)
+		}
+	}))
+	defer srv.Close()
+
+	v := &VaultResolver{Address: srv.URL, KVVersion: 1}
+	if s, err := v.Resolve("kv/app#password"); err != nil || s != "v1-secret" {
+		t.Fatalf("unexpected result %q %v", s, err)
+	}
+	if s, err := v.Resolve("kv/app#port"); err != nil || s != "5432" {
+		t.Fatalf("unexpected result %q %v", s, err)
+	}
+	if _, err := v.Resolve("kv/app#missing"); err == nil || !strings.Contains(err.Error(), `key "missing" not found`) {
+		t.Fatalf("expected missing key error, got %v", err)
+	}
+	if _, err := v.Resolve("kv/other#password"); err == nil || !strings.Contains(err.Error(), "403") {
+		t.Fatalf("expected status error, got %v", err)
+	}
+	if _, err := v.Resolve("kv#password"); err == nil {
+		t.Fatal("expected error for invalid reference")
+	}
+}
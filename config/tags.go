@@ -0,0 +1,188 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// applyDefaults 以default标签填充零值字段，递归处理嵌套结构体及结构体切片的各元素。
+// 无法区分未配置与显式配置为零值，需要零值生效的字段不应设置default
+func applyDefaults(v reflect.Value, path string) error {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		key, ok := fieldKey(f)
+		if !ok {
+			continue
+		}
+		fv := v.Field(i)
+		if def, ok := f.Tag.Lookup("default"); ok && fv.IsZero() {
+			if err := setString(fv, def); err != nil {
+				return fmt.Errorf("%s: invalid default %q: %w", join(path, key), def, err)
+			}
+		}
+		if err := walk(fv, join(path, key), applyDefaults); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// walk 对嵌套的结构体、结构体指针及结构体切片的元素调用fn
+func walk(v reflect.Value, path string, fn func(reflect.Value, string) error) error {
+	switch {
+	case v.Kind() == reflect.Pointer && !v.IsNil() && isStruct(v.Elem().Type()):
+		return fn(v.Elem(), path)
+	case isStruct(v.Type()):
+		return fn(v, path)
+	case v.Kind() == reflect.Slice && isStruct(indirect(v.Type().Elem())):
+		for i := 0; i < v.Len(); i++ {
+			if err := walk(v.Index(i), fmt.Sprintf("%s[%d]", path, i), fn); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// setString 将字符串按字段类型解析后赋值，切片以逗号分隔
+func setString(v reflect.Value, s string) error {
+	if v.Type() == reflect.TypeOf(time.Duration(0)) {
+		d, err := time.ParseDuration(s)
+		if err != nil {
+			return err
+		}
+		v.SetInt(int64(d))
+		return nil
+	}
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(s)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return err
+		}
+		v.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(s, 0, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(s, 0, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		n, err := strconv.ParseFloat(s, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetFloat(n)
+	case reflect.Slice:
+		parts := strings.Split(s, ",")
+		slice := reflect.MakeSlice(v.Type(), len(parts), len(parts))
+		for i, part := range parts {
+			if err := setString(slice.Index(i), strings.TrimSpace(part)); err != nil {
+				return err
+			}
+		}
+		v.Set(slice)
+	default:
+		return fmt.Errorf("unsupported type %s", v.Type())
+	}
+	return nil
+}
+
+// validate 按validate标签校验字段，多个规则以逗号分隔：
+// required 不能为零值；min=n、max=n 数值的范围，字符串、切片、map的长度范围；oneof=a b c 取值之一
+func validate(v reflect.Value, path string) []error {
+	var errs []error
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		key, ok := fieldKey(f)
+		if !ok {
+			continue
+		}
+		fv := v.Field(i)
+		fieldPath := join(path, key)
+		if rules := f.Tag.Get("validate"); rules != "" {
+			for _, rule := range strings.Split(rules, ",") {
+				if err := checkRule(fv, strings.TrimSpace(rule)); err != nil {
+					errs = append(errs, fmt.Errorf("%s: %w", fieldPath, err))
+				}
+			}
+		}
+		_ = walk(fv, fieldPath, func(v reflect.Value, path string) error {
+			errs = append(errs, validate(v, path)...)
+			return nil
+		})
+	}
+	return errs
+}
+
+func checkRule(v reflect.Value, rule string) error {
+	name, arg, _ := strings.Cut(rule, "=")
+	switch name {
+	case "":
+		return nil
+	case "required":
+		if v.IsZero() {
+			return fmt.Errorf("is required")
+		}
+		return nil
+	case "oneof":
+		options := strings.Fields(arg)
+		if s := fmt.Sprint(v.Interface()); !slices.Contains(options, s) {
+			return fmt.Errorf("must be one of [%s], got %q", strings.Join(options, " "), s)
+		}
+		return nil
+	case "min", "max":
+		limit, err := strconv.ParseFloat(arg, 64)
+		if err != nil {
+			return fmt.Errorf("invalid rule %q", rule)
+		}
+		n, unit, ok := measure(v)
+		if !ok {
+			return fmt.Errorf("rule %q does not apply to %s", rule, v.Type())
+		}
+		if name == "min" && n < limit {
+			return fmt.Errorf("must be at least %s%s, got %v", arg, unit, n)
+		}
+		if name == "max" && n > limit {
+			return fmt.Errorf("must be at most %s%s, got %v", arg, unit, n)
+		}
+		return nil
+	}
+	return fmt.Errorf("unknown validate rule %q", rule)
+}
+
+// measure 返回min、max比较的数值：数值类型为其值，字符串、切片、map为长度
+func measure(v reflect.Value) (float64, string, bool) {
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(v.Int()), "", true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(v.Uint()), "", true
+	case reflect.Float32, reflect.Float64:
+		return v.Float(), "", true
+	case reflect.String, reflect.Slice, reflect.Map:
+		return float64(v.Len()), " in length", true
+	}
+	return 0, "", false
+}
+
+func join(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
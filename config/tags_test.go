@@ -0,0 +1,71 @@
+package config
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+type tagsItem struct {
+	Name   string `mapstructure:"name" validate:"required"`
+	Weight int    `mapstructure:"weight" default:"1"`
+}
+
+type tagsConfig struct {
+	Enabled bool          `default:"true"`
+	Ratio   float64       `default:"0.5" validate:"min=0,max=1"`
+	Port    uint16        `default:"8080"`
+	Delay   time.Duration `default:"1m"`
+	Items   []tagsItem    `mapstructure:"items" validate:"min=1"`
+	Nested  *tagsItem     `mapstructure:"nested"`
+	Ignored string        `mapstructure:"-" default:"x"`
+}
+
+func TestApplyDefaults(t *testing.T) {
+	cfg := tagsConfig{Port: 9090, Items: []tagsItem{{Name: "a"}, {Name: "b", Weight: 3}}, Nested: &tagsItem{}}
+	if err := applyDefaults(reflect.ValueOf(&cfg).Elem(), ""); err != nil {
+		t.Fatal(err)
+	}
+	if !cfg.Enabled || cfg.Ratio != 0.5 || cfg.Port != 9090 || cfg.Delay != time.Minute || cfg.Ignored != "" {
+		t.Fatalf("unexpected config %+v", cfg)
+	}
+	if cfg.Items[0].Weight != 1 || cfg.Items[1].Weight != 3 || cfg.Nested.Weight != 1 {
+		t.Fatalf("expected defaults in nested structs, got %+v %+v", cfg.Items, cfg.Nested)
+	}
+
+	var bad struct {
+		Port int `mapstructure:"port" default:"http"`
+	}
+	if err := applyDefaults(reflect.ValueOf(&bad).Elem(), ""); err == nil || !strings.Contains(err.Error(), `port: invalid default "http"`) {
+		t.Fatalf("expected invalid default error, got %v", err)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	cfg := tagsConfig{Ratio: 1.5, Items: []tagsItem{{Name: "a"}, {}}, Nested: &tagsItem{}}
+	var msgs []string
+	for _, err := range validate(reflect.ValueOf(&cfg).Elem(), "") {
+		msgs = append(msgs, err.Error())
+	}
+	want := []string{
+		"ratio: must be at most 1, got 1.5",
+		"items[1].name: is required",
+		"nested.name: is required",
+	}
+	if !reflect.DeepEqual(msgs, want) {
+		t.Fatalf("expected %q, got %q", want, msgs)
+	}
+
+	cfg = tagsConfig{}
+	if errs := validate(reflect.ValueOf(&cfg).Elem(), ""); len(errs) != 1 || errs[0].Error() != "items: must be at least 1 in length, got 0" {
+		t.Fatalf("unexpected errors %v", errs)
+	}
+
+	var unknown struct {
+		Name string `validate:"email"`
+	}
+	if errs := validate(reflect.ValueOf(&unknown).Elem(), ""); len(errs) != 1 || !strings.Contains(errs[0].Error(), `unknown validate rule "email"`) {
+		t.Fatalf("unexpected errors %v", errs)
+	}
+}
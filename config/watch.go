@@ -0,0 +1,202 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// watchDebounce 编辑器保存、ConfigMap更新等一次变更常触发多个事件，静默该时长后才重新加载
+const watchDebounce = 100 * time.Millisecond
+
+// WithErrorHandler 指定Watch重新加载失败时的处理函数，默认输出到stderr。
+// 加载或校验失败时保留原配置，不调用变更回调
+func WithErrorHandler(fn func(error)) Option {
+	return func(o *options) { o.onError = fn }
+}
+
+// Watcher 监听配置的变更，由Watch或WatchRemote创建
+type Watcher[T any] struct {
+	load     func(ctx context.Context, out any) error
+	onError  func(error)
+	onChange func(old, new T)
+	current  atomic.Pointer[T]
+
+	ctx      context.Context
+	cancel   context.CancelFunc
+	closeFn  func() error
+	stopOnce sync.Once
+	stopped  chan struct{}
+}
+
+// newWatcher 首次加载配置到out，失败时返回错误
+func newWatcher[T any](out *T, onChange func(old, new T), o *options, load func(ctx context.Context, out any) error) (*Watcher[T], error) {
+	if err := checkTarget(out); err != nil {
+		return nil, err
+	}
+	if o.onError == nil {
+		o.onError = func(err error) { fmt.Fprintln(os.Stderr, err) }
+	}
+	var cfg T
+	if err := load(context.Background(), &cfg); err != nil {
+		return nil, err
+	}
+	w := &Watcher[T]{
+		load:     load,
+		onError:  o.onError,
+		onChange: onChange,
+		stopped:  make(chan struct{}),
+	}
+	w.ctx, w.cancel = context.WithCancel(context.Background())
+	w.current.Store(&cfg)
+	*out = cfg
+	return w, nil
+}
+
+// Watch 以Load的方式加载path到out，然后监听path对应的各文件及profile文件的变更：变更后重新加载并校验，
+// 新配置与当前配置不同(reflect.DeepEqual)时调用onChange。out只接收首次加载的结果，
+// 之后的配置通过onChange或Watcher.Get获取；onChange在同一goroutine中串行调用
+func Watch[T any](path string, out *T, onChange func(old, new T), opts ...Option) (*Watcher[T], error) {
+	o := newOptions(opts)
+	w, err := newWatcher(out, onChange, o, func(_ context.Context, out any) error {
+		return load(path, out, o)
+	})
+	if err != nil {
+		return nil, err
+	}
+	files, err := sourceFiles(path, o)
+	if err != nil {
+		w.cancel()
+		return nil, err
+	}
+	fsw, err := fsnotify.NewWatcher()
+	if err != nil {
+		w.cancel()
+		return nil, fmt.Errorf("config %s: %w", path, err)
+	}
+	// 监听所在目录而非文件本身，文件被重命名替换后仍能收到事件
+	dirs := make(map[string]bool)
+	for _, file := range files {
+		dir := filepath.Dir(file)
+		if dirs[dir] {
+			continue
+		}
+		dirs[dir] = true
+		if err := fsw.Add(dir); err != nil {
+			w.cancel()
+			fsw.Close()
+			return nil, fmt.Errorf("config %s: %w", path, err)
+		}
+	}
+	w.closeFn = fsw.Close
+	go w.watchFile(fsw, files)
+	return w, nil
+}
+
+// Get 返回当前生效的配置
+func (w *Watcher[T]) Get() T {
+	return *w.current.Load()
+}
+
+// Close 停止监听，等待进行中的回调结束
+func (w *Watcher[T]) Close() error {
+	var err error
+	w.stopOnce.Do(func() {
+		w.cancel()
+		if w.closeFn != nil {
+			err = w.closeFn()
+		}
+		<-w.stopped
+	})
+	return err
+}
+
+// watchFile 监听files的变更，files为配置文件及各profile文件，尚不存在的profile文件创建后同样触发重新加载
+func (w *Watcher[T]) watchFile(fsw *fsnotify.Watcher, files []string) {
+	defer close(w.stopped)
+	real := make(map[string]string, len(files))
+	for _, file := range files {
+		file = filepath.Clean(file)
+		real[file], _ = filepath.EvalSymlinks(file)
+	}
+	timer := time.NewTimer(watchDebounce)
+	timer.Stop()
+	defer timer.Stop()
+	for {
+		select {
+		case <-w.ctx.Done():
+			return
+		case event, ok := <-fsw.Events:
+			if !ok {
+				return
+			}
+			if fileChanged(event, real) {
+				timer.Reset(watchDebounce)
+			}
+		case err, ok := <-fsw.Errors:
+			if !ok {
+				return
+			}
+			w.onError(fmt.Errorf("config %s: %w", files[0], err))
+		case <-timer.C:
+			w.reload()
+		}
+	}
+}
+
+// fileChanged 事件是否可能改变了配置：文件本身被写入、创建、删除，或符号链接指向了新文件。
+// real为各文件解析符号链接后的路径
+func fileChanged(event fsnotify.Event, real map[string]string) bool {
+	if _, ok := real[filepath.Clean(event.Name)]; ok && event.Op != fsnotify.Chmod {
+		return true
+	}
+	changed := false
+	for file, prev := range real {
+		current, _ := filepath.EvalSymlinks(file)
+		if current != "" && current != prev {
+			real[file] = current
+			changed = true
+		}
+	}
+	return changed
+}
+
+// poll 每隔interval重新加载一次
+func (w *Watcher[T]) poll(interval time.Duration) {
+	defer close(w.stopped)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-w.ctx.Done():
+			return
+		case <-ticker.C:
+			w.reload()
+		}
+	}
+}
+
+func (w *Watcher[T]) reload() {
+	var cfg T
+	if err := w.load(w.ctx, &cfg); err != nil {
+		if w.ctx.Err() == nil {
+			w.onError(err)
+		}
+		return
+	}
+	old := w.current.Load()
+	if reflect.DeepEqual(*old, cfg) {
+		return
+	}
+	w.current.Store(&cfg)
+	if w.onChange != nil {
+		w.onChange(*old, cfg)
+	}
+}
@@ -0,0 +1,140 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+type watchConfig struct {
+	Level string `mapstructure:"level" validate:"oneof=debug info warn"`
+	Rate  int    `mapstructure:"rate" default:"100"`
+}
+
+type change struct{ old, new watchConfig }
+
+func TestWatch(t *testing.T) {
+	path := writeFile(t, "app.yaml", "level: info\n")
+	changes := make(chan change, 10)
+	errs := make(chan error, 10)
+
+	var cfg watchConfig
+	w, err := Watch(path, &cfg, func(old, new watchConfig) {
+		changes <- change{old, new}
+	}, WithErrorHandler(func(err error) { errs <- err }))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Close()
+	if cfg.Level != "info" || cfg.Rate != 100 || w.Get() != cfg {
+		t.Fatalf("unexpected initial config %+v", cfg)
+	}
+
+	write := func(content string) {
+		t.Helper()
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	write("level: warn\n")
+	select {
+	case c := <-changes:
+		if c.old.Level != "info" || c.new.Level != "warn" || c.new.Rate != 100 {
+			t.Fatalf("unexpected change %+v", c)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected change callback")
+	}
+	if w.Get().Level != "warn" {
+		t.Fatalf("Get should return the reloaded config, got %+v", w.Get())
+	}
+
+	// 校验失败时保留原配置
+	write("level: trace\n")
+	select {
+	case err := <-errs:
+		if !strings.Contains(err.Error(), "level: must be one of") {
+			t.Fatalf("unexpected error %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected validation error")
+	}
+	if w.Get().Level != "warn" {
+		t.Fatalf("invalid config should not be applied, got %+v", w.Get())
+	}
+
+	// 内容等价的变更不触发回调
+	write("# comment\nlevel: warn\nrate: 100\n")
+	time.Sleep(5 * watchDebounce)
+	if len(changes) != 0 {
+		t.Fatalf("unexpected change %+v", <-changes)
+	}
+	write("level: debug\n")
+	select {
+	case c := <-changes:
+		if c.old.Level != "warn" || c.new.Level != "debug" {
+			t.Fatalf("unexpected change %+v", c)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected change callback")
+	}
+
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	write("level: info\n")
+	time.Sleep(3 * watchDebounce)
+	if len(changes) != 0 {
+		t.Fatal("no callback expected after Close")
+	}
+}
+
+func TestWatchSymlink(t *testing.T) {
+	dir := t.TempDir()
+	for name, content := range map[string]string{"v1.yaml": "level: info\n", "v2.yaml": "level: debug\n"} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	path := filepath.Join(dir, "app.yaml")
+	if err := os.Symlink("v1.yaml", path); err != nil {
+		t.Skip(err)
+	}
+	changes := make(chan change, 10)
+	var cfg watchConfig
+	w, err := Watch(path, &cfg, func(old, new watchConfig) { changes <- change{old, new} })
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Close()
+
+	// 以ConfigMap的方式原子替换符号链接
+	tmp := filepath.Join(dir, "app.yaml.tmp")
+	if err := os.Symlink("v2.yaml", tmp); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case c := <-changes:
+		if c.new.Level != "debug" {
+			t.Fatalf("unexpected change %+v", c)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected change callback after symlink swap")
+	}
+}
+
+func TestWatchErrors(t *testing.T) {
+	var cfg watchConfig
+	if _, err := Watch(filepath.Join(t.TempDir(), "missing.yaml"), &cfg, nil); err == nil {
+		t.Fatal("expected error for missing file")
+	}
+	if _, err := Watch(writeFile(t, "app.yaml", "level: trace\n"), &cfg, nil); err == nil {
+		t.Fatal("expected validation error")
+	}
+}
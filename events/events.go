@@ -0,0 +1,193 @@
+// Package events 以Go结构体定义业务事件，Emit时校验必填字段、补充上下文并通过专用logger输出，
+// 使零散的业务日志成为有约定可校验的事件流：
+//
+//	type PaymentFailed struct {
+//		OrderID string `event:"order_id,required"`
+//		Amount  int64  `event:"amount,required"`
+//		Reason  string `event:"reason"`
+//	}
+//
+//	func (PaymentFailed) EventName() string { return "payment_failed" }
+//
+//	err := events.Emit(ctx, PaymentFailed{OrderID: "A1", Amount: 1999})
+package events
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+	"unicode"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+
+	"github.com/allanchen1214/goeasy/log"
+)
+
+// LoggerName 输出事件的logger名称，未配置时使用default logger
+const LoggerName = "events"
+
+// Event 业务事件，EventName返回稳定的事件名，作为下游检索和统计的依据
+type Event interface {
+	EventName() string
+}
+
+// Validator 事件可实现此接口，补充字段之间的校验
+type Validator interface {
+	Validate() error
+}
+
+// Leveler 事件可实现此接口指定输出级别，默认info
+type Leveler interface {
+	Level() zapcore.Level
+}
+
+// ErrInvalidEvent 事件未通过校验时返回的错误均包装此错误
+var ErrInvalidEvent = errors.New("invalid event")
+
+// field 事件结构体中的字段
+type field struct {
+	index     int
+	key       string
+	required  bool
+	omitempty bool
+}
+
+// schemas 事件类型到字段定义的缓存
+var schemas sync.Map
+
+// Emit 校验事件并输出：必填字段不能为零值，实现Validator时还需通过Validate；
+// 输出时附加事件名和ctx上的字段（见log.WithFields）。校验失败时不输出事件，返回包装ErrInvalidEvent的错误
+func Emit(ctx context.Context, ev Event) error {
+	fields, err := encode(ev)
+	if err != nil {
+		log.GetLogger(LoggerName).Warn("invalid event", zap.String("event", eventName(ev)), zap.Error(err))
+		return err
+	}
+
+	level := zapcore.InfoLevel
+	if l, ok := ev.(Leveler); ok {
+		level = l.Level()
+	}
+	logger := log.FromContext(ctx, LoggerName)
+	if ce := logger.Check(level, ev.EventName()); ce != nil {
+		ce.Write(append([]zap.Field{zap.String("event", ev.EventName())}, fields...)...)
+	}
+	return nil
+}
+
+// Validate 只校验事件而不输出，可用于测试事件定义
+func Validate(ev Event) error {
+	_, err := encode(ev)
+	return err
+}
+
+// eventName 返回事件名，nil事件返回其类型名
+func eventName(ev Event) string {
+	if v := reflect.ValueOf(ev); !v.IsValid() || v.Kind() == reflect.Pointer && v.IsNil() {
+		return fmt.Sprintf("%T", ev)
+	}
+	return ev.EventName()
+}
+
+// encode 校验事件并转换为日志字段
+func encode(ev Event) ([]zap.Field, error) {
+	if ev == nil {
+		return nil, fmt.Errorf("%w: nil event", ErrInvalidEvent)
+	}
+	v := reflect.ValueOf(ev)
+	for v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return nil, fmt.Errorf("%w: nil %s", ErrInvalidEvent, v.Type())
+		}
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("%w: %s: event must be a struct, got %s", ErrInvalidEvent, ev.EventName(), v.Kind())
+	}
+	if ev.EventName() == "" {
+		return nil, fmt.Errorf("%w: %s: empty event name", ErrInvalidEvent, v.Type())
+	}
+
+	var (
+		fields  []zap.Field
+		missing []string
+	)
+	for _, f := range schemaOf(v.Type()) {
+		fv := v.Field(f.index)
+		if fv.IsZero() {
+			if f.required {
+				missing = append(missing, f.key)
+				continue
+			}
+			if f.omitempty {
+				continue
+			}
+		}
+		fields = append(fields, zap.Any(f.key, fv.Interface()))
+	}
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("%w: %s: missing required fields %s", ErrInvalidEvent, ev.EventName(), strings.Join(missing, ", "))
+	}
+	if val, ok := ev.(Validator); ok {
+		if err := val.Validate(); err != nil {
+			return nil, fmt.Errorf("%w: %s: %w", ErrInvalidEvent, ev.EventName(), err)
+		}
+	}
+	return fields, nil
+}
+
+// schemaOf 解析结构体字段的event标签：`event:"name,required,omitempty"`，"-"表示忽略，
+// 未设置名称时使用字段名的snake_case形式
+func schemaOf(t reflect.Type) []field {
+	if s, ok := schemas.Load(t); ok {
+		return s.([]field)
+	}
+
+	var fields []field
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		if !sf.IsExported() {
+			continue
+		}
+		tag := sf.Tag.Get("event")
+		if tag == "-" {
+			continue
+		}
+		name, opts, _ := strings.Cut(tag, ",")
+		if name == "" {
+			name = snakeCase(sf.Name)
+		}
+		f := field{index: i, key: name}
+		for _, opt := range strings.Split(opts, ",") {
+			switch opt {
+			case "required":
+				f.required = true
+			case "omitempty":
+				f.omitempty = true
+			}
+		}
+		fields = append(fields, f)
+	}
+	s, _ := schemas.LoadOrStore(t, fields)
+	return s.([]field)
+}
+
+// snakeCase OrderID -> order_id
+func snakeCase(s string) string {
+	runes := []rune(s)
+	var b strings.Builder
+	for i, r := range runes {
+		if unicode.IsUpper(r) {
+			if i > 0 && (unicode.IsLower(runes[i-1]) || i+1 < len(runes) && unicode.IsLower(runes[i+1])) {
+				b.WriteByte('_')
+			}
+			r = unicode.ToLower(r)
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
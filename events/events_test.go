@@ -0,0 +1,106 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+
+	"github.com/allanchen1214/goeasy/log"
+)
+
+type PaymentFailed struct {
+	OrderID  string        `event:"order_id,required"`
+	Amount   int64         `event:"amount,required"`
+	Reason   string        `event:",omitempty"`
+	Retries  int           `event:"retries"`
+	Duration time.Duration `event:"-"`
+	internal string
+}
+
+func (PaymentFailed) EventName() string { return "payment_failed" }
+
+func (PaymentFailed) Level() zapcore.Level { return zapcore.WarnLevel }
+
+type RefundIssued struct {
+	OrderID string
+	Amount  int64 `event:",required"`
+	Refund  int64 `event:",required"`
+}
+
+func (RefundIssued) EventName() string { return "refund_issued" }
+
+func (e RefundIssued) Validate() error {
+	if e.Refund > e.Amount {
+		return errors.New("refund exceeds amount")
+	}
+	return nil
+}
+
+func TestEmit(t *testing.T) {
+	logs := log.InstallObserver(LoggerName)
+	t.Cleanup(log.Close)
+
+	ctx := log.WithFields(context.Background(), zap.String("request_id", "r1"))
+	if err := Emit(ctx, PaymentFailed{OrderID: "A1", Amount: 1999, internal: "x"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := Emit(ctx, &RefundIssued{OrderID: "A1", Amount: 1999, Refund: 500}); err != nil {
+		t.Fatal(err)
+	}
+
+	all := logs.All()
+	if len(all) != 2 {
+		t.Fatalf("expected 2 events, got %+v", all)
+	}
+	payment := all[0]
+	if payment.Message != "payment_failed" || payment.Level != zapcore.WarnLevel {
+		t.Fatalf("unexpected payment event: %+v", payment)
+	}
+	got := payment.ContextMap()
+	want := map[string]any{"request_id": "r1", "event": "payment_failed", "order_id": "A1", "amount": int64(1999), "retries": int64(0)}
+	if len(got) != len(want) {
+		t.Fatalf("expected fields %v, got %v", want, got)
+	}
+	for k, v := range want {
+		if got[k] != v {
+			t.Fatalf("field %s: expected %v, got %v", k, v, got[k])
+		}
+	}
+	if refund := all[1].ContextMap(); refund["order_id"] != "A1" || refund["refund"] != int64(500) {
+		t.Fatalf("unexpected refund fields: %v", refund)
+	}
+}
+
+func TestEmitInvalid(t *testing.T) {
+	logs := log.InstallObserver(LoggerName)
+	t.Cleanup(log.Close)
+
+	err := Emit(context.Background(), PaymentFailed{Reason: "card declined"})
+	if !errors.Is(err, ErrInvalidEvent) || !strings.Contains(err.Error(), "missing required fields order_id, amount") {
+		t.Fatalf("expected missing fields error, got %v", err)
+	}
+	err = Emit(context.Background(), RefundIssued{Amount: 1, Refund: 2})
+	if !errors.Is(err, ErrInvalidEvent) || !strings.Contains(err.Error(), "refund exceeds amount") {
+		t.Fatalf("expected validation error, got %v", err)
+	}
+	if err := Emit(context.Background(), (*RefundIssued)(nil)); !errors.Is(err, ErrInvalidEvent) {
+		t.Fatalf("expected nil pointer error, got %v", err)
+	}
+
+	if n := logs.FilterMessage("invalid event").Len(); n != 3 || logs.Len() != 3 {
+		t.Fatalf("invalid events should only be reported, got %+v", logs.All())
+	}
+}
+
+func TestSnakeCase(t *testing.T) {
+	for in, want := range map[string]string{"OrderID": "order_id", "HTTPStatus": "http_status", "Amount": "amount", "userName": "user_name"} {
+		if got := snakeCase(in); got != want {
+			t.Errorf("snakeCase(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
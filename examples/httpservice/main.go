@@ -0,0 +1,93 @@
+// httpservice 演示带访问日志的HTTP服务：
+//
+//   - access logger记录访问日志，/healthz降为debug，慢请求以warn记录
+//   - 未匹配的路由记录到fallback logger并提示最接近的路由
+//   - /debug/log/ 挂载日志管理接口，可配合 goeasy logctl 使用
+//   - 退出时在超时时间内落盘所有日志
+//
+// 用法:
+//
+//	go run ./examples/httpservice -config examples/httpservice/config.yaml
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/allanchen1214/goeasy/log"
+)
+
+func main() {
+	config := flag.String("config", "config.yaml", "log config file")
+	addr := flag.String("addr", ":8080", "listen address")
+	flag.Parse()
+
+	if err := log.Init(log.WithConfigFile(*config)); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	err := serve(ctx, *addr)
+	closeCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := errors.Join(err, log.CloseContext(closeCtx)); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+// serve 启动HTTP服务，ctx取消后优雅退出
+func serve(ctx context.Context, addr string) error {
+	srv := &http.Server{Addr: addr, Handler: newHandler()}
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		_ = srv.Shutdown(shutdownCtx)
+	}()
+
+	log.GetDefaultLogger().Info("http service started", zap.String("addr", addr))
+	if err := srv.ListenAndServe(); !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	log.GetDefaultLogger().Info("http service stopped")
+	return nil
+}
+
+// newHandler 组装业务路由与日志中间件
+func newHandler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /healthz", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	})
+	mux.HandleFunc("GET /hello/{name}", func(w http.ResponseWriter, r *http.Request) {
+		name := r.PathValue("name")
+		log.FromContext(r.Context(), "default").Debug("greeting", zap.String("name", name))
+		fmt.Fprintf(w, "hello, %s\n", name)
+	})
+	mux.HandleFunc("GET /panic", func(w http.ResponseWriter, r *http.Request) {
+		panic("boom")
+	})
+	mux.Handle("/debug/log/", log.AdminHandler())
+	mux.Handle("/", log.NotFoundHandler("fallback", "/healthz", "/hello/{name}", "/panic"))
+
+	var h http.Handler = mux
+	h = log.HTTPRecovery("default", true)(h)
+	h = log.HTTPMiddleware("access",
+		log.WithMethodLevel("/healthz", "debug"),
+		log.WithSlowThreshold(time.Second),
+	)(h)
+	return h
+}
@@ -0,0 +1,91 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/allanchen1214/goeasy/log"
+)
+
+func initLogs(t *testing.T) string {
+	t.Helper()
+
+	dir := t.TempDir()
+	data, err := os.ReadFile("config.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	config := strings.ReplaceAll(string(data), "./logs", dir)
+	configPath := filepath.Join(dir, "config.yaml")
+	if err := os.WriteFile(configPath, []byte(config), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := log.Init(log.WithConfigFile(configPath)); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(log.Close)
+	return dir
+}
+
+func readLog(t *testing.T, path string) string {
+	t.Helper()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+func TestHTTPService(t *testing.T) {
+	dir := initLogs(t)
+	srv := httptest.NewServer(newHandler())
+	defer srv.Close()
+
+	for path, want := range map[string]int{
+		"/healthz":         http.StatusNoContent,
+		"/hello/gopher":    http.StatusOK,
+		"/helo/gopher":     http.StatusNotFound,
+		"/panic":           http.StatusInternalServerError,
+		"/debug/log/stats": http.StatusOK,
+	} {
+		resp, err := http.Get(srv.URL + path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != want {
+			t.Fatalf("GET %s: status %d, want %d", path, resp.StatusCode, want)
+		}
+	}
+	log.Close()
+
+	access := readLog(t, filepath.Join(dir, "access.log"))
+	for _, want := range []string{
+		`"path":"/hello/gopher","query":"","status":200`,
+		`"path":"/helo/gopher","query":"","status":404`,
+		`"path":"/panic","query":"","status":500`,
+	} {
+		if !strings.Contains(access, want) {
+			t.Fatalf("access log missing %s:\n%s", want, access)
+		}
+	}
+	if strings.Contains(access, `"path":"/healthz"`) {
+		t.Fatalf("health checks should be logged at debug:\n%s", access)
+	}
+
+	fallback := readLog(t, filepath.Join(dir, "fallback.log"))
+	if !strings.Contains(fallback, `"nearest_route":"/hello/{name}"`) {
+		t.Fatalf("fallback log missing nearest route:\n%s", fallback)
+	}
+
+	app := readLog(t, filepath.Join(dir, "app.log"))
+	if !strings.Contains(app, fmt.Sprintf("%q", "http panic recovered")) {
+		t.Fatalf("app log missing recovered panic:\n%s", app)
+	}
+}
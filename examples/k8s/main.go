@@ -0,0 +1,72 @@
+// k8s 演示在Kubernetes中运行时的日志用法：
+//
+//   - json日志同时输出到stdout，由节点上的采集器收集；文件写入emptyDir仅作为本地排查的副本
+//   - k8s_metadata为每条日志附加Pod名称、命名空间、节点和容器名，汇总后可区分副本
+//   - 配置可由环境变量覆盖，如 GOEASY_LOG_DEFAULT_LEVEL=warn，无需重新构建镜像
+//   - 通过downward API挂载的Pod注解调整级别：kubectl annotate pod <pod> goeasy.io/log-level=debug
+//   - 收到SIGTERM后在terminationGracePeriodSeconds内落盘日志
+//
+// 部署示例见 deployment.yaml。
+//
+// 用法:
+//
+//	go run ./examples/k8s -config examples/k8s/config.yaml -annotations /etc/podinfo/annotations
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/allanchen1214/goeasy/log"
+)
+
+func main() {
+	config := flag.String("config", "config.yaml", "log config file")
+	annotations := flag.String("annotations", "/etc/podinfo/annotations", "pod annotations mounted by the downward API")
+	admin := flag.String("admin", ":6060", "admin listen address")
+	flag.Parse()
+
+	if err := log.Init(log.WithConfigFile(*config)); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGTERM, os.Interrupt)
+	defer stop()
+
+	go watchLevels(ctx, *annotations, 10*time.Second)
+
+	srv := &http.Server{Addr: *admin, Handler: log.AdminHandler()}
+	go func() {
+		if err := srv.ListenAndServe(); !errors.Is(err, http.ErrServerClosed) {
+			log.GetDefaultLogger().Error("admin server stopped", zap.Error(err))
+		}
+	}()
+
+	log.GetDefaultLogger().Info("service started")
+	<-ctx.Done()
+	_ = srv.Close()
+
+	closeCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := log.CloseContext(closeCtx); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+// watchLevels 按Pod注解调整日志级别，直到ctx取消
+func watchLevels(ctx context.Context, path string, interval time.Duration) {
+	log.WatchLevelAnnotationsFile(ctx, path, interval, func(err error) {
+		log.GetDefaultLogger().Warn("failed to apply level annotations", zap.Error(err))
+	})
+}
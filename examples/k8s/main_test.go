@@ -0,0 +1,86 @@
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+
+	"github.com/allanchen1214/goeasy/log"
+)
+
+func initLogs(t *testing.T) string {
+	t.Helper()
+
+	dir := t.TempDir()
+	data, err := os.ReadFile("config.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	config := strings.ReplaceAll(string(data), "/var/log/app", dir)
+	configPath := filepath.Join(dir, "config.yaml")
+	if err := os.WriteFile(configPath, []byte(config), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := log.Init(log.WithConfigFile(configPath)); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(log.Close)
+	return dir
+}
+
+func enabled(level zapcore.Level) bool {
+	return log.GetDefaultLogger().Core().Enabled(level)
+}
+
+func TestEnvOverride(t *testing.T) {
+	t.Setenv("GOEASY_LOG_DEFAULT_LEVEL", "warn")
+	initLogs(t)
+
+	if enabled(zapcore.InfoLevel) || !enabled(zapcore.WarnLevel) {
+		t.Fatal("GOEASY_LOG_DEFAULT_LEVEL should override the configured level")
+	}
+}
+
+func TestWatchLevels(t *testing.T) {
+	dir := initLogs(t)
+	annotations := filepath.Join(dir, "annotations")
+	if err := os.WriteFile(annotations, []byte(`goeasy.io/log-level="debug"`+"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		watchLevels(ctx, annotations, 10*time.Millisecond)
+		close(done)
+	}()
+	defer func() {
+		cancel()
+		<-done
+	}()
+
+	waitFor(t, func() bool { return enabled(zapcore.DebugLevel) })
+
+	// 注解删除后恢复配置的级别
+	if err := os.WriteFile(annotations, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	waitFor(t, func() bool { return !enabled(zapcore.DebugLevel) && enabled(zapcore.InfoLevel) })
+}
+
+func waitFor(t *testing.T, cond func() bool) {
+	t.Helper()
+
+	deadline := time.Now().Add(time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatal("condition not met in time")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
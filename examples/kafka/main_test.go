@@ -0,0 +1,80 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/allanchen1214/goeasy/log"
+	"github.com/allanchen1214/goeasy/logtest/integration"
+)
+
+func initLogs(t *testing.T, kafkaURL string, batchSize int) {
+	t.Helper()
+
+	dir := t.TempDir()
+	data, err := os.ReadFile("config.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	config := strings.NewReplacer(
+		"./logs", dir,
+		"http://127.0.0.1:8082", kafkaURL,
+		"batch_size: 100", fmt.Sprintf("batch_size: %d", batchSize),
+	).Replace(string(data))
+	configPath := filepath.Join(dir, "config.yaml")
+	if err := os.WriteFile(configPath, []byte(config), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := log.Init(log.WithConfigFile(configPath)); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(log.Close)
+}
+
+func TestKafkaSink(t *testing.T) {
+	kafka := integration.NewKafka(t)
+	initLogs(t, kafka.URL(), 2)
+
+	logger := log.GetLogger("order")
+	for i := 1; i <= 3; i++ {
+		logger.Info("order created", zap.Int("order_id", i))
+	}
+	// 第3条仍在缓存中
+	kafka.WaitFor(t, 2, time.Second)
+	if got := kafka.Requests(); got != 1 {
+		t.Fatalf("expected one batch before close, got %d requests", got)
+	}
+
+	if err := log.CloseContext(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	records := kafka.WaitFor(t, 3, time.Second)
+	kafka.AssertLabel(t, "topic", "order-logs")
+	kafka.AssertLabel(t, "key", "order")
+	if !strings.Contains(records[2].Line, `"order_id":3`) {
+		t.Fatalf("last record should be flushed on close, got %q", records[2].Line)
+	}
+}
+
+func TestKafkaSinkRetriesFailedBatch(t *testing.T) {
+	kafka := integration.NewKafka(t)
+	initLogs(t, kafka.URL(), 100)
+
+	kafka.FailNext(1)
+	log.GetLogger("order").Info("order created", zap.Int("order_id", 1))
+	if err := log.GetLogger("order").Sync(); err == nil {
+		t.Fatal("expected sync error while the proxy fails")
+	}
+	if err := log.CloseContext(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	kafka.WaitFor(t, 1, time.Second)
+	kafka.AssertContains(t, `"order_id":1`)
+}
@@ -0,0 +1,94 @@
+// worker 演示定时任务的日志用法：
+//
+//   - 每次执行开启job作用域，run_id只出现在本次执行的日志中
+//   - 逐条处理记录时使用批量记录器，执行结束后一次性写入，避免大量小写入
+//   - 配置中的静默时段让etl logger在每天02:00起的1小时内只输出error
+//   - 失败的执行记录ctx的超时信息
+//
+// 用法:
+//
+//	go run ./examples/worker -config examples/worker/config.yaml -every 1m
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"strconv"
+	"syscall"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/allanchen1214/goeasy/log"
+)
+
+func main() {
+	config := flag.String("config", "config.yaml", "log config file")
+	every := flag.Duration("every", time.Minute, "job interval")
+	timeout := flag.Duration("timeout", 30*time.Second, "timeout of a single run")
+	flag.Parse()
+
+	if err := log.Init(log.WithConfigFile(*config)); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	defer log.Close()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	ticker := time.NewTicker(*every)
+	defer ticker.Stop()
+	for run := 1; ; run++ {
+		runCtx, cancel := context.WithTimeout(ctx, *timeout)
+		_ = runJob(runCtx, strconv.Itoa(run), fetchRecords())
+		cancel()
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// fetchRecords 模拟待处理的记录
+func fetchRecords() []string {
+	return []string{"order-1", "order-2", "order-3"}
+}
+
+// runJob 处理一批记录：逐条记录写入批量记录器，结束时提交并输出汇总
+func runJob(ctx context.Context, runID string, records []string) error {
+	ctx = log.ContextWithStart(ctx, time.Now())
+	ctx, closeScope := log.OpenScope(ctx, "job")
+	defer closeScope()
+	ctx = log.WithFields(ctx, zap.String("run_id", runID))
+
+	batch := log.Batch("etl")
+	logger := batch.With(log.ContextFields(ctx)...)
+
+	var processed int
+	var err error
+	for _, record := range records {
+		if err = ctx.Err(); err != nil {
+			break
+		}
+		logger.Info("record processed", zap.String("record", record))
+		processed++
+	}
+	if commitErr := batch.Commit(); commitErr != nil {
+		err = errors.Join(err, commitErr)
+	}
+
+	summary := log.FromContext(ctx, "default")
+	if err != nil {
+		summary.Error("job failed", zap.Int("processed", processed), log.CtxErr(ctx, err))
+		return err
+	}
+	summary.Info("job finished", zap.Int("processed", processed))
+	return nil
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/allanchen1214/goeasy/log"
+)
+
+// testConfig 与config.yaml的logger一致，但不包含静默时段，避免测试结果依赖运行时刻
+const testConfig = `
+panic_file: %[1]s/panic.log
+zaplog:
+  - name: default
+    level: info
+    file_name: %[1]s/worker.log
+    encoder: json
+  - name: etl
+    level: info
+    file_name: %[1]s/etl.log
+    encoder: json
+`
+
+func initLogs(t *testing.T) string {
+	t.Helper()
+
+	dir := t.TempDir()
+	configPath := filepath.Join(dir, "config.yaml")
+	if err := os.WriteFile(configPath, []byte(fmt.Sprintf(testConfig, dir)), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := log.Init(log.WithConfigFile(configPath)); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(log.Close)
+	return dir
+}
+
+func readLog(t *testing.T, path string) string {
+	t.Helper()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+func TestRunJob(t *testing.T) {
+	dir := initLogs(t)
+
+	if err := runJob(context.Background(), "1", []string{"a", "b"}); err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := runJob(ctx, "2", []string{"c"}); err == nil {
+		t.Fatal("canceled run should fail")
+	}
+	log.Close()
+
+	etl := readLog(t, filepath.Join(dir, "etl.log"))
+	if n := strings.Count(etl, `"msg":"record processed"`); n != 2 {
+		t.Fatalf("expected 2 batched records, got %d:\n%s", n, etl)
+	}
+	if !strings.Contains(etl, `"run_id":"1","record":"a"`) {
+		t.Fatalf("batched records should carry the run id:\n%s", etl)
+	}
+
+	summary := readLog(t, filepath.Join(dir, "worker.log"))
+	for _, want := range []string{
+		`"msg":"job finished","run_id":"1","processed":2`,
+		`"msg":"job failed","run_id":"2","processed":0`,
+		`"error_kind":"canceled"`,
+	} {
+		if !strings.Contains(summary, want) {
+			t.Fatalf("worker log missing %s:\n%s", want, summary)
+		}
+	}
+}
@@ -0,0 +1,106 @@
+package log
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+)
+
+// AdminHandler 返回日志管理HTTP接口，挂载到服务的调试端口上使用：
+//
+//	GET  /debug/log/stats                       查看各logger级别与统计
+//	GET  /debug/log/loggers                     查看各logger级别、输出目标与切割策略
+//	PUT  /debug/log/level?name=xx&level=debug   动态调整日志级别
+//	POST /debug/log/rotate?name=xx              立即切分日志文件，name为空时切分全部
+//	GET  /debug/log/usage                       查看各logger日志量及月度推算值
+//	POST /debug/log/debug?name=xx&duration=10m  临时开启debug级别，到期自动恢复
+//	GET  /debug/log/inventory                   查看日志配置清单
+//	GET  /debug/log/recent?name=xx&level=warn   查看日志文件末尾的日志，供ViewerHandler使用
+//	GET  /debug/log/tail?name=xx&level=info     以SSE实时推送新日志，见TailHandler
+//	GET  /debug/log/health                      查看各输出端状态，有输出端失败时返回503
+//	GET  /debug/log/dump?name=xx&limit=100      导出内存环形缓冲中的最近日志，需开启ring_buffer
+//	GET  /debug/log/search?name=xx&since=1h&level=warn&field.trace_id=xx  在日志文件及备份中查询，见Query
+//
+// 所有接口都在/debug/log/前缀下，挂载一次即可：mux.Handle("/debug/log/", log.AdminHandler())
+func AdminHandler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /debug/log/stats", handleStats)
+	mux.HandleFunc("GET /debug/log/loggers", handleList)
+	mux.HandleFunc("PUT /debug/log/level", handleSetLevel)
+	mux.HandleFunc("POST /debug/log/rotate", handleRotate)
+	mux.HandleFunc("GET /debug/log/usage", handleUsage)
+	mux.HandleFunc("POST /debug/log/debug", handleDebugWindow)
+	mux.HandleFunc("GET /debug/log/inventory", handleInventory)
+	mux.HandleFunc("GET /debug/log/recent", handleRecent)
+	mux.HandleFunc("GET /debug/log/tail", handleTail)
+	mux.HandleFunc("GET /debug/log/health", handleHealth)
+	mux.HandleFunc("GET /debug/log/dump", handleDump)
+	mux.HandleFunc("GET /debug/log/search", handleSearch)
+	return mux
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, map[string]string{"error": err.Error()})
+}
+
+func handleStats(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, Stats())
+}
+
+func handleSetLevel(w http.ResponseWriter, r *http.Request) {
+	name, level := r.URL.Query().Get("name"), r.URL.Query().Get("level")
+	if err := SetLevel(name, level); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]string{"name": name, "level": level})
+}
+
+func handleRotate(w http.ResponseWriter, r *http.Request) {
+	if err := Rotate(r.URL.Query().Get("name")); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func handleUsage(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, UsageReport())
+}
+
+func handleList(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, List())
+}
+
+func handleHealth(w http.ResponseWriter, r *http.Request) {
+	status := Health()
+	code := http.StatusOK
+	if !status.Healthy {
+		code = http.StatusServiceUnavailable
+	}
+	writeJSON(w, code, status)
+}
+
+func handleInventory(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, Inventory())
+}
+
+func handleDebugWindow(w http.ResponseWriter, r *http.Request) {
+	name := r.URL.Query().Get("name")
+	d, err := time.ParseDuration(r.URL.Query().Get("duration"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	if err := EnableDebugFor(name, d); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]string{"name": name, "until": time.Now().Add(d).Format(time.RFC3339)})
+}
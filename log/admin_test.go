@@ -0,0 +1,57 @@
+package log
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestAdminHandler(t *testing.T) {
+	initTestLoggers(t, "access")
+	srv := httptest.NewServer(AdminHandler())
+	defer srv.Close()
+
+	req, _ := http.NewRequest(http.MethodPut, srv.URL+"/debug/log/level?name=access&level=debug", nil)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("set level: got status %d", resp.StatusCode)
+	}
+	GetLogger("access").Debug("debug entry")
+
+	req, _ = http.NewRequest(http.MethodPut, srv.URL+"/debug/log/level?name=access&level=verbose", nil)
+	resp, err = http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Fatalf("invalid level: got status %d", resp.StatusCode)
+	}
+
+	resp, err = http.Get(srv.URL + "/debug/log/stats")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	var stats []LoggerStats
+	if err := json.NewDecoder(resp.Body).Decode(&stats); err != nil {
+		t.Fatal(err)
+	}
+	if len(stats) != 2 || stats[0].Name != "access" || stats[0].Level != "debug" || stats[0].Entries["debug"] != 1 {
+		t.Fatalf("unexpected stats: %+v", stats)
+	}
+
+	resp, err = http.Post(srv.URL+"/debug/log/rotate?name=access", "", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusNoContent {
+		t.Fatalf("rotate: got status %d", resp.StatusCode)
+	}
+}
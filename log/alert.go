@@ -0,0 +1,230 @@
+package log
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// AlertAnnotationConfig error日志关联Alertmanager告警的配置
+type AlertAnnotationConfig struct {
+	URL         string            `yaml:"url" mapstructure:"url"`                   // Alertmanager地址，为空时不启用
+	Matchers    map[string]string `yaml:"matchers" mapstructure:"matchers"`         // 关联告警需匹配的标签，如alertname、service
+	MinInterval time.Duration     `yaml:"min_interval" mapstructure:"min_interval"` // 同一指纹两次标注的最小间隔，默认1m
+}
+
+// alertmanagerAlert Alertmanager v2 API中的告警
+type alertmanagerAlert struct {
+	Labels       map[string]string `json:"labels"`
+	Annotations  map[string]string `json:"annotations,omitempty"`
+	StartsAt     time.Time         `json:"startsAt,omitempty"`
+	EndsAt       time.Time         `json:"endsAt,omitempty"`
+	GeneratorURL string            `json:"generatorURL,omitempty"`
+}
+
+// AnnotateAlerts 为Alertmanager中标签匹配matchers的活跃告警追加annotations，返回标注的告警数。
+// 只更新已存在的告警，不会产生新告警
+func AnnotateAlerts(ctx context.Context, client *http.Client, alertmanagerURL string, matchers, annotations map[string]string) (int, error) {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	base := strings.TrimSuffix(alertmanagerURL, "/") + "/api/v2/alerts"
+
+	query := url.Values{"active": {"true"}}
+	for k, v := range matchers {
+		query.Add("filter", k+"="+strconv.Quote(v))
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, base+"?"+query.Encode(), nil)
+	if err != nil {
+		return 0, err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return 0, fmt.Errorf("list alerts: unexpected status %s", resp.Status)
+	}
+	var alerts []alertmanagerAlert
+	if err := json.NewDecoder(resp.Body).Decode(&alerts); err != nil {
+		return 0, fmt.Errorf("list alerts: %w", err)
+	}
+	if len(alerts) == 0 {
+		return 0, nil
+	}
+
+	// 以相同标签重新推送，Alertmanager会合并到已有告警并更新annotations
+	for i := range alerts {
+		if alerts[i].Annotations == nil {
+			alerts[i].Annotations = make(map[string]string)
+		}
+		for k, v := range annotations {
+			alerts[i].Annotations[k] = v
+		}
+	}
+	body, err := json.Marshal(alerts)
+	if err != nil {
+		return 0, err
+	}
+	req, err = http.NewRequestWithContext(ctx, http.MethodPost, base, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err = client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return 0, fmt.Errorf("post alerts: unexpected status %s", resp.Status)
+	}
+	return len(alerts), nil
+}
+
+// alertQueueSize 待标注告警的队列长度，队列满时丢弃新的标注
+const alertQueueSize = 64
+
+// alertAnnotator 按指纹限流，由单个后台goroutine异步标注告警
+type alertAnnotator struct {
+	cfg    AlertAnnotationConfig
+	logger string
+	client *http.Client
+
+	queue    chan map[string]string
+	done     chan struct{}
+	stopOnce sync.Once
+
+	mu     sync.Mutex
+	last   map[string]time.Time
+	pruned time.Time
+}
+
+func newAlertAnnotator(cfg AlertAnnotationConfig, logger string) *alertAnnotator {
+	if cfg.MinInterval <= 0 {
+		cfg.MinInterval = time.Minute
+	}
+	return &alertAnnotator{
+		cfg:    cfg,
+		logger: logger,
+		client: &http.Client{Timeout: 5 * time.Second},
+		queue:  make(chan map[string]string, alertQueueSize),
+		done:   make(chan struct{}),
+		last:   make(map[string]time.Time),
+	}
+}
+
+func (a *alertAnnotator) start() {
+	go func() {
+		for {
+			select {
+			case <-a.done:
+				return
+			case annotations := <-a.queue:
+				ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+				if _, err := AnnotateAlerts(ctx, a.client, a.cfg.URL, a.cfg.Matchers, annotations); err != nil {
+					diagnostics.Warn("failed to annotate alert", zap.String("logger", a.logger), zap.Error(err))
+				}
+				cancel()
+			}
+		}
+	}()
+}
+
+func (a *alertAnnotator) stop() {
+	a.stopOnce.Do(func() { close(a.done) })
+}
+
+// allow 同一指纹在MinInterval内只标注一次，每隔MinInterval清理已过期的指纹
+func (a *alertAnnotator) allow(fp string, now time.Time) bool {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if now.Sub(a.pruned) >= a.cfg.MinInterval {
+		for k, t := range a.last {
+			if now.Sub(t) >= a.cfg.MinInterval {
+				delete(a.last, k)
+			}
+		}
+		a.pruned = now
+	}
+	if last, ok := a.last[fp]; ok && now.Sub(last) < a.cfg.MinInterval {
+		return false
+	}
+	a.last[fp] = now
+	return true
+}
+
+// annotate 在日志调用的goroutine中执行，以调用处计算指纹，与error.fingerprint字段一致
+func (a *alertAnnotator) annotate(ent zapcore.Entry, fields []zapcore.Field) {
+	var err error
+	for _, f := range fields {
+		if e, ok := f.Interface.(error); ok && f.Type == zapcore.ErrorType && e != nil {
+			err = e
+			break
+		}
+	}
+	sample := ent.Message
+	if err != nil {
+		sample += ": " + err.Error()
+	} else {
+		err = errors.New(ent.Message)
+	}
+	fp := fingerprint(err, callSite())
+	if !a.allow(fp, ent.Time) {
+		return
+	}
+
+	annotations := map[string]string{
+		"log_fingerprint": fp,
+		"log_sample":      sample,
+		"log_logger":      a.logger,
+		"log_time":        ent.Time.Format(time.RFC3339),
+	}
+	// Alertmanager响应慢时丢弃，不阻塞日志调用
+	select {
+	case a.queue <- annotations:
+	default:
+	}
+}
+
+// alertCore error及以上级别的日志写入后标注关联的告警
+type alertCore struct {
+	zapcore.Core
+	annotator *alertAnnotator
+}
+
+func newAlertCore(core zapcore.Core, annotator *alertAnnotator) zapcore.Core {
+	return &alertCore{Core: core, annotator: annotator}
+}
+
+func (c *alertCore) With(fields []zapcore.Field) zapcore.Core {
+	return &alertCore{Core: c.Core.With(fields), annotator: c.annotator}
+}
+
+func (c *alertCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+func (c *alertCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	err := c.Core.Write(ent, fields)
+	if ent.Level >= zapcore.ErrorLevel {
+		c.annotator.annotate(ent, fields)
+	}
+	return err
+}
@@ -0,0 +1,101 @@
+package log
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+func TestAlertAnnotation(t *testing.T) {
+	posted := make(chan []alertmanagerAlert, 4)
+	var filters []string
+	am := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			filters = r.URL.Query()["filter"]
+			_ = json.NewEncoder(w).Encode([]alertmanagerAlert{{
+				Labels:      map[string]string{"alertname": "HighErrorRate", "service": "api", "severity": "page"},
+				Annotations: map[string]string{"summary": "error rate above 5%"},
+				StartsAt:    time.Now().Add(-time.Minute),
+			}})
+		case http.MethodPost:
+			var alerts []alertmanagerAlert
+			_ = json.NewDecoder(r.Body).Decode(&alerts)
+			posted <- alerts
+		}
+	}))
+	defer am.Close()
+
+	entry, err := newLogger(LogConfig{
+		Name:     "alert",
+		FileName: t.TempDir() + "/alert.log",
+		AlertAnnotation: AlertAnnotationConfig{
+			URL:      am.URL,
+			Matchers: map[string]string{"alertname": "HighErrorRate"},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer entry.writer.Close()
+
+	entry.logger.Warn("not annotated")
+	entry.logger.Error("query failed", zap.Error(errors.New("connection refused")))
+	// 相同指纹在min_interval内不重复标注
+	entry.logger.Error("query failed", zap.Error(errors.New("connection refused")))
+
+	var alerts []alertmanagerAlert
+	select {
+	case alerts = <-posted:
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for annotation")
+	}
+	if len(filters) != 1 || filters[0] != `alertname="HighErrorRate"` {
+		t.Fatalf("unexpected filters %v", filters)
+	}
+	a := alerts[0]
+	if a.Labels["severity"] != "page" || a.Annotations["summary"] == "" {
+		t.Fatalf("existing labels and annotations should be kept: %+v", a)
+	}
+	if a.Annotations["log_sample"] != "query failed: connection refused" || a.Annotations["log_fingerprint"] == "" {
+		t.Fatalf("unexpected annotations %v", a.Annotations)
+	}
+
+	select {
+	case <-posted:
+		t.Fatal("duplicate fingerprint should be rate limited")
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+func TestAlertAnnotatorBounded(t *testing.T) {
+	a := newAlertAnnotator(AlertAnnotationConfig{URL: "http://127.0.0.1:0", MinInterval: time.Minute}, "alert")
+	now := time.Now()
+	for i := 0; i < 100; i++ {
+		if !a.allow(fmt.Sprintf("fp-%d", i), now) {
+			t.Fatal("new fingerprint should be allowed")
+		}
+	}
+	// 超过MinInterval后过期的指纹被清理
+	if !a.allow("fp-0", now.Add(2*time.Minute)) {
+		t.Fatal("expired fingerprint should be allowed again")
+	}
+	if len(a.last) != 1 {
+		t.Fatalf("expired fingerprints should be pruned, %d left", len(a.last))
+	}
+
+	// worker未启动时队列满后丢弃，不阻塞调用方
+	for i := 0; i < alertQueueSize*2; i++ {
+		a.annotate(zapcore.Entry{Level: zapcore.ErrorLevel, Message: fmt.Sprintf("failure %c", 'a'+i%26), Time: now.Add(time.Duration(i) * time.Hour)}, nil)
+	}
+	if len(a.queue) != alertQueueSize {
+		t.Fatalf("expected a full queue of %d, got %d", alertQueueSize, len(a.queue))
+	}
+}
@@ -0,0 +1,430 @@
+package log
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/md5"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// ArchiveConfig 切割后的压缩文件归档到对象存储（S3兼容接口，如AWS S3、阿里云OSS、MinIO）的配置，
+// 上传成功并校验ETag后删除本地文件
+type ArchiveConfig struct {
+	Endpoint    string        `yaml:"endpoint" mapstructure:"endpoint"`         // 服务地址，如 https://s3.amazonaws.com、http://minio:9000
+	Bucket      string        `yaml:"bucket" mapstructure:"bucket"`             // 存储桶，为空时不启用
+	Region      string        `yaml:"region" mapstructure:"region"`             // 区域，默认us-east-1
+	AccessKey   string        `yaml:"access_key" mapstructure:"access_key"`     // 为空时读取环境变量AWS_ACCESS_KEY_ID
+	SecretKey   string        `yaml:"secret_key" mapstructure:"secret_key"`     // 为空时读取环境变量AWS_SECRET_ACCESS_KEY
+	KeyTemplate string        `yaml:"key_template" mapstructure:"key_template"` // 对象名模板，支持{logger}、{hostname}、{date}、{file}，默认 {logger}/{date}/{file}
+	PathStyle   bool          `yaml:"path_style" mapstructure:"path_style"`     // 使用路径风格的地址，MinIO等需开启
+	Interval    time.Duration `yaml:"interval" mapstructure:"interval"`         // 扫描间隔，默认5m
+	KeepLocal   bool          `yaml:"keep_local" mapstructure:"keep_local"`     // 上传后保留本地文件，交由max_backups和max_age清理（含加密后的备份），已上传的文件不再重复上传
+}
+
+const (
+	defaultArchiveInterval = 5 * time.Minute
+	defaultArchiveTemplate = "{logger}/{date}/{file}"
+	defaultArchiveRegion   = "us-east-1"
+)
+
+func validateArchive(lc LogConfig) []error {
+	a := lc.Archive
+	if a.Bucket == "" {
+		return nil
+	}
+	var errs []error
+	if u, err := url.Parse(a.Endpoint); err != nil || u.Scheme == "" || u.Host == "" {
+		errs = append(errs, fmt.Errorf("logger %s: archive: invalid endpoint %q", lc.Name, a.Endpoint))
+	}
+	if !lc.Compress {
+		errs = append(errs, fmt.Errorf("logger %s: archive requires compress: true", lc.Name))
+	}
+	if a.Interval < 0 {
+		errs = append(errs, fmt.Errorf("logger %s: archive: interval must not be negative", lc.Name))
+	}
+	return errs
+}
+
+// archiver 定期上传某个logger的压缩备份文件
+type archiver struct {
+	cfg      ArchiveConfig
+	logger   string
+	files    []string // 日志文件，按级别分文件时为各分片
+	suffix   string   // 待上传文件的后缀，.gz或开启加密时的.gz.enc
+	client   *http.Client
+	hostname string
+
+	mu       sync.Mutex          // 串行执行run
+	uploaded map[string]struct{} // keep_local时已上传的本地文件
+	stopOnce sync.Once
+	done     chan struct{}
+}
+
+func newArchiver(cfg ArchiveConfig, logger string, files ...string) *archiver {
+	if cfg.Region == "" {
+		cfg.Region = defaultArchiveRegion
+	}
+	if cfg.KeyTemplate == "" {
+		cfg.KeyTemplate = defaultArchiveTemplate
+	}
+	if cfg.Interval == 0 {
+		cfg.Interval = defaultArchiveInterval
+	}
+	if cfg.AccessKey == "" {
+		cfg.AccessKey = os.Getenv("AWS_ACCESS_KEY_ID")
+	}
+	if cfg.SecretKey == "" {
+		cfg.SecretKey = os.Getenv("AWS_SECRET_ACCESS_KEY")
+	}
+	hostname, _ := os.Hostname()
+	return &archiver{
+		cfg:      cfg,
+		logger:   logger,
+		files:    files,
+		suffix:   ".gz",
+		client:   &http.Client{Timeout: time.Minute},
+		hostname: hostname,
+		uploaded: make(map[string]struct{}),
+		done:     make(chan struct{}),
+	}
+}
+
+// start 启动定期扫描
+func (a *archiver) start() {
+	go func() {
+		ticker := time.NewTicker(a.cfg.Interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-a.done:
+				return
+			case <-ticker.C:
+				a.run(context.Background())
+			}
+		}
+	}()
+}
+
+func (a *archiver) stop() {
+	a.stopOnce.Do(func() { close(a.done) })
+}
+
+// pending 返回待上传的压缩备份，开启加密时为加密后的文件
+func (a *archiver) pending() []string {
+	var files []string
+	for _, name := range a.files {
+		files = append(files, rotatedFiles(name, a.suffix)...)
+	}
+	return files
+}
+
+// rotatedFiles 返回filename切割出的、以suffix结尾的备份文件，按文件名即切割时间排序。
+// lumberjack压缩完成后才删除原文件，原文件仍存在时说明压缩尚未完成，跳过对应的.gz文件
+func rotatedFiles(filename, suffix string) []string {
+	dir := filepath.Dir(filename)
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil
+	}
+	var files []string
+	for _, e := range entries {
+		name := e.Name()
+		if e.IsDir() || !strings.HasSuffix(name, suffix) {
+			continue
+		}
+		if _, ok := backupTime(filename, name); !ok {
+			continue
+		}
+		path := filepath.Join(dir, name)
+		if strings.HasSuffix(name, ".gz") {
+			if _, err := os.Stat(strings.TrimSuffix(path, ".gz")); err == nil {
+				continue
+			}
+		}
+		files = append(files, path)
+	}
+	sort.Strings(files)
+	return files
+}
+
+// backupTimeFormat lumberjack备份文件名中切割时间的格式
+const backupTimeFormat = "2006-01-02T15-04-05.000"
+
+// backupTime 解析备份文件名 name-<切割时间>.ext[.gz][.enc] 中的切割时间，name不是filename的备份时返回false。
+// 只匹配前缀会把app.log与app-access.log的备份混在一起
+func backupTime(filename, name string) (time.Time, bool) {
+	ext := filepath.Ext(filename)
+	rest, ok := strings.CutPrefix(name, strings.TrimSuffix(filepath.Base(filename), ext)+"-")
+	if !ok || len(rest) < len(backupTimeFormat) {
+		return time.Time{}, false
+	}
+	switch strings.TrimSuffix(rest[len(backupTimeFormat):], encryptedSuffix) {
+	case ext, ext + ".gz":
+	default:
+		return time.Time{}, false
+	}
+	t, err := time.Parse(backupTimeFormat, rest[:len(backupTimeFormat)])
+	return t, err == nil
+}
+
+// run 上传所有待上传的文件，返回第一个错误
+func (a *archiver) run(ctx context.Context) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	var first error
+	pending := a.pending()
+	if a.cfg.KeepLocal {
+		pending = a.notUploaded(ctx, pending)
+	}
+	for _, path := range pending {
+		if err := a.upload(ctx, path); err != nil {
+			diagnostics.Warn("failed to archive log file", zap.String("logger", a.logger), zap.String("file", path), zap.Error(err))
+			if first == nil {
+				first = err
+			}
+			continue
+		}
+		if a.cfg.KeepLocal {
+			a.uploaded[path] = struct{}{}
+		} else if err := os.Remove(path); err != nil && first == nil {
+			first = err
+		}
+	}
+	return first
+}
+
+// notUploaded 返回尚未上传的文件，已被max_backups、max_age清理的文件从记录中移除。
+// 首次遇到的文件先以HEAD请求确认对象是否已存在，避免重启后重复上传
+func (a *archiver) notUploaded(ctx context.Context, files []string) []string {
+	seen := make(map[string]struct{}, len(files))
+	var pending []string
+	for _, path := range files {
+		seen[path] = struct{}{}
+		if _, ok := a.uploaded[path]; ok {
+			continue
+		}
+		if a.exists(ctx, path) {
+			a.uploaded[path] = struct{}{}
+			continue
+		}
+		pending = append(pending, path)
+	}
+	for path := range a.uploaded {
+		if _, ok := seen[path]; !ok {
+			delete(a.uploaded, path)
+		}
+	}
+	return pending
+}
+
+// exists 对象存在且ETag与本地文件内容的MD5一致
+func (a *archiver) exists(ctx context.Context, path string) bool {
+	info, err := os.Stat(path)
+	if err != nil {
+		return false
+	}
+	body, err := os.ReadFile(path)
+	if err != nil {
+		return false
+	}
+	u, err := a.objectURL(a.objectKey(path, info.ModTime()))
+	if err != nil {
+		return false
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, u.String(), nil)
+	if err != nil {
+		return false
+	}
+	empty := sha256.Sum256(nil)
+	signV4(req, hex.EncodeToString(empty[:]), a.cfg.AccessKey, a.cfg.SecretKey, a.cfg.Region, time.Now())
+	resp, err := a.client.Do(req)
+	if err != nil {
+		return false
+	}
+	resp.Body.Close()
+	md := md5.Sum(body)
+	return resp.StatusCode == http.StatusOK && strings.EqualFold(strings.Trim(resp.Header.Get("ETag"), `"`), hex.EncodeToString(md[:]))
+}
+
+// objectKey 按模板生成对象名
+func (a *archiver) objectKey(path string, modTime time.Time) string {
+	return strings.NewReplacer(
+		"{logger}", a.logger,
+		"{hostname}", a.hostname,
+		"{date}", modTime.UTC().Format("2006-01-02"),
+		"{file}", filepath.Base(path),
+	).Replace(a.cfg.KeyTemplate)
+}
+
+// objectURL 返回对象的地址，默认使用虚拟主机风格
+func (a *archiver) objectURL(key string) (*url.URL, error) {
+	u, err := url.Parse(strings.TrimSuffix(a.cfg.Endpoint, "/"))
+	if err != nil {
+		return nil, err
+	}
+	if a.cfg.PathStyle {
+		u.Path += "/" + a.cfg.Bucket + "/" + key
+	} else {
+		u.Host = a.cfg.Bucket + "." + u.Host
+		u.Path += "/" + key
+	}
+	u.RawPath = awsURIEscape(u.Path, false)
+	return u, nil
+}
+
+// upload 上传单个文件，响应的ETag与内容的MD5一致时才认为上传成功
+func (a *archiver) upload(ctx context.Context, path string) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	body, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	u, err := a.objectURL(a.objectKey(path, info.ModTime()))
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, u.String(), bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	contentType := "application/gzip"
+	if strings.HasSuffix(path, encryptedSuffix) {
+		contentType = "application/octet-stream"
+	}
+	req.Header.Set("Content-Type", contentType)
+	sum := sha256.Sum256(body)
+	signV4(req, hex.EncodeToString(sum[:]), a.cfg.AccessKey, a.cfg.SecretKey, a.cfg.Region, time.Now())
+
+	resp, err := a.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("put %s: unexpected status %s: %s", u.Path, resp.Status, bytes.TrimSpace(msg))
+	}
+	if etag := strings.Trim(resp.Header.Get("ETag"), `"`); etag != "" {
+		md := md5.Sum(body)
+		if !strings.EqualFold(etag, hex.EncodeToString(md[:])) {
+			return fmt.Errorf("put %s: etag %s does not match content", u.Path, etag)
+		}
+	}
+	return nil
+}
+
+// ArchiveNow 立即上传指定logger待归档的文件，未配置archive时返回错误
+func ArchiveNow(ctx context.Context, name string) error {
+	metux.RLock()
+	entry, ok := loggers[name]
+	metux.RUnlock()
+	if !ok {
+		return fmt.Errorf("logger %s not found", name)
+	}
+	if entry.archiver == nil {
+		return fmt.Errorf("logger %s: archive not configured", name)
+	}
+	return entry.archiver.run(ctx)
+}
+
+// signV4 按AWS Signature Version 4为请求签名，签名包含host及请求上已设置的所有header
+func signV4(req *http.Request, payloadHash, accessKey, secretKey, region string, now time.Time) {
+	now = now.UTC()
+	amzDate := now.Format("20060102T150405Z")
+	date := now.Format("20060102")
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+
+	headers := map[string]string{"host": req.URL.Host}
+	for k, v := range req.Header {
+		headers[strings.ToLower(k)] = strings.Join(v, ",")
+	}
+	names := make([]string, 0, len(headers))
+	for k := range headers {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+	var canonicalHeaders strings.Builder
+	for _, k := range names {
+		canonicalHeaders.WriteString(k + ":" + strings.TrimSpace(headers[k]) + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		awsURIEscape(req.URL.Path, false),
+		canonicalQuery(req.URL.Query()),
+		canonicalHeaders.String(),
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+	scope := date + "/" + region + "/s3/aws4_request"
+	hash := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + hex.EncodeToString(hash[:])
+
+	key := []byte("AWS4" + secretKey)
+	for _, part := range []string{date, region, "s3", "aws4_request"} {
+		key = hmacSHA256(key, part)
+	}
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		accessKey, scope, signedHeaders, signature))
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(data))
+	return h.Sum(nil)
+}
+
+func canonicalQuery(query url.Values) string {
+	keys := make([]string, 0, len(query))
+	for k := range query {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	var parts []string
+	for _, k := range keys {
+		values := append([]string(nil), query[k]...)
+		sort.Strings(values)
+		for _, v := range values {
+			parts = append(parts, awsURIEscape(k, true)+"="+awsURIEscape(v, true))
+		}
+	}
+	return strings.Join(parts, "&")
+}
+
+// awsURIEscape 按SigV4的规则编码，只保留非保留字符，encodeSlash为false时保留路径中的/
+func awsURIEscape(s string, encodeSlash bool) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case c >= 'A' && c <= 'Z', c >= 'a' && c <= 'z', c >= '0' && c <= '9', c == '-', c == '_', c == '.', c == '~':
+			b.WriteByte(c)
+		case c == '/' && !encodeSlash:
+			b.WriteByte(c)
+		default:
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,185 @@
+package log
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"os"
+	"sync"
+
+	"go.uber.org/zap/zapcore"
+)
+
+const (
+	auditPrevKey = `,"prev_hash":"`
+	auditHashKey = `,"hash":"`
+	// auditTailSize 启动时为恢复哈希链读取的文件末尾字节数
+	auditTailSize = 64 << 10
+)
+
+// auditWriteSyncer 审计模式的输出端，为每条日志追加prev_hash和hash字段，
+// hash为该行内容（含prev_hash）的SHA-256，配置了密钥时为HMAC-SHA256，
+// 任何一行被修改、删除或插入都会使后续的校验失败
+type auditWriteSyncer struct {
+	zapcore.WriteSyncer
+
+	mu   sync.Mutex
+	key  []byte
+	prev string
+}
+
+func newAuditWriteSyncer(ws zapcore.WriteSyncer, key []byte, prev string) *auditWriteSyncer {
+	return &auditWriteSyncer{WriteSyncer: ws, key: key, prev: prev}
+}
+
+// Write p为一条或多条（批量写入时）编码后的JSON日志
+func (w *auditWriteSyncer) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	var out bytes.Buffer
+	prev := w.prev
+	for _, line := range bytes.SplitAfter(p, []byte("\n")) {
+		body := bytes.TrimRight(line, "\n")
+		if len(body) == 0 {
+			continue
+		}
+		if body[len(body)-1] != '}' {
+			return 0, errors.New("audit: entry is not a JSON object")
+		}
+		signed := auditSigned(body[:len(body)-1], prev)
+		prev = auditHash(w.key, signed)
+		out.Write(signed)
+		out.WriteString(auditHashKey + prev + "\"}\n")
+	}
+	if _, err := w.WriteSyncer.Write(out.Bytes()); err != nil {
+		return 0, err
+	}
+	// 写入成功后才推进哈希链
+	w.prev = prev
+	return len(p), nil
+}
+
+// auditSigned 返回参与哈希计算的内容：去掉结尾}的原始行加上prev_hash字段
+func auditSigned(body []byte, prev string) []byte {
+	signed := make([]byte, 0, len(body)+len(auditPrevKey)+len(prev)+1)
+	signed = append(signed, body...)
+	signed = append(signed, auditPrevKey...)
+	signed = append(signed, prev...)
+	return append(signed, '"')
+}
+
+func auditHash(key, data []byte) string {
+	var h hash.Hash
+	if len(key) > 0 {
+		h = hmac.New(sha256.New, key)
+	} else {
+		h = sha256.New()
+	}
+	h.Write(data)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// auditKey 返回审计日志的HMAC密钥，未配置时为nil
+func auditKey(cfg LogConfig) ([]byte, error) {
+	switch {
+	case cfg.AuditKey != "":
+		return []byte(cfg.AuditKey), nil
+	case cfg.AuditKeyEnv != "":
+		v := os.Getenv(cfg.AuditKeyEnv)
+		if v == "" {
+			return nil, fmt.Errorf("audit: environment variable %s is empty", cfg.AuditKeyEnv)
+		}
+		return []byte(v), nil
+	}
+	return nil, nil
+}
+
+// lastAuditHash 读取已有日志文件最后一行的hash，使重启后哈希链得以延续
+func lastAuditHash(path string) string {
+	f, err := os.Open(path)
+	if err != nil {
+		return ""
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return ""
+	}
+	offset := max(info.Size()-auditTailSize, 0)
+	tail := make([]byte, info.Size()-offset)
+	if _, err := f.ReadAt(tail, offset); err != nil && err != io.EOF {
+		return ""
+	}
+	lines := bytes.Split(bytes.TrimRight(tail, "\n"), []byte("\n"))
+	_, _, sum, ok := splitAuditLine(lines[len(lines)-1])
+	if !ok {
+		return ""
+	}
+	return sum
+}
+
+// splitAuditLine 拆分审计日志行，返回参与哈希计算的内容、prev_hash和hash
+func splitAuditLine(line []byte) (signed []byte, prev, sum string, ok bool) {
+	i := bytes.LastIndex(line, []byte(auditHashKey))
+	if i < 0 || !bytes.HasSuffix(line, []byte(`"}`)) {
+		return nil, "", "", false
+	}
+	signed = line[:i]
+	sum = string(line[i+len(auditHashKey) : len(line)-2])
+	j := bytes.LastIndex(signed, []byte(auditPrevKey))
+	if j < 0 || !bytes.HasSuffix(signed, []byte(`"`)) {
+		return nil, "", "", false
+	}
+	prev = string(signed[j+len(auditPrevKey) : len(signed)-1])
+	return signed, prev, sum, true
+}
+
+// AuditReport 审计日志文件的校验结果
+type AuditReport struct {
+	Entries   int    // 校验通过的日志条数
+	FirstPrev string // 第一条日志的prev_hash，应等于上一个文件的LastHash，新的哈希链为空
+	LastHash  string // 最后一条日志的hash，可与外部保存的锚点比对以发现末尾被截断
+}
+
+// VerifyAuditFile 校验审计模式日志文件的哈希链，配置了audit_key时需传入相同的密钥。
+// 返回的错误指明第一处不一致的行号，此时AuditReport为该行之前的校验结果
+func VerifyAuditFile(path string, hmacKey ...[]byte) (AuditReport, error) {
+	var key []byte
+	if len(hmacKey) > 0 {
+		key = hmacKey[0]
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return AuditReport{}, err
+	}
+	defer f.Close()
+
+	var report AuditReport
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64<<10), 64<<20)
+	for n := 1; scanner.Scan(); n++ {
+		signed, prev, sum, ok := splitAuditLine(scanner.Bytes())
+		switch {
+		case !ok:
+			return report, fmt.Errorf("line %d: missing audit hash", n)
+		case n == 1:
+			report.FirstPrev = prev
+		case prev != report.LastHash:
+			return report, fmt.Errorf("line %d: prev_hash does not match previous entry, entries were removed or reordered", n)
+		}
+		if !hmac.Equal([]byte(auditHash(key, signed)), []byte(sum)) {
+			return report, fmt.Errorf("line %d: hash mismatch, entry was modified", n)
+		}
+		report.Entries++
+		report.LastHash = sum
+	}
+	return report, scanner.Err()
+}
@@ -0,0 +1,95 @@
+package log
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+func newAuditLogger(t *testing.T, path, key string) *logEntry {
+	t.Helper()
+
+	entry, err := newLogger(LogConfig{Name: "audit", FileName: path, Encoder: "json", Audit: true, AuditKey: key})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return entry
+}
+
+func TestAuditChain(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.log")
+	entry := newAuditLogger(t, path, "secret")
+	entry.logger.Info("login", zap.String("user", "alice"))
+	entry.logger.Warn("role changed", zap.String("user", "bob"), zap.String("role", "admin"))
+	_ = entry.writer.Close()
+
+	// 重启后哈希链延续
+	entry = newAuditLogger(t, path, "secret")
+	entry.logger.Info("logout", zap.String("user", "alice"))
+	_ = entry.writer.Close()
+
+	report, err := VerifyAuditFile(path, []byte("secret"))
+	if err != nil || report.Entries != 3 || report.FirstPrev != "" || len(report.LastHash) != 64 {
+		t.Fatalf("unexpected report %+v: %v", report, err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.SplitAfter(strings.TrimSpace(string(data)), "\n")
+	var decoded map[string]any
+	if err := json.Unmarshal([]byte(lines[1]), &decoded); err != nil || decoded["role"] != "admin" || decoded["hash"] == "" {
+		t.Fatalf("audit lines should stay valid JSON, got %s: %v", lines[1], err)
+	}
+
+	if _, err := VerifyAuditFile(path, []byte("other")); err == nil || !strings.Contains(err.Error(), "line 1: hash mismatch") {
+		t.Fatalf("expected hmac mismatch, got %v", err)
+	}
+
+	for name, tc := range map[string]struct {
+		content string
+		want    string
+	}{
+		"modified": {lines[0] + strings.Replace(lines[1], "admin", "guest", 1) + lines[2], "line 2: hash mismatch"},
+		"removed":  {lines[0] + lines[2], "line 2: prev_hash does not match"},
+		"reorder":  {lines[1] + lines[0] + lines[2], "line 2: prev_hash does not match"},
+		"plain":    {lines[0] + `{"msg":"forged"}` + "\n", "line 2: missing audit hash"},
+	} {
+		tampered := filepath.Join(t.TempDir(), name+".log")
+		if err := os.WriteFile(tampered, []byte(tc.content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := VerifyAuditFile(tampered, []byte("secret")); err == nil || !strings.Contains(err.Error(), tc.want) {
+			t.Fatalf("%s: expected %q, got %v", name, tc.want, err)
+		}
+	}
+}
+
+func TestAuditBatchAndConfig(t *testing.T) {
+	var buf bytes.Buffer
+	ws := newAuditWriteSyncer(zapcore.AddSync(&buf), nil, "")
+	if _, err := ws.Write([]byte("{\"msg\":\"a\"}\n{\"msg\":\"b\"}\n")); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "batch.log")
+	if err := os.WriteFile(path, buf.Bytes(), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if report, err := VerifyAuditFile(path); err != nil || report.Entries != 2 {
+		t.Fatalf("batched lines should be chained individually, got %+v: %v", report, err)
+	}
+	if lastAuditHash(path) != ws.prev {
+		t.Fatal("last hash should be recovered from the file")
+	}
+
+	cfg := Config{Zaplog: []LogConfig{{Name: "default", FileName: "a.log", Encoder: "console", Audit: true}}}
+	if err := validateConfig(&cfg); err == nil || !strings.Contains(err.Error(), "audit requires encoder: json") {
+		t.Fatalf("expected audit encoder error, got %v", err)
+	}
+}
@@ -0,0 +1,87 @@
+package log
+
+import (
+	"bytes"
+	"sync"
+
+	"go.uber.org/zap"
+)
+
+// BatchLogger 批量记录日志，Commit时一次性写入输出端，适用于逐行输出大量记录的ETL任务
+type BatchLogger struct {
+	*zap.Logger
+
+	entry *logEntry
+	buf   *batchBuffer
+}
+
+// batchBuffer 缓存编码后的日志
+type batchBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *batchBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *batchBuffer) Sync() error {
+	return nil
+}
+
+// take 取出缓存内容并清空
+func (b *batchBuffer) take() []byte {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	data := bytes.Clone(b.buf.Bytes())
+	b.buf.Reset()
+	return data
+}
+
+// Batch 返回指定名称logger的批量记录器，如果不存在，则使用default logger。
+// 记录通过Debug/Info/Warn/Error等方法添加，调用Commit后才会写入。
+// 批量记录只写入logger的输出端，不写入团队文件，也不进入环形缓冲和tail
+func Batch(name string) *BatchLogger {
+	metux.RLock()
+	entry, ok := loggers[name]
+	if !ok {
+		entry, ok = loggers["default"]
+	}
+	metux.RUnlock()
+
+	if !ok || entry.newBatchCore == nil || isSharded(entry) {
+		// 无法批量写入时退化为直接写入，按级别分文件时缓存的内容无法区分级别
+		return &BatchLogger{Logger: GetLogger(name)}
+	}
+	buf := &batchBuffer{}
+	return &BatchLogger{
+		Logger: zap.New(entry.newBatchCore(entry.level, buf), entry.options...),
+		entry:  entry,
+		buf:    buf,
+	}
+}
+
+// Len 返回待写入的字节数
+func (b *BatchLogger) Len() int {
+	if b.buf == nil {
+		return 0
+	}
+	b.buf.mu.Lock()
+	defer b.buf.mu.Unlock()
+	return b.buf.buf.Len()
+}
+
+// Commit 将已添加的记录一次性写入输出端，之后可继续添加新的记录
+func (b *BatchLogger) Commit() error {
+	if b.buf == nil {
+		return b.Logger.Sync()
+	}
+	data := b.buf.take()
+	if len(data) == 0 {
+		return nil
+	}
+	_, err := b.entry.ws.Write(data)
+	return err
+}
@@ -0,0 +1,66 @@
+package log
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"go.uber.org/zap"
+)
+
+func TestBatch(t *testing.T) {
+	dir := initTestLoggers(t, "etl")
+	path := filepath.Join(dir, "etl.log")
+
+	b := Batch("etl")
+	for i := 0; i < 100; i++ {
+		b.Info("row", zap.Int("i", i))
+	}
+	b.Debug("filtered by level")
+
+	if data, _ := os.ReadFile(path); len(data) != 0 {
+		t.Fatalf("records should not be written before Commit, got %s", data)
+	}
+	if b.Len() == 0 {
+		t.Fatal("expected pending records")
+	}
+	if err := b.Commit(); err != nil {
+		t.Fatal(err)
+	}
+	if b.Len() != 0 {
+		t.Fatal("buffer should be empty after Commit")
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := strings.Count(string(data), "\n"); n != 100 {
+		t.Fatalf("expected 100 lines, got %d", n)
+	}
+	if got := Stats()[1].Entries["info"]; got != 100 {
+		t.Fatalf("expected 100 info entries in stats, got %d", got)
+	}
+}
+
+func TestBatchSkipsRing(t *testing.T) {
+	dir := t.TempDir()
+	configPath := filepath.Join(dir, "log_config.yaml")
+	config := "zaplog:\n  - name: default\n    file_name: " + filepath.Join(dir, "app.log") + "\n    ring_buffer: 10\n"
+	if err := os.WriteFile(configPath, []byte(config), 0644); err != nil {
+		t.Fatal(err)
+	}
+	Close()
+	if err := Init(WithConfigFile(configPath)); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(Close)
+
+	// 未提交的批量记录不应出现在环形缓冲中
+	b := Batch("default")
+	b.Info("row")
+	if dumps := DumpRing("default"); len(dumps) != 1 || len(dumps[0].Entries) != 0 {
+		t.Fatalf("uncommitted batch records should not reach the ring buffer: %v", dumps)
+	}
+}
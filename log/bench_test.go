@@ -0,0 +1,204 @@
+package log
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// 性能基准，用于发现回归：
+//
+//	go test -run '^$' -bench . -benchmem ./log
+
+var benchFields = []Field{
+	zap.String("path", "/api/orders"),
+	zap.Int("status", 200),
+	zap.Duration("latency", 1200*time.Microsecond),
+	zap.Error(errors.New("connection reset")),
+}
+
+// BenchmarkDisabled 级别未开启时的开销，Check、DebugFunc与无字段的调用应为0次分配
+func BenchmarkDisabled(b *testing.B) {
+	initBenchLoggers(b)
+	logger := New("default")
+	snapshot := map[string]int{"hits": 1}
+
+	b.Run("zap", func(b *testing.B) {
+		z := GetLogger("default")
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			z.Debug("cache miss", zap.String("key", "k"))
+		}
+	})
+	b.Run("wrapper", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			logger.Debug("cache miss")
+		}
+	})
+	b.Run("wrapper_lazy", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			logger.Debug("cache miss", Lazy("snapshot", func() any { return snapshot }))
+		}
+	})
+	b.Run("check_lazy", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			if ce := logger.Check(zapcore.DebugLevel, "cache miss"); ce != nil {
+				ce.Write(Lazy("snapshot", func() any { return snapshot }))
+			}
+		}
+	})
+	b.Run("debug_func", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			logger.DebugFunc("cache miss", func() []Field { return []Field{Any("snapshot", snapshot)} })
+		}
+	})
+	b.Run("with", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			logger.With(zap.String("order", "o1")).Debug("cache miss")
+		}
+	})
+	b.Run("sugar", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			Debugw("default", "cache miss", "key", "k")
+		}
+	})
+}
+
+// BenchmarkEncoders 各编码器的编码开销，输出到io.Discard
+func BenchmarkEncoders(b *testing.B) {
+	for _, encoder := range []string{"json", "console", "logfmt", "pretty", "msgpack"} {
+		b.Run(encoder, func(b *testing.B) {
+			entry := newBenchEntry(b, LogConfig{Encoder: encoder})
+			logger := zap.New(entry.newCore(entry.level, zapcore.AddSync(io.Discard)))
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				logger.Info("http request", benchFields...)
+			}
+		})
+	}
+}
+
+// BenchmarkOutputs 不同写入路径的开销：直接写文件、带缓冲的写入、BatchLogger批量提交、BufferRequest请求级缓冲
+func BenchmarkOutputs(b *testing.B) {
+	b.Run("file", func(b *testing.B) {
+		entry := newBenchEntry(b, LogConfig{Encoder: "json"})
+		b.ReportAllocs()
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			entry.logger.Info("http request", benchFields...)
+		}
+	})
+	b.Run("buffered", func(b *testing.B) {
+		entry := newBenchEntry(b, LogConfig{Encoder: "json"})
+		ws := &zapcore.BufferedWriteSyncer{WS: entry.ws, FlushInterval: time.Second}
+		defer ws.Stop()
+		logger := zap.New(entry.newCore(entry.level, ws), entry.options...)
+		b.ReportAllocs()
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			logger.Info("http request", benchFields...)
+		}
+	})
+	b.Run("batch", func(b *testing.B) {
+		initBenchLoggers(b)
+		batch := Batch("default")
+		b.ReportAllocs()
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			batch.Info("http request", benchFields...)
+			if i%100 == 99 {
+				batch.Commit()
+			}
+		}
+		batch.Commit()
+	})
+	b.Run("request_buffer", func(b *testing.B) {
+		initBenchLoggers(b)
+		b.ReportAllocs()
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			ctx, done := BufferRequest(context.Background(), "default", 0)
+			FromContext(ctx, "default").Info("http request", benchFields...)
+			done(nil)
+		}
+	})
+}
+
+// discardStdout logger创建时绑定os.Stdout，基准测试期间将其替换为空设备，避免输出干扰结果
+func discardStdout(b *testing.B) {
+	b.Helper()
+	null, err := os.OpenFile(os.DevNull, os.O_WRONLY, 0)
+	if err != nil {
+		b.Fatal(err)
+	}
+	stdout := os.Stdout
+	os.Stdout = null
+	b.Cleanup(func() {
+		os.Stdout = stdout
+		null.Close()
+	})
+}
+
+// initBenchLoggers 在临时目录下初始化info级别、json编码的default logger
+func initBenchLoggers(b *testing.B) {
+	b.Helper()
+	discardStdout(b)
+	Close()
+	cfg := Config{Zaplog: []LogConfig{{
+		Name:     "default",
+		Level:    "info",
+		Encoder:  "json",
+		FileName: filepath.Join(b.TempDir(), "default.log"),
+	}}}
+	if err := Init(WithConfig(cfg)); err != nil {
+		b.Fatal(err)
+	}
+	b.Cleanup(Close)
+}
+
+func newBenchEntry(b *testing.B, cfg LogConfig) *logEntry {
+	b.Helper()
+	discardStdout(b)
+	cfg.Name = "bench"
+	cfg.FileName = filepath.Join(b.TempDir(), "bench.log")
+	entry, err := newLogger(cfg)
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.Cleanup(func() { entry.writer.Close() })
+	return entry
+}
+
+// BenchmarkEncodeEntry 只计编码器本身的开销，用于比较msgpack与json的CPU和输出大小
+func BenchmarkEncodeEntry(b *testing.B) {
+	for _, encoder := range []string{"json", "msgpack"} {
+		b.Run(encoder, func(b *testing.B) {
+			enc, err := getEncoder(LogConfig{Encoder: encoder})
+			if err != nil {
+				b.Fatal(err)
+			}
+			ent := zapcore.Entry{Level: zapcore.InfoLevel, Time: time.Now(), Message: "http request"}
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				buf, _ := enc.EncodeEntry(ent, benchFields)
+				b.SetBytes(int64(buf.Len()))
+				buf.Free()
+			}
+		})
+	}
+}
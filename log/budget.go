@@ -0,0 +1,118 @@
+package log
+
+import (
+	"sync/atomic"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/buffer"
+	"go.uber.org/zap/zapcore"
+)
+
+// countingEncoder 统计编码后的日志字节数
+type countingEncoder struct {
+	zapcore.Encoder
+	onEncode func(level zapcore.Level, n int)
+}
+
+func newCountingEncoder(enc zapcore.Encoder, onEncode func(zapcore.Level, int)) zapcore.Encoder {
+	return &countingEncoder{Encoder: enc, onEncode: onEncode}
+}
+
+func (e *countingEncoder) Clone() zapcore.Encoder {
+	return &countingEncoder{Encoder: e.Encoder.Clone(), onEncode: e.onEncode}
+}
+
+func (e *countingEncoder) EncodeEntry(ent zapcore.Entry, fields []zapcore.Field) (*buffer.Buffer, error) {
+	buf, err := e.Encoder.EncodeEntry(ent, fields)
+	if err == nil {
+		e.onEncode(ent.Level, buf.Len())
+	}
+	return buf, err
+}
+
+// budget 每日日志量预算，按本地时间自然日重置
+type budget struct {
+	limit    int64
+	day      atomic.Int64
+	used     atomic.Int64
+	exceeded atomic.Bool
+	reported atomic.Bool
+}
+
+func newBudget(limit int64) *budget {
+	b := &budget{limit: limit}
+	b.day.Store(dayKey(time.Now()))
+	return b
+}
+
+func dayKey(t time.Time) int64 {
+	y, m, d := t.Date()
+	return int64(y*10000 + int(m)*100 + d)
+}
+
+// rollover 跨天时清零用量
+func (b *budget) rollover() {
+	today := dayKey(time.Now())
+	if old := b.day.Load(); old != today && b.day.CompareAndSwap(old, today) {
+		b.used.Store(0)
+		b.exceeded.Store(false)
+		b.reported.Store(false)
+	}
+}
+
+func (b *budget) add(_ zapcore.Level, n int) {
+	b.rollover()
+	if b.used.Add(int64(n)) > b.limit {
+		b.exceeded.Store(true)
+	}
+}
+
+func (b *budget) isExceeded() bool {
+	b.rollover()
+	return b.exceeded.Load()
+}
+
+// budgetCore 预算用尽后只放行error及以上级别，并输出一条预算超限记录
+type budgetCore struct {
+	zapcore.Core
+	budget *budget
+}
+
+func newBudgetCore(core zapcore.Core, b *budget) zapcore.Core {
+	return &budgetCore{Core: core, budget: b}
+}
+
+func (c *budgetCore) Enabled(level zapcore.Level) bool {
+	if level < zapcore.ErrorLevel && c.budget.isExceeded() {
+		return false
+	}
+	return c.Core.Enabled(level)
+}
+
+func (c *budgetCore) With(fields []zapcore.Field) zapcore.Core {
+	return &budgetCore{Core: c.Core.With(fields), budget: c.budget}
+}
+
+func (c *budgetCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+func (c *budgetCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	err := c.Core.Write(ent, fields)
+	if c.budget.exceeded.Load() && c.budget.reported.CompareAndSwap(false, true) {
+		_ = c.Core.Write(zapcore.Entry{
+			LoggerName: ent.LoggerName,
+			Time:       time.Now(),
+			Level:      zapcore.ErrorLevel,
+			Message:    "log budget exceeded, only error and above will be written until tomorrow",
+		}, []zapcore.Field{
+			zap.Int64("budget_bytes", c.budget.limit),
+			zap.Int64("used_bytes", c.budget.used.Load()),
+		})
+	}
+	return err
+}
@@ -0,0 +1,36 @@
+package log
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+func TestBudgetCore(t *testing.T) {
+	b := newBudget(1024)
+	enc := newCountingEncoder(zapcore.NewJSONEncoder(zap.NewProductionEncoderConfig()), b.add)
+	var sink strings.Builder
+	core := zapcore.NewCore(enc, zapcore.AddSync(&sink), zapcore.DebugLevel)
+	budgeted := zap.New(newBudgetCore(core, b))
+	for i := 0; i < 20 && !b.isExceeded(); i++ {
+		budgeted.Info(strings.Repeat("x", 100))
+	}
+	if !b.isExceeded() {
+		t.Fatal("budget should be exceeded")
+	}
+	if budgeted.Core().Enabled(zapcore.WarnLevel) || !budgeted.Core().Enabled(zapcore.ErrorLevel) {
+		t.Fatal("only error and above should be enabled once the budget is exceeded")
+	}
+	if strings.Count(sink.String(), "log budget exceeded") != 1 {
+		t.Fatalf("expected exactly one budget record:\n%s", sink.String())
+	}
+
+	// 模拟跨天
+	b.day.Store(dayKey(time.Now().AddDate(0, 0, -1)))
+	if b.isExceeded() || b.used.Load() != 0 {
+		t.Fatal("budget should reset on a new day")
+	}
+}
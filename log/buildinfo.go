@@ -0,0 +1,70 @@
+package log
+
+import (
+	"runtime/debug"
+	"sync"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// buildInfo 主模块的构建信息，附加为build字段，便于将日志对应到具体的版本
+type buildInfo struct {
+	Version  string
+	Revision string
+	Time     string
+	Modified bool
+}
+
+var currentBuildInfo = sync.OnceValue(func() buildInfo {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return buildInfo{}
+	}
+	return parseBuildInfo(info)
+})
+
+// parseBuildInfo 读取主模块版本及vcs.revision、vcs.time、vcs.modified，后者需以-buildvcs构建（go build默认开启）
+func parseBuildInfo(info *debug.BuildInfo) buildInfo {
+	b := buildInfo{Version: info.Main.Version}
+	if b.Version == "(devel)" {
+		b.Version = ""
+	}
+	for _, s := range info.Settings {
+		switch s.Key {
+		case "vcs.revision":
+			b.Revision = s.Value
+		case "vcs.time":
+			b.Time = s.Value
+		case "vcs.modified":
+			b.Modified = s.Value == "true"
+		}
+	}
+	return b
+}
+
+// MarshalLogObject 只输出非空的项
+func (b buildInfo) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	if b.Version != "" {
+		enc.AddString("version", b.Version)
+	}
+	if b.Revision != "" {
+		enc.AddString("revision", b.Revision)
+	}
+	if b.Time != "" {
+		enc.AddString("time", b.Time)
+	}
+	if b.Modified {
+		enc.AddBool("modified", true)
+	}
+	return nil
+}
+
+// buildInfoFields 返回附加到每条日志的build字段，没有构建信息时返回空
+func buildInfoFields() []zap.Field {
+	b := currentBuildInfo()
+	if b == (buildInfo{}) {
+		return nil
+	}
+	return []zap.Field{zap.Object("build", b)}
+}
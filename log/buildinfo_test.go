@@ -0,0 +1,60 @@
+package log
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"runtime/debug"
+	"testing"
+)
+
+func TestParseBuildInfo(t *testing.T) {
+	info := &debug.BuildInfo{
+		Main: debug.Module{Path: "example.com/app", Version: "(devel)"},
+		Settings: []debug.BuildSetting{
+			{Key: "vcs", Value: "git"},
+			{Key: "vcs.revision", Value: "2f1c0a9"},
+			{Key: "vcs.time", Value: "2026-10-01T08:00:00Z"},
+			{Key: "vcs.modified", Value: "true"},
+		},
+	}
+	want := buildInfo{Revision: "2f1c0a9", Time: "2026-10-01T08:00:00Z", Modified: true}
+	if got := parseBuildInfo(info); got != want {
+		t.Fatalf("parseBuildInfo() = %+v, want %+v", got, want)
+	}
+}
+
+func TestBuildInfoAndGoroutineFields(t *testing.T) {
+	old := currentBuildInfo
+	currentBuildInfo = func() buildInfo { return buildInfo{Version: "v1.2.0", Revision: "2f1c0a9"} }
+	t.Cleanup(func() { currentBuildInfo = old })
+
+	dir := t.TempDir()
+	entry, err := newLogger(LogConfig{
+		Name:        "default",
+		FileName:    filepath.Join(dir, "app.log"),
+		Encoder:     "json",
+		BuildInfo:   true,
+		GoroutineID: true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer entry.writer.Close()
+	entry.logger.Info("hello")
+
+	data, _ := os.ReadFile(filepath.Join(dir, "app.log"))
+	var line struct {
+		Build     map[string]any `json:"build"`
+		Goroutine uint64         `json:"goroutine"`
+	}
+	if err := json.Unmarshal(data, &line); err != nil {
+		t.Fatalf("unexpected log %q: %v", data, err)
+	}
+	if line.Build["version"] != "v1.2.0" || line.Build["revision"] != "2f1c0a9" || len(line.Build) != 2 {
+		t.Fatalf("unexpected build field %v", line.Build)
+	}
+	if line.Goroutine != goid() {
+		t.Fatalf("unexpected goroutine field %d", line.Goroutine)
+	}
+}
@@ -0,0 +1,258 @@
+package log
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// ErrorBurstConfig 错误突增告警的配置，滑动窗口内的错误数达到阈值时调用回调或发送webhook
+type ErrorBurstConfig struct {
+	Threshold   int           `yaml:"threshold" mapstructure:"threshold"`       // 窗口内的错误数阈值，0表示不启用
+	Window      time.Duration `yaml:"window" mapstructure:"window"`             // 滑动窗口，默认1m
+	Level       string        `yaml:"level" mapstructure:"level"`               // 计入的最低级别，默认error
+	Cooldown    time.Duration `yaml:"cooldown" mapstructure:"cooldown"`         // 两次告警的最小间隔，默认10m
+	Webhook     string        `yaml:"webhook" mapstructure:"webhook"`           // 群机器人的webhook地址
+	WebhookType string        `yaml:"webhook_type" mapstructure:"webhook_type"` // slack、dingtalk或feishu，为空时按webhook地址推断
+	Secret      string        `yaml:"secret" mapstructure:"secret"`             // 钉钉、飞书机器人的签名密钥
+}
+
+// ErrorBurst 一次错误突增告警
+type ErrorBurst struct {
+	Logger string        // logger名称
+	Count  int           // 窗口内的错误数
+	Window time.Duration // 滑动窗口
+	Sample string        // 触发告警的日志消息
+	Time   time.Time     // 触发告警的日志时间
+}
+
+// String 告警的文本，用于webhook消息
+func (b ErrorBurst) String() string {
+	return fmt.Sprintf("[goeasy] logger %s: %d errors in %s, latest: %s", b.Logger, b.Count, b.Window, b.Sample)
+}
+
+// burstWebhookTypes 支持的webhook类型
+var burstWebhookTypes = []string{"slack", "dingtalk", "feishu"}
+
+// webhookType 返回webhook类型，未设置时按地址推断
+func (c ErrorBurstConfig) webhookType() string {
+	if c.WebhookType != "" {
+		return strings.ToLower(c.WebhookType)
+	}
+	u, err := url.Parse(c.Webhook)
+	if err != nil {
+		return ""
+	}
+	switch host := u.Hostname(); {
+	case strings.HasSuffix(host, "slack.com"):
+		return "slack"
+	case strings.HasSuffix(host, "dingtalk.com"):
+		return "dingtalk"
+	case strings.HasSuffix(host, "feishu.cn"), strings.HasSuffix(host, "larksuite.com"):
+		return "feishu"
+	}
+	return ""
+}
+
+func validateErrorBurst(lc LogConfig) []error {
+	c := lc.ErrorBurst
+	var errs []error
+	if c.Threshold < 0 || c.Window < 0 || c.Cooldown < 0 {
+		errs = append(errs, fmt.Errorf("logger %s: error_burst: threshold, window and cooldown must not be negative", lc.Name))
+	}
+	if c.Level != "" && !isValidLevel(c.Level) {
+		errs = append(errs, fmt.Errorf("logger %s: error_burst: %w", lc.Name, invalidLevel(c.Level)))
+	}
+	if c.Webhook != "" {
+		if u, err := url.Parse(c.Webhook); err != nil || u.Scheme == "" || u.Host == "" {
+			errs = append(errs, fmt.Errorf("logger %s: error_burst: invalid webhook %q", lc.Name, c.Webhook))
+		} else if typ := c.webhookType(); typ == "" {
+			errs = append(errs, fmt.Errorf("logger %s: error_burst: cannot infer webhook_type from %q, set one of %s", lc.Name, c.Webhook, strings.Join(burstWebhookTypes, ", ")))
+		} else if !slices.Contains(burstWebhookTypes, typ) {
+			errs = append(errs, fmt.Errorf("logger %s: error_burst: unknown webhook_type %q%s", lc.Name, c.WebhookType, didYouMean(typ, burstWebhookTypes)))
+		}
+	}
+	return errs
+}
+
+// burstDetector 统计滑动窗口内的错误数，达到阈值时告警，之后在cooldown内不再告警
+type burstDetector struct {
+	cfg    ErrorBurstConfig
+	logger string
+	level  zapcore.Level
+	notify func(ErrorBurst)
+	client *http.Client
+
+	mu        sync.Mutex
+	times     []time.Time // 窗口内错误的时间，最多保留threshold个
+	lastAlert time.Time
+}
+
+func newBurstDetector(cfg ErrorBurstConfig, logger string, notify func(ErrorBurst)) *burstDetector {
+	if cfg.Window <= 0 {
+		cfg.Window = time.Minute
+	}
+	if cfg.Cooldown <= 0 {
+		cfg.Cooldown = 10 * time.Minute
+	}
+	level := zapcore.ErrorLevel
+	if cfg.Level != "" {
+		level = getLevel(cfg.Level)
+	}
+	d := &burstDetector{
+		cfg:    cfg,
+		logger: logger,
+		level:  level,
+		notify: notify,
+		client: &http.Client{Timeout: 10 * time.Second},
+	}
+	if d.notify == nil && cfg.Webhook != "" {
+		d.notify = d.post
+	}
+	return d
+}
+
+// hook 供hookList调用，告警在独立的goroutine中执行，不阻塞日志写入
+func (d *burstDetector) hook(ent zapcore.Entry) error {
+	if ent.Level < d.level {
+		return nil
+	}
+	if burst, ok := d.observe(ent); ok && d.notify != nil {
+		go d.notify(burst)
+	}
+	return nil
+}
+
+func (d *burstDetector) observe(ent zapcore.Entry) (ErrorBurst, bool) {
+	now := ent.Time
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	cutoff := now.Add(-d.cfg.Window)
+	i := 0
+	for i < len(d.times) && !d.times[i].After(cutoff) {
+		i++
+	}
+	d.times = append(d.times[i:], now)
+	if len(d.times) > d.cfg.Threshold {
+		d.times = d.times[len(d.times)-d.cfg.Threshold:]
+	}
+	if len(d.times) < d.cfg.Threshold || (!d.lastAlert.IsZero() && now.Sub(d.lastAlert) < d.cfg.Cooldown) {
+		return ErrorBurst{}, false
+	}
+	d.lastAlert = now
+	return ErrorBurst{
+		Logger: d.logger,
+		Count:  len(d.times),
+		Window: d.cfg.Window,
+		Sample: ent.Message,
+		Time:   now,
+	}, true
+}
+
+// post 按webhook类型发送群机器人消息
+func (d *burstDetector) post(burst ErrorBurst) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := postBurstWebhook(ctx, d.client, d.cfg, burst); err != nil {
+		diagnostics.Warn("failed to post error burst alert", zap.String("logger", d.logger), zap.Error(err))
+	}
+}
+
+func postBurstWebhook(ctx context.Context, client *http.Client, cfg ErrorBurstConfig, burst ErrorBurst) error {
+	target := cfg.Webhook
+	text := burst.String()
+	var payload map[string]any
+	switch cfg.webhookType() {
+	case "slack":
+		payload = map[string]any{"text": text}
+	case "dingtalk":
+		payload = map[string]any{"msgtype": "text", "text": map[string]string{"content": text}}
+		if cfg.Secret != "" {
+			// 钉钉加签：HmacSHA256(secret, timestamp+"\n"+secret)，作为URL参数
+			ts := strconv.FormatInt(time.Now().UnixMilli(), 10)
+			mac := hmac.New(sha256.New, []byte(cfg.Secret))
+			mac.Write([]byte(ts + "\n" + cfg.Secret))
+			u, err := url.Parse(cfg.Webhook)
+			if err != nil {
+				return err
+			}
+			query := u.Query()
+			query.Set("timestamp", ts)
+			query.Set("sign", base64.StdEncoding.EncodeToString(mac.Sum(nil)))
+			u.RawQuery = query.Encode()
+			target = u.String()
+		}
+	case "feishu":
+		payload = map[string]any{"msg_type": "text", "content": map[string]string{"text": text}}
+		if cfg.Secret != "" {
+			// 飞书签名：以timestamp+"\n"+secret为密钥对空串做HmacSHA256，放在消息体中
+			ts := strconv.FormatInt(time.Now().Unix(), 10)
+			mac := hmac.New(sha256.New, []byte(ts+"\n"+cfg.Secret))
+			payload["timestamp"] = ts
+			payload["sign"] = base64.StdEncoding.EncodeToString(mac.Sum(nil))
+		}
+	default:
+		return fmt.Errorf("unknown webhook_type %q", cfg.WebhookType)
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, target, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("post webhook: unexpected status %s", resp.Status)
+	}
+	// 钉钉、飞书在状态码200时通过errcode、code返回错误，slack返回纯文本ok
+	var result struct {
+		ErrCode int    `json:"errcode"`
+		ErrMsg  string `json:"errmsg"`
+		Code    int    `json:"code"`
+		Msg     string `json:"msg"`
+	}
+	if json.NewDecoder(resp.Body).Decode(&result) == nil {
+		if result.ErrCode != 0 {
+			return fmt.Errorf("post webhook: errcode %d: %s", result.ErrCode, result.ErrMsg)
+		}
+		if result.Code != 0 {
+			return fmt.Errorf("post webhook: code %d: %s", result.Code, result.Msg)
+		}
+	}
+	return nil
+}
+
+// AddErrorBurstHook 为指定logger注册错误突增告警，窗口内达到cfg.Threshold条错误时调用fn，
+// fn为nil时发送到cfg.Webhook。fn在独立的goroutine中调用，cooldown内只调用一次
+func AddErrorBurstHook(name string, cfg ErrorBurstConfig, fn func(ErrorBurst)) error {
+	if cfg.Threshold <= 0 {
+		return fmt.Errorf("logger %s: error_burst: threshold must be positive", name)
+	}
+	if errs := validateErrorBurst(LogConfig{Name: name, ErrorBurst: cfg}); len(errs) > 0 {
+		return errs[0]
+	}
+	return AddHook(name, newBurstDetector(cfg, name, fn).hook)
+}
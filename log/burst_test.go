@@ -0,0 +1,121 @@
+package log
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+func TestBurstDetectorWindow(t *testing.T) {
+	d := newBurstDetector(ErrorBurstConfig{Threshold: 3, Window: time.Minute, Cooldown: 5 * time.Minute}, "default", nil)
+	start := time.Date(2026, 10, 1, 8, 0, 0, 0, time.UTC)
+	fire := func(offset time.Duration) bool {
+		_, ok := d.observe(zapcore.Entry{Level: zapcore.ErrorLevel, Time: start.Add(offset), Message: "db down"})
+		return ok
+	}
+
+	// 跨越窗口的错误不累计
+	if fire(0) || fire(50*time.Second) || fire(70*time.Second) {
+		t.Fatal("errors spread over more than a window should not fire")
+	}
+	if !fire(80 * time.Second) {
+		t.Fatal("expected burst after 3 errors within a minute")
+	}
+	// cooldown内不重复告警
+	if fire(81*time.Second) || fire(82*time.Second) {
+		t.Fatal("expected no alert during cooldown")
+	}
+	if fire(6 * time.Minute) {
+		t.Fatal("a single error after cooldown should not fire")
+	}
+	if len(d.times) > 3 {
+		t.Fatalf("expected at most threshold timestamps, got %d", len(d.times))
+	}
+}
+
+func TestBurstWebhook(t *testing.T) {
+	var got map[string]any
+	var query string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.RawQuery
+		json.NewDecoder(r.Body).Decode(&got)
+		w.Write([]byte(`{"errcode":0,"errmsg":"ok"}`))
+	}))
+	defer srv.Close()
+
+	burst := ErrorBurst{Logger: "default", Count: 50, Window: time.Minute, Sample: "db down"}
+	cfg := ErrorBurstConfig{Webhook: srv.URL + "/robot/send?access_token=t", WebhookType: "dingtalk", Secret: "s"}
+	if err := postBurstWebhook(context.Background(), srv.Client(), cfg, burst); err != nil {
+		t.Fatal(err)
+	}
+	if got["msgtype"] != "text" || !strings.Contains(got["text"].(map[string]any)["content"].(string), "50 errors in 1m0s, latest: db down") {
+		t.Fatalf("unexpected dingtalk payload %v", got)
+	}
+	if !strings.Contains(query, "access_token=t") || !strings.Contains(query, "sign=") || !strings.Contains(query, "timestamp=") {
+		t.Fatalf("unexpected dingtalk query %q", query)
+	}
+
+	cfg = ErrorBurstConfig{Webhook: srv.URL, WebhookType: "feishu", Secret: "s"}
+	if err := postBurstWebhook(context.Background(), srv.Client(), cfg, burst); err != nil {
+		t.Fatal(err)
+	}
+	if got["msg_type"] != "text" || got["sign"] == nil || got["timestamp"] == nil {
+		t.Fatalf("unexpected feishu payload %v", got)
+	}
+}
+
+func TestBurstWebhookError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"code":19021,"msg":"sign match fail"}`))
+	}))
+	defer srv.Close()
+	err := postBurstWebhook(context.Background(), srv.Client(), ErrorBurstConfig{Webhook: srv.URL, WebhookType: "feishu"}, ErrorBurst{})
+	if err == nil || !strings.Contains(err.Error(), "sign match fail") {
+		t.Fatalf("expected feishu error, got %v", err)
+	}
+}
+
+func TestAddErrorBurstHook(t *testing.T) {
+	initTestLoggers(t)
+	bursts := make(chan ErrorBurst, 1)
+	if err := AddErrorBurstHook("default", ErrorBurstConfig{Threshold: 2}, func(b ErrorBurst) { bursts <- b }); err != nil {
+		t.Fatal(err)
+	}
+	GetDefaultLogger().Warn("slow")
+	GetDefaultLogger().Error("failed")
+	GetDefaultLogger().Error("failed again")
+	select {
+	case b := <-bursts:
+		if b.Logger != "default" || b.Count != 2 || b.Sample != "failed again" {
+			t.Fatalf("unexpected burst %+v", b)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected error burst callback")
+	}
+
+	if err := AddErrorBurstHook("default", ErrorBurstConfig{}, nil); err == nil {
+		t.Fatal("expected error for zero threshold")
+	}
+}
+
+func TestValidateErrorBurst(t *testing.T) {
+	for _, c := range []ErrorBurstConfig{
+		{Threshold: -1},
+		{Threshold: 1, Level: "eror"},
+		{Threshold: 1, Webhook: "https://example.com/hook"},
+		{Threshold: 1, Webhook: "https://example.com/hook", WebhookType: "wechat"},
+	} {
+		if errs := validateErrorBurst(LogConfig{Name: "default", ErrorBurst: c}); len(errs) != 1 {
+			t.Errorf("expected one error for %+v, got %v", c, errs)
+		}
+	}
+	if errs := validateErrorBurst(LogConfig{Name: "default", ErrorBurst: ErrorBurstConfig{Threshold: 1, Webhook: "https://hooks.slack.com/services/x"}}); len(errs) != 0 {
+		t.Fatalf("unexpected errors %v", errs)
+	}
+}
@@ -0,0 +1,61 @@
+package log
+
+import (
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"os"
+	"sync/atomic"
+	"syscall"
+	"time"
+)
+
+// DiskFaults 文件输出端的故障注入参数，仅用于测试
+type DiskFaults struct {
+	Latency   time.Duration // 每次写入前的固定延迟
+	Jitter    time.Duration // 在Latency基础上增加[0, Jitter)的随机延迟
+	ErrorRate float64       // 写入返回EIO的概率，取值[0, 1]
+}
+
+// faultWriter 按注入的故障参数延迟写入或返回EIO
+type faultWriter struct {
+	io.Writer
+	path   string
+	faults *atomic.Pointer[DiskFaults]
+}
+
+func (w *faultWriter) Write(p []byte) (int, error) {
+	f := w.faults.Load()
+	if f == nil {
+		return w.Writer.Write(p)
+	}
+	delay := f.Latency
+	if f.Jitter > 0 {
+		delay += rand.N(f.Jitter)
+	}
+	if delay > 0 {
+		time.Sleep(delay)
+	}
+	if f.ErrorRate > 0 && rand.Float64() < f.ErrorRate {
+		return 0, &os.PathError{Op: "write", Path: w.path, Err: syscall.EIO}
+	}
+	return w.Writer.Write(p)
+}
+
+// SimulateDiskFaults 为指定logger的文件输出注入写入延迟和间歇性EIO，返回的函数用于恢复。
+// 仅用于测试应用在日志磁盘异常时的延迟和容错表现，不要在生产环境调用
+func SimulateDiskFaults(name string, faults DiskFaults) (restore func(), err error) {
+	if faults.ErrorRate < 0 || faults.ErrorRate > 1 {
+		return nil, fmt.Errorf("invalid error rate %v", faults.ErrorRate)
+	}
+
+	metux.RLock()
+	entry, ok := loggers[name]
+	metux.RUnlock()
+	if !ok || len(entry.files()) == 0 {
+		return nil, fmt.Errorf("logger %s not found", name)
+	}
+
+	entry.faults.Store(&faults)
+	return func() { entry.faults.CompareAndSwap(&faults, nil) }, nil
+}
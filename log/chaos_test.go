@@ -0,0 +1,49 @@
+package log
+
+import (
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestSimulateDiskFaults(t *testing.T) {
+	initTestLoggers(t, "chaos")
+
+	metux.RLock()
+	entry := loggers["chaos"]
+	metux.RUnlock()
+
+	restore, err := SimulateDiskFaults("chaos", DiskFaults{Latency: 20 * time.Millisecond})
+	if err != nil {
+		t.Fatal(err)
+	}
+	start := time.Now()
+	if _, err := entry.ws.Write([]byte("slow\n")); err != nil {
+		t.Fatal(err)
+	}
+	if elapsed := time.Since(start); elapsed < 20*time.Millisecond {
+		t.Fatalf("expected injected latency, took %s", elapsed)
+	}
+	restore()
+
+	restore, err = SimulateDiskFaults("chaos", DiskFaults{ErrorRate: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 写入失败时改写stderr，失败记录在Health中
+	if _, err := entry.ws.Write([]byte("fail\n")); err != nil {
+		t.Fatalf("expected fallback to stderr, got %v", err)
+	}
+	if out := entry.outputs[0].status(); out.Healthy || !strings.Contains(out.LastError, syscall.EIO.Error()) {
+		t.Fatalf("expected EIO in output health, got %+v", out)
+	}
+	restore()
+
+	if _, err := entry.ws.Write([]byte("ok\n")); err != nil {
+		t.Fatalf("expected faults to be cleared, got %v", err)
+	}
+	if _, err := SimulateDiskFaults("chaos", DiskFaults{ErrorRate: 2}); err == nil {
+		t.Fatal("expected error for invalid error rate")
+	}
+}
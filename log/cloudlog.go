@@ -0,0 +1,247 @@
+package log
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// cloudRequestTimeout 单次上报的超时，Sync与Close同样以此为上限
+var cloudRequestTimeout = 30 * time.Second
+
+// cloudLogContentKey 非json日志行作为单个字段上报时的字段名
+const cloudLogContentKey = "content"
+
+// cloudLog 结构化日志服务中的一条日志，字段按名称排序
+type cloudLog struct {
+	time     time.Time
+	contents [][2]string
+}
+
+// size 估算编码后的大小，用于控制单次请求的大小
+func (l cloudLog) size() int {
+	n := 16
+	for _, c := range l.contents {
+		n += len(c[0]) + len(c[1]) + 8
+	}
+	return n
+}
+
+// parseCloudLog 将json编码的日志行拆分为字段，嵌套对象与数组保留json文本，非json时整行作为content字段
+func parseCloudLog(p []byte, now time.Time) cloudLog {
+	line := trimLineEnding(p)
+	l := cloudLog{time: now}
+	var fields map[string]json.RawMessage
+	if json.Unmarshal(line, &fields) != nil {
+		l.contents = [][2]string{{cloudLogContentKey, string(line)}}
+		return l
+	}
+	l.contents = make([][2]string, 0, len(fields))
+	for k, v := range fields {
+		l.contents = append(l.contents, [2]string{k, jsonText(v)})
+	}
+	sort.Slice(l.contents, func(i, j int) bool { return l.contents[i][0] < l.contents[j][0] })
+	return l
+}
+
+// cloudBatcher 缓冲日志并由后台按flush_interval或攒满一批时调用send上报，
+// 上报失败的日志保留到下次重试，缓冲超过max_buffer条时丢弃最早的日志
+type cloudBatcher struct {
+	service  string // 服务名，用于诊断信息
+	logger   string
+	send     func(ctx context.Context, logs []cloudLog) error
+	maxLogs  int
+	maxBytes int
+	opts     cloudBatchOptions
+
+	mu      sync.Mutex
+	pending []cloudLog
+	dropped uint64
+
+	sendMu    sync.Mutex // 保证批次按顺序发送
+	wake      chan struct{}
+	done      chan struct{}
+	stopped   chan struct{}
+	closeOnce sync.Once
+}
+
+// cloudBatchOptions 各云日志sink共用的flush_interval(默认5s)与max_buffer(默认100000条)
+type cloudBatchOptions struct {
+	flushInterval time.Duration
+	maxBuffer     int
+}
+
+func parseCloudBatchOptions(service string, options map[string]any) (cloudBatchOptions, error) {
+	var (
+		opts cloudBatchOptions
+		err  error
+	)
+	if opts.flushInterval, err = time.ParseDuration(sinkString(options, "flush_interval", "5s")); err != nil || opts.flushInterval <= 0 {
+		return opts, fmt.Errorf("%s: invalid flush_interval %q", service, sinkString(options, "flush_interval", ""))
+	}
+	if opts.maxBuffer, err = strconv.Atoi(sinkString(options, "max_buffer", "100000")); err != nil || opts.maxBuffer <= 0 {
+		return opts, fmt.Errorf("%s: invalid max_buffer %q", service, sinkString(options, "max_buffer", ""))
+	}
+	return opts, nil
+}
+
+func newCloudBatcher(service, logger string, maxLogs, maxBytes int, opts cloudBatchOptions,
+	send func(ctx context.Context, logs []cloudLog) error) *cloudBatcher {
+	b := &cloudBatcher{
+		service:  service,
+		logger:   logger,
+		send:     send,
+		maxLogs:  maxLogs,
+		maxBytes: maxBytes,
+		opts:     opts,
+		wake:     make(chan struct{}, 1),
+		done:     make(chan struct{}),
+		stopped:  make(chan struct{}),
+	}
+	go b.run()
+	return b
+}
+
+func (b *cloudBatcher) Write(p []byte) (int, error) {
+	if len(trimLineEnding(p)) == 0 {
+		return len(p), nil
+	}
+	l := parseCloudLog(p, time.Now())
+
+	b.mu.Lock()
+	b.pending = append(b.pending, l)
+	b.trim()
+	full := len(b.pending) >= b.maxLogs
+	b.mu.Unlock()
+
+	if full {
+		select {
+		case b.wake <- struct{}{}:
+		default:
+		}
+	}
+	return len(p), nil
+}
+
+// trim 丢弃超出max_buffer的最早日志，调用方需持有mu
+func (b *cloudBatcher) trim() {
+	if over := len(b.pending) - b.opts.maxBuffer; over > 0 {
+		b.pending = slices.Delete(b.pending, 0, over)
+		b.dropped += uint64(over)
+	}
+}
+
+func (b *cloudBatcher) run() {
+	defer close(b.stopped)
+	ticker := time.NewTicker(b.opts.flushInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-b.done:
+			return
+		case <-ticker.C:
+		case <-b.wake:
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), cloudRequestTimeout)
+		if err := b.flush(ctx); err != nil {
+			diagnostics.Warn("failed to send logs to "+b.service, zap.String("logger", b.logger), zap.Error(err))
+		}
+		cancel()
+	}
+}
+
+// flush 发送缓冲中的全部日志，失败的批次放回缓冲头部
+func (b *cloudBatcher) flush(ctx context.Context) error {
+	b.sendMu.Lock()
+	defer b.sendMu.Unlock()
+
+	b.mu.Lock()
+	if b.dropped > 0 {
+		diagnostics.Warn(b.service+" buffer full, dropped oldest logs",
+			zap.String("logger", b.logger), zap.Uint64("dropped", b.dropped))
+		b.dropped = 0
+	}
+	b.mu.Unlock()
+
+	for {
+		batch := b.nextBatch()
+		if len(batch) == 0 {
+			return nil
+		}
+		if err := b.send(ctx, batch); err != nil {
+			b.mu.Lock()
+			b.pending = append(batch, b.pending...)
+			b.trim()
+			b.mu.Unlock()
+			return err
+		}
+	}
+}
+
+// nextBatch 从缓冲头部取出不超过maxLogs条、maxBytes字节的一批日志
+func (b *cloudBatcher) nextBatch() []cloudLog {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	size, n := 0, 0
+	for n < len(b.pending) && n < b.maxLogs {
+		// 单条超过上限时单独发送，由服务端决定是否接受
+		if size += b.pending[n].size(); size > b.maxBytes && n > 0 {
+			break
+		}
+		n++
+	}
+	batch := slices.Clone(b.pending[:n])
+	b.pending = slices.Delete(b.pending, 0, n)
+	return batch
+}
+
+// Sync 立即发送缓冲中的日志
+func (b *cloudBatcher) Sync() error {
+	ctx, cancel := context.WithTimeout(context.Background(), cloudRequestTimeout)
+	defer cancel()
+	return b.flush(ctx)
+}
+
+// Close 停止后台发送并发送剩余日志
+func (b *cloudBatcher) Close() error {
+	var err error
+	b.closeOnce.Do(func() {
+		close(b.done)
+		<-b.stopped
+		err = b.Sync()
+	})
+	return err
+}
+
+// cloudEndpoint 解析服务地址，未指定协议时使用https
+func cloudEndpoint(endpoint string) (*url.URL, error) {
+	if !strings.Contains(endpoint, "://") {
+		endpoint = "https://" + endpoint
+	}
+	u, err := url.Parse(strings.TrimSuffix(endpoint, "/"))
+	if err != nil || u.Host == "" {
+		return nil, fmt.Errorf("invalid endpoint %q", endpoint)
+	}
+	return u, nil
+}
+
+// envOr 返回value，为空时读取环境变量
+func envOr(value string, keys ...string) string {
+	for _, key := range keys {
+		if value != "" {
+			break
+		}
+		value = os.Getenv(key)
+	}
+	return value
+}
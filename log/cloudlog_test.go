@@ -0,0 +1,103 @@
+package log
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+func TestParseCloudLog(t *testing.T) {
+	now := time.Now()
+	l := parseCloudLog([]byte(`{"msg":"hello","user":{"id":1},"level":"info"}`+"\n"), now)
+	want := [][2]string{{"level", "info"}, {"msg", "hello"}, {"user", `{"id":1}`}}
+	if len(l.contents) != len(want) {
+		t.Fatalf("unexpected contents %v", l.contents)
+	}
+	for i := range want {
+		if l.contents[i] != want[i] {
+			t.Fatalf("unexpected contents %v", l.contents)
+		}
+	}
+	l = parseCloudLog([]byte("plain text\n"), now)
+	if len(l.contents) != 1 || l.contents[0] != [2]string{cloudLogContentKey, "plain text"} {
+		t.Fatalf("non-json lines should be sent as content, got %v", l.contents)
+	}
+}
+
+func TestCloudBatcher(t *testing.T) {
+	var (
+		mu      sync.Mutex
+		batches [][]cloudLog
+		fail    = true
+	)
+	send := func(_ context.Context, logs []cloudLog) error {
+		mu.Lock()
+		defer mu.Unlock()
+		if fail {
+			fail = false
+			return errors.New("unavailable")
+		}
+		batches = append(batches, logs)
+		return nil
+	}
+	b := newCloudBatcher("test", "test", 2, 1<<20, cloudBatchOptions{flushInterval: time.Hour, maxBuffer: 4}, send)
+
+	for _, msg := range []string{"1", "2", "3", "4", "5"} {
+		b.Write([]byte(msg + "\n"))
+	}
+	if err := b.Sync(); err == nil {
+		t.Fatal("expected error")
+	}
+	// 失败的批次放回缓冲，超出max_buffer时丢弃最早的日志
+	if err := b.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if len(batches) != 2 || len(batches[0]) != 2 || batches[0][0].contents[0][1] != "2" || batches[1][1].contents[0][1] != "5" {
+		t.Fatalf("unexpected batches %v", batches)
+	}
+}
+
+// protoFields 解析一层protobuf消息，返回各字段的原始值，用于校验编码结果
+func protoFields(t *testing.T, b []byte) map[protowire.Number][][]byte {
+	t.Helper()
+	fields := make(map[protowire.Number][][]byte)
+	for len(b) > 0 {
+		num, typ, n := protowire.ConsumeTag(b)
+		if n < 0 {
+			t.Fatalf("invalid tag: %v", protowire.ParseError(n))
+		}
+		b = b[n:]
+		var value []byte
+		switch typ {
+		case protowire.VarintType:
+			v, m := protowire.ConsumeVarint(b)
+			value, n = protowire.AppendVarint(nil, v), m
+		case protowire.Fixed32Type:
+			_, n = protowire.ConsumeFixed32(b)
+			value = b[:n]
+		case protowire.BytesType:
+			value, n = protowire.ConsumeBytes(b)
+		default:
+			t.Fatalf("unexpected wire type %d", typ)
+		}
+		if n < 0 {
+			t.Fatalf("invalid field %d: %v", num, protowire.ParseError(n))
+		}
+		fields[num] = append(fields[num], value)
+		b = b[n:]
+	}
+	return fields
+}
+
+func protoVarint(t *testing.T, b []byte) uint64 {
+	t.Helper()
+	v, n := protowire.ConsumeVarint(b)
+	if n < 0 {
+		t.Fatal("invalid varint")
+	}
+	return v
+}
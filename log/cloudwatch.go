@@ -0,0 +1,337 @@
+package log
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs/types"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// PutLogEvents的限制：单批最多10000条、1MB（每条按消息字节数加26计算），单条最大256KB，单批时间跨度不超过24小时
+const (
+	cwMaxBatchEvents = 10000
+	cwMaxBatchBytes  = 1048576
+	cwEventOverhead  = 26
+	cwMaxEventBytes  = 256*1024 - cwEventOverhead
+	cwMaxBatchSpan   = 24 * time.Hour
+)
+
+// cloudWatchAPI cloudwatchlogs.Client中使用的方法
+type cloudWatchAPI interface {
+	PutLogEvents(ctx context.Context, in *cloudwatchlogs.PutLogEventsInput, optFns ...func(*cloudwatchlogs.Options)) (*cloudwatchlogs.PutLogEventsOutput, error)
+	CreateLogGroup(ctx context.Context, in *cloudwatchlogs.CreateLogGroupInput, optFns ...func(*cloudwatchlogs.Options)) (*cloudwatchlogs.CreateLogGroupOutput, error)
+	CreateLogStream(ctx context.Context, in *cloudwatchlogs.CreateLogStreamInput, optFns ...func(*cloudwatchlogs.Options)) (*cloudwatchlogs.CreateLogStreamOutput, error)
+}
+
+// cloudWatchOptions 解析后的cloudwatch sink参数
+type cloudWatchOptions struct {
+	group, stream string
+	createGroup   bool
+	cloudBatchOptions
+}
+
+// cloudWatchSink 将日志行批量写入AWS CloudWatch Logs，适用于Lambda、ECS等无法部署采集sidecar的环境。
+// 写入只进入内存缓冲，由后台按flush_interval或攒满一批时发送，发送失败的日志保留到下次重试，
+// 缓冲超过max_buffer时丢弃最早的日志。日志流不存在时自动创建，create_group为true时同时创建日志组。
+// options：log_group(必填)、log_stream(默认{hostname}/{logger})，均支持{logger}、{hostname}、{date}、{pid}；
+// region、endpoint、flush_interval(默认5s)、max_buffer(默认100000条)、create_group。
+// 凭证按AWS默认链读取：环境变量、共享配置文件、ECS任务角色、EC2实例角色等
+type cloudWatchSink struct {
+	client cloudWatchAPI
+	opts   cloudWatchOptions
+	logger string
+
+	mu      sync.Mutex
+	pending []types.InputLogEvent
+	dropped uint64
+
+	// sendMu 保证批次按顺序发送，并保护以下状态
+	sendMu  sync.Mutex
+	token   *string
+	created bool
+
+	wake      chan struct{}
+	done      chan struct{}
+	stopped   chan struct{}
+	closeOnce sync.Once
+}
+
+func newCloudWatchSink(logger string, options map[string]any) (zapcore.WriteSyncer, error) {
+	opts, err := parseCloudWatchOptions(logger, options)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), cloudRequestTimeout)
+	defer cancel()
+	var loadOpts []func(*awsconfig.LoadOptions) error
+	if region := sinkString(options, "region", ""); region != "" {
+		loadOpts = append(loadOpts, awsconfig.WithRegion(region))
+	}
+	awsCfg, err := awsconfig.LoadDefaultConfig(ctx, loadOpts...)
+	if err != nil {
+		return nil, fmt.Errorf("cloudwatch: failed to load aws config: %w", err)
+	}
+	if awsCfg.Region == "" {
+		return nil, errors.New("cloudwatch: region is required, set options.region or AWS_REGION")
+	}
+	endpoint := sinkString(options, "endpoint", "")
+	client := cloudwatchlogs.NewFromConfig(awsCfg, func(o *cloudwatchlogs.Options) {
+		if endpoint != "" {
+			o.BaseEndpoint = aws.String(endpoint)
+		}
+	})
+	return newCloudWatchWriter(client, logger, opts), nil
+}
+
+func parseCloudWatchOptions(logger string, options map[string]any) (cloudWatchOptions, error) {
+	hostname, _ := os.Hostname()
+	r := strings.NewReplacer(
+		"{logger}", logger,
+		"{hostname}", hostname,
+		"{date}", time.Now().UTC().Format("2006-01-02"),
+		"{pid}", strconv.Itoa(os.Getpid()),
+	)
+	opts := cloudWatchOptions{
+		group:  r.Replace(sinkString(options, "log_group", "")),
+		stream: r.Replace(sinkString(options, "log_stream", "{hostname}/{logger}")),
+	}
+	if opts.group == "" {
+		return opts, errors.New("cloudwatch: log_group is required")
+	}
+	// 日志流名称不允许包含冒号
+	opts.stream = strings.ReplaceAll(opts.stream, ":", "_")
+
+	var err error
+	if opts.createGroup, err = strconv.ParseBool(sinkString(options, "create_group", "false")); err != nil {
+		return opts, fmt.Errorf("cloudwatch: invalid create_group: %w", err)
+	}
+	opts.cloudBatchOptions, err = parseCloudBatchOptions("cloudwatch", options)
+	return opts, err
+}
+
+func newCloudWatchWriter(client cloudWatchAPI, logger string, opts cloudWatchOptions) *cloudWatchSink {
+	s := &cloudWatchSink{
+		client:  client,
+		opts:    opts,
+		logger:  logger,
+		wake:    make(chan struct{}, 1),
+		done:    make(chan struct{}),
+		stopped: make(chan struct{}),
+	}
+	go s.run()
+	return s
+}
+
+func (s *cloudWatchSink) Write(p []byte) (int, error) {
+	msg := string(trimLineEnding(p))
+	if msg == "" {
+		return len(p), nil
+	}
+	if len(msg) > cwMaxEventBytes {
+		msg = truncateUTF8(msg, cwMaxEventBytes)
+	}
+
+	s.mu.Lock()
+	s.pending = append(s.pending, types.InputLogEvent{
+		Message:   aws.String(msg),
+		Timestamp: aws.Int64(time.Now().UnixMilli()),
+	})
+	if over := len(s.pending) - s.opts.maxBuffer; over > 0 {
+		s.pending = slices.Delete(s.pending, 0, over)
+		s.dropped += uint64(over)
+	}
+	full := len(s.pending) >= cwMaxBatchEvents
+	s.mu.Unlock()
+
+	if full {
+		select {
+		case s.wake <- struct{}{}:
+		default:
+		}
+	}
+	return len(p), nil
+}
+
+// truncateUTF8 截断至不超过n字节，不拆分多字节字符
+func truncateUTF8(s string, n int) string {
+	for n > 0 && !utf8.RuneStart(s[n]) {
+		n--
+	}
+	return s[:n]
+}
+
+func (s *cloudWatchSink) run() {
+	defer close(s.stopped)
+	ticker := time.NewTicker(s.opts.flushInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-s.done:
+			return
+		case <-ticker.C:
+		case <-s.wake:
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), cloudRequestTimeout)
+		if err := s.flush(ctx); err != nil {
+			diagnostics.Warn("failed to send logs to cloudwatch",
+				zap.String("logger", s.logger), zap.String("log_group", s.opts.group), zap.Error(err))
+		}
+		cancel()
+	}
+}
+
+// flush 发送缓冲中的全部日志，失败的批次放回缓冲头部
+func (s *cloudWatchSink) flush(ctx context.Context) error {
+	s.sendMu.Lock()
+	defer s.sendMu.Unlock()
+
+	s.mu.Lock()
+	if s.dropped > 0 {
+		diagnostics.Warn("cloudwatch buffer full, dropped oldest logs",
+			zap.String("logger", s.logger), zap.Uint64("dropped", s.dropped))
+		s.dropped = 0
+	}
+	s.mu.Unlock()
+
+	for {
+		batch := s.nextBatch()
+		if len(batch) == 0 {
+			return nil
+		}
+		if err := s.put(ctx, batch); err != nil {
+			s.mu.Lock()
+			s.pending = append(batch, s.pending...)
+			if over := len(s.pending) - s.opts.maxBuffer; over > 0 {
+				s.pending = slices.Delete(s.pending, 0, over)
+				s.dropped += uint64(over)
+			}
+			s.mu.Unlock()
+			return err
+		}
+	}
+}
+
+// nextBatch 从缓冲头部取出不超过PutLogEvents限制的一批日志
+func (s *cloudWatchSink) nextBatch() []types.InputLogEvent {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.pending) == 0 {
+		return nil
+	}
+	first := *s.pending[0].Timestamp
+	size, n := 0, 0
+	for n < len(s.pending) && n < cwMaxBatchEvents {
+		e := s.pending[n]
+		eventSize := len(*e.Message) + cwEventOverhead
+		if size+eventSize > cwMaxBatchBytes || time.Duration(*e.Timestamp-first)*time.Millisecond >= cwMaxBatchSpan {
+			break
+		}
+		size += eventSize
+		n++
+	}
+	batch := slices.Clone(s.pending[:n])
+	s.pending = slices.Delete(s.pending, 0, n)
+	// 时钟回拨时保证批内按时间排序
+	slices.SortStableFunc(batch, func(a, b types.InputLogEvent) int {
+		return cmp.Compare(*a.Timestamp, *b.Timestamp)
+	})
+	return batch
+}
+
+// put 发送一批日志，调用方需持有sendMu。
+// 序列号无效时使用服务端返回的序列号重试，日志流不存在时创建后重试
+func (s *cloudWatchSink) put(ctx context.Context, batch []types.InputLogEvent) error {
+	for attempt := 0; ; attempt++ {
+		if !s.created {
+			if err := s.createStream(ctx); err != nil {
+				return err
+			}
+		}
+		out, err := s.client.PutLogEvents(ctx, &cloudwatchlogs.PutLogEventsInput{
+			LogGroupName:  aws.String(s.opts.group),
+			LogStreamName: aws.String(s.opts.stream),
+			LogEvents:     batch,
+			SequenceToken: s.token,
+		})
+		if err == nil {
+			s.token = out.NextSequenceToken
+			if r := out.RejectedLogEventsInfo; r != nil {
+				diagnostics.Warn("cloudwatch rejected log events outside the accepted time range",
+					zap.String("logger", s.logger), zap.Any("rejected", r))
+			}
+			return nil
+		}
+
+		var (
+			invalidToken *types.InvalidSequenceTokenException
+			accepted     *types.DataAlreadyAcceptedException
+			notFound     *types.ResourceNotFoundException
+		)
+		switch {
+		case errors.As(err, &accepted):
+			s.token = accepted.ExpectedSequenceToken
+			return nil
+		case attempt > 0:
+			return err
+		case errors.As(err, &invalidToken):
+			s.token = invalidToken.ExpectedSequenceToken
+		case errors.As(err, &notFound):
+			s.created, s.token = false, nil
+		default:
+			return err
+		}
+	}
+}
+
+// createStream 创建日志流，已存在时忽略；create_group为true时先创建日志组
+func (s *cloudWatchSink) createStream(ctx context.Context) error {
+	var exists *types.ResourceAlreadyExistsException
+	if s.opts.createGroup {
+		_, err := s.client.CreateLogGroup(ctx, &cloudwatchlogs.CreateLogGroupInput{LogGroupName: aws.String(s.opts.group)})
+		if err != nil && !errors.As(err, &exists) {
+			return fmt.Errorf("create log group %s: %w", s.opts.group, err)
+		}
+	}
+	_, err := s.client.CreateLogStream(ctx, &cloudwatchlogs.CreateLogStreamInput{
+		LogGroupName:  aws.String(s.opts.group),
+		LogStreamName: aws.String(s.opts.stream),
+	})
+	if err != nil && !errors.As(err, &exists) {
+		return fmt.Errorf("create log stream %s/%s: %w", s.opts.group, s.opts.stream, err)
+	}
+	s.created = true
+	return nil
+}
+
+// Sync 立即发送缓冲中的日志
+func (s *cloudWatchSink) Sync() error {
+	ctx, cancel := context.WithTimeout(context.Background(), cloudRequestTimeout)
+	defer cancel()
+	return s.flush(ctx)
+}
+
+// Close 停止后台发送并发送剩余日志
+func (s *cloudWatchSink) Close() error {
+	var err error
+	s.closeOnce.Do(func() {
+		close(s.done)
+		<-s.stopped
+		err = s.Sync()
+	})
+	return err
+}
@@ -0,0 +1,195 @@
+package log
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs/types"
+)
+
+// fakeCloudWatch 模拟日志流与序列号，记录收到的批次
+type fakeCloudWatch struct {
+	mu       sync.Mutex
+	streams  map[string]bool
+	token    int
+	batches  [][]types.InputLogEvent
+	groups   int
+	failNext error
+}
+
+func newFakeCloudWatch() *fakeCloudWatch {
+	return &fakeCloudWatch{streams: make(map[string]bool)}
+}
+
+func (f *fakeCloudWatch) PutLogEvents(_ context.Context, in *cloudwatchlogs.PutLogEventsInput, _ ...func(*cloudwatchlogs.Options)) (*cloudwatchlogs.PutLogEventsOutput, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.failNext; err != nil {
+		f.failNext = nil
+		return nil, err
+	}
+	if !f.streams[*in.LogGroupName+"/"+*in.LogStreamName] {
+		return nil, &types.ResourceNotFoundException{Message: aws.String("stream not found")}
+	}
+	expected := aws.String(string(rune('a' + f.token)))
+	if f.token > 0 && aws.ToString(in.SequenceToken) != *expected {
+		return nil, &types.InvalidSequenceTokenException{ExpectedSequenceToken: expected}
+	}
+	f.token++
+	f.batches = append(f.batches, in.LogEvents)
+	return &cloudwatchlogs.PutLogEventsOutput{NextSequenceToken: aws.String(string(rune('a' + f.token)))}, nil
+}
+
+func (f *fakeCloudWatch) CreateLogGroup(context.Context, *cloudwatchlogs.CreateLogGroupInput, ...func(*cloudwatchlogs.Options)) (*cloudwatchlogs.CreateLogGroupOutput, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.groups++
+	return nil, &types.ResourceAlreadyExistsException{}
+}
+
+func (f *fakeCloudWatch) CreateLogStream(_ context.Context, in *cloudwatchlogs.CreateLogStreamInput, _ ...func(*cloudwatchlogs.Options)) (*cloudwatchlogs.CreateLogStreamOutput, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.streams[*in.LogGroupName+"/"+*in.LogStreamName] = true
+	return &cloudwatchlogs.CreateLogStreamOutput{}, nil
+}
+
+func (f *fakeCloudWatch) events() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var msgs []string
+	for _, b := range f.batches {
+		for _, e := range b {
+			msgs = append(msgs, *e.Message)
+		}
+	}
+	return msgs
+}
+
+func testCloudWatchOptions() cloudWatchOptions {
+	return cloudWatchOptions{group: "/app/test", stream: "host/test", createGroup: true, cloudBatchOptions: cloudBatchOptions{flushInterval: time.Hour, maxBuffer: 100000}}
+}
+
+func TestCloudWatchSink(t *testing.T) {
+	fake := newFakeCloudWatch()
+	s := newCloudWatchWriter(fake, "test", testCloudWatchOptions())
+
+	s.Write([]byte(`{"msg":"first"}` + "\n"))
+	s.Write([]byte(`{"msg":"second"}` + "\n"))
+	if err := s.Sync(); err != nil {
+		t.Fatal(err)
+	}
+	if got := fake.events(); len(got) != 2 || got[0] != `{"msg":"first"}` {
+		t.Fatalf("unexpected events %q", got)
+	}
+	if fake.groups != 1 {
+		t.Fatalf("log group should be created once, got %d", fake.groups)
+	}
+
+	// 序列号失效时使用服务端返回的序列号重试
+	s.sendMu.Lock()
+	s.token = aws.String("stale")
+	s.sendMu.Unlock()
+	s.Write([]byte("third\n"))
+	if err := s.Sync(); err != nil {
+		t.Fatal(err)
+	}
+
+	// 日志流被删除后重新创建
+	fake.mu.Lock()
+	fake.streams = make(map[string]bool)
+	fake.token = 0
+	fake.mu.Unlock()
+	s.Write([]byte("fourth\n"))
+	if err := s.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if got := fake.events(); len(got) != 4 || got[3] != "fourth" {
+		t.Fatalf("unexpected events %q", got)
+	}
+}
+
+func TestCloudWatchRetryOnFailure(t *testing.T) {
+	fake := newFakeCloudWatch()
+	s := newCloudWatchWriter(fake, "test", testCloudWatchOptions())
+	defer s.Close()
+
+	fake.failNext = errors.New("throttled")
+	s.Write([]byte("kept\n"))
+	if err := s.Sync(); err == nil {
+		t.Fatal("expected error")
+	}
+	// 失败的日志保留到下次发送
+	if err := s.Sync(); err != nil {
+		t.Fatal(err)
+	}
+	if got := fake.events(); len(got) != 1 || got[0] != "kept" {
+		t.Fatalf("unexpected events %q", got)
+	}
+}
+
+func TestCloudWatchBatchLimits(t *testing.T) {
+	s := newCloudWatchWriter(newFakeCloudWatch(), "test", cloudWatchOptions{cloudBatchOptions: cloudBatchOptions{flushInterval: time.Hour, maxBuffer: 3}})
+	defer s.Close()
+
+	s.Write([]byte(strings.Repeat("x", 300*1024)))
+	if n := len(*s.pending[0].Message); n != cwMaxEventBytes {
+		t.Fatalf("oversized event should be truncated to %d bytes, got %d", cwMaxEventBytes, n)
+	}
+	for _, msg := range []string{"a", "b", "c"} {
+		s.Write([]byte(msg))
+	}
+	if len(s.pending) != 3 || *s.pending[0].Message != "a" || s.dropped != 1 {
+		t.Fatalf("oldest events should be dropped when the buffer is full, got %d pending, %d dropped", len(s.pending), s.dropped)
+	}
+
+	// 按条数、字节数与时间跨度分批
+	now := time.Now().UnixMilli()
+	s.pending = s.pending[:0]
+	for i := 0; i < cwMaxBatchEvents+1; i++ {
+		s.pending = append(s.pending, types.InputLogEvent{Message: aws.String("m"), Timestamp: aws.Int64(now)})
+	}
+	if n := len(s.nextBatch()); n != cwMaxBatchEvents {
+		t.Fatalf("batch should be capped at %d events, got %d", cwMaxBatchEvents, n)
+	}
+	big := strings.Repeat("x", cwMaxEventBytes)
+	s.pending = s.pending[:0]
+	for i := 0; i < 5; i++ {
+		s.pending = append(s.pending, types.InputLogEvent{Message: aws.String(big), Timestamp: aws.Int64(now)})
+	}
+	if n := len(s.nextBatch()); n != 4 {
+		t.Fatalf("batch should be capped at 1MB, got %d events", n)
+	}
+	s.pending = []types.InputLogEvent{
+		{Message: aws.String("old"), Timestamp: aws.Int64(now - 25*time.Hour.Milliseconds())},
+		{Message: aws.String("new"), Timestamp: aws.Int64(now)},
+	}
+	if n := len(s.nextBatch()); n != 1 {
+		t.Fatalf("batch should span less than 24h, got %d events", n)
+	}
+}
+
+func TestParseCloudWatchOptions(t *testing.T) {
+	opts, err := parseCloudWatchOptions("access", map[string]any{"log_group": "/app/{logger}", "log_stream": "{logger}:{pid}"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if opts.group != "/app/access" || !strings.HasPrefix(opts.stream, "access_") || opts.flushInterval != 5*time.Second {
+		t.Fatalf("unexpected options %+v", opts)
+	}
+	for _, options := range []map[string]any{
+		{},
+		{"log_group": "g", "flush_interval": "soon"},
+		{"log_group": "g", "max_buffer": 0},
+	} {
+		if _, err := parseCloudWatchOptions("access", options); err == nil {
+			t.Errorf("expected error for %v", options)
+		}
+	}
+}
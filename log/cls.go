@@ -0,0 +1,191 @@
+package log
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// 上传结构化日志接口的限制：单次最多10000条、5MB
+const (
+	clsMaxBatchLogs  = 10000
+	clsMaxBatchBytes = 5 * 1024 * 1024
+	clsSignExpire    = 5 * time.Minute
+)
+
+// clsSink 通过上传结构化日志接口以protobuf批量写入腾讯云日志服务CLS，json日志行的各字段对应CLS的字段。
+// options：endpoint(如ap-guangzhou.cls.tencentcs.com，必填)、topic_id(必填)、source(默认主机名)、
+// secret_id、secret_key、token(默认读取TENCENTCLOUD_SECRET_ID等环境变量)、flush_interval(默认5s)、max_buffer(默认100000条)
+type clsSink struct {
+	*cloudBatcher
+
+	endpoint  *url.URL
+	topicID   string
+	source    string
+	logger    string
+	secretID  string
+	secretKey string
+	token     string
+	client    *http.Client
+}
+
+func newCLSSink(logger string, options map[string]any) (zapcore.WriteSyncer, error) {
+	topicID := sinkString(options, "topic_id", "")
+	if topicID == "" {
+		return nil, errors.New("cls: topic_id is required")
+	}
+	u, err := cloudEndpoint(sinkString(options, "endpoint", ""))
+	if err != nil {
+		return nil, fmt.Errorf("cls: %w", err)
+	}
+
+	hostname, _ := os.Hostname()
+	s := &clsSink{
+		endpoint:  u,
+		topicID:   topicID,
+		source:    sinkString(options, "source", hostname),
+		logger:    logger,
+		secretID:  envOr(sinkString(options, "secret_id", ""), "TENCENTCLOUD_SECRET_ID"),
+		secretKey: envOr(sinkString(options, "secret_key", ""), "TENCENTCLOUD_SECRET_KEY"),
+		token:     envOr(sinkString(options, "token", ""), "TENCENTCLOUD_SESSION_TOKEN"),
+		client:    &http.Client{Timeout: cloudRequestTimeout},
+	}
+	if s.secretID == "" || s.secretKey == "" {
+		return nil, errors.New("cls: secret_id and secret_key are required")
+	}
+	opts, err := parseCloudBatchOptions("cls", options)
+	if err != nil {
+		return nil, err
+	}
+	s.cloudBatcher = newCloudBatcher("cls", logger, clsMaxBatchLogs, clsMaxBatchBytes, opts, s.send)
+	return s, nil
+}
+
+// encodeCLSLogGroupList 按CLS的LogGroupList定义编码：
+// Log{time=1 int64微秒, contents=2 {key=1, value=2}}，LogGroup{logs=1, filename=3, source=4, logTags=5 {key=1, value=2}}，
+// LogGroupList{logGroupList=1}
+func encodeCLSLogGroupList(logs []cloudLog, filename, source string, tags [][2]string) []byte {
+	var group []byte
+	for _, l := range logs {
+		var entry []byte
+		entry = protowire.AppendTag(entry, 1, protowire.VarintType)
+		entry = protowire.AppendVarint(entry, uint64(l.time.UnixMicro()))
+		for _, c := range l.contents {
+			entry = protowire.AppendTag(entry, 2, protowire.BytesType)
+			entry = protowire.AppendBytes(entry, encodeProtoPair(c))
+		}
+		group = protowire.AppendTag(group, 1, protowire.BytesType)
+		group = protowire.AppendBytes(group, entry)
+	}
+	if filename != "" {
+		group = protowire.AppendTag(group, 3, protowire.BytesType)
+		group = protowire.AppendString(group, filename)
+	}
+	if source != "" {
+		group = protowire.AppendTag(group, 4, protowire.BytesType)
+		group = protowire.AppendString(group, source)
+	}
+	for _, tag := range tags {
+		group = protowire.AppendTag(group, 5, protowire.BytesType)
+		group = protowire.AppendBytes(group, encodeProtoPair(tag))
+	}
+
+	var list []byte
+	list = protowire.AppendTag(list, 1, protowire.BytesType)
+	return protowire.AppendBytes(list, group)
+}
+
+func (s *clsSink) send(ctx context.Context, logs []cloudLog) error {
+	body := encodeCLSLogGroupList(logs, s.logger, s.source, [][2]string{{"logger", s.logger}})
+	u := *s.endpoint
+	u.Path += "/structuredlog"
+	u.RawQuery = url.Values{"topic_id": {s.topicID}}.Encode()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, u.String(), bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-protobuf")
+	if s.token != "" {
+		req.Header.Set("X-Cls-Token", s.token)
+	}
+	req.Header.Set("Authorization", signCLS(req, s.secretID, s.secretKey, time.Now()))
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("cls: upload logs to %s: unexpected status %s: %s", s.topicID, resp.Status, bytes.TrimSpace(msg))
+	}
+	return nil
+}
+
+// signCLS 计算CLS请求签名，签名Host、Content-Type头与全部查询参数，有效期5分钟：
+// SignKey=hex(hmac-sha1(SecretKey, KeyTime))，HttpRequestInfo=method\npath\nparams\nheaders\n，
+// Signature=hex(hmac-sha1(SignKey, "sha1\nKeyTime\nhex(sha1(HttpRequestInfo))\n"))
+func signCLS(req *http.Request, secretID, secretKey string, now time.Time) string {
+	keyTime := fmt.Sprintf("%d;%d", now.Unix(), now.Add(clsSignExpire).Unix())
+
+	headers := map[string]string{
+		"content-type": req.Header.Get("Content-Type"),
+		"host":         req.URL.Host,
+	}
+	params := make(map[string]string)
+	for key, values := range req.URL.Query() {
+		params[strings.ToLower(key)] = values[0]
+	}
+	headerList, headerStr := clsFormat(headers)
+	paramList, paramStr := clsFormat(params)
+
+	info := strings.ToLower(req.Method) + "\n" + req.URL.Path + "\n" + paramStr + "\n" + headerStr + "\n"
+	infoSum := sha1.Sum([]byte(info))
+	toSign := "sha1\n" + keyTime + "\n" + hex.EncodeToString(infoSum[:]) + "\n"
+	signKey := hmacSHA1Hex([]byte(secretKey), keyTime)
+	signature := hmacSHA1Hex([]byte(signKey), toSign)
+
+	return strings.Join([]string{
+		"q-sign-algorithm=sha1",
+		"q-ak=" + secretID,
+		"q-sign-time=" + keyTime,
+		"q-key-time=" + keyTime,
+		"q-header-list=" + headerList,
+		"q-url-param-list=" + paramList,
+		"q-signature=" + signature,
+	}, "&")
+}
+
+// clsFormat 按键排序，返回以;连接的键与以&连接的key=value
+func clsFormat(m map[string]string) (keys, pairs string) {
+	names := make([]string, 0, len(m))
+	for k := range m {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+	kv := make([]string, len(names))
+	for i, k := range names {
+		kv[i] = k + "=" + url.QueryEscape(m[k])
+	}
+	return strings.Join(names, ";"), strings.Join(kv, "&")
+}
+
+func hmacSHA1Hex(key []byte, data string) string {
+	mac := hmac.New(sha1.New, key)
+	mac.Write([]byte(data))
+	return hex.EncodeToString(mac.Sum(nil))
+}
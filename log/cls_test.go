@@ -0,0 +1,68 @@
+package log
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCLSSink(t *testing.T) {
+	var body []byte
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/structuredlog" || r.URL.Query().Get("topic_id") != "topic-1" {
+			http.NotFound(w, r)
+			return
+		}
+		auth := r.Header.Get("Authorization")
+		if !strings.Contains(auth, "q-ak=sid&") || !strings.Contains(auth, "q-header-list=content-type;host&q-url-param-list=topic_id&") {
+			t.Errorf("unexpected Authorization %q", auth)
+		}
+		if r.Header.Get("X-Cls-Token") != "token" {
+			t.Error("missing X-Cls-Token")
+		}
+		body, _ = io.ReadAll(r.Body)
+	}))
+	defer srv.Close()
+
+	u, err := cloudEndpoint(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := &clsSink{endpoint: u, topicID: "topic-1", source: "10.0.0.1", logger: "access",
+		secretID: "sid", secretKey: "skey", token: "token", client: srv.Client()}
+	s.cloudBatcher = newCloudBatcher("cls", "access", clsMaxBatchLogs, clsMaxBatchBytes, cloudBatchOptions{flushInterval: time.Hour, maxBuffer: 10}, s.send)
+	s.Write([]byte("plain line\n"))
+	if err := s.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	list := protoFields(t, body)
+	group := protoFields(t, list[1][0])
+	if string(group[3][0]) != "access" || string(group[4][0]) != "10.0.0.1" || len(group[5]) != 1 {
+		t.Fatalf("unexpected log group %v", group)
+	}
+	entry := protoFields(t, group[1][0])
+	if us := protoVarint(t, entry[1][0]); time.Since(time.UnixMicro(int64(us))) > time.Minute {
+		t.Fatalf("time should be in microseconds, got %d", us)
+	}
+	if c := protoFields(t, entry[2][0]); string(c[1][0]) != cloudLogContentKey || string(c[2][0]) != "plain line" {
+		t.Fatalf("unexpected content %v", c)
+	}
+}
+
+func TestSignCLS(t *testing.T) {
+	req, _ := http.NewRequest(http.MethodPost, "https://ap-guangzhou.cls.tencentcs.com/structuredlog?topic_id=abc", nil)
+	req.Header.Set("Content-Type", "application/x-protobuf")
+	now := time.Unix(1700000000, 0)
+	auth := signCLS(req, "sid", "skey", now)
+	if !strings.HasPrefix(auth, "q-sign-algorithm=sha1&q-ak=sid&q-sign-time=1700000000;1700000300&q-key-time=1700000000;1700000300&") {
+		t.Fatalf("unexpected Authorization %q", auth)
+	}
+	// 签名只取决于请求内容与时间
+	if auth != signCLS(req, "sid", "skey", now) || auth == signCLS(req, "sid", "other", now) {
+		t.Fatal("signature should depend on the secret key only")
+	}
+}
@@ -0,0 +1,47 @@
+package log
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseCron(t *testing.T) {
+	at := func(s string) time.Time {
+		tm, err := time.ParseInLocation("2006-01-02 15:04", s, time.Local)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return tm
+	}
+
+	cases := []struct {
+		expr string
+		time string
+		want bool
+	}{
+		{"0 2 * * *", "2026-10-16 02:00", true},
+		{"0 2 * * *", "2026-10-16 02:01", false},
+		{"*/15 * * * *", "2026-10-16 13:45", true},
+		{"*/15 * * * *", "2026-10-16 13:46", false},
+		{"0 22-23 * * 1-5", "2026-10-16 23:00", true},  // 周五
+		{"0 22-23 * * 1-5", "2026-10-17 23:00", false}, // 周六
+		{"30 1 1,15 * *", "2026-10-15 01:30", true},
+		{"0 0 * * 7", "2026-10-18 00:00", true}, // 周日
+		{"0 0 1 * 1", "2026-10-19 00:00", true}, // 日和周满足其一
+	}
+	for _, c := range cases {
+		s, err := parseCron(c.expr)
+		if err != nil {
+			t.Fatalf("%s: %v", c.expr, err)
+		}
+		if got := s.matches(at(c.time)); got != c.want {
+			t.Errorf("%s at %s: got %v, want %v", c.expr, c.time, got, c.want)
+		}
+	}
+
+	for _, expr := range []string{"", "* * * *", "60 * * * *", "*/0 * * * *", "a * * * *", "5-1 * * * *"} {
+		if _, err := parseCron(expr); err == nil {
+			t.Errorf("%q: expected error", expr)
+		}
+	}
+}
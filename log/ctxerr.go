@@ -0,0 +1,65 @@
+package log
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+type startTimeKey struct{}
+
+// ContextWithStart 在ctx上记录操作的开始时间，CtxErr据此输出elapsed和timeout
+func ContextWithStart(ctx context.Context, start time.Time) context.Context {
+	return context.WithValue(ctx, startTimeKey{}, start)
+}
+
+// CtxErr 返回描述err的字段，区分超时(deadline_exceeded)、取消(canceled)和其他错误(other)，
+// 并附加ctx的截止时间、context.Cause以及通过ContextWithStart记录的耗时
+func CtxErr(ctx context.Context, err error) zap.Field {
+	return zap.Inline(ctxErr{ctx: ctx, err: err})
+}
+
+type ctxErr struct {
+	ctx context.Context
+	err error
+}
+
+func (c ctxErr) kind() string {
+	err := c.err
+	if !errors.Is(err, context.DeadlineExceeded) && !errors.Is(err, context.Canceled) {
+		// err可能是下游包装后的错误，以ctx自身的状态为准
+		err = c.ctx.Err()
+	}
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		return "deadline_exceeded"
+	case errors.Is(err, context.Canceled):
+		return "canceled"
+	}
+	return "other"
+}
+
+func (c ctxErr) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	if c.err != nil {
+		enc.AddString("error", c.err.Error())
+	}
+	enc.AddString("error_kind", c.kind())
+
+	if cause := context.Cause(c.ctx); cause != nil && cause != c.ctx.Err() {
+		enc.AddString("cause", cause.Error())
+	}
+	deadline, hasDeadline := c.ctx.Deadline()
+	if hasDeadline {
+		enc.AddTime("deadline", deadline)
+	}
+	if start, ok := c.ctx.Value(startTimeKey{}).(time.Time); ok {
+		enc.AddDuration("elapsed", time.Since(start))
+		if hasDeadline {
+			enc.AddDuration("timeout", deadline.Sub(start))
+		}
+	}
+	return nil
+}
@@ -0,0 +1,47 @@
+package log
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestCtxErr(t *testing.T) {
+	logs := observeLogger(t, "ctxerr")
+	logger := GetLogger("ctxerr")
+
+	start := time.Now()
+	ctx, cancel := context.WithTimeout(ContextWithStart(context.Background(), start), time.Millisecond)
+	defer cancel()
+	<-ctx.Done()
+	logger.Error("timeout", CtxErr(ctx, fmt.Errorf("query: %w", ctx.Err())))
+
+	ctx2, cancel2 := context.WithCancelCause(context.Background())
+	cancel2(errors.New("client gone"))
+	// 下游返回的错误未包装ctx.Err()时以ctx状态判断
+	logger.Error("canceled", CtxErr(ctx2, errors.New("rpc error")))
+
+	logger.Error("other", CtxErr(context.Background(), errors.New("boom")))
+
+	entries := logs.AllUntimed()
+	first := entries[0].ContextMap()
+	if first["error_kind"] != "deadline_exceeded" {
+		t.Fatalf("unexpected kind %v", first["error_kind"])
+	}
+	if _, ok := first["deadline"]; !ok {
+		t.Fatal("missing deadline")
+	}
+	if first["timeout"].(time.Duration) < time.Millisecond || first["elapsed"].(time.Duration) < time.Millisecond {
+		t.Fatalf("unexpected timeout/elapsed %v %v", first["timeout"], first["elapsed"])
+	}
+
+	second := entries[1].ContextMap()
+	if second["error_kind"] != "canceled" || second["cause"] != "client gone" {
+		t.Fatalf("unexpected fields %v", second)
+	}
+	if third := entries[2].ContextMap(); third["error_kind"] != "other" || third["error"] != "boom" {
+		t.Fatalf("unexpected fields %v", third)
+	}
+}
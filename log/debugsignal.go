@@ -0,0 +1,23 @@
+package log
+
+import "go.uber.org/zap/zapcore"
+
+// DebugAll 将所有logger调整为debug级别
+func DebugAll() {
+	metux.RLock()
+	defer metux.RUnlock()
+
+	for _, entry := range loggers {
+		setBaseLevel(entry, zapcore.DebugLevel)
+	}
+}
+
+// RestoreLevels 将所有logger恢复为配置文件中的级别
+func RestoreLevels() {
+	metux.RLock()
+	defer metux.RUnlock()
+
+	for _, entry := range loggers {
+		setBaseLevel(entry, getLevel(entry.cfg.Level))
+	}
+}
@@ -0,0 +1,8 @@
+//go:build !unix
+
+package log
+
+// startDebugSignalHandler 当前平台不支持SIGUSR1/SIGUSR2
+func startDebugSignalHandler() {}
+
+func stopDebugSignalHandler() {}
@@ -0,0 +1,44 @@
+//go:build unix
+
+package log
+
+import (
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+var debugSignalStop chan struct{}
+
+// startDebugSignalHandler 收到SIGUSR1时调用DebugAll，收到SIGUSR2时调用RestoreLevels，调用方需持有metux写锁
+func startDebugSignalHandler() {
+	stopDebugSignalHandler()
+
+	ch := make(chan os.Signal, 1)
+	signal.Notify(ch, syscall.SIGUSR1, syscall.SIGUSR2)
+	stop := make(chan struct{})
+	debugSignalStop = stop
+	go func() {
+		defer signal.Stop(ch)
+		for {
+			select {
+			case <-stop:
+				return
+			case sig := <-ch:
+				if sig == syscall.SIGUSR1 {
+					DebugAll()
+				} else {
+					RestoreLevels()
+				}
+			}
+		}
+	}()
+}
+
+// stopDebugSignalHandler 调用方需持有metux写锁
+func stopDebugSignalHandler() {
+	if debugSignalStop != nil {
+		close(debugSignalStop)
+		debugSignalStop = nil
+	}
+}
@@ -0,0 +1,43 @@
+//go:build unix
+
+package log
+
+import (
+	"os"
+	"syscall"
+	"testing"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+func TestDebugSignals(t *testing.T) {
+	initTestLoggers(t, "access")
+	metux.Lock()
+	startDebugSignalHandler()
+	metux.Unlock()
+
+	waitFor := func(debug bool) {
+		t.Helper()
+		deadline := time.Now().Add(time.Second)
+		for GetLogger("access").Core().Enabled(zapcore.DebugLevel) != debug {
+			if time.Now().After(deadline) {
+				t.Fatalf("expected debug enabled=%v", debug)
+			}
+			time.Sleep(5 * time.Millisecond)
+		}
+	}
+
+	if err := syscall.Kill(os.Getpid(), syscall.SIGUSR1); err != nil {
+		t.Fatal(err)
+	}
+	waitFor(true)
+	if !GetDefaultLogger().Core().Enabled(zapcore.DebugLevel) {
+		t.Fatal("default should be at debug too")
+	}
+
+	if err := syscall.Kill(os.Getpid(), syscall.SIGUSR2); err != nil {
+		t.Fatal(err)
+	}
+	waitFor(false)
+}
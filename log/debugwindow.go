@@ -0,0 +1,107 @@
+package log
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// debugWindow 临时调试窗口
+type debugWindow struct {
+	timer   *time.Timer
+	restore zapcore.Level
+}
+
+var (
+	debugWindows  = make(map[*logEntry]*debugWindow)
+	debugWindowMu sync.Mutex
+)
+
+// EnableDebugFor 将指定logger临时调整为debug级别，d之后自动恢复为调用前的级别。
+// 窗口未结束时再次调用会重新计时
+func EnableDebugFor(name string, d time.Duration) error {
+	if d <= 0 {
+		return fmt.Errorf("invalid duration %s", d)
+	}
+
+	metux.RLock()
+	entry, ok := loggers[name]
+	metux.RUnlock()
+	if !ok {
+		return fmt.Errorf("logger %s not found", name)
+	}
+
+	debugWindowMu.Lock()
+	defer debugWindowMu.Unlock()
+
+	w, ok := debugWindows[entry]
+	if ok {
+		w.timer.Stop()
+	} else {
+		w = &debugWindow{restore: entry.level.Level()}
+		debugWindows[entry] = w
+	}
+	entry.level.SetLevel(zapcore.DebugLevel)
+	w.timer = time.AfterFunc(d, func() {
+		debugWindowMu.Lock()
+		defer debugWindowMu.Unlock()
+
+		if debugWindows[entry] == w {
+			entry.level.SetLevel(w.restore)
+			delete(debugWindows, entry)
+		}
+	})
+	return nil
+}
+
+// stopDebugWindow 取消logger尚未结束的调试窗口，不恢复级别
+func stopDebugWindow(entry *logEntry) {
+	debugWindowMu.Lock()
+	defer debugWindowMu.Unlock()
+
+	if w, ok := debugWindows[entry]; ok {
+		w.timer.Stop()
+		delete(debugWindows, entry)
+	}
+}
+
+// setBaseLevel 调整logger级别，调试窗口期间只修改窗口结束后恢复的级别
+func setBaseLevel(entry *logEntry, level zapcore.Level) {
+	debugWindowMu.Lock()
+	defer debugWindowMu.Unlock()
+
+	if w, ok := debugWindows[entry]; ok {
+		w.restore = level
+		return
+	}
+	entry.level.SetLevel(level)
+}
+
+// resetBaseLevel 级别仍为old时恢复为level，已被其他途径修改时保持不变
+func resetBaseLevel(entry *logEntry, old, level zapcore.Level) {
+	debugWindowMu.Lock()
+	defer debugWindowMu.Unlock()
+
+	if w, ok := debugWindows[entry]; ok {
+		if w.restore == old {
+			w.restore = level
+		}
+		return
+	}
+	if entry.level.Level() == old {
+		entry.level.SetLevel(level)
+	}
+}
+
+// baseLevel 返回logger不计调试窗口时的级别
+func baseLevel(entry *logEntry) zapcore.Level {
+	debugWindowMu.Lock()
+	defer debugWindowMu.Unlock()
+
+	if w, ok := debugWindows[entry]; ok {
+		return w.restore
+	}
+	return entry.level.Level()
+}
@@ -0,0 +1,56 @@
+package log
+
+import (
+	"testing"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+func TestEnableDebugFor(t *testing.T) {
+	initTestLoggers(t, "access")
+	if err := SetLevel("access", "warn"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := EnableDebugFor("access", 30*time.Millisecond); err != nil {
+		t.Fatal(err)
+	}
+	// 再次调用重新计时，恢复的仍是最初的级别
+	if err := EnableDebugFor("access", 50*time.Millisecond); err != nil {
+		t.Fatal(err)
+	}
+	core := GetLogger("access").Core()
+	if !core.Enabled(zapcore.DebugLevel) {
+		t.Fatal("access should be at debug")
+	}
+
+	time.Sleep(100 * time.Millisecond)
+	if core.Enabled(zapcore.InfoLevel) || !core.Enabled(zapcore.WarnLevel) {
+		t.Fatal("access should be restored to warn")
+	}
+
+	if err := EnableDebugFor("missing", time.Second); err == nil {
+		t.Fatal("expected error for unknown logger")
+	}
+}
+
+func TestSetLevelDuringDebugWindow(t *testing.T) {
+	initTestLoggers(t, "access")
+	if err := EnableDebugFor("access", 50*time.Millisecond); err != nil {
+		t.Fatal(err)
+	}
+	// 窗口期间调整的级别在窗口结束后生效，不被窗口开始前的级别覆盖
+	if err := SetLevel("access", "error"); err != nil {
+		t.Fatal(err)
+	}
+	core := GetLogger("access").Core()
+	if !core.Enabled(zapcore.DebugLevel) {
+		t.Fatal("access should stay at debug during the window")
+	}
+
+	time.Sleep(100 * time.Millisecond)
+	if core.Enabled(zapcore.WarnLevel) || !core.Enabled(zapcore.ErrorLevel) {
+		t.Fatal("access should be at error after the window")
+	}
+}
@@ -0,0 +1,66 @@
+package log
+
+import (
+	"sync"
+
+	"go.uber.org/zap"
+)
+
+// deprecations 已输出过的弃用提示，每项只提示一次
+var deprecations sync.Map
+
+// deprecated 输出一次性的弃用记录，说明替代方式
+func deprecated(api, replacement string) {
+	if _, loaded := deprecations.LoadOrStore(api, struct{}{}); loaded {
+		return
+	}
+	diagnostics.Warn("deprecated api in use",
+		zap.String("deprecated", api),
+		zap.String("replacement", replacement),
+	)
+}
+
+// migrateConfig 将旧配置项映射到新的配置模型
+func migrateConfig(cfg *Config) {
+	for i := range cfg.Zaplog {
+		lc := &cfg.Zaplog[i]
+		if lc.JsonEncoder {
+			deprecated("config json_encoder", "encoder: json")
+			if lc.Encoder == "" {
+				lc.Encoder = "json"
+			}
+		}
+	}
+}
+
+// InitOption 初始化选项
+type InitOption func(*initOptions)
+
+type initOptions struct {
+	configPath string
+	format     string
+	config     *Config
+}
+
+// WithConfigFile 从配置文件加载，path为目录或glob时合并其中的多个文件，见LoadConfigFormat
+func WithConfigFile(path string) InitOption {
+	return func(o *initOptions) { o.configPath = path }
+}
+
+// WithConfigFormat 指定配置文件格式，默认根据扩展名判断
+func WithConfigFormat(format string) InitOption {
+	return func(o *initOptions) { o.format = format }
+}
+
+// WithConfig 直接使用cfg，不读取配置文件
+func WithConfig(cfg Config) InitOption {
+	return func(o *initOptions) { o.config = &cfg }
+}
+
+// InitFromLocalFileConfig 初始化日志，configPath可以是目录或glob，见LoadConfigFormat
+//
+// Deprecated: 使用 Init(WithConfigFile(configPath))
+func InitFromLocalFileConfig(configPath string) error {
+	deprecated("log.InitFromLocalFileConfig", "log.Init(log.WithConfigFile(path))")
+	return Init(WithConfigFile(configPath))
+}
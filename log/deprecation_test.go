@@ -0,0 +1,57 @@
+package log
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestDeprecationShims(t *testing.T) {
+	var (
+		mu  sync.Mutex
+		buf bytes.Buffer
+	)
+	stderrOutput = func(p []byte) (int, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		return buf.Write(p)
+	}
+	t.Cleanup(func() { stderrOutput = writeStderr })
+	deprecations.Delete("log.InitFromLocalFileConfig")
+	deprecations.Delete("config json_encoder")
+
+	dir := t.TempDir()
+	cfgPath := filepath.Join(dir, "log_config.yaml")
+	config := "zaplog:\n  - name: default\n    file_name: " + filepath.Join(dir, "app.log") + "\n    json_encoder: true\n"
+	if err := os.WriteFile(cfgPath, []byte(config), 0644); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(Close)
+	// 旧接口和json_encoder仍然可用，重复调用只提示一次
+	for i := 0; i < 2; i++ {
+		Close()
+		if err := InitFromLocalFileConfig(cfgPath); err != nil {
+			t.Fatal(err)
+		}
+	}
+	cfg, err := LoadConfig(cfgPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Zaplog[0].Encoder != "json" {
+		t.Fatalf("json_encoder should map to encoder json, got %q", cfg.Zaplog[0].Encoder)
+	}
+
+	mu.Lock()
+	out := buf.String()
+	mu.Unlock()
+	if strings.Count(out, `"deprecated":"log.InitFromLocalFileConfig"`) != 1 {
+		t.Fatalf("expected one deprecation record for InitFromLocalFileConfig, got %q", out)
+	}
+	if !strings.Contains(out, `"replacement":"encoder: json"`) {
+		t.Fatalf("expected json_encoder deprecation record, got %q", out)
+	}
+}
@@ -0,0 +1,14 @@
+package log
+
+import (
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// diagnostics 包内部诊断日志，直接写fd 2，不依赖任何配置
+var diagnostics = func() *zap.Logger {
+	encoderConfig := zap.NewProductionEncoderConfig()
+	encoderConfig.EncodeTime = zapcore.ISO8601TimeEncoder
+	core := zapcore.NewCore(zapcore.NewJSONEncoder(encoderConfig), stderrWriter{}, zapcore.DebugLevel)
+	return zap.New(core).Named("goeasy.log")
+}()
@@ -0,0 +1,49 @@
+package log
+
+import (
+	"context"
+	"io"
+	"sync/atomic"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// disabledAll Disable后为true，之后创建的logger均为禁用状态
+var disabledAll atomic.Bool
+
+// Disable 禁用所有日志：已注册和之后创建的logger都替换为zap.NewNop()，不再产生任何I/O，
+// 调用方无需修改，适用于压测和不关心日志的测试。
+// 调用前通过GetLogger等获取并自行保存的logger不受影响
+func Disable() {
+	disabledAll.Store(true)
+
+	metux.Lock()
+	defer metux.Unlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), closeTimeout)
+	defer cancel()
+	for name, entry := range loggers {
+		_ = closeEntry(ctx, name, entry)
+		loggers[name] = newDisabledLogger(entry.cfg)
+	}
+	modules = make(map[string]*module)
+	zap.ReplaceGlobals(zap.NewNop())
+}
+
+// newDisabledLogger 创建丢弃所有日志的logger
+func newDisabledLogger(cfg LogConfig) *logEntry {
+	entry := &logEntry{
+		cfg:    cfg,
+		level:  zap.NewAtomicLevelAt(getLevel(cfg.Level)),
+		stats:  newLevelCounter(),
+		usage:  newUsageCounter(),
+		hooks:  &hookList{},
+		ws:     zapcore.AddSync(io.Discard),
+		logger: zap.NewNop(),
+	}
+	entry.newCore = func(zapcore.LevelEnabler, zapcore.WriteSyncer) zapcore.Core {
+		return zapcore.NewNopCore()
+	}
+	return entry
+}
@@ -0,0 +1,66 @@
+package log
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"go.uber.org/zap"
+)
+
+func TestDisabledLogger(t *testing.T) {
+	dir := t.TempDir()
+	config := fmt.Sprintf(`zaplog:
+  - name: default
+    file_name: %s
+  - name: bench
+    disabled: true
+    file_name: %s
+  - name: nofile
+    disabled: true
+`, filepath.Join(dir, "app.log"), filepath.Join(dir, "bench.log"))
+	configPath := filepath.Join(dir, "log_config.yaml")
+	if err := os.WriteFile(configPath, []byte(config), 0644); err != nil {
+		t.Fatal(err)
+	}
+	Close()
+	if err := Init(WithConfigFile(configPath)); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(Close)
+
+	GetLogger("bench").Error("dropped")
+	Batch("bench").Info("dropped")
+	if err := Rotate(""); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "bench.log")); !os.IsNotExist(err) {
+		t.Fatalf("disabled logger should not create its file, stat: %v", err)
+	}
+	if GetLogger("bench").Core().Enabled(zap.ErrorLevel) {
+		t.Fatal("disabled logger should not enable any level")
+	}
+}
+
+func TestDisable(t *testing.T) {
+	dir := initTestLoggers(t, "access")
+	t.Cleanup(func() { disabledAll.Store(false) })
+
+	Disable()
+	GetLogger("access").Info("dropped")
+	Module("dao").Info("dropped")
+	zap.L().Info("dropped")
+	if data, _ := os.ReadFile(filepath.Join(dir, "access.log")); len(data) != 0 {
+		t.Fatalf("disabled logger wrote %q", data)
+	}
+
+	// 之后创建的logger同样被禁用
+	if err := AddLogger(LogConfig{Name: "late", FileName: filepath.Join(dir, "late.log")}); err != nil {
+		t.Fatal(err)
+	}
+	GetLogger("late").Info("dropped")
+	if _, err := os.Stat(filepath.Join(dir, "late.log")); !os.IsNotExist(err) {
+		t.Fatalf("logger created after Disable should not create its file, stat: %v", err)
+	}
+}
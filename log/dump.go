@@ -0,0 +1,126 @@
+package log
+
+import (
+	"cmp"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math/rand/v2"
+	"unicode/utf8"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// defaultDumpMaxBytes 未设置max_bytes时序列化内容的大小上限
+const defaultDumpMaxBytes = 64 << 10
+
+// DumpConfig Dump记录请求体、响应体等大内容的配置，生产环境按比例抽样记录，避免日志量和开销失控
+type DumpConfig struct {
+	Rate     float64 `yaml:"rate" mapstructure:"rate"`           // 记录的比例，0~1，0表示只记录ForceDump标记的请求
+	MaxBytes int     `yaml:"max_bytes" mapstructure:"max_bytes"` // 序列化后的大小上限（字节），超出时截断，默认64KB
+	Level    string  `yaml:"level" mapstructure:"level"`         // 记录的级别，默认info
+}
+
+func validateDump(lc LogConfig) []error {
+	d := lc.Dump
+	var errs []error
+	if d.Rate < 0 || d.Rate > 1 {
+		errs = append(errs, fmt.Errorf("logger %s: dump: rate must be between 0 and 1, got %v", lc.Name, d.Rate))
+	}
+	if d.MaxBytes < 0 {
+		errs = append(errs, fmt.Errorf("logger %s: dump: max_bytes must not be negative", lc.Name))
+	}
+	if d.Level != "" && !isValidLevel(d.Level) {
+		errs = append(errs, fmt.Errorf("logger %s: dump: level: %w", lc.Name, invalidLevel(d.Level)))
+	}
+	return errs
+}
+
+type dumpForcedKey struct{}
+
+// ForceDump 标记ctx中的请求，DumpContext对其始终记录，不受rate抽样影响。
+// 中间件配置WithDumpHeader时，带有该header的请求会自动标记
+func ForceDump(ctx context.Context) context.Context {
+	return context.WithValue(ctx, dumpForcedKey{}, true)
+}
+
+func dumpForced(ctx context.Context) bool {
+	forced, _ := ctx.Value(dumpForcedKey{}).(bool)
+	return forced
+}
+
+// Dump 按logger的dump.rate抽样记录obj，如log.Dump("access", "request", req)。
+// []byte和string原样记录(非UTF-8的[]byte记录为base64)，其他类型序列化为json，
+// 未抽中时不序列化，开销仅为一次随机数
+func Dump(name, key string, obj any) {
+	dump(context.Background(), name, key, obj)
+}
+
+// DumpContext 与Dump相同，ctx经ForceDump标记时始终记录，并附加ctx上的字段
+func DumpContext(ctx context.Context, name, key string, obj any) {
+	dump(ctx, name, key, obj)
+}
+
+func dump(ctx context.Context, name, key string, obj any) {
+	metux.RLock()
+	entry, ok := loggers[name]
+	var cfg DumpConfig
+	if ok {
+		cfg = entry.cfg.Dump
+	}
+	metux.RUnlock()
+	if !ok {
+		return
+	}
+	forced := dumpForced(ctx)
+	if !forced && (cfg.Rate <= 0 || rand.Float64() >= cfg.Rate) {
+		return
+	}
+	level := zapcore.InfoLevel
+	if cfg.Level != "" {
+		level = getLevel(cfg.Level)
+	}
+	ce := FromContext(ctx, name).WithOptions(zap.AddCallerSkip(2)).Check(level, "dump")
+	if ce == nil {
+		return
+	}
+	payload, size := dumpPayload(obj, cmp.Or(cfg.MaxBytes, defaultDumpMaxBytes))
+	fields := []zap.Field{zap.String(key, payload), zap.Int("dump_bytes", size)}
+	if forced {
+		fields = append(fields, zap.Bool("dump_forced", true))
+	}
+	ce.Write(fields...)
+}
+
+// dumpPayload 返回序列化后的内容(超出limit时截断)及截断前的字节数
+func dumpPayload(obj any, limit int) (string, int) {
+	var s string
+	switch v := obj.(type) {
+	case string:
+		s = v
+	case []byte:
+		if utf8.Valid(v) {
+			s = string(v)
+		} else {
+			s = base64.StdEncoding.EncodeToString(v)
+		}
+	default:
+		data, err := json.Marshal(v)
+		if err != nil {
+			s = fmt.Sprintf("%+v", v)
+		} else {
+			s = string(data)
+		}
+	}
+	size := len(s)
+	if size <= limit {
+		return s, size
+	}
+	keep := max(limit-len(truncatedSuffix), 0)
+	for keep > 0 && !utf8.RuneStart(s[keep]) {
+		keep--
+	}
+	return s[:keep] + truncatedSuffix, size
+}
@@ -0,0 +1,73 @@
+package log
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func setDumpConfig(name string, cfg DumpConfig) {
+	metux.Lock()
+	loggers[name].cfg.Dump = cfg
+	metux.Unlock()
+}
+
+func TestDump(t *testing.T) {
+	logs := observeLogger(t, "access")
+
+	Dump("access", "request", map[string]string{"user": "bob"})
+	if logs.Len() != 0 {
+		t.Fatal("rate 0 should only dump forced requests")
+	}
+	DumpContext(ForceDump(context.Background()), "access", "request", map[string]string{"user": "bob"})
+	entries := logs.TakeAll()
+	if len(entries) != 1 || entries[0].Message != "dump" {
+		t.Fatalf("unexpected entries %+v", entries)
+	}
+	fields := entries[0].ContextMap()
+	if fields["request"] != `{"user":"bob"}` || fields["dump_forced"] != true {
+		t.Fatalf("unexpected fields %v", fields)
+	}
+
+	setDumpConfig("access", DumpConfig{Rate: 1, MaxBytes: 20, Level: "debug"})
+	Dump("access", "body", strings.Repeat("a", 100))
+	Dump("access", "raw", []byte{0xff, 0xfe})
+	entries = logs.TakeAll()
+	if len(entries) != 2 || entries[0].Level.String() != "debug" {
+		t.Fatalf("unexpected entries %+v", entries)
+	}
+	if body := entries[0].ContextMap()["body"].(string); len(body) != 20 || !strings.HasSuffix(body, truncatedSuffix) {
+		t.Fatalf("unexpected body %q", body)
+	}
+	if entries[0].ContextMap()["dump_bytes"] != int64(100) || entries[1].ContextMap()["raw"] != "//4=" {
+		t.Fatalf("unexpected fields %v %v", entries[0].ContextMap(), entries[1].ContextMap())
+	}
+
+	Dump("missing", "body", "x")
+	errs := validateDump(LogConfig{Name: "access", Dump: DumpConfig{Rate: 2, MaxBytes: -1, Level: "loud"}})
+	if len(errs) != 3 {
+		t.Fatalf("expected 3 errors, got %v", errs)
+	}
+}
+
+func TestDumpHeader(t *testing.T) {
+	initTestLoggers(t, "access")
+	logs := observeLogger(t, "access")
+
+	handler := HTTPMiddleware("default", WithDumpHeader("X-Debug-Dump"))(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		DumpContext(r.Context(), "access", "body", "payload")
+	}))
+	for _, value := range []string{"", "0", "1"} {
+		req := httptest.NewRequest(http.MethodPost, "/orders", nil)
+		if value != "" {
+			req.Header.Set("X-Debug-Dump", value)
+		}
+		handler.ServeHTTP(httptest.NewRecorder(), req)
+	}
+	entries := logs.TakeAll()
+	if len(entries) != 1 || entries[0].ContextMap()["body"] != "payload" {
+		t.Fatalf("unexpected entries %+v", entries)
+	}
+}
@@ -0,0 +1,71 @@
+package log
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"go.uber.org/zap"
+)
+
+// AddLogger 在初始化之后新增logger，如多租户平台按租户或任务创建独立的日志文件。
+// 未设置的配置项继承Init时的defaults，名称已存在时返回错误
+func AddLogger(cfg LogConfig) error {
+	_, err := addLogger(cfg, "")
+	return err
+}
+
+// addLogger 创建并注册logger，template为创建该logger的模板名
+func addLogger(cfg LogConfig, template string) (*zap.Logger, error) {
+	if cfg.Name == "" {
+		return nil, errors.New("logger name is required")
+	}
+
+	metux.RLock()
+	c := Config{Defaults: logDefaults, Zaplog: []LogConfig{cfg}}
+	metux.RUnlock()
+	migrateConfig(&c)
+	applyDefaults(&c)
+	lc := c.Zaplog[0]
+	if err := errors.Join(validateLogConfig(lc, make(map[string]bool))...); err != nil {
+		return nil, err
+	}
+
+	metux.Lock()
+	defer metux.Unlock()
+
+	if _, ok := loggers[lc.Name]; ok {
+		return nil, fmt.Errorf("logger %s already exists", lc.Name)
+	}
+	entry, err := newLogger(lc)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create logger %s: %w", lc.Name, err)
+	}
+	entry.template = template
+	loggers[lc.Name] = entry
+	if lc.Name == "default" {
+		zap.ReplaceGlobals(entry.logger)
+	}
+	return entry.logger, nil
+}
+
+// RemoveLogger 移除logger，同步后关闭其日志文件和sink，返回同步或关闭时的错误。
+// 之后GetLogger(name)返回default logger；default logger只能通过Close关闭
+func RemoveLogger(name string) error {
+	if name == "default" {
+		return errors.New("default logger cannot be removed")
+	}
+
+	metux.Lock()
+	entry, ok := loggers[name]
+	delete(loggers, name)
+	metux.Unlock()
+	if !ok {
+		return fmt.Errorf("logger %s not found", name)
+	}
+
+	stopDebugWindow(entry)
+	ctx, cancel := context.WithTimeout(context.Background(), closeTimeout)
+	defer cancel()
+	return errors.Join(closeEntry(ctx, name, entry)...)
+}
@@ -0,0 +1,73 @@
+package log
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+func TestAddRemoveLogger(t *testing.T) {
+	dir := t.TempDir()
+	config := "defaults:\n  level: warn\n  encoder: json\n  directory: " + dir + "\nzaplog:\n  - name: default\n"
+	configPath := filepath.Join(dir, "log_config.yaml")
+	if err := os.WriteFile(configPath, []byte(config), 0644); err != nil {
+		t.Fatal(err)
+	}
+	Close()
+	if err := Init(WithConfigFile(configPath)); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(Close)
+
+	if err := AddLogger(LogConfig{Name: "tenant-a"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := AddLogger(LogConfig{Name: "tenant-a"}); err == nil || !strings.Contains(err.Error(), "already exists") {
+		t.Fatalf("expected duplicate error, got %v", err)
+	}
+	if err := AddLogger(LogConfig{Name: "tenant-b", Level: "loud"}); err == nil {
+		t.Fatal("expected invalid level error")
+	}
+
+	// 继承defaults中的级别、编码与目录
+	logger := GetLogger("tenant-a")
+	if logger.Core().Enabled(zapcore.InfoLevel) {
+		t.Fatal("tenant-a should inherit the default warn level")
+	}
+	logger.Warn("quota exceeded")
+	if err := EnableDebugFor("tenant-a", time.Hour); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := RemoveLogger("tenant-a"); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(filepath.Join(dir, "tenant-a.log"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), `"msg":"quota exceeded"`) {
+		t.Fatalf("unexpected tenant log %q", data)
+	}
+	if GetLogger("tenant-a") != GetDefaultLogger() {
+		t.Fatal("removed logger should fall back to default")
+	}
+	if err := RemoveLogger("tenant-a"); err == nil {
+		t.Fatal("expected not found error")
+	}
+	if err := RemoveLogger("default"); err == nil {
+		t.Fatal("default logger should not be removable")
+	}
+
+	// 移除后可以重新创建
+	if err := AddLogger(LogConfig{Name: "tenant-a", Level: "info"}); err != nil {
+		t.Fatal(err)
+	}
+	if !GetLogger("tenant-a").Core().Enabled(zapcore.InfoLevel) {
+		t.Fatal("re-added logger should use its own level")
+	}
+}
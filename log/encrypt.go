@@ -0,0 +1,363 @@
+package log
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	"gopkg.in/natefinch/lumberjack.v2"
+)
+
+// EncryptionConfig 切割后的备份文件以AES-GCM加密的配置，密钥按key、key_env、key_provider的顺序取第一个非空的来源。
+// 加密后的文件以.enc结尾，原文件删除，可通过DecryptBackup解密
+type EncryptionConfig struct {
+	Key         string         `yaml:"key" mapstructure:"key"`                   // base64编码的16、24或32字节密钥
+	KeyEnv      string         `yaml:"key_env" mapstructure:"key_env"`           // 保存base64密钥的环境变量名
+	KeyProvider string         `yaml:"key_provider" mapstructure:"key_provider"` // 通过RegisterKeyProvider注册的密钥来源，如KMS
+	Options     map[string]any `yaml:"options" mapstructure:"options"`           // 传给KeyProvider的参数
+	Interval    time.Duration  `yaml:"interval" mapstructure:"interval"`         // 扫描间隔，默认1m
+}
+
+func (c EncryptionConfig) enabled() bool {
+	return c.Key != "" || c.KeyEnv != "" || c.KeyProvider != ""
+}
+
+// KeyProvider 根据logger名称和options返回加密密钥，用于从KMS等外部系统获取密钥
+type KeyProvider func(logger string, options map[string]any) ([]byte, error)
+
+var keyProviders = make(map[string]KeyProvider)
+
+// RegisterKeyProvider 注册密钥来源，配置中通过encryption.key_provider: <name>引用。
+// 需在Init之前调用，重复注册时覆盖原有实现
+func RegisterKeyProvider(name string, provider KeyProvider) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	keyProviders[strings.ToLower(name)] = provider
+}
+
+func lookupKeyProvider(name string) (KeyProvider, bool) {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	p, ok := keyProviders[strings.ToLower(name)]
+	return p, ok
+}
+
+const (
+	encryptedSuffix        = ".enc"
+	encryptionMagic        = "GOEASYE1"
+	encryptChunkSize       = 64 << 10
+	encryptNoncePrefix     = 8
+	defaultEncryptInterval = time.Minute
+)
+
+// encryptionKey 按配置取得密钥
+func encryptionKey(logger string, c EncryptionConfig) ([]byte, error) {
+	var (
+		key []byte
+		err error
+	)
+	switch {
+	case c.Key != "":
+		key, err = base64.StdEncoding.DecodeString(c.Key)
+	case c.KeyEnv != "":
+		v := os.Getenv(c.KeyEnv)
+		if v == "" {
+			return nil, fmt.Errorf("environment variable %s is empty", c.KeyEnv)
+		}
+		key, err = base64.StdEncoding.DecodeString(v)
+	default:
+		provider, ok := lookupKeyProvider(c.KeyProvider)
+		if !ok {
+			return nil, fmt.Errorf("unknown key_provider %q", c.KeyProvider)
+		}
+		key, err = provider(logger, c.Options)
+	}
+	if err != nil {
+		return nil, err
+	}
+	switch len(key) {
+	case 16, 24, 32:
+		return key, nil
+	}
+	return nil, fmt.Errorf("invalid key length %d, must be 16, 24 or 32 bytes", len(key))
+}
+
+func validateEncryption(lc LogConfig) []error {
+	c := lc.Encryption
+	if !c.enabled() {
+		return nil
+	}
+	var errs []error
+	if c.Key != "" {
+		if _, err := encryptionKey(lc.Name, EncryptionConfig{Key: c.Key}); err != nil {
+			errs = append(errs, fmt.Errorf("logger %s: encryption: %w", lc.Name, err))
+		}
+	}
+	if c.KeyProvider != "" {
+		if _, ok := lookupKeyProvider(c.KeyProvider); !ok {
+			errs = append(errs, fmt.Errorf("logger %s: encryption: unknown key_provider %q", lc.Name, c.KeyProvider))
+		}
+	}
+	if c.Interval < 0 {
+		errs = append(errs, fmt.Errorf("logger %s: encryption: interval must not be negative", lc.Name))
+	}
+	return errs
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// encryptStream 以分块AES-GCM加密r写入w。格式为：魔数 | 8字节随机nonce前缀 | 若干块(4字节密文长度 | 密文)，
+// 每块的nonce为前缀加块序号，附加数据标记是否为最后一块，截断或调换顺序都会导致解密失败
+func encryptStream(w io.Writer, r io.Reader, key []byte) error {
+	aead, err := newGCM(key)
+	if err != nil {
+		return err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce[:encryptNoncePrefix]); err != nil {
+		return err
+	}
+	if _, err := io.WriteString(w, encryptionMagic); err != nil {
+		return err
+	}
+	if _, err := w.Write(nonce[:encryptNoncePrefix]); err != nil {
+		return err
+	}
+
+	br := bufio.NewReaderSize(r, encryptChunkSize)
+	buf := make([]byte, encryptChunkSize)
+	var out []byte
+	for counter := uint32(0); ; counter++ {
+		n, err := io.ReadFull(br, buf)
+		if err != nil && err != io.ErrUnexpectedEOF && err != io.EOF {
+			return err
+		}
+		_, peekErr := br.Peek(1)
+		final := peekErr != nil
+		binary.BigEndian.PutUint32(nonce[encryptNoncePrefix:], counter)
+		out = aead.Seal(out[:0], nonce, buf[:n], chunkAD(final))
+
+		var size [4]byte
+		binary.BigEndian.PutUint32(size[:], uint32(len(out)))
+		if _, err := w.Write(size[:]); err != nil {
+			return err
+		}
+		if _, err := w.Write(out); err != nil {
+			return err
+		}
+		if final {
+			return nil
+		}
+	}
+}
+
+func chunkAD(final bool) []byte {
+	if final {
+		return []byte{1}
+	}
+	return []byte{0}
+}
+
+// DecryptBackup 解密由encryption配置加密的备份文件，r为.enc文件内容，明文写入w
+func DecryptBackup(w io.Writer, r io.Reader, key []byte) error {
+	aead, err := newGCM(key)
+	if err != nil {
+		return err
+	}
+	header := make([]byte, len(encryptionMagic)+encryptNoncePrefix)
+	if _, err := io.ReadFull(r, header); err != nil {
+		return fmt.Errorf("read header: %w", err)
+	}
+	if !bytes.Equal(header[:len(encryptionMagic)], []byte(encryptionMagic)) {
+		return errors.New("not an encrypted log file")
+	}
+	nonce := make([]byte, aead.NonceSize())
+	copy(nonce, header[len(encryptionMagic):])
+
+	br := bufio.NewReader(r)
+	var out []byte
+	for counter := uint32(0); ; counter++ {
+		var size [4]byte
+		if _, err := io.ReadFull(br, size[:]); err != nil {
+			return fmt.Errorf("chunk %d: %w", counter, io.ErrUnexpectedEOF)
+		}
+		n := binary.BigEndian.Uint32(size[:])
+		if n > encryptChunkSize+uint32(aead.Overhead()) {
+			return fmt.Errorf("chunk %d: invalid size %d", counter, n)
+		}
+		chunk := make([]byte, n)
+		if _, err := io.ReadFull(br, chunk); err != nil {
+			return fmt.Errorf("chunk %d: %w", counter, io.ErrUnexpectedEOF)
+		}
+		_, peekErr := br.Peek(1)
+		final := peekErr != nil
+		binary.BigEndian.PutUint32(nonce[encryptNoncePrefix:], counter)
+		if out, err = aead.Open(out[:0], nonce, chunk, chunkAD(final)); err != nil {
+			return fmt.Errorf("chunk %d: %w", counter, err)
+		}
+		if _, err := w.Write(out); err != nil {
+			return err
+		}
+		if final {
+			return nil
+		}
+	}
+}
+
+// encryptor 定期加密某个logger切割出的备份文件
+type encryptor struct {
+	logger   string
+	files    []*lumberjack.Logger // 日志文件，按级别分文件时为各分片
+	suffix   string               // 待加密文件的后缀，开启压缩时为.gz
+	key      []byte
+	interval time.Duration
+
+	mu       sync.Mutex // 串行执行run
+	stopOnce sync.Once
+	done     chan struct{}
+}
+
+func newEncryptor(cfg LogConfig, files ...*lumberjack.Logger) (*encryptor, error) {
+	key, err := encryptionKey(cfg.Name, cfg.Encryption)
+	if err != nil {
+		return nil, fmt.Errorf("encryption: %w", err)
+	}
+	suffix := filepath.Ext(cfg.FileName)
+	if cfg.Compress {
+		suffix = ".gz"
+	}
+	interval := cfg.Encryption.Interval
+	if interval == 0 {
+		interval = defaultEncryptInterval
+	}
+	return &encryptor{
+		logger:   cfg.Name,
+		files:    files,
+		suffix:   suffix,
+		key:      key,
+		interval: interval,
+		done:     make(chan struct{}),
+	}, nil
+}
+
+func (e *encryptor) start() {
+	go func() {
+		ticker := time.NewTicker(e.interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-e.done:
+				return
+			case <-ticker.C:
+				_ = e.run()
+			}
+		}
+	}()
+}
+
+func (e *encryptor) stop() {
+	e.stopOnce.Do(func() { close(e.done) })
+}
+
+// run 加密所有待加密的备份，返回第一个错误
+func (e *encryptor) run() error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	var first error
+	for _, f := range e.files {
+		for _, path := range rotatedFiles(f.Filename, e.suffix) {
+			if err := e.encryptFile(path); err != nil {
+				diagnostics.Warn("failed to encrypt log file", zap.String("logger", e.logger), zap.String("file", path), zap.Error(err))
+				if first == nil {
+					first = err
+				}
+			}
+		}
+		if err := pruneEncrypted(f, time.Now()); err != nil && first == nil {
+			first = err
+		}
+	}
+	return first
+}
+
+// pruneEncrypted 按max_backups和max_age清理加密后的备份。lumberjack只清理.log和.log.gz，
+// 改名为.enc的备份由这里清理，max_backups同时计入尚未加密的备份
+func pruneEncrypted(f *lumberjack.Logger, now time.Time) error {
+	if f.MaxBackups <= 0 && f.MaxAge <= 0 {
+		return nil
+	}
+	backups := rotatedFiles(f.Filename, "")
+	cutoff := now.Add(-time.Duration(f.MaxAge) * 24 * time.Hour)
+	var errs []error
+	for i, path := range backups {
+		if !strings.HasSuffix(path, encryptedSuffix) {
+			continue
+		}
+		rotated, _ := backupTime(f.Filename, filepath.Base(path))
+		// backups按切割时间升序排列
+		if f.MaxBackups > 0 && len(backups)-i > f.MaxBackups || f.MaxAge > 0 && rotated.Before(cutoff) {
+			if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+				errs = append(errs, err)
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// encryptFile 加密到临时文件，落盘后改名为.enc并删除原文件
+func (e *encryptor) encryptFile(path string) error {
+	src, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	info, err := src.Stat()
+	if err != nil {
+		return err
+	}
+	tmp := path + encryptedSuffix + ".tmp"
+	dst, err := os.OpenFile(tmp, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, info.Mode().Perm())
+	if err != nil {
+		return err
+	}
+	err = encryptStream(dst, src, e.key)
+	if err == nil {
+		err = dst.Sync()
+	}
+	if cerr := dst.Close(); err == nil {
+		err = cerr
+	}
+	if err == nil {
+		// 保留切割时间，归档时按修改时间生成日期
+		err = os.Chtimes(tmp, info.ModTime(), info.ModTime())
+	}
+	if err == nil {
+		err = os.Rename(tmp, path+encryptedSuffix)
+	}
+	if err != nil {
+		_ = os.Remove(tmp)
+		return err
+	}
+	return os.Remove(path)
+}
@@ -0,0 +1,164 @@
+package log
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/base64"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"gopkg.in/natefinch/lumberjack.v2"
+)
+
+func TestEncryptStream(t *testing.T) {
+	key := bytes.Repeat([]byte{7}, 32)
+	for _, size := range []int{0, 10, encryptChunkSize, 3*encryptChunkSize + 17} {
+		plain := make([]byte, size)
+		_, _ = rand.Read(plain)
+
+		var enc bytes.Buffer
+		if err := encryptStream(&enc, bytes.NewReader(plain), key); err != nil {
+			t.Fatal(err)
+		}
+		var dec bytes.Buffer
+		if err := DecryptBackup(&dec, bytes.NewReader(enc.Bytes()), key); err != nil {
+			t.Fatalf("size %d: %v", size, err)
+		}
+		if !bytes.Equal(dec.Bytes(), plain) {
+			t.Fatalf("size %d: round trip mismatch", size)
+		}
+	}
+
+	plain := bytes.Repeat([]byte("log line\n"), encryptChunkSize/4)
+	var enc bytes.Buffer
+	if err := encryptStream(&enc, bytes.NewReader(plain), key); err != nil {
+		t.Fatal(err)
+	}
+	data := enc.Bytes()
+
+	tampered := bytes.Clone(data)
+	tampered[len(tampered)/2] ^= 1
+	truncated := data[:len(encryptionMagic)+encryptNoncePrefix+4+encryptChunkSize+16]
+	wrongKey := bytes.Repeat([]byte{8}, 32)
+	for name, tc := range map[string]struct {
+		data []byte
+		key  []byte
+	}{
+		"tampered":  {tampered, key},
+		"truncated": {truncated, key},
+		"wrong key": {data, wrongKey},
+		"plain":     {plain, key},
+	} {
+		if err := DecryptBackup(&bytes.Buffer{}, bytes.NewReader(tc.data), tc.key); err == nil {
+			t.Fatalf("%s: expected decryption error", name)
+		}
+	}
+}
+
+func TestEncryptor(t *testing.T) {
+	key := bytes.Repeat([]byte{1}, 16)
+	RegisterKeyProvider("test-kms", func(logger string, options map[string]any) ([]byte, error) {
+		if logger != "app" || options["key_id"] != "k1" {
+			t.Errorf("unexpected provider args %s %v", logger, options)
+		}
+		return key, nil
+	})
+
+	dir := t.TempDir()
+	e, err := newEncryptor(LogConfig{
+		Name:       "app",
+		FileName:   filepath.Join(dir, "app.log"),
+		Compress:   true,
+		Encryption: EncryptionConfig{KeyProvider: "test-kms", Options: map[string]any{"key_id": "k1"}},
+	}, &lumberjack.Logger{Filename: filepath.Join(dir, "app.log")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	done := filepath.Join(dir, "app-2024-01-02T03-04-05.000.log.gz")
+	compressing := filepath.Join(dir, "app-2024-01-03T03-04-05.000.log.gz")
+	rotated := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	for _, path := range []string{done, compressing, strings.TrimSuffix(compressing, ".gz")} {
+		writeSized(t, path, 100, rotated)
+	}
+	if err := os.WriteFile(done, []byte("compressed backup"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chmod(done, 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chtimes(done, rotated, rotated); err != nil {
+		t.Fatal(err)
+	}
+	if err := e.run(); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := os.Stat(done); !os.IsNotExist(err) {
+		t.Fatal("plaintext backup should be removed")
+	}
+	if _, err := os.Stat(compressing); err != nil {
+		t.Fatal("file still being compressed should be skipped")
+	}
+	f, err := os.Open(done + encryptedSuffix)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	if info, _ := f.Stat(); !info.ModTime().Equal(rotated) || info.Mode().Perm() != 0600 {
+		t.Fatalf("encrypted file should keep mode and rotation time, got %v %v", info.Mode(), info.ModTime())
+	}
+	var dec bytes.Buffer
+	if err := DecryptBackup(&dec, f, key); err != nil || dec.String() != "compressed backup" {
+		t.Fatalf("unexpected decrypted content %q: %v", dec.String(), err)
+	}
+}
+
+func TestPruneEncrypted(t *testing.T) {
+	dir := t.TempDir()
+	f := &lumberjack.Logger{Filename: filepath.Join(dir, "app.log"), MaxBackups: 2, MaxAge: 7}
+	now := time.Date(2024, 1, 20, 0, 0, 0, 0, time.UTC)
+	backup := func(day int, suffix string) string {
+		return filepath.Join(dir, "app-"+time.Date(2024, 1, day, 0, 0, 0, 0, time.UTC).Format(backupTimeFormat)+".log"+suffix)
+	}
+	expired := backup(10, ".gz.enc")
+	oldest := backup(15, ".gz.enc")
+	kept := backup(18, ".gz.enc")
+	plain := backup(19, ".gz")
+	other := filepath.Join(dir, "app-access-"+now.Format(backupTimeFormat)+".log.gz.enc")
+	for _, path := range []string{expired, oldest, kept, plain, other} {
+		writeSized(t, path, 10, now)
+	}
+
+	if err := pruneEncrypted(f, now); err != nil {
+		t.Fatal(err)
+	}
+	for path, want := range map[string]bool{expired: false, oldest: false, kept: true, plain: true, other: true} {
+		if _, err := os.Stat(path); (err == nil) != want {
+			t.Errorf("%s: exists=%v, want %v", filepath.Base(path), err == nil, want)
+		}
+	}
+}
+
+func TestEncryptionConfig(t *testing.T) {
+	t.Setenv("TEST_LOG_KEY", base64.StdEncoding.EncodeToString(bytes.Repeat([]byte{2}, 24)))
+	if key, err := encryptionKey("app", EncryptionConfig{KeyEnv: "TEST_LOG_KEY"}); err != nil || len(key) != 24 {
+		t.Fatalf("unexpected env key %v %v", key, err)
+	}
+
+	cfg := Config{Zaplog: []LogConfig{{
+		Name:       "default",
+		FileName:   "a.log",
+		Encryption: EncryptionConfig{Key: base64.StdEncoding.EncodeToString([]byte("short"))},
+	}, {
+		Name:       "audit",
+		FileName:   "b.log",
+		Encryption: EncryptionConfig{KeyProvider: "missing"},
+	}}}
+	err := validateConfig(&cfg)
+	if err == nil || !strings.Contains(err.Error(), "invalid key length 5") || !strings.Contains(err.Error(), `unknown key_provider "missing"`) {
+		t.Fatalf("expected encryption errors, got %v", err)
+	}
+}
@@ -0,0 +1,66 @@
+package log
+
+import (
+	"os"
+	"reflect"
+	"strings"
+)
+
+// EnvPrefix 环境变量覆盖配置的前缀
+const EnvPrefix = "GOEASY_LOG"
+
+// envKey 返回logger配置项对应的环境变量名，如GOEASY_LOG_ACCESS_LEVEL，名称中的非字母数字字符替换为下划线
+func envKey(parts ...string) string {
+	key := strings.Join(append([]string{EnvPrefix}, parts...), "_")
+	return strings.ToUpper(strings.Map(func(r rune) rune {
+		if r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' {
+			return r
+		}
+		return '_'
+	}, key))
+}
+
+// scalarKeys 返回结构体中字符串、数字、布尔类型字段的mapstructure标签
+func scalarKeys(t reflect.Type) []string {
+	var keys []string
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := f.Tag.Get("mapstructure")
+		if tag == "" || tag == "-" {
+			continue
+		}
+		switch f.Type.Kind() {
+		case reflect.String, reflect.Bool, reflect.Int, reflect.Int64, reflect.Float64:
+			keys = append(keys, tag)
+		}
+	}
+	return keys
+}
+
+// applyEnvOverrides 以GOEASY_LOG_<NAME>_<KEY>形式的环境变量覆盖同名logger的配置，如GOEASY_LOG_ACCESS_LEVEL、
+// GOEASY_LOG_ACCESS_FILE_NAME。顶层配置如GOEASY_LOG_PANIC_FILE由config.WithEnvPrefix覆盖
+func applyEnvOverrides(settings map[string]any) {
+	items, ok := settings["zaplog"].([]any)
+	if !ok {
+		return
+	}
+	keys := scalarKeys(reflect.TypeOf(LogConfig{}))
+	for _, item := range items {
+		lc, ok := item.(map[string]any)
+		if !ok {
+			continue
+		}
+		name, _ := lc["name"].(string)
+		if name == "" {
+			continue
+		}
+		for _, key := range keys {
+			if key == "name" {
+				continue
+			}
+			if value, ok := os.LookupEnv(envKey(name, key)); ok {
+				lc[key] = value
+			}
+		}
+	}
+}
@@ -0,0 +1,51 @@
+package log
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestEnvOverrides(t *testing.T) {
+	dir := t.TempDir()
+	configPath := filepath.Join(dir, "log_config.yaml")
+	config := `zaplog:
+  - name: default
+    level: info
+    file_name: ./logs/app.log
+  - name: slow-sql
+    level: info
+    file_name: ./logs/sql.log
+disk_quota:
+  max_dir_mb: ${env:LOG_QUOTA_MB}
+`
+	if err := os.WriteFile(configPath, []byte(config), 0644); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("GOEASY_LOG_DEFAULT_LEVEL", "debug")
+	t.Setenv("GOEASY_LOG_SLOW_SQL_FILE_NAME", "/var/log/sql.log")
+	t.Setenv("GOEASY_LOG_SLOW_SQL_MAX_SIZE", "20")
+	t.Setenv("GOEASY_LOG_SLOW_SQL_COMPRESS", "true")
+	t.Setenv("GOEASY_LOG_PANIC_FILE", "/var/log/panic.log")
+	// 与config.Load规则一致：嵌套的配置项及占位符
+	t.Setenv("GOEASY_LOG_DISK_QUOTA_MIN_FREE_MB", "512")
+	t.Setenv("LOG_QUOTA_MB", "2048")
+
+	cfg, err := LoadConfig(configPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Zaplog[0].Level != "debug" {
+		t.Fatalf("expected default level override, got %s", cfg.Zaplog[0].Level)
+	}
+	sql := cfg.Zaplog[1]
+	if sql.FileName != "/var/log/sql.log" || sql.MaxSize != 20 || !sql.Compress || sql.Level != "info" {
+		t.Fatalf("unexpected slow-sql config %+v", sql)
+	}
+	if cfg.PanicFile != "/var/log/panic.log" {
+		t.Fatalf("expected panic_file override, got %s", cfg.PanicFile)
+	}
+	if cfg.DiskQuota.MaxDirMB != 2048 || cfg.DiskQuota.MinFreeMB != 512 {
+		t.Fatalf("unexpected disk_quota %+v", cfg.DiskQuota)
+	}
+}
@@ -0,0 +1,119 @@
+package log
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"runtime"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// maxErrChain 展开错误链的最大层数，防止异常实现导致的循环
+const maxErrChain = 32
+
+// Err 返回描述err的字段，除error外还输出error_type，以及逐层展开的error_chain：
+// 每一层的错误信息、类型和pkg/errors风格错误携带的堆栈。errors.Join合并的错误按深度优先展开。
+// err为nil时不输出任何字段
+func Err(err error) zap.Field {
+	if err == nil {
+		return zap.Skip()
+	}
+	return zap.Inline(errField{err})
+}
+
+type errField struct {
+	err error
+}
+
+func (e errField) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	enc.AddString("error", e.err.Error())
+	enc.AddString("error_type", fmt.Sprintf("%T", e.err))
+
+	chain := unwrapChain(e.err)
+	if len(chain) > 1 || chain[0].stack != nil {
+		return enc.AddArray("error_chain", chain)
+	}
+	return nil
+}
+
+// errCause 错误链中的一层
+type errCause struct {
+	err   error
+	stack stackFrames
+}
+
+func (c errCause) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	enc.AddString("error", c.err.Error())
+	enc.AddString("type", fmt.Sprintf("%T", c.err))
+	if c.stack != nil {
+		return enc.AddArray("stack", c.stack)
+	}
+	return nil
+}
+
+type errChain []errCause
+
+func (c errChain) MarshalLogArray(enc zapcore.ArrayEncoder) error {
+	for _, cause := range c {
+		if err := enc.AppendObject(cause); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// unwrapChain 按深度优先展开err的包装链
+func unwrapChain(err error) errChain {
+	var chain errChain
+	var walk func(err error)
+	walk = func(err error) {
+		if err == nil || len(chain) >= maxErrChain {
+			return
+		}
+		chain = append(chain, errCause{err: err, stack: errStack(err)})
+		switch x := err.(type) {
+		case interface{ Unwrap() []error }:
+			for _, e := range x.Unwrap() {
+				walk(e)
+			}
+		default:
+			walk(errors.Unwrap(err))
+		}
+	}
+	walk(err)
+	return chain
+}
+
+// errStack 返回err自身携带的堆栈，支持pkg/errors的StackTrace() errors.StackTrace，
+// 通过反射识别以免引入依赖
+func errStack(err error) stackFrames {
+	m := reflect.ValueOf(err).MethodByName("StackTrace")
+	if !m.IsValid() || m.Type().NumIn() != 0 || m.Type().NumOut() != 1 {
+		return nil
+	}
+	out := m.Type().Out(0)
+	if out.Kind() != reflect.Slice || out.Elem().Kind() != reflect.Uintptr {
+		return nil
+	}
+	trace := m.Call(nil)[0]
+	if trace.Len() == 0 {
+		return nil
+	}
+	// pkg/errors的Frame即runtime.Callers返回的程序计数器
+	pcs := make([]uintptr, trace.Len())
+	for i := range pcs {
+		pcs[i] = uintptr(trace.Index(i).Uint())
+	}
+	frames := runtime.CallersFrames(pcs)
+	stack := stackFrames{}
+	for {
+		frame, more := frames.Next()
+		stack = append(stack, stackFrame{Function: frame.Function, File: frame.File, Line: frame.Line})
+		if !more {
+			break
+		}
+	}
+	return stack
+}
@@ -0,0 +1,84 @@
+package log
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"runtime"
+	"strings"
+	"testing"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// stackErr 模拟pkg/errors携带堆栈的错误
+type frame uintptr
+
+type stackTrace []frame
+
+type stackErr struct {
+	msg   string
+	stack []uintptr
+}
+
+func newStackErr(msg string) *stackErr {
+	pcs := make([]uintptr, 8)
+	n := runtime.Callers(2, pcs)
+	return &stackErr{msg: msg, stack: pcs[:n]}
+}
+
+func (e *stackErr) Error() string { return e.msg }
+
+func (e *stackErr) StackTrace() stackTrace {
+	st := make(stackTrace, len(e.stack))
+	for i, pc := range e.stack {
+		st[i] = frame(pc)
+	}
+	return st
+}
+
+func encodeErr(t *testing.T, err error) map[string]any {
+	t.Helper()
+
+	enc := zapcore.NewMapObjectEncoder()
+	Err(err).AddTo(enc)
+	return enc.Fields
+}
+
+func TestErr(t *testing.T) {
+	if fields := encodeErr(t, nil); len(fields) != 0 {
+		t.Fatalf("nil error should be skipped, got %+v", fields)
+	}
+
+	fields := encodeErr(t, io.EOF)
+	if fields["error"] != "EOF" || fields["error_type"] != "*errors.errorString" || fields["error_chain"] != nil {
+		t.Fatalf("plain error should not expand a chain, got %+v", fields)
+	}
+
+	root := newStackErr("connection reset")
+	err := fmt.Errorf("query users: %w", errors.Join(root, io.ErrUnexpectedEOF))
+	fields = encodeErr(t, err)
+	if fields["error_type"] != "*fmt.wrapError" {
+		t.Fatalf("unexpected error_type %v", fields["error_type"])
+	}
+	chain := fields["error_chain"].([]any)
+	if len(chain) != 4 {
+		t.Fatalf("expected 4 causes, got %+v", chain)
+	}
+	var types []string
+	for _, c := range chain {
+		types = append(types, c.(map[string]any)["type"].(string))
+	}
+	if got := strings.Join(types, ","); got != "*fmt.wrapError,*errors.joinError,*log.stackErr,*errors.errorString" {
+		t.Fatalf("unexpected chain order %s", got)
+	}
+
+	cause := chain[2].(map[string]any)
+	stack, ok := cause["stack"].([]any)
+	if cause["error"] != "connection reset" || !ok || len(stack) == 0 {
+		t.Fatalf("stack of pkg/errors style errors should be expanded, got %+v", cause)
+	}
+	if top := stack[0].(map[string]any); !strings.Contains(top["func"].(string), "TestErr") {
+		t.Fatalf("top frame should be the creating function, got %+v", top)
+	}
+}
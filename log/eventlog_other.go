@@ -0,0 +1,13 @@
+//go:build !windows
+
+package log
+
+import (
+	"errors"
+
+	"go.uber.org/zap/zapcore"
+)
+
+func newEventLogSink(string, map[string]any) (zapcore.WriteSyncer, error) {
+	return nil, errors.New("eventlog sink is only supported on windows")
+}
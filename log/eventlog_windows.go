@@ -0,0 +1,70 @@
+//go:build windows
+
+package log
+
+import (
+	"fmt"
+	"strconv"
+	"syscall"
+	"unsafe"
+
+	"go.uber.org/zap/zapcore"
+)
+
+var (
+	advapi32                  = syscall.NewLazyDLL("advapi32.dll")
+	procRegisterEventSourceW  = advapi32.NewProc("RegisterEventSourceW")
+	procDeregisterEventSource = advapi32.NewProc("DeregisterEventSource")
+	procReportEventW          = advapi32.NewProc("ReportEventW")
+)
+
+// eventLogSink 写入Windows事件日志，error及以上为错误、warn为警告、其余为信息，事件内容为完整的日志行。
+// 事件源需由安装程序注册，未注册时事件查看器会提示找不到描述，但内容仍完整保留。
+// options：source(事件源，默认程序名)、event_id(默认1)、level_key、message_key、time_key
+type eventLogSink struct {
+	handle  uintptr
+	eventID uint32
+	keys    sysEntryKeys
+}
+
+func newEventLogSink(logger string, options map[string]any) (zapcore.WriteSyncer, error) {
+	source, err := syscall.UTF16PtrFromString(sinkString(options, "source", programName()))
+	if err != nil {
+		return nil, err
+	}
+	eventID, err := strconv.ParseUint(sinkString(options, "event_id", "1"), 10, 32)
+	if err != nil {
+		return nil, fmt.Errorf("invalid event_id: %w", err)
+	}
+	handle, _, err := procRegisterEventSourceW.Call(0, uintptr(unsafe.Pointer(source)))
+	if handle == 0 {
+		return nil, fmt.Errorf("register event source: %w", err)
+	}
+	return &eventLogSink{handle: handle, eventID: uint32(eventID), keys: newSysEntryKeys(options)}, nil
+}
+
+func (s *eventLogSink) Write(p []byte) (int, error) {
+	e := parseSysEntry(p, s.keys)
+	text, err := syscall.UTF16PtrFromString(string(trimLineEnding(p)))
+	if err != nil {
+		return 0, err
+	}
+	strs := []*uint16{text}
+	r, _, err := procReportEventW.Call(s.handle, uintptr(eventLogType(e.level)), 0, uintptr(s.eventID),
+		0, 1, 0, uintptr(unsafe.Pointer(&strs[0])), 0)
+	if r == 0 {
+		return 0, fmt.Errorf("report event: %w", err)
+	}
+	return len(p), nil
+}
+
+func (s *eventLogSink) Sync() error {
+	return nil
+}
+
+func (s *eventLogSink) Close() error {
+	if r, _, err := procDeregisterEventSource.Call(s.handle); r == 0 {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,69 @@
+package log
+
+import (
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+const eventTimeKey = "goeasy.event_time"
+
+// At 指定日志的事件时间，替换默认的写入时间，用于历史事件的回放/补录。
+// 通过With传入时对该logger后续的所有日志生效
+func At(t time.Time) zap.Field {
+	return zap.Field{Key: eventTimeKey, Type: zapcore.SkipType, Interface: t}
+}
+
+// eventTime 从fields中取出At指定的事件时间，返回剔除标记后的fields
+func eventTime(fields []zapcore.Field) (time.Time, []zapcore.Field, bool) {
+	for i, f := range fields {
+		if f.Type != zapcore.SkipType || f.Key != eventTimeKey {
+			continue
+		}
+		t, ok := f.Interface.(time.Time)
+		rest := make([]zapcore.Field, 0, len(fields)-1)
+		rest = append(rest, fields[:i]...)
+		rest = append(rest, fields[i+1:]...)
+		if !ok {
+			return time.Time{}, rest, false
+		}
+		return t, rest, true
+	}
+	return time.Time{}, fields, false
+}
+
+// eventTimeCore 将At指定的事件时间写入Entry.Time
+type eventTimeCore struct {
+	zapcore.Core
+	at time.Time
+}
+
+func newEventTimeCore(core zapcore.Core) zapcore.Core {
+	return &eventTimeCore{Core: core}
+}
+
+func (c *eventTimeCore) With(fields []zapcore.Field) zapcore.Core {
+	at := c.at
+	if t, rest, ok := eventTime(fields); ok {
+		at, fields = t, rest
+	}
+	return &eventTimeCore{Core: c.Core.With(fields), at: at}
+}
+
+func (c *eventTimeCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+func (c *eventTimeCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	if !c.at.IsZero() {
+		ent.Time = c.at
+	}
+	if t, rest, ok := eventTime(fields); ok {
+		ent.Time, fields = t, rest
+	}
+	return c.Core.Write(ent, fields)
+}
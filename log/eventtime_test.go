@@ -0,0 +1,35 @@
+package log
+
+import (
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestAt(t *testing.T) {
+	core, logs := observer.New(zapcore.DebugLevel)
+	logger := zap.New(newEventTimeCore(core))
+
+	at := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+	logger.Info("replay", At(at), zap.String("k", "v"))
+	logger.With(At(at.Add(time.Hour))).Info("backfill")
+	logger.Info("now")
+
+	entries := logs.AllUntimed()
+	got := logs.All()
+	if !got[0].Time.Equal(at) {
+		t.Fatalf("unexpected time %v", got[0].Time)
+	}
+	if len(entries[0].Context) != 1 || entries[0].Context[0].Key != "k" {
+		t.Fatalf("event time marker should be removed, got %v", entries[0].Context)
+	}
+	if !got[1].Time.Equal(at.Add(time.Hour)) {
+		t.Fatalf("unexpected time %v", got[1].Time)
+	}
+	if time.Since(got[2].Time) > time.Minute {
+		t.Fatalf("entry without At should keep write time, got %v", got[2].Time)
+	}
+}
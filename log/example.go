@@ -0,0 +1,34 @@
+package log
+
+import (
+	_ "embed"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+//go:embed example_config.yaml
+var exampleConfig []byte
+
+// ExampleConfig 返回带注释的完整示例配置，包含所有支持的配置项及推荐的默认值
+func ExampleConfig() []byte {
+	return append([]byte(nil), exampleConfig...)
+}
+
+// WriteExampleConfig 将示例配置写入path，自动创建所在目录，文件已存在时返回错误而不覆盖
+func WriteExampleConfig(path string) error {
+	if dir := filepath.Dir(path); dir != "." {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return err
+		}
+	}
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o644)
+	if err != nil {
+		return fmt.Errorf("failed to create example config: %w", err)
+	}
+	if _, err := f.Write(exampleConfig); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
@@ -0,0 +1,56 @@
+package log
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestWriteExampleConfig(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "conf", "log_config.yaml")
+	if err := WriteExampleConfig(path); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := LoadConfig(path)
+	if err != nil {
+		t.Fatalf("example config should be valid: %v", err)
+	}
+	if len(cfg.Zaplog) != 1 || cfg.Zaplog[0].Name != "default" || cfg.Zaplog[0].MaxBackups != 10 {
+		t.Fatalf("unexpected example config %+v", cfg.Zaplog)
+	}
+	// 已存在时不覆盖
+	if err := WriteExampleConfig(path); !errors.Is(err, os.ErrExist) {
+		t.Fatalf("expected ErrExist, got %v", err)
+	}
+}
+
+// TestExampleConfigCoversFields 新增配置项时需同步更新example_config.yaml
+func TestExampleConfigCoversFields(t *testing.T) {
+	example := string(ExampleConfig())
+	var check func(typ reflect.Type, path string)
+	check = func(typ reflect.Type, path string) {
+		for i := 0; i < typ.NumField(); i++ {
+			f := typ.Field(i)
+			key := strings.Split(f.Tag.Get("yaml"), ",")[0]
+			if key == "" || key == "-" {
+				continue
+			}
+			pattern := `(?m)^[ #]*(- )?` + regexp.QuoteMeta(key) + `:`
+			if !regexp.MustCompile(pattern).MatchString(example) {
+				t.Errorf("example config is missing %s%s", path, key)
+			}
+			ft := f.Type
+			for ft.Kind() == reflect.Slice || ft.Kind() == reflect.Pointer {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct && ft.PkgPath() == typ.PkgPath() {
+				check(ft, path+key+".")
+			}
+		}
+	}
+	check(reflect.TypeOf(Config{}), "")
+}
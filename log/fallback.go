@@ -0,0 +1,147 @@
+package log
+
+import (
+	"net/http"
+	"runtime/debug"
+	"sort"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// GinNoRoute 返回记录404请求的gin处理函数，附带与请求路径最接近的已注册路由，
+// 用于排查404突增。使用方式：engine.NoRoute(log.GinNoRoute("access", engine))
+func GinNoRoute(name string, engine *gin.Engine) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		routes := engine.Routes()
+		paths := make([]string, 0, len(routes))
+		for _, r := range routes {
+			paths = append(paths, r.Method+" "+r.Path)
+		}
+		GetLogger(name).Warn("http route not found",
+			zap.String("method", c.Request.Method),
+			zap.String("path", c.Request.URL.Path),
+			zap.String("nearest_route", nearestRoute(c.Request.Method+" "+c.Request.URL.Path, paths)),
+			zap.String("client_ip", c.ClientIP()),
+		)
+	}
+}
+
+// GinNoMethod 返回记录405请求的gin处理函数，附带该路径允许的方法，并设置Allow响应头。
+// 需开启engine.HandleMethodNotAllowed，使用方式：engine.NoMethod(log.GinNoMethod("access", engine))
+func GinNoMethod(name string, engine *gin.Engine) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var allowed []string
+		for _, r := range engine.Routes() {
+			if matchRoute(r.Path, c.Request.URL.Path) {
+				allowed = append(allowed, r.Method)
+			}
+		}
+		sort.Strings(allowed)
+		if len(allowed) > 0 {
+			c.Header("Allow", strings.Join(allowed, ", "))
+		}
+		GetLogger(name).Warn("http method not allowed",
+			zap.String("method", c.Request.Method),
+			zap.String("path", c.Request.URL.Path),
+			zap.Strings("allowed", allowed),
+			zap.String("client_ip", c.ClientIP()),
+		)
+	}
+}
+
+// NotFoundHandler 返回记录404请求的net/http处理器，routes为已注册的路由，用于给出最接近的路由
+func NotFoundHandler(name string, routes ...string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		GetLogger(name).Warn("http route not found",
+			zap.String("method", r.Method),
+			zap.String("path", r.URL.Path),
+			zap.String("nearest_route", nearestRoute(r.URL.Path, routes)),
+			zap.String("remote_addr", r.RemoteAddr),
+		)
+		http.NotFound(w, r)
+	})
+}
+
+// HTTPRecovery 返回net/http的panic恢复中间件，记录panic并返回500
+func HTTPRecovery(name string, stack bool) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			defer func() {
+				rec := recover()
+				if rec == nil {
+					return
+				}
+				if rec == http.ErrAbortHandler {
+					// 由net/http负责中断连接
+					panic(rec)
+				}
+
+				request := dumpRequest(r)
+				fields := []zap.Field{
+					zap.String("path", r.URL.Path),
+					zap.Any("error", rec),
+					zap.ByteString("request", request),
+				}
+				if stack {
+					fields = append(fields, zap.ByteString("stack", debug.Stack()))
+				}
+				GetLogger(name).Error("http panic recovered", fields...)
+				if !isBrokenPipe(rec) {
+					http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+				}
+			}()
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// nearestRoute 返回与target编辑距离最小的路由
+func nearestRoute(target string, routes []string) string {
+	best, bestDist := "", -1
+	for _, route := range routes {
+		if d := editDistance(target, route); bestDist < 0 || d < bestDist {
+			best, bestDist = route, d
+		}
+	}
+	return best
+}
+
+func editDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	cur := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(b)]
+}
+
+// matchRoute 判断path是否匹配gin路由pattern，支持:param和*catchall
+func matchRoute(pattern, path string) bool {
+	ps := strings.Split(strings.Trim(pattern, "/"), "/")
+	segs := strings.Split(strings.Trim(path, "/"), "/")
+	for i, p := range ps {
+		if strings.HasPrefix(p, "*") {
+			return true
+		}
+		if i >= len(segs) {
+			return false
+		}
+		if !strings.HasPrefix(p, ":") && p != segs[i] {
+			return false
+		}
+	}
+	return len(ps) == len(segs)
+}
@@ -0,0 +1,81 @@
+package log
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestGinFallbackHandlers(t *testing.T) {
+	logs := observeLogger(t, "access")
+	gin.SetMode(gin.TestMode)
+
+	r := gin.New()
+	r.HandleMethodNotAllowed = true
+	r.NoRoute(GinNoRoute("access", r))
+	r.NoMethod(GinNoMethod("access", r))
+	r.GET("/users/:id", func(c *gin.Context) { c.Status(http.StatusOK) })
+	r.DELETE("/users/:id", func(c *gin.Context) { c.Status(http.StatusOK) })
+	r.POST("/orders", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/user/1", nil))
+	if w.Code != http.StatusNotFound {
+		t.Fatalf("expected 404, got %d", w.Code)
+	}
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/users/1", nil))
+	if w.Code != http.StatusMethodNotAllowed || w.Header().Get("Allow") != "DELETE, GET" {
+		t.Fatalf("expected 405 with Allow header, got %d %q", w.Code, w.Header().Get("Allow"))
+	}
+
+	entries := logs.AllUntimed()
+	if len(entries) != 2 {
+		t.Fatalf("expected 2 entries, got %d", len(entries))
+	}
+	if got := entries[0].ContextMap()["nearest_route"]; got != "GET /users/:id" {
+		t.Fatalf("unexpected nearest route %v", got)
+	}
+	if entries[1].Message != "http method not allowed" {
+		t.Fatalf("unexpected entry %v", entries[1].Message)
+	}
+}
+
+func TestHTTPFallbackHandlers(t *testing.T) {
+	logs := observeLogger(t, "access")
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /panic", func(http.ResponseWriter, *http.Request) { panic("boom") })
+	mux.Handle("/", NotFoundHandler("access", "/panic", "/orders/{id}"))
+	h := HTTPRecovery("access", true)(mux)
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/order/1", nil))
+	if w.Code != http.StatusNotFound {
+		t.Fatalf("expected 404, got %d", w.Code)
+	}
+	w = httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/panic", nil)
+	req.Header.Set("X-Api-Key", "secret")
+	h.ServeHTTP(w, req)
+	if w.Code != http.StatusInternalServerError {
+		t.Fatalf("expected 500, got %d", w.Code)
+	}
+
+	entries := logs.AllUntimed()
+	if len(entries) != 2 {
+		t.Fatalf("expected 2 entries, got %d", len(entries))
+	}
+	if got := entries[0].ContextMap()["nearest_route"]; got != "/orders/{id}" {
+		t.Fatalf("unexpected nearest route %v", got)
+	}
+	if entries[1].Message != "http panic recovered" || entries[1].ContextMap()["stack"] == nil {
+		t.Fatalf("unexpected recovery entry %v", entries[1].ContextMap())
+	}
+	if request := entries[1].ContextMap()["request"].(string); strings.Contains(request, "secret") {
+		t.Fatalf("X-Api-Key should be redacted: %q", request)
+	}
+}
@@ -0,0 +1,56 @@
+package log
+
+import (
+	"os"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// stderrOutput 直接写fd 2，不经过缓冲和日志管道，测试中可替换
+var stderrOutput = writeStderr
+
+// writeStderr 经os.Stderr写fd 2，os.File.Write在被信号中断(EINTR)时自动重试
+func writeStderr(p []byte) (int, error) {
+	return os.Stderr.Write(p)
+}
+
+// stderrWriter 同步写fd 2的WriteSyncer，每条日志一次write调用
+type stderrWriter struct{}
+
+func (stderrWriter) Write(p []byte) (int, error) {
+	written := 0
+	for written < len(p) {
+		n, err := stderrOutput(p[written:])
+		if err != nil {
+			return written, err
+		}
+		// 异常的负数返回值按未写入处理，避免切片越界
+		written += max(n, 0)
+	}
+	return written, nil
+}
+
+func (stderrWriter) Sync() error {
+	return nil
+}
+
+// fallback 日志管道不可用时使用的logger：Init之前、Close之后以及信号处理中，直接同步写fd 2
+var fallback = func() *zap.Logger {
+	encoderConfig := zap.NewProductionEncoderConfig()
+	encoderConfig.EncodeTime = zapcore.ISO8601TimeEncoder
+	core := zapcore.NewCore(zapcore.NewJSONEncoder(encoderConfig), stderrWriter{}, zapcore.InfoLevel)
+	// Init之前的panic同样写入崩溃日志文件
+	return zap.New(zapcore.NewTee(core, newPanicCore()))
+}()
+
+func init() {
+	// 未初始化时GetLogger返回的全局logger也输出到stderr，避免启动阶段的日志丢失
+	zap.ReplaceGlobals(fallback)
+}
+
+// Fallback 返回直接同步写stderr(fd 2)的logger，用于日志管道不可用时（初始化前、关闭中、信号处理中）记录关键信息。
+// 未初始化或Close之后，GetLogger返回的也是该logger
+func Fallback() *zap.Logger {
+	return fallback
+}
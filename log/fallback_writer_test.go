@@ -0,0 +1,65 @@
+package log
+
+import (
+	"bytes"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestFallbackBeforeInitAndAfterClose(t *testing.T) {
+	var (
+		mu  sync.Mutex
+		buf bytes.Buffer
+	)
+	stderrOutput = func(p []byte) (int, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		// 模拟短写，验证会写完整条日志
+		if len(p) > 8 {
+			p = p[:8]
+		}
+		return buf.Write(p)
+	}
+	t.Cleanup(func() { stderrOutput = writeStderr })
+	output := func() string {
+		mu.Lock()
+		defer mu.Unlock()
+		return buf.String()
+	}
+
+	Close()
+	GetLogger("access").Info("early startup")
+	if got := output(); !strings.Contains(got, `"msg":"early startup"`) || !strings.HasSuffix(got, "}\n") {
+		t.Fatalf("expected fallback output, got %q", got)
+	}
+
+	initTestLoggers(t, "access")
+	GetLogger("access").Info("normal")
+	if strings.Contains(output(), "normal") {
+		t.Fatal("initialized logger should not use fallback")
+	}
+
+	Close()
+	GetDefaultLogger().Warn("shutdown")
+	if !strings.Contains(output(), `"msg":"shutdown"`) {
+		t.Fatalf("expected fallback output after Close, got %q", output())
+	}
+}
+
+func TestStderrWriterRetries(t *testing.T) {
+	var buf bytes.Buffer
+	calls := 0
+	stderrOutput = func(p []byte) (int, error) {
+		calls++
+		if calls == 1 {
+			return -1, nil
+		}
+		return buf.Write(p)
+	}
+	t.Cleanup(func() { stderrOutput = writeStderr })
+
+	if n, err := (stderrWriter{}).Write([]byte("interrupted\n")); err != nil || n != 12 || buf.String() != "interrupted\n" {
+		t.Fatalf("Write = %d, %v, %q", n, err, buf.String())
+	}
+}
@@ -0,0 +1,59 @@
+package log
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// fatalFlushTimeout Fatal/Panic时等待所有logger落盘的最长时间，避免输出端阻塞导致进程无法退出
+const fatalFlushTimeout = 3 * time.Second
+
+// fatalAction Fatal日志的后续动作，测试中替换以避免退出进程
+var fatalAction zapcore.CheckWriteHook = zapcore.WriteThenFatal
+
+// flushHook 在Fatal/Panic日志写入后同步所有已注册的logger，再执行原有的退出或panic动作，
+// 防止进程退出前最重要的日志仍停留在缓冲区或网络输出端中
+type flushHook struct {
+	next zapcore.CheckWriteHook
+}
+
+func (h flushHook) OnWrite(ce *zapcore.CheckedEntry, fields []zapcore.Field) {
+	ctx, cancel := context.WithTimeout(context.Background(), fatalFlushTimeout)
+	_ = syncAll(ctx)
+	cancel()
+	h.next.OnWrite(ce, fields)
+}
+
+// flushOptions 挂载Fatal/Panic刷新钩子的zap选项
+func flushOptions() []zap.Option {
+	return []zap.Option{
+		zap.WithFatalHook(flushHook{next: fatalAction}),
+		zap.WithPanicHook(flushHook{next: zapcore.WriteThenPanic}),
+	}
+}
+
+// syncAll 同步所有logger的输出端，包括额外注册的sink和崩溃日志文件
+func syncAll(ctx context.Context) error {
+	metux.RLock()
+	entries := make(map[string]*logEntry, len(loggers))
+	for name, entry := range loggers {
+		entries[name] = entry
+	}
+	metux.RUnlock()
+
+	var errs []error
+	for name, entry := range entries {
+		if err := syncContext(ctx, entry.logger.Sync); err != nil {
+			errs = append(errs, fmt.Errorf("logger %s: sync: %w", name, err))
+		}
+	}
+	if err := panicSink.Sync(); err != nil {
+		errs = append(errs, fmt.Errorf("panic file: sync: %w", err))
+	}
+	return errors.Join(errs...)
+}
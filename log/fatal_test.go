@@ -0,0 +1,84 @@
+package log
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// syncCountSink 记录Sync调用次数的测试输出端
+type syncCountSink struct {
+	syncs atomic.Int32
+}
+
+func (s *syncCountSink) Write(p []byte) (int, error) { return len(p), nil }
+
+func (s *syncCountSink) Sync() error {
+	s.syncs.Add(1)
+	return nil
+}
+
+func initFlushTest(t *testing.T) *syncCountSink {
+	t.Helper()
+
+	sink := &syncCountSink{}
+	RegisterSink("test-sync-count", func(string, map[string]any) (zapcore.WriteSyncer, error) {
+		return sink, nil
+	})
+	dir := t.TempDir()
+	config := fmt.Sprintf(`zaplog:
+  - name: default
+    file_name: %s
+  - name: audit
+    file_name: %s
+    sinks:
+      - type: test-sync-count
+`, filepath.Join(dir, "app.log"), filepath.Join(dir, "audit.log"))
+	configPath := filepath.Join(dir, "log_config.yaml")
+	if err := os.WriteFile(configPath, []byte(config), 0644); err != nil {
+		t.Fatal(err)
+	}
+	Close()
+	if err := Init(WithConfigFile(configPath)); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(Close)
+	return sink
+}
+
+func TestPanicFlushesAllLoggers(t *testing.T) {
+	sink := initFlushTest(t)
+
+	func() {
+		defer func() {
+			if r := recover(); r != "fatal config" {
+				t.Fatalf("Panic should still panic, got %v", r)
+			}
+		}()
+		GetDefaultLogger().Panic("fatal config")
+	}()
+	if sink.syncs.Load() == 0 {
+		t.Fatal("Panic should sync the sinks of every logger")
+	}
+}
+
+func TestFatalFlushesAllLoggers(t *testing.T) {
+	fatalAction = zapcore.WriteThenGoexit
+	t.Cleanup(func() { fatalAction = zapcore.WriteThenFatal })
+	sink := initFlushTest(t)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		GetDefaultLogger().Fatal("cannot start")
+		t.Error("Fatal should stop the goroutine")
+	}()
+	<-done
+	if sink.syncs.Load() == 0 {
+		t.Fatal("Fatal should sync the sinks of every logger")
+	}
+}
@@ -0,0 +1,179 @@
+package log
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// DropRule 丢弃日志的匹配规则，如健康检查与指标抓取产生的访问日志。设置的条件需同时满足
+type DropRule struct {
+	Field    string `yaml:"field" mapstructure:"field"`         // 匹配的字段名，为空时匹配消息
+	Equals   string `yaml:"equals" mapstructure:"equals"`       // 值等于
+	Prefix   string `yaml:"prefix" mapstructure:"prefix"`       // 值以此开头
+	Contains string `yaml:"contains" mapstructure:"contains"`   // 值包含
+	Regex    string `yaml:"regex" mapstructure:"regex"`         // 值匹配正则表达式
+	MaxLevel string `yaml:"max_level" mapstructure:"max_level"` // 只丢弃该级别及以下的日志，为空时不限制，如info可保留失败的健康检查
+}
+
+func validateDropRules(lc LogConfig) []error {
+	var errs []error
+	for i, r := range lc.DropIf {
+		if r.Equals == "" && r.Prefix == "" && r.Contains == "" && r.Regex == "" {
+			errs = append(errs, fmt.Errorf("logger %s: drop_if %d: one of equals, prefix, contains or regex is required", lc.Name, i))
+		}
+		if r.Regex != "" {
+			if _, err := regexp.Compile(r.Regex); err != nil {
+				errs = append(errs, fmt.Errorf("logger %s: drop_if %d: %w", lc.Name, i, err))
+			}
+		}
+		if r.MaxLevel != "" && !isValidLevel(r.MaxLevel) {
+			errs = append(errs, fmt.Errorf("logger %s: drop_if %d: invalid max_level %q", lc.Name, i, r.MaxLevel))
+		}
+	}
+	return errs
+}
+
+// dropMatcher 编译后的DropRule
+type dropMatcher struct {
+	DropRule
+	re       *regexp.Regexp
+	maxLevel zapcore.Level
+}
+
+func newDropMatchers(rules []DropRule) ([]dropMatcher, error) {
+	matchers := make([]dropMatcher, 0, len(rules))
+	for _, r := range rules {
+		m := dropMatcher{DropRule: r, maxLevel: zapcore.InvalidLevel}
+		if r.Regex != "" {
+			re, err := regexp.Compile(r.Regex)
+			if err != nil {
+				return nil, err
+			}
+			m.re = re
+		}
+		if r.MaxLevel != "" {
+			m.maxLevel = getLevel(r.MaxLevel)
+		}
+		matchers = append(matchers, m)
+	}
+	return matchers, nil
+}
+
+func (m *dropMatcher) match(value string) bool {
+	return (m.Equals == "" || value == m.Equals) &&
+		(m.Prefix == "" || strings.HasPrefix(value, m.Prefix)) &&
+		(m.Contains == "" || strings.Contains(value, m.Contains)) &&
+		(m.re == nil || m.re.MatchString(value))
+}
+
+func (m *dropMatcher) levelAllows(level zapcore.Level) bool {
+	return m.maxLevel == zapcore.InvalidLevel || level <= m.maxLevel
+}
+
+// filterCore 编码前丢弃匹配DropRule的日志；只匹配消息的规则在Check时即生效，不产生任何开销，
+// Write中再次检查，覆盖BufferRequest等不经过Check直接调用Write的情况
+type filterCore struct {
+	zapcore.Core
+	matchers []dropMatcher
+	// context With添加的字段中被规则引用的部分
+	context []zapcore.Field
+}
+
+func newFilterCore(core zapcore.Core, matchers []dropMatcher) zapcore.Core {
+	return &filterCore{Core: core, matchers: matchers}
+}
+
+func (c *filterCore) With(fields []zapcore.Field) zapcore.Core {
+	clone := &filterCore{Core: c.Core.With(fields), matchers: c.matchers, context: c.context}
+	for _, f := range fields {
+		if c.references(f.Key) {
+			clone.context = append(clone.context[:len(clone.context):len(clone.context)], f)
+		}
+	}
+	return clone
+}
+
+func (c *filterCore) references(key string) bool {
+	for i := range c.matchers {
+		if c.matchers[i].Field == key {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *filterCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if !c.Enabled(ent.Level) {
+		return ce
+	}
+	for i := range c.matchers {
+		m := &c.matchers[i]
+		if m.Field == "" && m.levelAllows(ent.Level) && m.match(ent.Message) {
+			return ce
+		}
+	}
+	return ce.AddCore(ent, c)
+}
+
+func (c *filterCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	for i := range c.matchers {
+		m := &c.matchers[i]
+		if !m.levelAllows(ent.Level) {
+			continue
+		}
+		if m.Field == "" {
+			if m.match(ent.Message) {
+				return nil
+			}
+			continue
+		}
+		if value, ok := c.lookup(m.Field, fields); ok && m.match(value) {
+			return nil
+		}
+	}
+	return c.Core.Write(ent, fields)
+}
+
+// lookup 返回字段的文本值，同名字段以最后添加的为准
+func (c *filterCore) lookup(key string, fields []zapcore.Field) (string, bool) {
+	for i := len(fields) - 1; i >= 0; i-- {
+		if fields[i].Key == key {
+			return fieldString(fields[i]), true
+		}
+	}
+	for i := len(c.context) - 1; i >= 0; i-- {
+		if c.context[i].Key == key {
+			return fieldString(c.context[i]), true
+		}
+	}
+	return "", false
+}
+
+// fieldString 返回字段值的文本形式
+func fieldString(f zapcore.Field) string {
+	switch f.Type {
+	case zapcore.StringType:
+		return f.String
+	case zapcore.Int64Type, zapcore.Int32Type, zapcore.Int16Type, zapcore.Int8Type:
+		return strconv.FormatInt(f.Integer, 10)
+	case zapcore.Uint64Type, zapcore.Uint32Type, zapcore.Uint16Type, zapcore.Uint8Type:
+		return strconv.FormatUint(uint64(f.Integer), 10)
+	case zapcore.BoolType:
+		return strconv.FormatBool(f.Integer == 1)
+	case zapcore.StringerType:
+		if s, ok := f.Interface.(fmt.Stringer); ok {
+			return s.String()
+		}
+	case zapcore.ErrorType:
+		if err, ok := f.Interface.(error); ok {
+			return err.Error()
+		}
+	}
+	enc := zapcore.NewMapObjectEncoder()
+	f.AddTo(enc)
+	return fmt.Sprint(enc.Fields[f.Key])
+}
@@ -0,0 +1,96 @@
+package log
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestFilterCore(t *testing.T) {
+	matchers, err := newDropMatchers([]DropRule{
+		{Field: "path", Equals: "/healthz", MaxLevel: "info"},
+		{Field: "path", Prefix: "/metrics"},
+		{Field: "status", Equals: "304"},
+		{Contains: "heartbeat"},
+		{Field: "agent", Regex: `^kube-probe/`},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	obs, logs := observer.New(zapcore.DebugLevel)
+	logger := zap.New(newFilterCore(obs, matchers))
+
+	logger.Info("http request", zap.String("path", "/healthz"))
+	logger.Error("http request", zap.String("path", "/healthz"))
+	logger.Info("http request", zap.String("path", "/metrics/node"))
+	logger.Info("http request", zap.Int("status", 304))
+	logger.Info("http request", zap.Int("status", 200))
+	logger.Debug("sent heartbeat to peer")
+	logger.With(zap.String("agent", "kube-probe/1.29")).Info("http request")
+	// 同名字段以最后添加的为准
+	logger.With(zap.String("path", "/healthz")).Info("http request", zap.String("path", "/orders"))
+	logger.With(zap.Duration("latency", time.Second)).Info("http request", zap.String("path", "/users"))
+
+	entries := logs.All()
+	var got []string
+	for _, e := range entries {
+		f := e.ContextMap()
+		got = append(got, e.Level.String()+" "+toString(f["path"])+" "+toString(f["status"]))
+	}
+	want := []string{"error /healthz ", "info  200", "info /orders ", "info /users "}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Fatalf("unexpected entries\n got: %q\nwant: %q", got, want)
+	}
+
+	// 不经过Check直接调用Write时消息规则同样生效
+	core := newFilterCore(obs, matchers)
+	if err := core.Write(zapcore.Entry{Level: zapcore.InfoLevel, Message: "heartbeat"}, nil); err != nil {
+		t.Fatal(err)
+	}
+	if logs.Len() != len(want) {
+		t.Fatal("message rules should apply in Write")
+	}
+}
+
+func toString(v any) string {
+	if v == nil {
+		return ""
+	}
+	return fieldString(zap.Any("v", v))
+}
+
+func TestDropIfConfig(t *testing.T) {
+	dir := t.TempDir()
+	entry, err := newLogger(LogConfig{
+		Name:     "access",
+		FileName: filepath.Join(dir, "access.log"),
+		Encoder:  "json",
+		DropIf:   []DropRule{{Field: "path", Equals: "/healthz"}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer entry.writer.Close()
+	entry.logger.Info("http request", zap.String("path", "/healthz"))
+	entry.logger.Info("http request", zap.String("path", "/orders"))
+
+	data, _ := os.ReadFile(filepath.Join(dir, "access.log"))
+	if strings.Contains(string(data), "/healthz") || !strings.Contains(string(data), "/orders") {
+		t.Fatalf("unexpected log %q", data)
+	}
+
+	errs := validateDropRules(LogConfig{Name: "access", DropIf: []DropRule{
+		{Field: "path"},
+		{Regex: "("},
+		{Equals: "x", MaxLevel: "loud"},
+	}})
+	if len(errs) != 3 {
+		t.Fatalf("expected 3 errors, got %v", errs)
+	}
+}
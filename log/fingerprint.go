@@ -0,0 +1,140 @@
+package log
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"strings"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+var fingerprintNormalizers = []struct {
+	re   *regexp.Regexp
+	repl string
+}{
+	{regexp.MustCompile(`[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}`), "<uuid>"},
+	{regexp.MustCompile(`0x[0-9a-fA-F]+`), "<hex>"},
+	{regexp.MustCompile(`"[^"]*"|'[^']*'`), "<str>"},
+	{regexp.MustCompile(`\d+`), "<n>"},
+}
+
+// Fingerprint 计算错误的稳定指纹：错误类型 + 归一化后的错误信息（去掉数字、UUID、引号内容等易变部分）
+// + 错误自身携带堆栈的栈顶函数
+func Fingerprint(err error) string {
+	return fingerprint(err, "")
+}
+
+// fingerprint frame为产生日志的函数名，错误自身携带堆栈时以错误产生处的函数为准，
+// 参与指纹计算以区分不同位置的相同错误
+func fingerprint(err error, frame string) string {
+	if f := errFrame(err); f != "" {
+		frame = f
+	}
+	msg := err.Error()
+	for _, n := range fingerprintNormalizers {
+		msg = n.re.ReplaceAllString(msg, n.repl)
+	}
+	sum := sha1.Sum([]byte(fmt.Sprintf("%T\n%s\n%s", err, msg, frame)))
+	return hex.EncodeToString(sum[:8])
+}
+
+// errFrame 返回错误链中最内层携带堆栈的错误的栈顶函数，均未携带堆栈时返回空
+func errFrame(err error) string {
+	frame := ""
+	for _, cause := range unwrapChain(err) {
+		if len(cause.stack) > 0 {
+			frame = cause.stack[0].Function
+		}
+	}
+	return frame
+}
+
+// logPackageDir 本包源码目录，用于在调用栈中跳过本包的帧
+var logPackageDir = func() string {
+	_, file, _, _ := runtime.Caller(0)
+	return filepath.Dir(file)
+}()
+
+// callSite 返回日志调用处的函数名，跳过zap及本包的帧，不依赖show_caller配置
+func callSite() string {
+	var pcs [32]uintptr
+	n := runtime.Callers(3, pcs[:])
+	frames := runtime.CallersFrames(pcs[:n])
+	for {
+		frame, more := frames.Next()
+		inLog := filepath.Dir(frame.File) == logPackageDir && !strings.HasSuffix(frame.File, "_test.go")
+		if !inLog && !strings.HasPrefix(frame.Function, "go.uber.org/zap") {
+			return frame.Function
+		}
+		if !more {
+			return ""
+		}
+	}
+}
+
+// fingerprintCore 为每个error字段附加 <key>.fingerprint 字段，
+// With附加的error字段在写入时以日志调用处计算指纹
+type fingerprintCore struct {
+	zapcore.Core
+	errs []zapcore.Field
+}
+
+func newFingerprintCore(core zapcore.Core) zapcore.Core {
+	return &fingerprintCore{Core: core}
+}
+
+func (c *fingerprintCore) With(fields []zapcore.Field) zapcore.Core {
+	errs := c.errs
+	for _, f := range fields {
+		if f.Type == zapcore.ErrorType {
+			errs = append(errs[:len(errs):len(errs)], f)
+		}
+	}
+	return &fingerprintCore{Core: c.Core.With(fields), errs: errs}
+}
+
+func (c *fingerprintCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+// Write 在日志调用的goroutine中同步执行，此时的调用栈即日志调用处
+func (c *fingerprintCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	if len(c.errs) == 0 && !hasErrorField(fields) {
+		return c.Core.Write(ent, fields)
+	}
+	frame := callSite()
+	out := appendFingerprints(fields, c.errs, frame)
+	return c.Core.Write(ent, appendFingerprints(out, fields, frame))
+}
+
+func hasErrorField(fields []zapcore.Field) bool {
+	for _, f := range fields {
+		if f.Type == zapcore.ErrorType {
+			return true
+		}
+	}
+	return false
+}
+
+// appendFingerprints 为src中的error字段计算指纹并追加到dst之后，不修改dst的底层数组
+func appendFingerprints(dst, src []zapcore.Field, frame string) []zapcore.Field {
+	for _, f := range src {
+		if f.Type != zapcore.ErrorType {
+			continue
+		}
+		err, ok := f.Interface.(error)
+		if !ok || err == nil {
+			continue
+		}
+		dst = append(dst[:len(dst):len(dst)], zap.String(f.Key+".fingerprint", fingerprint(err, frame)))
+	}
+	return dst
+}
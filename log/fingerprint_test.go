@@ -0,0 +1,91 @@
+package log
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+type testError string
+
+func (e testError) Error() string { return string(e) }
+
+func TestFingerprint(t *testing.T) {
+	a := fmt.Errorf("user %d not found in shard 0x1f", 42)
+	b := fmt.Errorf("user %d not found in shard 0x2a", 7)
+	c := testError("user 42 not found in shard 0x1f")
+	if Fingerprint(a) != Fingerprint(b) {
+		t.Fatal("errors differing only in ids should share a fingerprint")
+	}
+	if Fingerprint(a) == Fingerprint(c) {
+		t.Fatal("errors of different types should not share a fingerprint")
+	}
+	if Fingerprint(a) == Fingerprint(errors.New("timeout")) {
+		t.Fatal("different messages should not share a fingerprint")
+	}
+}
+
+func TestFingerprintCore(t *testing.T) {
+	core, logs := observer.New(zap.DebugLevel)
+	logger := zap.New(newFingerprintCore(core))
+
+	err := fmt.Errorf("dial 10.0.0.%d: timeout", 1)
+	logger.With(zap.NamedError("cause", err)).Error("failed", zap.Error(err), zap.String("k", "v"))
+
+	// With附加的错误与日志调用处的错误使用相同的栈顶函数
+	want := fingerprint(err, "github.com/allanchen1214/goeasy/log.TestFingerprintCore")
+	fields := logs.All()[0].ContextMap()
+	if fields["error.fingerprint"] != want || fields["cause.fingerprint"] != want {
+		t.Fatalf("unexpected fingerprints: %v", fields)
+	}
+}
+
+func TestFingerprintIgnoresShowCaller(t *testing.T) {
+	dir := t.TempDir()
+	err := errors.New("connection refused")
+	fingerprints := make(map[bool]string)
+	for _, showCaller := range []bool{false, true} {
+		path := filepath.Join(dir, fmt.Sprintf("fp-%t.log", showCaller))
+		entry, lerr := newLogger(LogConfig{
+			Name:             "fp",
+			FileName:         path,
+			JsonEncoder:      true,
+			ShowCaller:       showCaller,
+			ErrorFingerprint: true,
+		})
+		if lerr != nil {
+			t.Fatal(lerr)
+		}
+		entry.logger.Error("query failed", zap.Error(err))
+		_ = entry.logger.Sync()
+		entry.writer.Close()
+
+		data, rerr := os.ReadFile(path)
+		if rerr != nil {
+			t.Fatal(rerr)
+		}
+		var line map[string]any
+		if jerr := json.Unmarshal(data, &line); jerr != nil {
+			t.Fatal(jerr)
+		}
+		fingerprints[showCaller], _ = line["error.fingerprint"].(string)
+	}
+	if fingerprints[false] == "" || fingerprints[false] != fingerprints[true] {
+		t.Fatalf("fingerprint should not depend on show_caller: %v", fingerprints)
+	}
+	if fingerprints[false] == Fingerprint(err) {
+		t.Fatal("fingerprint should include the call site")
+	}
+
+	// 错误自身携带堆栈时以错误产生处为准
+	serr := newStackErr("connection refused")
+	if fingerprint(serr, "a") != fingerprint(serr, "b") || fingerprint(serr, "a") != Fingerprint(serr) {
+		t.Fatal("fingerprint should use the error's own stack")
+	}
+}
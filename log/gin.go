@@ -0,0 +1,126 @@
+package log
+
+import (
+	"errors"
+	"net"
+	"net/http"
+	"os"
+	"runtime/debug"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// GinLogger 返回gin访问日志中间件，替代gin默认的Logger
+func GinLogger(name string, opts ...Option) gin.HandlerFunc {
+	o := newOptions(opts)
+	return func(c *gin.Context) {
+		start := time.Now()
+		path := c.Request.URL.Path
+		query := c.Request.URL.RawQuery
+		ctx := WithMDC(c.Request.Context())
+		if o.dumpRequested(c.Request.Header) {
+			ctx = ForceDump(ctx)
+		}
+		c.Request = c.Request.WithContext(ctx)
+		c.Next()
+
+		status := c.Writer.Status()
+		latency := time.Since(start)
+		level := o.levelFor(path)
+		switch {
+		case status >= http.StatusInternalServerError:
+			level = zapcore.ErrorLevel
+		case status >= http.StatusBadRequest:
+			level = zapcore.WarnLevel
+		case o.isSlow(latency) && level < zapcore.WarnLevel:
+			level = zapcore.WarnLevel
+		}
+
+		ce := GetLogger(name).Check(level, "http request")
+		if ce == nil {
+			return
+		}
+		fields := []zap.Field{
+			zap.String("method", c.Request.Method),
+			zap.String("path", path),
+			zap.String("query", query),
+			zap.String("route", c.FullPath()),
+			zap.Int("status", status),
+			zap.Int("bytes", c.Writer.Size()),
+			zap.Duration("latency", latency),
+			zap.String("client_ip", c.ClientIP()),
+			zap.String("user_agent", c.Request.UserAgent()),
+		}
+		if id := c.GetHeader(o.requestIDKey); id != "" {
+			fields = append(fields, zap.String("request_id", id))
+		}
+		if o.isSlow(latency) {
+			fields = append(fields, zap.Bool("slow", true))
+		}
+		if len(c.Errors) > 0 {
+			fields = append(fields, zap.Strings("errors", c.Errors.Errors()))
+		}
+		fields = append(fields, mdcFields(c.Request.Context())...)
+		ce.Write(fields...)
+	}
+}
+
+// GinRecovery 返回gin panic恢复中间件，stack为true时记录调用栈
+func GinRecovery(name string, stack bool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		defer func() {
+			r := recover()
+			if r == nil {
+				return
+			}
+
+			logger := GetLogger(name)
+			request := dumpRequest(c.Request)
+			if isBrokenPipe(r) {
+				// 连接已断开，无法再写入响应
+				logger.Error("http connection broken",
+					zap.String("path", c.Request.URL.Path),
+					zap.Any("error", r),
+					zap.ByteString("request", request),
+				)
+				_ = c.Error(r.(error))
+				c.Abort()
+				return
+			}
+
+			fields := []zap.Field{
+				zap.String("path", c.Request.URL.Path),
+				zap.Any("error", r),
+				zap.ByteString("request", request),
+			}
+			if stack {
+				fields = append(fields, zap.ByteString("stack", debug.Stack()))
+			}
+			logger.Error("http panic recovered", fields...)
+			c.AbortWithStatus(http.StatusInternalServerError)
+		}()
+		c.Next()
+	}
+}
+
+func isBrokenPipe(r interface{}) bool {
+	err, ok := r.(error)
+	if !ok {
+		return false
+	}
+	var opErr *net.OpError
+	if !errors.As(err, &opErr) {
+		return false
+	}
+	var syscallErr *os.SyscallError
+	if errors.As(opErr.Err, &syscallErr) {
+		return errors.Is(syscallErr.Err, syscall.EPIPE) || errors.Is(syscallErr.Err, syscall.ECONNRESET)
+	}
+	msg := strings.ToLower(opErr.Err.Error())
+	return strings.Contains(msg, "broken pipe") || strings.Contains(msg, "connection reset by peer")
+}
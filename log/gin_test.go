@@ -0,0 +1,52 @@
+package log
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap/zapcore"
+)
+
+func TestGinMiddleware(t *testing.T) {
+	logs := observeLogger(t, "access")
+	gin.SetMode(gin.TestMode)
+
+	r := gin.New()
+	r.Use(GinLogger("access", WithMethodLevel("/healthz", "debug")), GinRecovery("access", true))
+	r.GET("/users/:id", func(c *gin.Context) { c.String(http.StatusOK, "ok") })
+	r.GET("/healthz", func(c *gin.Context) { c.Status(http.StatusOK) })
+	r.GET("/panic", func(c *gin.Context) { panic("boom") })
+
+	for _, path := range []string{"/users/1", "/healthz", "/panic"} {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		req.Header.Set("X-Request-Id", "req-1")
+		req.Header.Set("Authorization", "Bearer secret")
+		req.Header.Set("Cookie", "session=secret")
+		r.ServeHTTP(httptest.NewRecorder(), req)
+	}
+
+	entries := logs.All()
+	if len(entries) != 4 {
+		t.Fatalf("expected 4 entries, got %d", len(entries))
+	}
+	fields := entries[0].ContextMap()
+	if fields["route"] != "/users/:id" || fields["status"] != int64(200) || fields["request_id"] != "req-1" {
+		t.Fatalf("unexpected access entry: %v", fields)
+	}
+	if entries[1].Level != zapcore.DebugLevel {
+		t.Fatalf("healthz should be logged at debug, got %v", entries[1].Level)
+	}
+	if entries[2].Message != "http panic recovered" || entries[2].ContextMap()["stack"] == nil {
+		t.Fatalf("unexpected recovery entry: %v %v", entries[2].Message, entries[2].ContextMap())
+	}
+	// 敏感请求头脱敏，其他请求头保留
+	if request := entries[2].ContextMap()["request"].(string); strings.Contains(request, "secret") || !strings.Contains(request, "Authorization: *") || !strings.Contains(request, "X-Request-Id: req-1") {
+		t.Fatalf("sensitive headers should be redacted: %q", request)
+	}
+	if entries[3].Level != zapcore.ErrorLevel || entries[3].ContextMap()["status"] != int64(500) {
+		t.Fatalf("unexpected access entry for panic: %v %v", entries[3].Level, entries[3].ContextMap())
+	}
+}
@@ -0,0 +1,58 @@
+package log
+
+import (
+	"context"
+	"runtime"
+	"runtime/pprof"
+	"strconv"
+	"strings"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// goid 返回当前goroutine的ID，通过解析runtime.Stack获得，开销约为一次小的栈拷贝
+func goid() uint64 {
+	var buf [64]byte
+	n := runtime.Stack(buf[:], false)
+	s := strings.TrimPrefix(string(buf[:n]), "goroutine ")
+	if i := strings.IndexByte(s, ' '); i > 0 {
+		s = s[:i]
+	}
+	id, _ := strconv.ParseUint(s, 10, 64)
+	return id
+}
+
+// goroutineCore 为每条日志附加goroutine字段
+type goroutineCore struct {
+	zapcore.Core
+}
+
+func newGoroutineCore(core zapcore.Core) zapcore.Core {
+	return &goroutineCore{Core: core}
+}
+
+func (c *goroutineCore) With(fields []zapcore.Field) zapcore.Core {
+	return &goroutineCore{Core: c.Core.With(fields)}
+}
+
+func (c *goroutineCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+func (c *goroutineCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	return c.Core.Write(ent, append(fields, zap.Uint64("goroutine", goid())))
+}
+
+// PprofLabels 返回ctx上通过pprof.Do/pprof.WithLabels设置的标签，作为pprof_labels字段
+func PprofLabels(ctx context.Context) zap.Field {
+	labels := make(map[string]string)
+	pprof.ForLabels(ctx, func(key, value string) bool {
+		labels[key] = value
+		return true
+	})
+	return zap.Any("pprof_labels", labels)
+}
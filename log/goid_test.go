@@ -0,0 +1,39 @@
+package log
+
+import (
+	"context"
+	"runtime/pprof"
+	"testing"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestGoroutineCore(t *testing.T) {
+	core, logs := observer.New(zapcore.DebugLevel)
+	logger := zap.New(newGoroutineCore(core))
+
+	ctx := pprof.WithLabels(context.Background(), pprof.Labels("job", "sync"))
+	logger.Info("hello", PprofLabels(ctx))
+
+	fields := logs.All()[0].ContextMap()
+	if fields["goroutine"] != goid() {
+		t.Fatalf("unexpected goroutine field: %v", fields["goroutine"])
+	}
+	if labels, ok := fields["pprof_labels"].(map[string]string); !ok || labels["job"] != "sync" {
+		t.Fatalf("unexpected pprof labels: %#v", fields["pprof_labels"])
+	}
+}
+
+func TestGoid(t *testing.T) {
+	id := goid()
+	if id == 0 {
+		t.Fatal("goid should not be zero")
+	}
+	ch := make(chan uint64)
+	go func() { ch <- goid() }()
+	if other := <-ch; other == id || other == 0 {
+		t.Fatalf("expected a different goroutine id, got %d and %d", id, other)
+	}
+}
@@ -0,0 +1,128 @@
+package log
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+	gormlogger "gorm.io/gorm/logger"
+	"gorm.io/gorm/utils"
+)
+
+// GormConfig GORM日志配置
+type GormConfig struct {
+	Level                string        `yaml:"level" mapstructure:"level"`                                     // silent/error/warn/info，默认warn
+	SlowThreshold        time.Duration `yaml:"slow_threshold" mapstructure:"slow_threshold"`                   // 慢查询阈值，默认200ms
+	IgnoreRecordNotFound bool          `yaml:"ignore_record_not_found" mapstructure:"ignore_record_not_found"` // 是否忽略记录不存在错误
+	ParameterizedQueries bool          `yaml:"parameterized_queries" mapstructure:"parameterized_queries"`     // 是否隐藏SQL参数值
+}
+
+func getGormLevel(level string) gormlogger.LogLevel {
+	switch strings.ToLower(level) {
+	case "silent":
+		return gormlogger.Silent
+	case "error":
+		return gormlogger.Error
+	case "info":
+		return gormlogger.Info
+	default:
+		return gormlogger.Warn
+	}
+}
+
+// GormLogger 实现gorm logger.Interface，SQL日志写入指定名称的logger：
+// 普通SQL为debug级别，慢查询为warn级别，执行出错为error级别
+type GormLogger struct {
+	name   string
+	level  gormlogger.LogLevel
+	config GormConfig
+}
+
+// NewGormLogger 基于指定名称的logger创建GORM日志，配置取自该logger的gorm配置项
+func NewGormLogger(name string) *GormLogger {
+	metux.RLock()
+	var cfg GormConfig
+	if entry, ok := loggers[name]; ok {
+		cfg = entry.cfg.Gorm
+	}
+	metux.RUnlock()
+
+	if cfg.SlowThreshold == 0 {
+		cfg.SlowThreshold = 200 * time.Millisecond
+	}
+	return &GormLogger{
+		name:   name,
+		level:  getGormLevel(cfg.Level),
+		config: cfg,
+	}
+}
+
+// LogMode 实现gorm logger.Interface
+func (l *GormLogger) LogMode(level gormlogger.LogLevel) gormlogger.Interface {
+	nl := *l
+	nl.level = level
+	return &nl
+}
+
+// Info 实现gorm logger.Interface
+func (l *GormLogger) Info(ctx context.Context, msg string, data ...interface{}) {
+	if l.level >= gormlogger.Info {
+		GetLogger(l.name).Info(fmt.Sprintf(msg, data...), zap.String("source", utils.FileWithLineNum()))
+	}
+}
+
+// Warn 实现gorm logger.Interface
+func (l *GormLogger) Warn(ctx context.Context, msg string, data ...interface{}) {
+	if l.level >= gormlogger.Warn {
+		GetLogger(l.name).Warn(fmt.Sprintf(msg, data...), zap.String("source", utils.FileWithLineNum()))
+	}
+}
+
+// Error 实现gorm logger.Interface
+func (l *GormLogger) Error(ctx context.Context, msg string, data ...interface{}) {
+	if l.level >= gormlogger.Error {
+		GetLogger(l.name).Error(fmt.Sprintf(msg, data...), zap.String("source", utils.FileWithLineNum()))
+	}
+}
+
+// Trace 实现gorm logger.Interface
+func (l *GormLogger) Trace(ctx context.Context, begin time.Time, fc func() (sql string, rowsAffected int64), err error) {
+	if l.level <= gormlogger.Silent {
+		return
+	}
+
+	elapsed := time.Since(begin)
+	logger := GetLogger(l.name)
+	fields := func() []zap.Field {
+		sql, rows := fc()
+		return []zap.Field{
+			zap.String("sql", sql),
+			zap.Int64("rows", rows),
+			zap.Duration("latency", elapsed),
+			zap.String("source", utils.FileWithLineNum()),
+		}
+	}
+
+	switch {
+	case err != nil && l.level >= gormlogger.Error &&
+		(!errors.Is(err, gormlogger.ErrRecordNotFound) || !l.config.IgnoreRecordNotFound):
+		logger.Error("sql error", append(fields(), zap.Error(err))...)
+	case l.config.SlowThreshold > 0 && elapsed > l.config.SlowThreshold && l.level >= gormlogger.Warn:
+		logger.Warn("slow sql", append(fields(), zap.Duration("slow_threshold", l.config.SlowThreshold))...)
+	case l.level >= gormlogger.Info:
+		if ce := logger.Check(zap.DebugLevel, "sql"); ce != nil {
+			ce.Write(fields()...)
+		}
+	}
+}
+
+// ParamsFilter 开启parameterized_queries时，gorm输出的SQL中不包含参数值
+func (l *GormLogger) ParamsFilter(ctx context.Context, sql string, params ...interface{}) (string, []interface{}) {
+	if l.config.ParameterizedQueries {
+		return sql, nil
+	}
+	return sql, params
+}
@@ -0,0 +1,51 @@
+package log
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+	gormlogger "gorm.io/gorm/logger"
+)
+
+func TestGormLogger(t *testing.T) {
+	logs := observeLogger(t, "gorm")
+	metux.Lock()
+	loggers["gorm"].cfg.Gorm = GormConfig{
+		Level:                "info",
+		SlowThreshold:        10 * time.Millisecond,
+		IgnoreRecordNotFound: true,
+		ParameterizedQueries: true,
+	}
+	metux.Unlock()
+
+	l := NewGormLogger("gorm")
+	ctx := context.Background()
+	sql := func() (string, int64) { return "SELECT * FROM users WHERE id = ?", 1 }
+
+	l.Trace(ctx, time.Now(), sql, nil)
+	l.Trace(ctx, time.Now().Add(-time.Second), sql, nil)
+	l.Trace(ctx, time.Now(), sql, errors.New("connection refused"))
+	l.Trace(ctx, time.Now(), sql, gormlogger.ErrRecordNotFound)
+	l.LogMode(gormlogger.Silent).Trace(ctx, time.Now(), sql, errors.New("ignored"))
+
+	entries := logs.All()
+	levels := []zapcore.Level{zapcore.DebugLevel, zapcore.WarnLevel, zapcore.ErrorLevel, zapcore.DebugLevel}
+	if len(entries) != len(levels) {
+		t.Fatalf("expected %d entries, got %d", len(levels), len(entries))
+	}
+	for i, level := range levels {
+		if entries[i].Level != level {
+			t.Fatalf("entry %d: expected %v, got %v", i, level, entries[i].Level)
+		}
+	}
+	if entries[0].ContextMap()["sql"] != "SELECT * FROM users WHERE id = ?" {
+		t.Fatalf("unexpected sql field: %v", entries[0].ContextMap())
+	}
+
+	if _, params := l.ParamsFilter(ctx, "SELECT ?", "secret"); params != nil {
+		t.Fatal("params should be redacted")
+	}
+}
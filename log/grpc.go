@@ -0,0 +1,144 @@
+package log
+
+import (
+	"context"
+	"path"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// UnaryServerInterceptor 返回记录gRPC一元调用的服务端拦截器
+func UnaryServerInterceptor(name string, opts ...Option) grpc.UnaryServerInterceptor {
+	o := newOptions(opts)
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		start := time.Now()
+		ctx = WithMDC(ctx)
+		resp, err := handler(ctx, req)
+
+		latency := time.Since(start)
+		fields := grpcFields(ctx, info.FullMethod, latency, err, o, metadata.FromIncomingContext)
+		if o.logPayload {
+			fields = append(fields, Any("grpc.request", req), Any("grpc.response", resp))
+		}
+		fields = append(fields, mdcFields(ctx)...)
+		logGRPC(GetLogger(name), o, info.FullMethod, "grpc server call", err, latency, fields)
+		return resp, err
+	}
+}
+
+// StreamServerInterceptor 返回记录gRPC流式调用的服务端拦截器
+func StreamServerInterceptor(name string, opts ...Option) grpc.StreamServerInterceptor {
+	o := newOptions(opts)
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		start := time.Now()
+		logger := GetLogger(name)
+		if o.logPayload {
+			ss = &payloadServerStream{ServerStream: ss, logger: logger, method: info.FullMethod}
+		}
+		err := handler(srv, ss)
+
+		latency := time.Since(start)
+		fields := grpcFields(ss.Context(), info.FullMethod, latency, err, o, metadata.FromIncomingContext)
+		logGRPC(logger, o, info.FullMethod, "grpc server stream", err, latency, fields)
+		return err
+	}
+}
+
+// UnaryClientInterceptor 返回记录gRPC一元调用的客户端拦截器
+func UnaryClientInterceptor(name string, opts ...Option) grpc.UnaryClientInterceptor {
+	o := newOptions(opts)
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, callOpts ...grpc.CallOption) error {
+		start := time.Now()
+		err := invoker(ctx, method, req, reply, cc, callOpts...)
+
+		latency := time.Since(start)
+		fields := grpcFields(ctx, method, latency, err, o, metadata.FromOutgoingContext)
+		fields = append(fields, zap.String("grpc.target", cc.Target()))
+		if o.logPayload {
+			fields = append(fields, Any("grpc.request", req), Any("grpc.response", reply))
+		}
+		logGRPC(GetLogger(name), o, method, "grpc client call", err, latency, fields)
+		return err
+	}
+}
+
+// StreamClientInterceptor 返回记录gRPC流式调用建立过程的客户端拦截器
+func StreamClientInterceptor(name string, opts ...Option) grpc.StreamClientInterceptor {
+	o := newOptions(opts)
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, callOpts ...grpc.CallOption) (grpc.ClientStream, error) {
+		start := time.Now()
+		cs, err := streamer(ctx, desc, cc, method, callOpts...)
+
+		latency := time.Since(start)
+		fields := grpcFields(ctx, method, latency, err, o, metadata.FromOutgoingContext)
+		fields = append(fields, zap.String("grpc.target", cc.Target()))
+		logGRPC(GetLogger(name), o, method, "grpc client stream", err, latency, fields)
+		return cs, err
+	}
+}
+
+func grpcFields(ctx context.Context, method string, latency time.Duration, err error, o *options,
+	fromContext func(context.Context) (metadata.MD, bool)) []zap.Field {
+	fields := []zap.Field{
+		zap.String("grpc.service", path.Dir(method)[1:]),
+		zap.String("grpc.method", path.Base(method)),
+		zap.String("grpc.code", status.Code(err).String()),
+		zap.Duration("latency", latency),
+	}
+	if o.isSlow(latency) {
+		fields = append(fields, zap.Bool("slow", true))
+	}
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		fields = append(fields, zap.String("grpc.peer", p.Addr.String()))
+	}
+	if md, ok := fromContext(ctx); ok {
+		if ids := md.Get(o.requestIDKey); len(ids) > 0 {
+			fields = append(fields, zap.String("request_id", ids[0]))
+		}
+	}
+	if err != nil {
+		fields = append(fields, zap.Error(err))
+	}
+	return fields
+}
+
+func logGRPC(logger *zap.Logger, o *options, method, msg string, err error, latency time.Duration, fields []zap.Field) {
+	level := o.levelFor(method)
+	if err != nil {
+		level = zapcore.ErrorLevel
+	} else if o.isSlow(latency) && level < zapcore.WarnLevel {
+		level = zapcore.WarnLevel
+	}
+	if ce := logger.Check(level, msg); ce != nil {
+		ce.Write(fields...)
+	}
+}
+
+// payloadServerStream 逐条记录流式消息内容
+type payloadServerStream struct {
+	grpc.ServerStream
+	logger *zap.Logger
+	method string
+}
+
+func (s *payloadServerStream) SendMsg(m interface{}) error {
+	err := s.ServerStream.SendMsg(m)
+	if err == nil {
+		s.logger.Debug("grpc stream send", zap.String("grpc.method", s.method), Any("grpc.message", m))
+	}
+	return err
+}
+
+func (s *payloadServerStream) RecvMsg(m interface{}) error {
+	err := s.ServerStream.RecvMsg(m)
+	if err == nil {
+		s.logger.Debug("grpc stream recv", zap.String("grpc.method", s.method), Any("grpc.message", m))
+	}
+	return err
+}
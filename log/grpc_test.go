@@ -0,0 +1,64 @@
+package log
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"go.uber.org/zap/zapcore"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+func TestGRPCInterceptors(t *testing.T) {
+	serverLogs := observeLogger(t, "grpc-server")
+	clientLogs := observeLogger(t, "grpc-client")
+
+	lis := bufconn.Listen(1 << 20)
+	srv := grpc.NewServer(grpc.UnaryInterceptor(UnaryServerInterceptor("grpc-server", WithPayload(true))))
+	healthpb.RegisterHealthServer(srv, health.NewServer())
+	go srv.Serve(lis)
+	defer srv.Stop()
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithUnaryInterceptor(UnaryClientInterceptor("grpc-client",
+			WithMethodLevel("/grpc.health.v1.Health/Check", "debug"))),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	ctx := metadata.AppendToOutgoingContext(context.Background(), "x-request-id", "req-1")
+	client := healthpb.NewHealthClient(conn)
+	if _, err := client.Check(ctx, &healthpb.HealthCheckRequest{}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.Check(ctx, &healthpb.HealthCheckRequest{Service: "missing"}); err == nil {
+		t.Fatal("expected NotFound error")
+	}
+
+	entries := serverLogs.All()
+	if len(entries) != 2 {
+		t.Fatalf("expected 2 server entries, got %d", len(entries))
+	}
+	fields := entries[0].ContextMap()
+	if entries[0].Level != zapcore.InfoLevel || fields["grpc.method"] != "Check" ||
+		fields["grpc.code"] != "OK" || fields["request_id"] != "req-1" || fields["grpc.request"] == nil {
+		t.Fatalf("unexpected server entry: %v %v", entries[0].Level, fields)
+	}
+	if entries[1].Level != zapcore.ErrorLevel || entries[1].ContextMap()["grpc.code"] != "NotFound" {
+		t.Fatalf("unexpected server error entry: %v %v", entries[1].Level, entries[1].ContextMap())
+	}
+
+	entries = clientLogs.All()
+	if len(entries) != 2 || entries[0].Level != zapcore.DebugLevel || entries[1].Level != zapcore.ErrorLevel {
+		t.Fatalf("unexpected client entries: %+v", entries)
+	}
+}
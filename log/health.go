@@ -0,0 +1,159 @@
+package log
+
+import (
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// 输出端失败后重试的退避间隔，每次失败翻倍
+var (
+	healthRetryMin = time.Second
+	healthRetryMax = time.Minute
+)
+
+// healthWriter 输出端写入失败时改写stderr，按退避间隔重试输出端，成功后自动恢复。
+// 失败期间的日志只写stderr，不会因输出端阻塞或报错而丢失
+type healthWriter struct {
+	zapcore.WriteSyncer
+	logger, output string
+	fallback       zapcore.WriteSyncer
+	writeErrors    *atomic.Uint64
+
+	mu          sync.Mutex
+	healthy     bool
+	failures    uint64
+	lastError   error
+	lastErrorAt time.Time
+	backoff     time.Duration
+	retryAt     time.Time
+}
+
+func newHealthWriter(logger, output string, ws zapcore.WriteSyncer, writeErrors *atomic.Uint64) *healthWriter {
+	return &healthWriter{
+		WriteSyncer: ws,
+		logger:      logger,
+		output:      output,
+		fallback:    stderrWriter{},
+		writeErrors: writeErrors,
+		healthy:     true,
+	}
+}
+
+func (w *healthWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	skip := !w.healthy && time.Now().Before(w.retryAt)
+	w.mu.Unlock()
+	if skip {
+		return w.fallback.Write(p)
+	}
+
+	n, err := w.WriteSyncer.Write(p)
+	if err == nil {
+		w.succeed()
+		return n, nil
+	}
+	w.fail(err)
+	return w.fallback.Write(p)
+}
+
+// fail 记录失败并推迟下次重试，首次失败时输出诊断信息
+func (w *healthWriter) fail(err error) {
+	w.writeErrors.Add(1)
+	w.mu.Lock()
+	now := time.Now()
+	first := w.healthy
+	w.healthy = false
+	w.failures++
+	w.lastError, w.lastErrorAt = err, now
+	if first {
+		w.backoff = healthRetryMin
+	} else {
+		w.backoff = min(w.backoff*2, healthRetryMax)
+	}
+	w.retryAt = now.Add(w.backoff)
+	w.mu.Unlock()
+
+	if first {
+		diagnostics.Warn("log output failed, falling back to stderr",
+			zap.String("logger", w.logger), zap.String("output", w.output), zap.Error(err))
+	}
+}
+
+func (w *healthWriter) succeed() {
+	w.mu.Lock()
+	recovered := !w.healthy
+	w.healthy = true
+	w.backoff = 0
+	w.mu.Unlock()
+
+	if recovered {
+		diagnostics.Info("log output recovered", zap.String("logger", w.logger), zap.String("output", w.output))
+	}
+}
+
+func (w *healthWriter) status() OutputHealth {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	h := OutputHealth{
+		Output:      w.output,
+		Healthy:     w.healthy,
+		Failures:    w.failures,
+		LastErrorAt: w.lastErrorAt,
+	}
+	if w.lastError != nil {
+		h.LastError = w.lastError.Error()
+	}
+	if !w.healthy {
+		h.RetryAt = w.retryAt
+	}
+	return h
+}
+
+// OutputHealth 单个输出端的状态
+type OutputHealth struct {
+	Output      string    `json:"output"`                  // 输出端，如 file:./logs/app.log、sink:kafka
+	Healthy     bool      `json:"healthy"`                 // 最近一次写入是否成功
+	Failures    uint64    `json:"failures"`                // 累计失败次数
+	LastError   string    `json:"last_error,omitempty"`    // 最近一次失败的原因
+	LastErrorAt time.Time `json:"last_error_at,omitempty"` // 最近一次失败的时间
+	RetryAt     time.Time `json:"retry_at,omitempty"`      // 失败期间下次重试输出端的时间，之前的日志写stderr
+}
+
+// LoggerHealth 单个logger各输出端的状态
+type LoggerHealth struct {
+	Name    string         `json:"name"`
+	Healthy bool           `json:"healthy"`
+	Outputs []OutputHealth `json:"outputs"`
+}
+
+// HealthStatus 所有logger的输出端状态
+type HealthStatus struct {
+	Healthy bool           `json:"healthy"` // 所有输出端都正常
+	Loggers []LoggerHealth `json:"loggers"`
+}
+
+// Health 返回各logger文件与sink输出端的状态，可接入服务的健康检查。
+// 输出端写入失败时日志改写stderr，并按1s起、最长1m的退避间隔重试，成功后自动恢复
+func Health() HealthStatus {
+	metux.RLock()
+	defer metux.RUnlock()
+
+	status := HealthStatus{Healthy: true, Loggers: make([]LoggerHealth, 0, len(loggers))}
+	for name, entry := range loggers {
+		lh := LoggerHealth{Name: name, Healthy: true, Outputs: make([]OutputHealth, 0, len(entry.outputs))}
+		for _, w := range entry.outputs {
+			h := w.status()
+			lh.Healthy = lh.Healthy && h.Healthy
+			lh.Outputs = append(lh.Outputs, h)
+		}
+		status.Healthy = status.Healthy && lh.Healthy
+		status.Loggers = append(status.Loggers, lh)
+	}
+	sort.Slice(status.Loggers, func(i, j int) bool { return status.Loggers[i].Name < status.Loggers[j].Name })
+	return status
+}
@@ -0,0 +1,130 @@
+package log
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// flakySink 在down为true时写入失败
+type flakySink struct {
+	mu   sync.Mutex
+	down bool
+	buf  bytes.Buffer
+}
+
+func (s *flakySink) Write(p []byte) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.down {
+		return 0, errors.New("connection refused")
+	}
+	return s.buf.Write(p)
+}
+
+func (s *flakySink) Sync() error { return nil }
+
+func (s *flakySink) setDown(down bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.down = down
+}
+
+func TestHealthWriter(t *testing.T) {
+	var (
+		mu     sync.Mutex
+		stderr bytes.Buffer
+	)
+	stderrOutput = func(p []byte) (int, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		return stderr.Write(p)
+	}
+	t.Cleanup(func() { stderrOutput = writeStderr })
+	healthRetryMin = 50 * time.Millisecond
+	t.Cleanup(func() { healthRetryMin = time.Second })
+
+	sink := &flakySink{down: true}
+	var writeErrors atomic.Uint64
+	w := newHealthWriter("access", "sink:kafka", zapcore.AddSync(sink), &writeErrors)
+
+	if _, err := w.Write([]byte("first\n")); err != nil {
+		t.Fatal(err)
+	}
+	// 退避期间不再尝试输出端
+	sink.setDown(false)
+	if _, err := w.Write([]byte("second\n")); err != nil {
+		t.Fatal(err)
+	}
+	status := w.status()
+	if status.Healthy || status.Failures != 1 || status.LastError != "connection refused" || status.RetryAt.IsZero() {
+		t.Fatalf("unexpected status %+v", status)
+	}
+	if writeErrors.Load() != 1 {
+		t.Fatalf("expected 1 write error, got %d", writeErrors.Load())
+	}
+	mu.Lock()
+	got := stderr.String()
+	mu.Unlock()
+	if !strings.Contains(got, "first") || !strings.Contains(got, "second") || !strings.Contains(got, "falling back to stderr") {
+		t.Fatalf("failed writes should go to stderr, got %q", got)
+	}
+
+	time.Sleep(60 * time.Millisecond)
+	if _, err := w.Write([]byte("third\n")); err != nil {
+		t.Fatal(err)
+	}
+	if status := w.status(); !status.Healthy || status.Failures != 1 {
+		t.Fatalf("output should recover after backoff, got %+v", status)
+	}
+	if sink.buf.String() != "third\n" {
+		t.Fatalf("unexpected sink content %q", sink.buf.String())
+	}
+}
+
+func TestHealth(t *testing.T) {
+	sink := &flakySink{}
+	RegisterSink("test-flaky", func(string, map[string]any) (zapcore.WriteSyncer, error) {
+		return sink, nil
+	})
+	stderrOutput = func(p []byte) (int, error) { return len(p), nil }
+	t.Cleanup(func() { stderrOutput = writeStderr })
+
+	dir := t.TempDir()
+	Close()
+	if err := Init(WithConfig(Config{Zaplog: []LogConfig{
+		{Name: "default", FileName: dir + "/app.log"},
+		{Name: "access", FileName: dir + "/access.log", Sinks: []SinkConfig{{Type: "test-flaky"}}},
+	}})); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(Close)
+
+	if status := Health(); !status.Healthy || len(status.Loggers) != 2 || len(status.Loggers[0].Outputs) != 2 {
+		t.Fatalf("unexpected health %+v", status)
+	}
+	sink.setDown(true)
+	GetLogger("access").Info("lost sink")
+
+	status := Health()
+	if status.Healthy || status.Loggers[0].Healthy || !status.Loggers[1].Healthy {
+		t.Fatalf("access should be unhealthy, got %+v", status)
+	}
+	if out := status.Loggers[0].Outputs[1]; out.Output != "sink:test-flaky" || out.Healthy {
+		t.Fatalf("unexpected sink status %+v", out)
+	}
+
+	rec := httptest.NewRecorder()
+	AdminHandler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/debug/log/health", nil))
+	if rec.Code != http.StatusServiceUnavailable || !strings.Contains(rec.Body.String(), "connection refused") {
+		t.Fatalf("unexpected response %d %s", rec.Code, rec.Body.String())
+	}
+}
@@ -0,0 +1,48 @@
+package log
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// hookList logger上通过AddHook注册的钩子，创建logger时即挂载，
+// 之后注册的钩子对已获取的logger和模块logger同样生效
+type hookList struct {
+	mu    sync.RWMutex
+	hooks []func(zapcore.Entry) error
+}
+
+func (h *hookList) add(fn func(zapcore.Entry) error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.hooks = append(h.hooks, fn)
+}
+
+func (h *hookList) run(ent zapcore.Entry) error {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	var errs []error
+	for _, fn := range h.hooks {
+		if err := fn(ent); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// AddHook 为指定logger注册钩子，每条输出的日志都会调用fn，可用于错误计数、告警或转发到自定义系统。
+// fn返回的错误写入zap的ErrorOutput
+func AddHook(name string, fn func(zapcore.Entry) error) error {
+	metux.RLock()
+	entry, ok := loggers[name]
+	metux.RUnlock()
+	if !ok || entry.hooks == nil {
+		return fmt.Errorf("logger %s not found", name)
+	}
+	entry.hooks.add(fn)
+	return nil
+}
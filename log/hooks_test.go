@@ -0,0 +1,49 @@
+package log
+
+import (
+	"errors"
+	"sync/atomic"
+	"testing"
+
+	"go.uber.org/zap/zapcore"
+)
+
+func TestAddHook(t *testing.T) {
+	initTestLoggers(t, "access")
+	logger := GetLogger("access")
+	module := Module("dao")
+
+	var errorsSeen atomic.Int64
+	err := AddHook("access", func(ent zapcore.Entry) error {
+		if ent.Level >= zapcore.ErrorLevel {
+			errorsSeen.Add(1)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 注册前获取的logger同样生效
+	logger.Info("ok")
+	logger.Error("failed")
+	logger.Debug("filtered")
+	if got := errorsSeen.Load(); got != 1 {
+		t.Fatalf("expected 1 error, got %d", got)
+	}
+
+	var defaultSeen atomic.Int64
+	if err := AddHook("default", func(zapcore.Entry) error {
+		defaultSeen.Add(1)
+		return errors.New("hook failed")
+	}); err != nil {
+		t.Fatal(err)
+	}
+	module.Warn("from module")
+	if defaultSeen.Load() != 1 {
+		t.Fatal("hook should apply to module loggers")
+	}
+
+	if err := AddHook("missing", func(zapcore.Entry) error { return nil }); err == nil {
+		t.Fatal("expected error for unknown logger")
+	}
+}
@@ -0,0 +1,114 @@
+package log
+
+import (
+	"net/http"
+	"net/http/httputil"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// HTTPMiddleware 返回net/http访问日志中间件，适用于未使用web框架的服务
+func HTTPMiddleware(name string, opts ...Option) func(http.Handler) http.Handler {
+	o := newOptions(opts)
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+			rw := &responseWriter{ResponseWriter: w, status: http.StatusOK}
+			ctx := WithMDC(r.Context())
+			if o.dumpRequested(r.Header) {
+				ctx = ForceDump(ctx)
+			}
+			r = r.WithContext(ctx)
+			next.ServeHTTP(rw, r)
+
+			latency := time.Since(start)
+			level := o.levelFor(r.URL.Path)
+			switch {
+			case rw.status >= http.StatusInternalServerError:
+				level = zapcore.ErrorLevel
+			case rw.status >= http.StatusBadRequest:
+				level = zapcore.WarnLevel
+			case o.isSlow(latency) && level < zapcore.WarnLevel:
+				level = zapcore.WarnLevel
+			}
+
+			ce := GetLogger(name).Check(level, "http request")
+			if ce == nil {
+				return
+			}
+			fields := []zap.Field{
+				zap.String("method", r.Method),
+				zap.String("path", r.URL.Path),
+				zap.String("query", r.URL.RawQuery),
+				zap.Int("status", rw.status),
+				zap.Int64("bytes", rw.bytes),
+				zap.Duration("latency", latency),
+				zap.String("remote_addr", r.RemoteAddr),
+				zap.String("user_agent", r.UserAgent()),
+			}
+			if r.Pattern != "" {
+				fields = append(fields, zap.String("route", r.Pattern))
+			}
+			if id := r.Header.Get(o.requestIDKey); id != "" {
+				fields = append(fields, zap.String("request_id", id))
+			}
+			if o.isSlow(latency) {
+				fields = append(fields, zap.Bool("slow", true))
+			}
+			fields = append(fields, mdcFields(r.Context())...)
+			ce.Write(fields...)
+		})
+	}
+}
+
+// responseWriter 记录响应状态码与写入字节数
+type responseWriter struct {
+	http.ResponseWriter
+	status      int
+	bytes       int64
+	wroteHeader bool
+}
+
+func (w *responseWriter) WriteHeader(status int) {
+	if !w.wroteHeader {
+		w.status = status
+		w.wroteHeader = true
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *responseWriter) Write(b []byte) (int, error) {
+	w.wroteHeader = true
+	n, err := w.ResponseWriter.Write(b)
+	w.bytes += int64(n)
+	return n, err
+}
+
+func (w *responseWriter) Flush() {
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Unwrap 供http.ResponseController访问底层ResponseWriter
+func (w *responseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// sensitiveHeaders 记录请求时脱敏的请求头
+var sensitiveHeaders = []string{"Authorization", "Proxy-Authorization", "Cookie", "X-Api-Key", "X-Auth-Token"}
+
+// dumpRequest 返回请求行与请求头，敏感请求头的值替换为*，用于panic恢复日志
+func dumpRequest(r *http.Request) []byte {
+	req := *r
+	req.Header = r.Header.Clone()
+	for _, key := range sensitiveHeaders {
+		if req.Header.Get(key) != "" {
+			req.Header.Set(key, "*")
+		}
+	}
+	request, _ := httputil.DumpRequest(&req, false)
+	return request
+}
@@ -0,0 +1,42 @@
+package log
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+func TestHTTPMiddleware(t *testing.T) {
+	logs := observeLogger(t, "access")
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /users/{id}", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("hello"))
+	})
+	mux.HandleFunc("GET /slow", func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(20 * time.Millisecond)
+	})
+	handler := HTTPMiddleware("access", WithSlowThreshold(10*time.Millisecond))(mux)
+
+	for _, path := range []string{"/users/1", "/slow", "/missing"} {
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+	}
+
+	entries := logs.All()
+	if len(entries) != 3 {
+		t.Fatalf("expected 3 entries, got %d", len(entries))
+	}
+	fields := entries[0].ContextMap()
+	if entries[0].Level != zapcore.InfoLevel || fields["status"] != int64(200) || fields["bytes"] != int64(5) {
+		t.Fatalf("unexpected entry: %v %v", entries[0].Level, fields)
+	}
+	if entries[1].Level != zapcore.WarnLevel || entries[1].ContextMap()["slow"] != true {
+		t.Fatalf("slow request should be logged at warn: %v %v", entries[1].Level, entries[1].ContextMap())
+	}
+	if entries[2].Level != zapcore.WarnLevel || entries[2].ContextMap()["status"] != int64(404) {
+		t.Fatalf("unexpected not found entry: %v %v", entries[2].Level, entries[2].ContextMap())
+	}
+}
@@ -0,0 +1,158 @@
+package log
+
+import (
+	"runtime"
+	"runtime/debug"
+	"slices"
+	"sort"
+	"time"
+)
+
+// InventoryReport 日志配置清单，供合规检查工具解析
+type InventoryReport struct {
+	GeneratedAt  time.Time         `json:"generated_at"`
+	GoVersion    string            `json:"go_version"`
+	Components   []Component       `json:"components"`              // goeasy及日志相关依赖的版本
+	Loggers      []LoggerInventory `json:"loggers"`                 //
+	ModuleLevels map[string]string `json:"module_levels,omitempty"` // 模块级别覆盖
+	Teams        []TeamConfig      `json:"teams,omitempty"`
+	Silence      []SilenceWindow   `json:"silence_windows,omitempty"`
+	Encoders     []string          `json:"encoders"` // 已注册的编码器
+	Sinks        []string          `json:"sinks"`    // 已注册的输出端
+}
+
+// Component 依赖组件及版本
+type Component struct {
+	Path    string `json:"path"`
+	Version string `json:"version"`
+}
+
+// LoggerInventory 单个logger的配置清单
+type LoggerInventory struct {
+	Name     string            `json:"name"`
+	Level    string            `json:"level"`     // 当前生效的级别
+	Encoder  string            `json:"encoder"`   // 编码格式
+	FileName string            `json:"file_name"` // 文件输出路径
+	Rotation RotationInventory `json:"rotation"`
+	Sinks    []string          `json:"sinks,omitempty"`    // 额外输出端
+	Features []string          `json:"features,omitempty"` // 启用的附加功能，如fingerprint、daily_budget
+}
+
+// RotationInventory 文件切割与保留策略
+type RotationInventory struct {
+	MaxSizeMB  int  `json:"max_size_mb"`
+	MaxAgeDays int  `json:"max_age_days"`
+	MaxBackups int  `json:"max_backups"`
+	Compress   bool `json:"compress"`
+}
+
+// inventoryModules 清单中列出版本的依赖
+var inventoryModules = []string{
+	"github.com/allanchen1214/goeasy",
+	"go.uber.org/zap",
+	"gopkg.in/natefinch/lumberjack.v2",
+	"github.com/spf13/viper",
+}
+
+// Inventory 返回当前日志配置的清单：各logger的级别、编码、输出、切割策略和启用的功能，以及相关组件版本
+func Inventory() InventoryReport {
+	report := InventoryReport{
+		GeneratedAt: time.Now(),
+		GoVersion:   runtime.Version(),
+		Components:  components(),
+		Encoders:    registeredList(encoderFactories),
+		Sinks:       registeredList(sinkFactories),
+	}
+
+	metux.RLock()
+	defer metux.RUnlock()
+
+	for _, entry := range loggers {
+		report.Loggers = append(report.Loggers, loggerInventory(entry))
+	}
+	sort.Slice(report.Loggers, func(i, j int) bool { return report.Loggers[i].Name < report.Loggers[j].Name })
+	if len(moduleLevels) > 0 {
+		report.ModuleLevels = make(map[string]string, len(moduleLevels))
+		for k, v := range moduleLevels {
+			report.ModuleLevels[k] = v
+		}
+	}
+	for _, t := range teams {
+		report.Teams = append(report.Teams, t.cfg)
+	}
+	report.Silence = slices.Clone(silenceWindows)
+	return report
+}
+
+func loggerInventory(entry *logEntry) LoggerInventory {
+	cfg := entry.cfg
+	inv := LoggerInventory{
+		Name:     cfg.Name,
+		Level:    entry.level.Level().String(),
+		Encoder:  encoderName(cfg),
+		FileName: cfg.FileName,
+		Rotation: RotationInventory{
+			MaxSizeMB:  cfg.MaxSize,
+			MaxAgeDays: cfg.MaxAge,
+			MaxBackups: cfg.MaxBackups,
+			Compress:   cfg.Compress,
+		},
+	}
+	for _, s := range cfg.Sinks {
+		inv.Sinks = append(inv.Sinks, s.Type)
+	}
+
+	features := []struct {
+		name    string
+		enabled bool
+	}{
+		{"caller", cfg.ShowCaller},
+		{"development", cfg.Development},
+		{"error_fingerprint", cfg.ErrorFingerprint},
+		{"daily_budget", cfg.DailyBudgetMB > 0},
+		{"reentrancy_guard", cfg.ReentrancyGuard},
+		{"goroutine_id", cfg.GoroutineID},
+		{"k8s_metadata", cfg.K8sMetadata},
+		{"build_info", cfg.BuildInfo},
+		{"sanitize", cfg.Sanitize},
+		{"stacktrace", cfg.StacktraceLevel != "" && cfg.StacktraceLevel != "none"},
+		{"alert_annotation", cfg.AlertAnnotation.URL != ""},
+		{"error_burst", cfg.ErrorBurst.Threshold > 0},
+		{"sampling", cfg.Sampling.enabled()},
+		{"dump", cfg.Dump.Rate > 0},
+		{"ring_buffer", cfg.RingBuffer > 0},
+		{"archive", cfg.Archive.Bucket != ""},
+		{"encryption", cfg.Encryption.enabled()},
+		{"audit", cfg.Audit},
+	}
+	for _, f := range features {
+		if f.enabled {
+			inv.Features = append(inv.Features, f.name)
+		}
+	}
+	return inv
+}
+
+// components 从构建信息中读取相关依赖的版本
+func components() []Component {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return nil
+	}
+	versions := map[string]string{info.Main.Path: info.Main.Version}
+	for _, dep := range info.Deps {
+		version := dep.Version
+		if dep.Replace != nil {
+			version = dep.Replace.Version
+		}
+		versions[dep.Path] = version
+	}
+
+	var out []Component
+	for _, path := range inventoryModules {
+		if version, ok := versions[path]; ok {
+			out = append(out, Component{Path: path, Version: version})
+		}
+	}
+	return out
+}
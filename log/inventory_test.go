@@ -0,0 +1,38 @@
+package log
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+)
+
+func TestInventory(t *testing.T) {
+	initTestLoggers(t, "access")
+
+	report := Inventory()
+	if len(report.Loggers) != 2 || report.Loggers[0].Name != "access" || report.Loggers[1].Name != "default" {
+		t.Fatalf("unexpected loggers %+v", report.Loggers)
+	}
+	access := report.Loggers[0]
+	if access.Encoder != "json" || access.Level != "info" || access.Rotation.MaxSizeMB != 100 {
+		t.Fatalf("unexpected access inventory %+v", access)
+	}
+	if !slices.Contains(report.Encoders, "logfmt") {
+		t.Fatalf("expected built-in encoders, got %v", report.Encoders)
+	}
+	if report.GoVersion == "" {
+		t.Fatal("missing go version")
+	}
+
+	rec := httptest.NewRecorder()
+	AdminHandler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/debug/log/inventory", nil))
+	var got InventoryReport
+	if err := json.Unmarshal(rec.Body.Bytes(), &got); err != nil {
+		t.Fatal(err)
+	}
+	if len(got.Loggers) != 2 {
+		t.Fatalf("unexpected inventory response %s", rec.Body.String())
+	}
+}
@@ -0,0 +1,127 @@
+//go:build linux
+
+package log
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"syscall"
+
+	"go.uber.org/zap/zapcore"
+)
+
+const journaldSocket = "/run/systemd/journal/socket"
+
+// journaldSink 通过journald原生协议写入systemd journal，日志行中的字段转换为journal字段，
+// 可用journalctl -o verbose或journalctl USER_ID=42查看与过滤。
+// options：identifier(SYSLOG_IDENTIFIER，默认程序名)、socket、level_key、message_key、time_key、caller_key
+type journaldSink struct {
+	conn       *net.UnixConn
+	addr       *net.UnixAddr
+	identifier string
+	logger     string
+	keys       sysEntryKeys
+	callerKey  string
+}
+
+func newJournaldSink(logger string, options map[string]any) (zapcore.WriteSyncer, error) {
+	socket := sinkString(options, "socket", journaldSocket)
+	if _, err := os.Stat(socket); err != nil {
+		return nil, fmt.Errorf("journald socket not available: %w", err)
+	}
+	conn, err := net.ListenUnixgram("unixgram", &net.UnixAddr{Net: "unixgram"})
+	if err != nil {
+		return nil, err
+	}
+	return &journaldSink{
+		conn:       conn,
+		addr:       &net.UnixAddr{Name: socket, Net: "unixgram"},
+		identifier: sinkString(options, "identifier", programName()),
+		logger:     logger,
+		keys:       newSysEntryKeys(options),
+		callerKey:  sinkString(options, "caller_key", "caller"),
+	}, nil
+}
+
+func (s *journaldSink) Write(p []byte) (int, error) {
+	e := parseSysEntry(p, s.keys)
+
+	var buf bytes.Buffer
+	appendJournalField(&buf, "MESSAGE", e.message)
+	appendJournalField(&buf, "PRIORITY", strconv.Itoa(journalPriority(e.level)))
+	appendJournalField(&buf, "SYSLOG_IDENTIFIER", s.identifier)
+	appendJournalField(&buf, "LOGGER", s.logger)
+	if raw, ok := e.fields[s.callerKey]; ok {
+		caller := jsonText(raw)
+		if i := strings.LastIndexByte(caller, ':'); i > 0 {
+			appendJournalField(&buf, "CODE_FILE", caller[:i])
+			appendJournalField(&buf, "CODE_LINE", caller[i+1:])
+		}
+		delete(e.fields, s.callerKey)
+	}
+	keys := make([]string, 0, len(e.fields))
+	for k := range e.fields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		appendJournalField(&buf, journalFieldName(k), jsonText(e.fields[k]))
+	}
+
+	if err := s.send(buf.Bytes()); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// send 超过数据报大小上限时，按协议将内容写入已删除的临时文件并传递文件描述符
+func (s *journaldSink) send(data []byte) error {
+	_, _, err := s.conn.WriteMsgUnix(data, nil, s.addr)
+	if err == nil || !errors.Is(err, syscall.EMSGSIZE) && !errors.Is(err, syscall.ENOBUFS) {
+		return err
+	}
+
+	f, err := os.CreateTemp("/dev/shm", "goeasy-journal-")
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	if err := os.Remove(f.Name()); err != nil {
+		return err
+	}
+	if _, err := f.Write(data); err != nil {
+		return err
+	}
+	_, _, err = s.conn.WriteMsgUnix(nil, syscall.UnixRights(int(f.Fd())), s.addr)
+	return err
+}
+
+func (s *journaldSink) Sync() error {
+	return nil
+}
+
+func (s *journaldSink) Close() error {
+	return s.conn.Close()
+}
+
+// appendJournalField 单行值写为KEY=value，多行值写为KEY、8字节小端长度与原始内容
+func appendJournalField(buf *bytes.Buffer, key, value string) {
+	buf.WriteString(key)
+	if !strings.ContainsRune(value, '\n') {
+		buf.WriteByte('=')
+		buf.WriteString(value)
+		buf.WriteByte('\n')
+		return
+	}
+	buf.WriteByte('\n')
+	_ = binary.Write(buf, binary.LittleEndian, uint64(len(value)))
+	buf.WriteString(value)
+	buf.WriteByte('\n')
+}
@@ -0,0 +1,59 @@
+package log
+
+import (
+	"bytes"
+	"encoding/binary"
+	"net"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestJournaldSink(t *testing.T) {
+	socket := filepath.Join(t.TempDir(), "journal.sock")
+	server, err := net.ListenUnixgram("unixgram", &net.UnixAddr{Name: socket, Net: "unixgram"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer server.Close()
+
+	sink, err := newJournaldSink("access", map[string]any{"socket": socket, "identifier": "api"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sink.(*journaldSink).Close()
+
+	line := `{"level":"ERROR","ts":"2024-01-02T03:04:05Z","caller":"dao/user.go:42","msg":"query failed","user_id":"42","stack":"a\nb"}` + "\n"
+	if n, err := sink.Write([]byte(line)); err != nil || n != len(line) {
+		t.Fatalf("Write = %d, %v", n, err)
+	}
+
+	buf := make([]byte, 4096)
+	_ = server.SetReadDeadline(time.Now().Add(2 * time.Second))
+	n, err := server.Read(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := string(buf[:n])
+	for _, want := range []string{
+		"MESSAGE=query failed\n", "PRIORITY=3\n", "SYSLOG_IDENTIFIER=api\n", "LOGGER=access\n",
+		"CODE_FILE=dao/user.go\n", "CODE_LINE=42\n", "USER_ID=42\n",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("missing %q in %q", want, got)
+		}
+	}
+	// 多行值以长度前缀编码
+	var multi bytes.Buffer
+	multi.WriteString("STACK\n")
+	_ = binary.Write(&multi, binary.LittleEndian, uint64(3))
+	multi.WriteString("a\nb\n")
+	if !strings.Contains(got, multi.String()) {
+		t.Errorf("multi-line field not encoded with length prefix: %q", got)
+	}
+
+	if _, err := newJournaldSink("access", map[string]any{"socket": filepath.Join(t.TempDir(), "missing")}); err == nil {
+		t.Fatal("expected error for a missing socket")
+	}
+}
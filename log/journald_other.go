@@ -0,0 +1,13 @@
+//go:build !linux
+
+package log
+
+import (
+	"errors"
+
+	"go.uber.org/zap/zapcore"
+)
+
+func newJournaldSink(string, map[string]any) (zapcore.WriteSyncer, error) {
+	return nil, errors.New("journald sink is only supported on linux")
+}
@@ -0,0 +1,141 @@
+package log
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// LevelAnnotation 控制日志级别的注解名：
+//
+//	goeasy.io/log-level: warn            调整所有logger
+//	goeasy.io/log-level.access: debug    调整指定logger，优先级高于上一条
+//
+// 注解被删除后，logger恢复为注解生效前的级别；期间通过SetLevel、SIGUSR1等途径修改过的级别保持不变，
+// EnableDebugFor的调试窗口结束后恢复为注解设置的级别。
+const LevelAnnotation = "goeasy.io/log-level"
+
+// annotatedLevel 注解设置的级别及注解生效前的级别
+type annotatedLevel struct {
+	level   zapcore.Level
+	restore zapcore.Level
+}
+
+var (
+	annotatedLevels = make(map[*logEntry]annotatedLevel)
+	annotationMu    sync.Mutex
+)
+
+// ApplyLevelAnnotations 按注解调整所有已注册logger的级别，可在controller/informer的回调中直接调用
+func ApplyLevelAnnotations(annotations map[string]string) error {
+	metux.RLock()
+	defer metux.RUnlock()
+	annotationMu.Lock()
+	defer annotationMu.Unlock()
+
+	var errs []error
+	for name, entry := range loggers {
+		level, ok := annotations[LevelAnnotation+"."+name]
+		if !ok {
+			level, ok = annotations[LevelAnnotation]
+		}
+		prev, annotated := annotatedLevels[entry]
+		if !ok {
+			// 只撤销注解自身设置的级别
+			if annotated {
+				resetBaseLevel(entry, prev.level, prev.restore)
+				delete(annotatedLevels, entry)
+			}
+			continue
+		}
+		if !isValidLevel(level) {
+			errs = append(errs, fmt.Errorf("logger %s: %w", name, invalidLevel(level)))
+			continue
+		}
+		next := annotatedLevel{level: getLevel(level), restore: baseLevel(entry)}
+		if annotated {
+			next.restore = prev.restore
+		}
+		annotatedLevels[entry] = next
+		setBaseLevel(entry, next.level)
+	}
+	// 清理已移除的logger
+	for entry := range annotatedLevels {
+		if loggers[entry.cfg.Name] != entry {
+			delete(annotatedLevels, entry)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// WatchLevelAnnotationsFile 定期读取downward API挂载的注解文件，文件变化时调用ApplyLevelAnnotations，
+// ctx取消后返回。onError可为nil。
+func WatchLevelAnnotationsFile(ctx context.Context, path string, interval time.Duration, onError func(error)) {
+	if onError == nil {
+		onError = func(error) {}
+	}
+
+	var last []byte
+	apply := func() {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			onError(fmt.Errorf("failed to read annotations: %w", err))
+			return
+		}
+		if last != nil && bytes.Equal(data, last) {
+			return
+		}
+		last = data
+
+		annotations, err := parseAnnotations(data)
+		if err != nil {
+			onError(err)
+			return
+		}
+		if err := ApplyLevelAnnotations(annotations); err != nil {
+			onError(err)
+		}
+	}
+
+	apply()
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			apply()
+		}
+	}
+}
+
+// parseAnnotations 解析downward API注解文件，每行格式为 key="value"
+func parseAnnotations(data []byte) (map[string]string, error) {
+	annotations := make(map[string]string)
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid annotation line %q", line)
+		}
+		if unquoted, err := strconv.Unquote(value); err == nil {
+			value = unquoted
+		}
+		annotations[key] = value
+	}
+	return annotations, scanner.Err()
+}
@@ -0,0 +1,95 @@
+package log
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+func TestApplyLevelAnnotations(t *testing.T) {
+	initTestLoggers(t, "access")
+
+	err := ApplyLevelAnnotations(map[string]string{
+		LevelAnnotation:             "warn",
+		LevelAnnotation + ".access": "debug",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !GetLogger("access").Core().Enabled(zapcore.DebugLevel) {
+		t.Fatal("access should be at debug")
+	}
+	if GetDefaultLogger().Core().Enabled(zapcore.InfoLevel) {
+		t.Fatal("default should be at warn")
+	}
+
+	if err := ApplyLevelAnnotations(nil); err != nil {
+		t.Fatal(err)
+	}
+	if GetLogger("access").Core().Enabled(zapcore.DebugLevel) {
+		t.Fatal("access should be restored to info")
+	}
+
+	if err := ApplyLevelAnnotations(map[string]string{LevelAnnotation: "verbose"}); err == nil {
+		t.Fatal("expected error for invalid level")
+	}
+}
+
+func TestLevelAnnotationKeepsOverrides(t *testing.T) {
+	initTestLoggers(t, "access")
+
+	if err := ApplyLevelAnnotations(map[string]string{LevelAnnotation: "warn"}); err != nil {
+		t.Fatal(err)
+	}
+	// 删除注解不影响调试窗口，窗口结束后恢复为注解生效前的级别
+	if err := EnableDebugFor("access", time.Hour); err != nil {
+		t.Fatal(err)
+	}
+	if err := ApplyLevelAnnotations(nil); err != nil {
+		t.Fatal(err)
+	}
+	if !GetLogger("access").Core().Enabled(zapcore.DebugLevel) {
+		t.Fatal("debug window should survive annotation removal")
+	}
+	metux.RLock()
+	entry := loggers["access"]
+	metux.RUnlock()
+	if level := baseLevel(entry); level != zapcore.InfoLevel {
+		t.Fatalf("expected info after debug window, got %s", level)
+	}
+
+	// 级别被其他途径修改后，删除注解不再覆盖
+	if err := ApplyLevelAnnotations(map[string]string{LevelAnnotation: "warn"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := SetLevel("default", "error"); err != nil {
+		t.Fatal(err)
+	}
+	if err := ApplyLevelAnnotations(nil); err != nil {
+		t.Fatal(err)
+	}
+	if GetDefaultLogger().Core().Enabled(zapcore.WarnLevel) {
+		t.Fatal("level set by SetLevel should be kept")
+	}
+}
+
+func TestWatchLevelAnnotationsFile(t *testing.T) {
+	dir := initTestLoggers(t, "access")
+	path := filepath.Join(dir, "annotations")
+	content := "app=\"demo\"\n" + LevelAnnotation + ".access=\"debug\"\n"
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	WatchLevelAnnotationsFile(ctx, path, 10*time.Millisecond, func(err error) { t.Error(err) })
+
+	if !GetLogger("access").Core().Enabled(zapcore.DebugLevel) {
+		t.Fatal("access should be at debug")
+	}
+}
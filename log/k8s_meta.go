@@ -0,0 +1,95 @@
+package log
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// k8sPodInfoDir downward API卷的挂载目录，文件名为pod_name、pod_namespace、node_name、container_name
+var k8sPodInfoDir = "/etc/podinfo"
+
+// k8sNamespaceFile ServiceAccount挂载的命名空间文件，未通过downward API提供命名空间时使用
+var k8sNamespaceFile = "/var/run/secrets/kubernetes.io/serviceaccount/namespace"
+
+// k8sMetadata Pod的元数据，附加为k8s字段
+type k8sMetadata struct {
+	Pod       string
+	Namespace string
+	Node      string
+	Container string
+}
+
+var (
+	k8sMetaOnce  sync.Once
+	k8sMetaValue k8sMetadata
+)
+
+// currentK8sMetadata 返回进程所在Pod的元数据，只读取一次
+func currentK8sMetadata() k8sMetadata {
+	k8sMetaOnce.Do(func() {
+		k8sMetaValue = readK8sMetadata()
+	})
+	return k8sMetaValue
+}
+
+// readK8sMetadata 依次从downward API注入的环境变量、downward API卷中的文件读取元数据。
+// 均未提供时，Pod名称取主机名（仅在Kubernetes中），命名空间取ServiceAccount的命名空间文件
+func readK8sMetadata() k8sMetadata {
+	meta := k8sMetadata{
+		Pod:       k8sValue("POD_NAME", "pod_name"),
+		Namespace: k8sValue("POD_NAMESPACE", "pod_namespace"),
+		Node:      k8sValue("NODE_NAME", "node_name"),
+		Container: k8sValue("CONTAINER_NAME", "container_name"),
+	}
+	if meta.Pod == "" && os.Getenv("KUBERNETES_SERVICE_HOST") != "" {
+		meta.Pod, _ = os.Hostname()
+	}
+	if meta.Namespace == "" {
+		meta.Namespace = readTrimmed(k8sNamespaceFile)
+	}
+	return meta
+}
+
+func k8sValue(env, file string) string {
+	if v := os.Getenv(env); v != "" {
+		return v
+	}
+	return readTrimmed(filepath.Join(k8sPodInfoDir, file))
+}
+
+func readTrimmed(path string) string {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(data))
+}
+
+// MarshalLogObject 只输出非空的项
+func (m k8sMetadata) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	for _, kv := range [][2]string{
+		{"pod", m.Pod},
+		{"namespace", m.Namespace},
+		{"node", m.Node},
+		{"container", m.Container},
+	} {
+		if kv[1] != "" {
+			enc.AddString(kv[0], kv[1])
+		}
+	}
+	return nil
+}
+
+// k8sFields 返回附加到每条日志的k8s字段，不在Kubernetes中运行时返回空
+func k8sFields() []zap.Field {
+	meta := currentK8sMetadata()
+	if meta == (k8sMetadata{}) {
+		return nil
+	}
+	return []zap.Field{zap.Object("k8s", meta)}
+}
@@ -0,0 +1,41 @@
+package log
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestReadK8sMetadata(t *testing.T) {
+	dir := t.TempDir()
+	oldDir, oldNamespace := k8sPodInfoDir, k8sNamespaceFile
+	k8sPodInfoDir, k8sNamespaceFile = dir, filepath.Join(dir, "sa-namespace")
+	t.Cleanup(func() { k8sPodInfoDir, k8sNamespaceFile = oldDir, oldNamespace })
+
+	t.Setenv("POD_NAME", "api-7d9f-x2k4")
+	t.Setenv("POD_NAMESPACE", "")
+	t.Setenv("NODE_NAME", "")
+	t.Setenv("CONTAINER_NAME", "")
+	if err := os.WriteFile(filepath.Join(dir, "node_name"), []byte("node-1\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "sa-namespace"), []byte("prod"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	want := k8sMetadata{Pod: "api-7d9f-x2k4", Namespace: "prod", Node: "node-1"}
+	if got := readK8sMetadata(); got != want {
+		t.Fatalf("readK8sMetadata() = %+v, want %+v", got, want)
+	}
+
+	core, logs := observer.New(zapcore.DebugLevel)
+	zap.New(core).Info("hello", zap.Object("k8s", want))
+	fields := logs.All()[0].ContextMap()["k8s"].(map[string]any)
+	if len(fields) != 3 || fields["pod"] != "api-7d9f-x2k4" || fields["node"] != "node-1" {
+		t.Fatalf("unexpected k8s field: %#v", fields)
+	}
+}
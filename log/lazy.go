@@ -0,0 +1,31 @@
+package log
+
+import (
+	"sync"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// Lazy 返回延迟求值的字段，fn只在日志通过级别检查、真正编码时才调用，
+// 适合序列化大结构体或查询数据快照等开销较大的字段。
+// 同一条日志输出到多个输出端时fn只调用一次，返回值按Any的规则编码。
+// 字段本身在调用时构造，热路径上需要级别未开启时零分配的，配合Logger.Check或DebugFunc使用
+func Lazy(key string, fn func() interface{}) zap.Field {
+	return zap.Inline(&lazyField{key: key, fn: fn})
+}
+
+type lazyField struct {
+	key  string
+	fn   func() interface{}
+	once sync.Once
+	val  zap.Field
+}
+
+func (f *lazyField) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	f.once.Do(func() {
+		f.val = Any(f.key, f.fn())
+	})
+	f.val.AddTo(enc)
+	return nil
+}
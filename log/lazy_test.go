@@ -0,0 +1,36 @@
+package log
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLazy(t *testing.T) {
+	dir := initTestLoggers(t)
+	logger := GetDefaultLogger()
+
+	calls := 0
+	dump := func() interface{} {
+		calls++
+		return map[string]int{"rows": 42}
+	}
+	logger.Debug("skipped", Lazy("snapshot", dump))
+	if calls != 0 {
+		t.Fatal("lazy field should not be evaluated below the logger level")
+	}
+
+	logger.Info("dumped", Lazy("snapshot", dump), Lazy("count", func() interface{} { return 7 }))
+	Close()
+	if calls != 1 {
+		t.Fatalf("lazy field should be evaluated once, got %d", calls)
+	}
+	data, err := os.ReadFile(filepath.Join(dir, "default.log"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := string(data); !strings.Contains(got, `"snapshot":{"rows":42},"count":7`) || strings.Contains(got, "skipped") {
+		t.Fatalf("unexpected output %s", got)
+	}
+}
@@ -0,0 +1,45 @@
+package log
+
+import "sort"
+
+// LoggerInfo 已注册logger的概要信息
+type LoggerInfo struct {
+	Name            string            `json:"name"`
+	Level           string            `json:"level"`            // 当前生效的级别
+	ConfiguredLevel string            `json:"configured_level"` // 配置文件中的级别
+	Outputs         []string          `json:"outputs"`          // 输出目标，如 file:./logs/app.log、stdout、sink:kafka
+	Rotation        RotationInventory `json:"rotation"`         // 文件切割与保留策略
+}
+
+// List 返回所有已注册logger的名称、级别、输出目标和切割策略，按名称排序
+func List() []LoggerInfo {
+	metux.RLock()
+	defer metux.RUnlock()
+
+	infos := make([]LoggerInfo, 0, len(loggers))
+	for name, entry := range loggers {
+		cfg := entry.cfg
+		var outputs []string
+		for _, name := range entry.fileNames() {
+			outputs = append(outputs, "file:"+name)
+		}
+		outputs = append(outputs, "stdout")
+		for _, s := range cfg.Sinks {
+			outputs = append(outputs, "sink:"+s.Type)
+		}
+		infos = append(infos, LoggerInfo{
+			Name:            name,
+			Level:           entry.level.Level().String(),
+			ConfiguredLevel: cfg.Level,
+			Outputs:         outputs,
+			Rotation: RotationInventory{
+				MaxSizeMB:  cfg.MaxSize,
+				MaxAgeDays: cfg.MaxAge,
+				MaxBackups: cfg.MaxBackups,
+				Compress:   cfg.Compress,
+			},
+		})
+	}
+	sort.Slice(infos, func(i, j int) bool { return infos[i].Name < infos[j].Name })
+	return infos
+}
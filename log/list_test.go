@@ -0,0 +1,48 @@
+package log
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestList(t *testing.T) {
+	dir := initTestLoggers(t, "access")
+	if err := SetLevel("access", "debug"); err != nil {
+		t.Fatal(err)
+	}
+
+	infos := List()
+	if len(infos) != 2 || infos[0].Name != "access" || infos[1].Name != "default" {
+		t.Fatalf("unexpected loggers: %+v", infos)
+	}
+	access := infos[0]
+	if access.Level != "debug" || access.ConfiguredLevel != "info" {
+		t.Fatalf("unexpected levels: %+v", access)
+	}
+	want := []string{"file:" + filepath.Join(dir, "access.log"), "stdout"}
+	if !slices.Equal(access.Outputs, want) {
+		t.Fatalf("expected outputs %v, got %v", want, access.Outputs)
+	}
+	if access.Rotation.MaxSizeMB == 0 {
+		t.Fatalf("rotation settings should be reported: %+v", access.Rotation)
+	}
+
+	srv := httptest.NewServer(AdminHandler())
+	defer srv.Close()
+	resp, err := http.Get(srv.URL + "/debug/log/loggers")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	var got []LoggerInfo
+	if err := json.NewDecoder(resp.Body).Decode(&got); err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 2 || got[0].Name != "access" {
+		t.Fatalf("unexpected admin response: %+v", got)
+	}
+}
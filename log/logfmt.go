@@ -0,0 +1,299 @@
+package log
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"go.uber.org/zap/buffer"
+	"go.uber.org/zap/zapcore"
+)
+
+var logfmtPool = buffer.NewPool()
+
+// logfmtEncoder 输出key=value格式，嵌套对象展开为parent.child=value，数组输出为[a,b]
+type logfmtEncoder struct {
+	cfg    *zapcore.EncoderConfig
+	buf    *buffer.Buffer
+	prefix string
+}
+
+func newLogfmtEncoder(cfg zapcore.EncoderConfig) zapcore.Encoder {
+	return &logfmtEncoder{cfg: &cfg, buf: logfmtPool.Get()}
+}
+
+func (enc *logfmtEncoder) Clone() zapcore.Encoder {
+	clone := &logfmtEncoder{cfg: enc.cfg, buf: logfmtPool.Get(), prefix: enc.prefix}
+	_, _ = clone.buf.Write(enc.buf.Bytes())
+	return clone
+}
+
+func (enc *logfmtEncoder) EncodeEntry(ent zapcore.Entry, fields []zapcore.Field) (*buffer.Buffer, error) {
+	final := &logfmtEncoder{cfg: enc.cfg, buf: logfmtPool.Get()}
+
+	if final.cfg.TimeKey != "" && final.cfg.EncodeTime != nil {
+		final.addEncoded(final.cfg.TimeKey, func(arr zapcore.PrimitiveArrayEncoder) { final.cfg.EncodeTime(ent.Time, arr) })
+	}
+	if final.cfg.LevelKey != "" && final.cfg.EncodeLevel != nil {
+		final.addEncoded(final.cfg.LevelKey, func(arr zapcore.PrimitiveArrayEncoder) { final.cfg.EncodeLevel(ent.Level, arr) })
+	}
+	if ent.LoggerName != "" && final.cfg.NameKey != "" {
+		final.AddString(final.cfg.NameKey, ent.LoggerName)
+	}
+	if ent.Caller.Defined && final.cfg.CallerKey != "" && final.cfg.EncodeCaller != nil {
+		final.addEncoded(final.cfg.CallerKey, func(arr zapcore.PrimitiveArrayEncoder) { final.cfg.EncodeCaller(ent.Caller, arr) })
+	}
+	if final.cfg.MessageKey != "" {
+		final.AddString(final.cfg.MessageKey, ent.Message)
+	}
+	if enc.buf.Len() > 0 {
+		if final.buf.Len() > 0 {
+			final.buf.AppendByte(' ')
+		}
+		_, _ = final.buf.Write(enc.buf.Bytes())
+	}
+	final.prefix = enc.prefix
+	for _, f := range fields {
+		f.AddTo(final)
+	}
+	final.prefix = ""
+	if ent.Stack != "" && final.cfg.StacktraceKey != "" {
+		final.AddString(final.cfg.StacktraceKey, ent.Stack)
+	}
+	final.buf.AppendString(zapcore.DefaultLineEnding)
+	if final.cfg.LineEnding != "" {
+		final.buf.TrimNewline()
+		final.buf.AppendString(final.cfg.LineEnding)
+	}
+	return final.buf, nil
+}
+
+// addEncoded 以EncodeTime/EncodeLevel等编码器的输出作为值，与字符串值同样按需加引号
+func (enc *logfmtEncoder) addEncoded(key string, encode func(zapcore.PrimitiveArrayEncoder)) {
+	arr := &logfmtArrayEncoder{cfg: enc.cfg}
+	encode(arr)
+	enc.addKey(key)
+	if len(arr.elems) > 0 {
+		enc.buf.AppendString(logfmtValue(arr.elems[0]))
+	}
+}
+
+func (enc *logfmtEncoder) addKey(key string) {
+	if enc.buf.Len() > 0 {
+		enc.buf.AppendByte(' ')
+	}
+	enc.buf.AppendString(logfmtKey(enc.prefix + key))
+	enc.buf.AppendByte('=')
+}
+
+func (enc *logfmtEncoder) addRaw(key, value string) {
+	enc.addKey(key)
+	enc.buf.AppendString(value)
+}
+
+func (enc *logfmtEncoder) AddArray(key string, marshaler zapcore.ArrayMarshaler) error {
+	arr := &logfmtArrayEncoder{cfg: enc.cfg}
+	err := marshaler.MarshalLogArray(arr)
+	enc.addRaw(key, logfmtValue("["+strings.Join(arr.elems, ",")+"]"))
+	return err
+}
+
+func (enc *logfmtEncoder) AddObject(key string, marshaler zapcore.ObjectMarshaler) error {
+	prefix := enc.prefix
+	enc.prefix += key + "."
+	err := marshaler.MarshalLogObject(enc)
+	enc.prefix = prefix
+	return err
+}
+
+func (enc *logfmtEncoder) AddBinary(key string, value []byte) {
+	enc.AddString(key, base64.StdEncoding.EncodeToString(value))
+}
+
+func (enc *logfmtEncoder) AddByteString(key string, value []byte) {
+	enc.AddString(key, string(value))
+}
+
+func (enc *logfmtEncoder) AddBool(key string, value bool) {
+	enc.addRaw(key, strconv.FormatBool(value))
+}
+
+func (enc *logfmtEncoder) AddComplex128(key string, value complex128) {
+	enc.addRaw(key, strconv.FormatComplex(value, 'g', -1, 128))
+}
+
+func (enc *logfmtEncoder) AddComplex64(key string, value complex64) {
+	enc.addRaw(key, strconv.FormatComplex(complex128(value), 'g', -1, 64))
+}
+
+func (enc *logfmtEncoder) AddDuration(key string, value time.Duration) {
+	enc.addEncoded(key, func(arr zapcore.PrimitiveArrayEncoder) { arr.(*logfmtArrayEncoder).AppendDuration(value) })
+}
+
+func (enc *logfmtEncoder) AddFloat64(key string, value float64) {
+	enc.addRaw(key, formatFloat(value, 64))
+}
+
+func (enc *logfmtEncoder) AddFloat32(key string, value float32) {
+	enc.addRaw(key, formatFloat(float64(value), 32))
+}
+
+func (enc *logfmtEncoder) AddInt(key string, value int)     { enc.AddInt64(key, int64(value)) }
+func (enc *logfmtEncoder) AddInt32(key string, value int32) { enc.AddInt64(key, int64(value)) }
+func (enc *logfmtEncoder) AddInt16(key string, value int16) { enc.AddInt64(key, int64(value)) }
+func (enc *logfmtEncoder) AddInt8(key string, value int8)   { enc.AddInt64(key, int64(value)) }
+
+func (enc *logfmtEncoder) AddInt64(key string, value int64) {
+	enc.addRaw(key, strconv.FormatInt(value, 10))
+}
+
+func (enc *logfmtEncoder) AddString(key, value string) {
+	enc.addRaw(key, logfmtValue(value))
+}
+
+func (enc *logfmtEncoder) AddTime(key string, value time.Time) {
+	enc.addEncoded(key, func(arr zapcore.PrimitiveArrayEncoder) { arr.(*logfmtArrayEncoder).AppendTime(value) })
+}
+
+func (enc *logfmtEncoder) AddUint(key string, value uint)       { enc.AddUint64(key, uint64(value)) }
+func (enc *logfmtEncoder) AddUint32(key string, value uint32)   { enc.AddUint64(key, uint64(value)) }
+func (enc *logfmtEncoder) AddUint16(key string, value uint16)   { enc.AddUint64(key, uint64(value)) }
+func (enc *logfmtEncoder) AddUint8(key string, value uint8)     { enc.AddUint64(key, uint64(value)) }
+func (enc *logfmtEncoder) AddUintptr(key string, value uintptr) { enc.AddUint64(key, uint64(value)) }
+
+func (enc *logfmtEncoder) AddUint64(key string, value uint64) {
+	enc.addRaw(key, strconv.FormatUint(value, 10))
+}
+
+func (enc *logfmtEncoder) AddReflected(key string, value any) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	enc.AddString(key, string(data))
+	return nil
+}
+
+func (enc *logfmtEncoder) OpenNamespace(key string) {
+	enc.prefix += key + "."
+}
+
+// logfmtArrayEncoder 收集数组元素的文本形式
+type logfmtArrayEncoder struct {
+	cfg   *zapcore.EncoderConfig
+	elems []string
+}
+
+func (arr *logfmtArrayEncoder) append(s string) {
+	arr.elems = append(arr.elems, s)
+}
+
+func (arr *logfmtArrayEncoder) AppendArray(marshaler zapcore.ArrayMarshaler) error {
+	inner := &logfmtArrayEncoder{cfg: arr.cfg}
+	err := marshaler.MarshalLogArray(inner)
+	arr.append("[" + strings.Join(inner.elems, ",") + "]")
+	return err
+}
+
+func (arr *logfmtArrayEncoder) AppendObject(marshaler zapcore.ObjectMarshaler) error {
+	enc := &logfmtEncoder{cfg: arr.cfg, buf: logfmtPool.Get()}
+	defer enc.buf.Free()
+	err := marshaler.MarshalLogObject(enc)
+	arr.append("{" + enc.buf.String() + "}")
+	return err
+}
+
+func (arr *logfmtArrayEncoder) AppendReflected(value any) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	arr.append(string(data))
+	return nil
+}
+
+func (arr *logfmtArrayEncoder) AppendBool(v bool)         { arr.append(strconv.FormatBool(v)) }
+func (arr *logfmtArrayEncoder) AppendByteString(v []byte) { arr.append(string(v)) }
+func (arr *logfmtArrayEncoder) AppendComplex128(v complex128) {
+	arr.append(strconv.FormatComplex(v, 'g', -1, 128))
+}
+func (arr *logfmtArrayEncoder) AppendComplex64(v complex64) {
+	arr.append(strconv.FormatComplex(complex128(v), 'g', -1, 64))
+}
+func (arr *logfmtArrayEncoder) AppendFloat64(v float64) { arr.append(formatFloat(v, 64)) }
+func (arr *logfmtArrayEncoder) AppendFloat32(v float32) { arr.append(formatFloat(float64(v), 32)) }
+func (arr *logfmtArrayEncoder) AppendInt(v int)         { arr.AppendInt64(int64(v)) }
+func (arr *logfmtArrayEncoder) AppendInt64(v int64)     { arr.append(strconv.FormatInt(v, 10)) }
+func (arr *logfmtArrayEncoder) AppendInt32(v int32)     { arr.AppendInt64(int64(v)) }
+func (arr *logfmtArrayEncoder) AppendInt16(v int16)     { arr.AppendInt64(int64(v)) }
+func (arr *logfmtArrayEncoder) AppendInt8(v int8)       { arr.AppendInt64(int64(v)) }
+func (arr *logfmtArrayEncoder) AppendString(v string)   { arr.append(v) }
+func (arr *logfmtArrayEncoder) AppendUint(v uint)       { arr.AppendUint64(uint64(v)) }
+func (arr *logfmtArrayEncoder) AppendUint64(v uint64)   { arr.append(strconv.FormatUint(v, 10)) }
+func (arr *logfmtArrayEncoder) AppendUint32(v uint32)   { arr.AppendUint64(uint64(v)) }
+func (arr *logfmtArrayEncoder) AppendUint16(v uint16)   { arr.AppendUint64(uint64(v)) }
+func (arr *logfmtArrayEncoder) AppendUint8(v uint8)     { arr.AppendUint64(uint64(v)) }
+func (arr *logfmtArrayEncoder) AppendUintptr(v uintptr) { arr.AppendUint64(uint64(v)) }
+
+func (arr *logfmtArrayEncoder) AppendDuration(v time.Duration) {
+	n := len(arr.elems)
+	if arr.cfg.EncodeDuration != nil {
+		arr.cfg.EncodeDuration(v, arr)
+	}
+	if len(arr.elems) == n {
+		arr.append(v.String())
+	}
+}
+
+func (arr *logfmtArrayEncoder) AppendTime(v time.Time) {
+	n := len(arr.elems)
+	if arr.cfg.EncodeTime != nil {
+		arr.cfg.EncodeTime(v, arr)
+	}
+	if len(arr.elems) == n {
+		arr.append(v.Format(time.RFC3339Nano))
+	}
+}
+
+func formatFloat(v float64, bitSize int) string {
+	switch {
+	case math.IsNaN(v):
+		return "NaN"
+	case math.IsInf(v, 1):
+		return "+Inf"
+	case math.IsInf(v, -1):
+		return "-Inf"
+	}
+	return strconv.FormatFloat(v, 'f', -1, bitSize)
+}
+
+// logfmtKey 去除key中的空白、=和引号
+func logfmtKey(key string) string {
+	if key == "" {
+		return "_"
+	}
+	return strings.Map(func(r rune) rune {
+		if r <= ' ' || r == '=' || r == '"' || r == utf8.RuneError {
+			return '_'
+		}
+		return r
+	}, key)
+}
+
+// logfmtValue 值为空或包含空白、=、引号及不可打印字符时加引号
+func logfmtValue(value string) string {
+	if value == "" {
+		return `""`
+	}
+	for _, r := range value {
+		if r <= ' ' || r == '=' || r == '"' || r == '\\' || r == utf8.RuneError || !unicode.IsPrint(r) {
+			return strconv.Quote(value)
+		}
+	}
+	return value
+}
@@ -0,0 +1,56 @@
+package log
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+func TestLogfmtEncoder(t *testing.T) {
+	enc, _ := getEncoder(LogConfig{Encoder: "logfmt", TimeFormat: "rfc3339", Timezone: "UTC"})
+	enc.AddString("svc", "api")
+
+	ts := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	buf, err := enc.EncodeEntry(zapcore.Entry{Level: zapcore.WarnLevel, Time: ts, Message: "slow query"}, []zapcore.Field{
+		zap.Int("rows", 42),
+		zap.String("sql", `select * from t where a="b"`),
+		zap.Duration("took", 1500*time.Millisecond),
+		zap.Strings("tags", []string{"a", "b"}),
+		zap.Dict("user", zap.String("id", "u1"), zap.Bool("admin", false)),
+		zap.Error(errors.New("timeout")),
+		zap.String("empty", ""),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := `ts=2024-01-02T03:04:05Z level=warn msg="slow query" svc=api rows=42 sql="select * from t where a=\"b\"" ` +
+		`took=1.5 tags=[a,b] user.id=u1 user.admin=false error=timeout empty=""` + "\n"
+	if got := buf.String(); got != want {
+		t.Fatalf("unexpected output\n got: %s\nwant: %s", got, want)
+	}
+
+	cfg := Config{Zaplog: []LogConfig{{Name: "default", FileName: "a.log", Encoder: "xml"}}}
+	if err := validateConfig(&cfg); err == nil || !strings.Contains(err.Error(), "unknown encoder") {
+		t.Fatalf("expected unknown encoder error, got %v", err)
+	}
+}
+
+func TestLogfmtQuotesEncodedValues(t *testing.T) {
+	enc, _ := getEncoder(LogConfig{Encoder: "logfmt", TimeFormat: "2006-01-02 15:04:05", Timezone: "UTC"})
+	ts := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	buf, err := enc.EncodeEntry(zapcore.Entry{Level: zapcore.InfoLevel, Time: ts, Message: "started"}, []zapcore.Field{
+		zap.Time("at", ts),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `ts="2024-01-02 03:04:05" level=info msg=started at="2024-01-02 03:04:05"` + "\n"
+	if got := buf.String(); got != want {
+		t.Fatalf("unexpected output\n got: %s\nwant: %s", got, want)
+	}
+}
@@ -1,22 +1,40 @@
 package log
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
+	"sort"
+	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
 
-	"github.com/spf13/viper"
 	"gopkg.in/natefinch/lumberjack.v2"
 
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
+
+	"github.com/allanchen1214/goeasy/config"
 )
 
 // Config 配置
 type Config struct {
-	Zaplog []LogConfig `yaml:"zaplog"`
+	Defaults       LogDefaults       `yaml:"defaults" mapstructure:"defaults"` // zaplog各项未设置时继承的默认值
+	Zaplog         []LogConfig       `yaml:"zaplog"`
+	ModuleLevels   map[string]string `yaml:"module_levels" mapstructure:"module_levels"`       // 模块级别覆盖，见Module
+	SilenceWindows []SilenceWindow   `yaml:"silence_windows" mapstructure:"silence_windows"`   // 静默时段
+	PanicFile      string            `yaml:"panic_file" mapstructure:"panic_file"`             // 崩溃日志文件，默认为default logger日志目录下的panic.log，配置生效前为临时目录下的<程序名>-<pid>-panic.log
+	ReopenOnSIGHUP bool              `yaml:"reopen_on_sighup" mapstructure:"reopen_on_sighup"` // 收到SIGHUP时重新打开日志文件，配合系统logrotate使用
+	DebugSignals   bool              `yaml:"debug_signals" mapstructure:"debug_signals"`       // 收到SIGUSR1时所有logger调整为debug，SIGUSR2恢复配置级别
+	Teams          []TeamConfig      `yaml:"teams" mapstructure:"teams"`                       // 团队归属，为日志附加team字段并可按团队分文件
+	DiskQuota      DiskQuotaConfig   `yaml:"disk_quota" mapstructure:"disk_quota"`             // 日志目录的磁盘配额
+	Templates      []LogConfig       `yaml:"templates" mapstructure:"templates"`               // logger模板，通过GetOrCreate按名称创建logger
 }
 
 // LogConfig 日志实例配置
@@ -28,36 +46,324 @@ type LogConfig struct {
 	MaxSize     int    `yaml:"max_size" mapstructure:"max_size"`         // 单个文件最大大小（MB）
 	MaxBackups  int    `yaml:"max_backups" mapstructure:"max_backups"`   // 最大备份数量
 	Compress    bool   `yaml:"compress" mapstructure:"compress"`         // 是否压缩
-	JsonEncoder bool   `yaml:"json_encoder" mapstructure:"json_encoder"` // 是否使用 JSON 格式
+	JsonEncoder bool   `yaml:"json_encoder" mapstructure:"json_encoder"` // 是否使用 JSON 格式，已弃用，使用encoder: json
+	Encoder     string `yaml:"encoder" mapstructure:"encoder"`           // 编码格式：json、console、logfmt、pretty、msgpack或RegisterEncoder注册的名称，默认console
 	Development bool   `yaml:"development" mapstructure:"development"`   // 开发模式
 	ShowCaller  bool   `yaml:"show_caller" mapstructure:"show_caller"`   // 是否显示调用者信息
+	LinkName    string `yaml:"link_name" mapstructure:"link_name"`       // 指向file_name的符号链接，Init时创建，供在其他路径读取日志的采集器使用。lumberjack始终写入file_name，链接无需随切割更新
+	Disabled    bool   `yaml:"disabled" mapstructure:"disabled"`         // 禁用后丢弃所有日志，不创建日志文件，用于压测或测试
+
+	FileMode int    `yaml:"file_mode" mapstructure:"file_mode"` // 日志文件权限，八进制如"0640"，默认0600，切割后的文件沿用
+	DirMode  int    `yaml:"dir_mode" mapstructure:"dir_mode"`   // 日志目录权限，八进制如"0750"，默认0755
+	Owner    string `yaml:"owner" mapstructure:"owner"`         // 日志文件的属主，用户名或UID，供以其他用户运行的采集器读取
+	Group    string `yaml:"group" mapstructure:"group"`         // 日志文件的属组，组名或GID
+
+	ErrorFingerprint bool `yaml:"error_fingerprint" mapstructure:"error_fingerprint"` // 是否为错误字段附加指纹
+	DailyBudgetMB    int  `yaml:"daily_budget_mb" mapstructure:"daily_budget_mb"`     // 每日日志量上限（MB），超出后当天只输出error及以上级别
+	ReentrancyGuard  bool `yaml:"reentrancy_guard" mapstructure:"reentrancy_guard"`   // 输出端写入过程中再次记录的日志转交内部诊断日志
+	GoroutineID      bool `yaml:"goroutine_id" mapstructure:"goroutine_id"`           // 是否附加goroutine ID，有额外开销，建议仅在开发环境开启
+	MaxEntryBytes    int  `yaml:"max_entry_bytes" mapstructure:"max_entry_bytes"`     // 单条日志的大小上限（字节），超出时截断最长的字符串字段并标记truncated，0表示不限制
+	K8sMetadata      bool `yaml:"k8s_metadata" mapstructure:"k8s_metadata"`           // 附加Pod名称、命名空间、节点和容器名，取自downward API注入的环境变量或挂载的文件
+	BuildInfo        bool `yaml:"build_info" mapstructure:"build_info"`               // 附加主模块的版本、vcs修订号和提交时间，取自debug.ReadBuildInfo
+	Sanitize         bool `yaml:"sanitize" mapstructure:"sanitize"`                   // 转义消息和字符串字段中的控制字符与无效UTF-8，防止CRLF伪造日志行
+
+	ShardByLevel bool           `yaml:"shard_by_level" mapstructure:"shard_by_level"` // 按级别分文件，如app.log拆分为app.debug.log、app.info.log、app.warn.log、app.error.log
+	ShardMaxAge  map[string]int `yaml:"shard_max_age" mapstructure:"shard_max_age"`   // 各级别分片的最大保存天数，未设置的级别使用max_age
+
+	Audit       bool   `yaml:"audit" mapstructure:"audit"`                 // 审计模式，每条日志附加与上一条链接的哈希，可通过VerifyAuditFile校验，需使用json编码
+	AuditKey    string `yaml:"audit_key" mapstructure:"audit_key"`         // 审计哈希使用的HMAC密钥，为空时使用SHA-256
+	AuditKeyEnv string `yaml:"audit_key_env" mapstructure:"audit_key_env"` // 保存HMAC密钥的环境变量名
+
+	StacktraceLevel     string `yaml:"stacktrace_level" mapstructure:"stacktrace_level"`           // 该级别及以上附加堆栈，为空或none时不附加
+	StacktraceMaxFrames int    `yaml:"stacktrace_max_frames" mapstructure:"stacktrace_max_frames"` // 堆栈最多保留的帧数，0表示不限制
+	CallerSkip          int    `yaml:"caller_skip" mapstructure:"caller_skip"`                     // 调用者信息跳过的栈帧数，供封装层使用
+	CallerTrimPrefix    string `yaml:"caller_trim_prefix" mapstructure:"caller_trim_prefix"`       // 调用者及堆栈路径去除的前缀，设置后输出相对于该前缀的完整路径
+	TimeFormat          string `yaml:"time_format" mapstructure:"time_format"`                     // 时间格式：iso8601(默认)、rfc3339、rfc3339nano、epoch、epoch_ms或Go时间layout
+	Timezone            string `yaml:"timezone" mapstructure:"timezone"`                           // 时区，如UTC、Asia/Shanghai，默认本地时区
+	DurationFormat      string `yaml:"duration_format" mapstructure:"duration_format"`             // 时长格式：s(默认，浮点秒)、ms、ns或human(如1m32s)
+	NumberLocale        string `yaml:"number_locale" mapstructure:"number_locale"`                 // 数值按地区添加千分位，如en输出"1,234.5"、de输出"1.234,5"，设置后数值以字符串输出，为空时保持数值
+	FloatPrecision      int    `yaml:"float_precision" mapstructure:"float_precision"`             // 浮点数保留的小数位数，0表示不处理
+
+	MessageKey string `yaml:"message_key" mapstructure:"message_key"` // 消息字段名，默认msg
+	LevelKey   string `yaml:"level_key" mapstructure:"level_key"`     // 级别字段名，默认level
+	TimeKey    string `yaml:"time_key" mapstructure:"time_key"`       // 时间字段名，默认ts
+	CallerKey  string `yaml:"caller_key" mapstructure:"caller_key"`   // 调用者字段名，默认caller
+
+	Sinks           []SinkConfig          `yaml:"sinks" mapstructure:"sinks"`                       // 除文件和标准输出外的额外输出端
+	RingBuffer      int                   `yaml:"ring_buffer" mapstructure:"ring_buffer"`           // 内存中保留的最近日志条数，可通过/debug/log/dump查看，0表示不保留
+	OrderedTee      bool                  `yaml:"ordered_tee" mapstructure:"ordered_tee"`           // 文件写入成功后才写标准输出和sink，并为每条日志附加序号，文件中的日志始终是sink的超集
+	SequenceKey     string                `yaml:"sequence_key" mapstructure:"sequence_key"`         // ordered_tee的序号字段名，默认seq
+	AlertAnnotation AlertAnnotationConfig `yaml:"alert_annotation" mapstructure:"alert_annotation"` // error日志关联的Alertmanager告警
+	ErrorBurst      ErrorBurstConfig      `yaml:"error_burst" mapstructure:"error_burst"`           // 错误突增告警
+	Archive         ArchiveConfig         `yaml:"archive" mapstructure:"archive"`                   // 压缩备份归档到对象存储
+	Encryption      EncryptionConfig      `yaml:"encryption" mapstructure:"encryption"`             // 备份文件加密
+	DropIf          []DropRule            `yaml:"drop_if" mapstructure:"drop_if"`                   // 丢弃匹配任一规则的日志
+	Sampling        SamplingConfig        `yaml:"sampling" mapstructure:"sampling"`                 // 对debug、info等低级别日志采样
+	Dump            DumpConfig            `yaml:"dump" mapstructure:"dump"`                         // log.Dump记录请求体等大内容的抽样配置
+
+	Gorm GormConfig `yaml:"gorm" mapstructure:"gorm"` // 作为GORM日志时的配置
+}
+
+// LogDefaults zaplog各项的默认配置
+type LogDefaults struct {
+	Level      string `yaml:"level" mapstructure:"level"`             // 日志级别
+	MaxAge     int    `yaml:"max_age" mapstructure:"max_age"`         // 最大保存天数
+	MaxSize    int    `yaml:"max_size" mapstructure:"max_size"`       // 单个文件最大大小（MB）
+	MaxBackups int    `yaml:"max_backups" mapstructure:"max_backups"` // 最大备份数量
+	Encoder    string `yaml:"encoder" mapstructure:"encoder"`         // 编码格式
+	Directory  string `yaml:"directory" mapstructure:"directory"`     // 日志目录，相对路径的file_name基于此目录，未设置file_name时为<directory>/<name>.log
+	FileMode   int    `yaml:"file_mode" mapstructure:"file_mode"`     // 日志文件权限
+	DirMode    int    `yaml:"dir_mode" mapstructure:"dir_mode"`       // 日志目录权限
+	Owner      string `yaml:"owner" mapstructure:"owner"`             // 日志文件的属主
+	Group      string `yaml:"group" mapstructure:"group"`             // 日志文件的属组
+
+	DurationFormat string `yaml:"duration_format" mapstructure:"duration_format"` // 时长格式
+	NumberLocale   string `yaml:"number_locale" mapstructure:"number_locale"`     // 数值的地区格式
+	FloatPrecision int    `yaml:"float_precision" mapstructure:"float_precision"` // 浮点数保留的小数位数
+}
+
+// applyDefaults 将defaults中的配置应用到未设置对应项的logger
+func applyDefaults(cfg *Config) {
+	d := cfg.Defaults
+	for i := range cfg.Zaplog {
+		lc := &cfg.Zaplog[i]
+		if lc.Level == "" {
+			lc.Level = d.Level
+		}
+		if lc.MaxAge == 0 {
+			lc.MaxAge = d.MaxAge
+		}
+		if lc.MaxSize == 0 {
+			lc.MaxSize = d.MaxSize
+		}
+		if lc.MaxBackups == 0 {
+			lc.MaxBackups = d.MaxBackups
+		}
+		if lc.Encoder == "" {
+			lc.Encoder = d.Encoder
+		}
+		if lc.DurationFormat == "" {
+			lc.DurationFormat = d.DurationFormat
+		}
+		if lc.NumberLocale == "" {
+			lc.NumberLocale = d.NumberLocale
+		}
+		if lc.FloatPrecision == 0 {
+			lc.FloatPrecision = d.FloatPrecision
+		}
+		if lc.FileMode == 0 {
+			lc.FileMode = d.FileMode
+		}
+		if lc.DirMode == 0 {
+			lc.DirMode = d.DirMode
+		}
+		if lc.Owner == "" {
+			lc.Owner = d.Owner
+		}
+		if lc.Group == "" {
+			lc.Group = d.Group
+		}
+		if d.Directory != "" && lc.Name != "" {
+			switch {
+			case lc.FileName == "":
+				lc.FileName = filepath.Join(d.Directory, lc.Name+".log")
+			case !filepath.IsAbs(lc.FileName):
+				lc.FileName = filepath.Join(d.Directory, lc.FileName)
+			}
+		}
+	}
+}
+
+// logEntry 注册表中的日志实例
+type logEntry struct {
+	cfg    LogConfig
+	level  zap.AtomicLevel
+	writer *lumberjack.Logger
+	stats  *levelCounter
+	usage  *usageCounter
+	hooks  *hookList
+	logger *zap.Logger
+
+	silenced atomic.Bool // 处于静默时段，只输出error及以上级别
+	faults   atomic.Pointer[DiskFaults]
+
+	writeErrors atomic.Uint64 // 写入输出端失败次数
+	sampled     atomic.Uint64 // 被采样丢弃的日志条数
+
+	ws      zapcore.WriteSyncer
+	sinks   []zapcore.WriteSyncer // 通过RegisterSink注册的额外输出端
+	outputs []*healthWriter       // 文件与sink输出端的健康状态，见Health
+
+	// newCore 以指定级别和输出构建core，与logger共用编码器，供子模块使用
+	newCore func(zapcore.LevelEnabler, zapcore.WriteSyncer) zapcore.Core
+	// newBatchCore 同newCore，但只写入指定输出，不写入团队文件、环形缓冲和tail，供批量写入使用
+	newBatchCore func(zapcore.LevelEnabler, zapcore.WriteSyncer) zapcore.Core
+	options      []zap.Option
+	ring         *ringBuffer
+	tail         *tailHub
+	// core logger未附加options时的core，修改options时以此重建logger
+	core zapcore.Core
+	// archiver 上传压缩备份到对象存储，未配置archive时为nil
+	archiver *archiver
+	// encryptor 加密备份文件，未配置encryption时为nil
+	encryptor *encryptor
+	// annotator 标注Alertmanager告警，未配置alert_annotation时为nil
+	annotator *alertAnnotator
+	// template 通过GetOrCreate创建时的模板名
+	template string
+	// teams 创建时的团队配置，配置了file_name的团队文件与其他logger共用
+	teams []*team
 }
 
 var (
-	loggers = make(map[string]*zap.Logger)
+	loggers = make(map[string]*logEntry)
 	metux   sync.RWMutex
+
+	// logDefaults Init时的defaults配置，供AddLogger使用
+	logDefaults LogDefaults
 )
 
 func validateConfig(cfg *Config) error {
 	if len(cfg.Zaplog) == 0 {
 		return fmt.Errorf("no logger configurations found")
 	}
-	var hasDefault bool = false
-	for _, lc := range cfg.Zaplog {
+	var (
+		errs       []error
+		hasDefault bool
+		names      = make(map[string]bool)
+		dirs       = make(map[string]bool)
+	)
+	for i, lc := range cfg.Zaplog {
 		if lc.Name == "" {
-			return fmt.Errorf("logger name is required")
+			errs = append(errs, fmt.Errorf("logger %d: name is required", i))
+			lc.Name = strconv.Itoa(i)
+		} else if names[lc.Name] {
+			errs = append(errs, fmt.Errorf("logger %s: duplicate name", lc.Name))
 		}
+		names[lc.Name] = true
 		if lc.Name == "default" {
 			hasDefault = true
 		}
-		if lc.FileName == "" {
-			return fmt.Errorf("logger %s: file_name is required", lc.Name)
-		}
+		errs = append(errs, validateLogConfig(lc, dirs)...)
 	}
 	if !hasDefault {
-		return fmt.Errorf("no default logger configuration found")
+		errs = append(errs, fmt.Errorf("no default logger configuration found"))
 	}
-	return nil
+	for module, level := range cfg.ModuleLevels {
+		if !isValidLevel(level) {
+			errs = append(errs, fmt.Errorf("module %s: %w", module, invalidLevel(level)))
+		}
+	}
+	if err := validateTeams(cfg.Teams); err != nil {
+		errs = append(errs, err)
+	}
+	for i, w := range cfg.SilenceWindows {
+		if _, err := parseCron(w.Cron); err != nil {
+			errs = append(errs, fmt.Errorf("silence window %d: %w", i, err))
+		}
+		if w.Duration <= 0 {
+			errs = append(errs, fmt.Errorf("silence window %d: duration must be positive", i))
+		}
+	}
+	errs = append(errs, validateDiskQuota(cfg.DiskQuota)...)
+	errs = append(errs, validateTemplates(cfg.Templates)...)
+	return errors.Join(errs...)
+}
+
+// validateLogConfig 检查单个logger的配置，dirs记录已检查过的目录
+func validateLogConfig(lc LogConfig, dirs map[string]bool) []error {
+	var errs []error
+	if lc.Level != "" && !isValidLevel(lc.Level) {
+		errs = append(errs, fmt.Errorf("logger %s: %w", lc.Name, invalidLevel(lc.Level)))
+	}
+	switch dir := filepath.Dir(lc.FileName); {
+	case lc.Disabled:
+		// 禁用的logger不创建日志文件
+	case lc.FileName == "":
+		errs = append(errs, fmt.Errorf("logger %s: file_name is required", lc.Name))
+	case !dirs[dir]:
+		dirs[dir] = true
+		if err := checkWritableDir(dir); err != nil {
+			errs = append(errs, fmt.Errorf("logger %s: %w", lc.Name, err))
+		}
+	}
+	if _, ok := lookupEncoder(lc.Encoder); lc.Encoder != "" && !ok {
+		errs = append(errs, fmt.Errorf("logger %s: unknown encoder %q, registered: %s", lc.Name, lc.Encoder, registeredNames(encoderFactories)))
+	}
+	for _, sc := range lc.Sinks {
+		if _, ok := lookupSink(sc.Type); !ok {
+			errs = append(errs, fmt.Errorf("logger %s: unknown sink %q, registered: %s", lc.Name, sc.Type, registeredNames(sinkFactories)))
+		}
+		if sc.SyncTimeout < 0 {
+			errs = append(errs, fmt.Errorf("logger %s: sink %s: sync_timeout must not be negative", lc.Name, sc.Type))
+		}
+	}
+	if lc.Timezone != "" {
+		if _, err := time.LoadLocation(lc.Timezone); err != nil {
+			errs = append(errs, fmt.Errorf("logger %s: invalid timezone %q: %w", lc.Name, lc.Timezone, err))
+		}
+	}
+	if lc.StacktraceLevel != "" && !strings.EqualFold(lc.StacktraceLevel, "none") && !isValidLevel(lc.StacktraceLevel) {
+		errs = append(errs, fmt.Errorf("logger %s: invalid stacktrace_level %q", lc.Name, lc.StacktraceLevel))
+	}
+	if !isValidDurationFormat(lc.DurationFormat) {
+		errs = append(errs, fmt.Errorf("logger %s: invalid duration_format %q", lc.Name, lc.DurationFormat))
+	}
+	if _, err := newNumberFormat(lc.NumberLocale, lc.FloatPrecision); err != nil {
+		errs = append(errs, fmt.Errorf("logger %s: %w", lc.Name, err))
+	}
+	if lc.RingBuffer < 0 {
+		errs = append(errs, fmt.Errorf("logger %s: ring_buffer must not be negative", lc.Name))
+	}
+	if lc.FloatPrecision < 0 {
+		errs = append(errs, fmt.Errorf("logger %s: float_precision must not be negative", lc.Name))
+	}
+	if lc.MaxEntryBytes < 0 {
+		errs = append(errs, fmt.Errorf("logger %s: max_entry_bytes must not be negative", lc.Name))
+	}
+	if lc.Audit && !strings.EqualFold(lc.Encoder, "json") {
+		errs = append(errs, fmt.Errorf("logger %s: audit requires encoder: json", lc.Name))
+	}
+	if lc.LinkName != "" && (lc.ShardByLevel || filepath.Clean(lc.LinkName) == filepath.Clean(lc.FileName)) {
+		errs = append(errs, fmt.Errorf("logger %s: link_name must differ from file_name and cannot be used with shard_by_level", lc.Name))
+	}
+	errs = append(errs, validateShards(lc)...)
+	errs = append(errs, validateArchive(lc)...)
+	errs = append(errs, validateEncryption(lc)...)
+	errs = append(errs, validateDropRules(lc)...)
+	errs = append(errs, validatePermissions(lc)...)
+	errs = append(errs, validateErrorBurst(lc)...)
+	errs = append(errs, validateSampling(lc)...)
+	errs = append(errs, validateDump(lc)...)
+	return errs
+}
+
+// checkWritableDir 检查日志目录可写，目录不存在时检查最近的已存在上级目录
+func checkWritableDir(dir string) error {
+	for {
+		info, err := os.Stat(dir)
+		if err == nil {
+			if !info.IsDir() {
+				return fmt.Errorf("%s is not a directory", dir)
+			}
+			break
+		}
+		if !os.IsNotExist(err) {
+			return err
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return nil
+		}
+		dir = parent
+	}
+
+	f, err := os.CreateTemp(dir, ".goeasy-log-check-*")
+	if err != nil {
+		return fmt.Errorf("directory %s is not writable: %w", dir, err)
+	}
+	f.Close()
+	return os.Remove(f.Name())
 }
 
 func setDefault(cfg *LogConfig) {
@@ -79,50 +385,139 @@ func setDefault(cfg *LogConfig) {
 
 // LoadConfig 加载配置
 func LoadConfig(configPath string) (Config, error) {
+	return LoadConfigFormat(configPath, "")
+}
+
+// LoadConfigFormat 以指定格式加载配置，format支持yaml、json、toml，为空时根据文件扩展名判断，无法判断时按yaml解析。
+// 配置通过config.Load加载，与其他子系统的配置规则一致：configPath为目录或glob(如conf/log.*.yaml)时按文件名顺序读取并合并，
+// zaplog按name合并到同名logger，可将共用的基础配置与各环境的覆盖配置分开维护，如00-base.yaml、10-prod.yaml；
+// 支持profile文件及${env:NAME}等占位符。环境变量GOEASY_LOG_<KEY>、GOEASY_LOG_<NAME>_<KEY>覆盖文件中的配置，见applyEnvOverrides
+func LoadConfigFormat(configPath, format string) (Config, error) {
 	var cfg Config
+	if err := config.Load(configPath, &cfg, configOptions(format)...); err != nil {
+		return cfg, err
+	}
+	return cfg, finishConfig(&cfg)
+}
 
-	v := viper.New()
-	v.SetConfigFile(configPath)
-	v.SetConfigType("yaml")
+// configOptions 日志配置的加载选项，本地文件与配置中心共用
+func configOptions(format string) []config.Option {
+	opts := []config.Option{
+		config.WithEnvPrefix(EnvPrefix),
+		config.WithMergeKey("zaplog", "name"),
+		config.WithSettingsHook(checkSettings),
+	}
+	// 未指定时由config根据扩展名判断
+	if format != "" {
+		opts = append(opts, config.WithFormat(format))
+	}
+	return opts
+}
 
-	if err := v.ReadInConfig(); err != nil {
-		return cfg, fmt.Errorf("failed to read config: %w", err)
+// checkSettings 以环境变量覆盖各logger的配置，并检查未知的键
+func checkSettings(settings map[string]any) error {
+	applyEnvOverrides(settings)
+	// 未知的键通常是拼写错误，继续解析会静默使用默认值
+	if errs := checkUnknownKeys(settings); len(errs) > 0 {
+		return fmt.Errorf("invalid config: %w", errors.Join(errs...))
 	}
+	return nil
+}
 
-	//fmt.Printf("config file content: %v", v.AllSettings())
+// finishConfig 迁移旧配置项，补全默认值后校验
+func finishConfig(cfg *Config) error {
+	migrateConfig(cfg)
+	applyDefaults(cfg)
+	return validateConfig(cfg)
+}
 
-	if err := v.Unmarshal(&cfg); err != nil {
-		return cfg, fmt.Errorf("failed to unmarshal config: %w", err)
+func getEncoder(cfg LogConfig) (zapcore.Encoder, error) {
+	encoderConfig := zap.NewProductionEncoderConfig()
+	encoderConfig.EncodeTime = getTimeEncoder(cfg.TimeFormat, cfg.Timezone)
+	encoderConfig.EncodeDuration = getDurationEncoder(cfg.DurationFormat)
+	encoderConfig.EncodeLevel = zapcore.CapitalLevelEncoder
+	encoderConfig.EncodeCaller = zapcore.ShortCallerEncoder
+	if cfg.CallerTrimPrefix != "" {
+		encoderConfig.EncodeCaller = trimCallerEncoder(cfg.CallerTrimPrefix)
+	}
+	if cfg.MessageKey != "" {
+		encoderConfig.MessageKey = cfg.MessageKey
+	}
+	if cfg.LevelKey != "" {
+		encoderConfig.LevelKey = cfg.LevelKey
+	}
+	if cfg.TimeKey != "" {
+		encoderConfig.TimeKey = cfg.TimeKey
+	}
+	if cfg.CallerKey != "" {
+		encoderConfig.CallerKey = cfg.CallerKey
 	}
 
-	/* 	data, err := os.ReadFile(configPath)
-	   	if err != nil {
-	   		return cfg, err
-	   	}
-	   	err = json.Unmarshal(data, &cfg)
-	   	if err != nil {
-	   		return cfg, err
-	   	} */
-
-	if err := validateConfig(&cfg); err != nil {
-		return cfg, err
+	name := encoderName(cfg)
+	factory, ok := lookupEncoder(name)
+	if !ok {
+		return nil, fmt.Errorf("unknown encoder %q", name)
 	}
+	return factory(encoderConfig)
+}
 
-	return cfg, nil
+// encoderName 返回logger使用的编码格式，未设置encoder时按已弃用的json_encoder选择json或console。
+// pretty输出多行彩色日志，会写入文件和sink，只在显式设置时使用
+func encoderName(cfg LogConfig) string {
+	switch {
+	case cfg.Encoder != "":
+		return cfg.Encoder
+	case cfg.JsonEncoder:
+		return "json"
+	}
+	return "console"
 }
 
-func getEncoder(jsonFormat bool) zapcore.Encoder {
-	encoderConfig := zap.NewProductionEncoderConfig()
-	encoderConfig.EncodeTime = zapcore.ISO8601TimeEncoder
-	encoderConfig.EncodeLevel = zapcore.CapitalLevelEncoder
+// getTimeEncoder 按time_format和timezone返回时间编码器
+func getTimeEncoder(format, timezone string) zapcore.TimeEncoder {
+	var enc zapcore.TimeEncoder
+	switch strings.ToLower(format) {
+	case "", "iso8601":
+		enc = zapcore.ISO8601TimeEncoder
+	case "rfc3339":
+		enc = zapcore.RFC3339TimeEncoder
+	case "rfc3339nano":
+		enc = zapcore.RFC3339NanoTimeEncoder
+	case "epoch":
+		enc = zapcore.EpochTimeEncoder
+	case "epoch_ms":
+		enc = zapcore.EpochMillisTimeEncoder
+	default:
+		enc = zapcore.TimeEncoderOfLayout(format)
+	}
 
-	if jsonFormat {
-		return zapcore.NewJSONEncoder(encoderConfig)
+	if timezone == "" {
+		return enc
+	}
+	loc, err := time.LoadLocation(timezone)
+	if err != nil {
+		return enc
+	}
+	return func(t time.Time, pae zapcore.PrimitiveArrayEncoder) {
+		enc(t.In(loc), pae)
 	}
+}
 
-	encoderConfig.EncodeLevel = zapcore.CapitalColorLevelEncoder
-	encoderConfig.EncodeCaller = zapcore.ShortCallerEncoder
-	return zapcore.NewConsoleEncoder(encoderConfig)
+// trimCallerEncoder 输出去除prefix后的调用者路径，不匹配prefix时退化为短路径
+func trimCallerEncoder(prefix string) zapcore.CallerEncoder {
+	prefix = strings.TrimSuffix(filepath.ToSlash(prefix), "/") + "/"
+	return func(caller zapcore.EntryCaller, enc zapcore.PrimitiveArrayEncoder) {
+		if !caller.Defined {
+			enc.AppendString("undefined")
+			return
+		}
+		rel, ok := strings.CutPrefix(caller.File, prefix)
+		if !ok {
+			enc.AppendString(caller.TrimmedPath())
+			return
+		}
+		enc.AppendString(rel + ":" + strconv.Itoa(caller.Line))
+	}
 }
 
 func getLevel(level string) zapcore.Level {
@@ -145,12 +540,18 @@ func getLevel(level string) zapcore.Level {
 	}
 }
 
-func getWriteSyncer(cfg LogConfig) zapcore.WriteSyncer {
-	if err := os.MkdirAll(filepath.Dir(cfg.FileName), 0755); err != nil {
-		panic(err)
+func isValidLevel(level string) bool {
+	switch strings.ToLower(level) {
+	case "debug", "info", "warn", "error", "panic", "fatal":
+		return true
 	}
+	return false
+}
 
-	lumberjackLogger := &lumberjack.Logger{
+func newFileWriter(cfg LogConfig) *lumberjack.Logger {
+	prepareLogFile(cfg)
+
+	return &lumberjack.Logger{
 		Filename:   cfg.FileName,
 		MaxAge:     cfg.MaxAge,
 		MaxSize:    cfg.MaxSize,
@@ -158,70 +559,318 @@ func getWriteSyncer(cfg LogConfig) zapcore.WriteSyncer {
 		Compress:   cfg.Compress,
 		LocalTime:  true,
 	}
+}
 
-	return zapcore.NewMultiWriteSyncer(
-		zapcore.AddSync(lumberjackLogger),
-		zapcore.AddSync(os.Stdout),
-	)
+// getStdoutWriteSyncer 同getWriteSyncer，但不包含日志文件
+func getStdoutWriteSyncer(sinks ...zapcore.WriteSyncer) zapcore.WriteSyncer {
+	return zapcore.NewMultiWriteSyncer(append([]zapcore.WriteSyncer{stdoutSyncer{os.Stdout}}, sinks...)...)
 }
 
-func newLogger(cfg LogConfig) (*zap.Logger, error) {
+func getWriteSyncer(writer io.Writer, sinks ...zapcore.WriteSyncer) zapcore.WriteSyncer {
+	return zapcore.NewMultiWriteSyncer(append([]zapcore.WriteSyncer{
+		zapcore.AddSync(writer),
+		stdoutSyncer{os.Stdout},
+	}, sinks...)...)
+}
+
+// getOrderedWriteSyncer 同getWriteSyncer，但文件写入失败时不再写入其余输出端
+func getOrderedWriteSyncer(writer io.Writer, sinks ...zapcore.WriteSyncer) zapcore.WriteSyncer {
+	return &orderedWriteSyncer{
+		file: zapcore.AddSync(writer),
+		rest: zapcore.NewMultiWriteSyncer(append([]zapcore.WriteSyncer{stdoutSyncer{os.Stdout}}, sinks...)...),
+	}
+}
+
+// stdoutSyncer 终端和管道不支持fsync，忽略此类错误以免Close总是报错
+type stdoutSyncer struct {
+	*os.File
+}
+
+func (s stdoutSyncer) Sync() error {
+	err := s.File.Sync()
+	if errors.Is(err, syscall.EINVAL) || errors.Is(err, syscall.ENOTSUP) || errors.Is(err, syscall.ENOTTY) {
+		return nil
+	}
+	return err
+}
+
+func newLogger(cfg LogConfig) (*logEntry, error) {
 	setDefault(&cfg)
+	if cfg.Disabled || disabledAll.Load() {
+		return newDisabledLogger(cfg), nil
+	}
 
-	encoder := getEncoder(cfg.JsonEncoder)
+	entry := &logEntry{
+		cfg:   cfg,
+		level: zap.NewAtomicLevelAt(getLevel(cfg.Level)),
+		stats: newLevelCounter(),
+		usage: newUsageCounter(),
+		hooks: &hookList{},
+	}
 
-	core := zapcore.NewCore(
-		encoder,
-		getWriteSyncer(cfg),
-		getLevel(cfg.Level),
-	)
+	var b *budget
+	onEncode := entry.usage.add
+	if cfg.DailyBudgetMB > 0 {
+		b = newBudget(int64(cfg.DailyBudgetMB) << 20)
+		onEncode = func(level zapcore.Level, n int) {
+			entry.usage.add(level, n)
+			b.add(level, n)
+		}
+	}
+	enc, err := getEncoder(cfg)
+	if err != nil {
+		return nil, err
+	}
+	encoder := newCountingEncoder(enc, onEncode)
+	numbers, err := newNumberFormat(cfg.NumberLocale, cfg.FloatPrecision)
+	if err != nil {
+		return nil, err
+	}
+	if entry.sinks, err = newSinks(cfg); err != nil {
+		return nil, err
+	}
+	// track 输出端写入失败时改写stderr并记录状态
+	track := func(output string, ws zapcore.WriteSyncer) zapcore.WriteSyncer {
+		w := newHealthWriter(cfg.Name, output, ws, &entry.writeErrors)
+		entry.outputs = append(entry.outputs, w)
+		return w
+	}
+	trackSinks := func() []zapcore.WriteSyncer {
+		sinks := make([]zapcore.WriteSyncer, len(entry.sinks))
+		for i, sink := range entry.sinks {
+			sinks[i] = track("sink:"+cfg.Sinks[i].Type, sink)
+		}
+		return sinks
+	}
+	var ordered *orderedOutput
+	switch {
+	case cfg.ShardByLevel:
+		entry.ws = newShardedOutput(cfg, &entry.faults, track, trackSinks)
+	case cfg.OrderedTee:
+		// 文件写入失败时需要跳过其余输出端，不改写stderr
+		entry.writer = newFileWriter(cfg)
+		file := &faultWriter{Writer: entry.writer, path: cfg.FileName, faults: &entry.faults}
+		ordered = newOrderedOutput(cfg.SequenceKey)
+		entry.ws = getOrderedWriteSyncer(file, trackSinks()...)
+	default:
+		entry.writer = newFileWriter(cfg)
+		file := track("file:"+cfg.FileName, zapcore.AddSync(&faultWriter{Writer: entry.writer, path: cfg.FileName, faults: &entry.faults}))
+		entry.ws = getWriteSyncer(file, trackSinks()...)
+	}
+	if cfg.Audit {
+		key, err := auditKey(cfg)
+		if err != nil {
+			return nil, err
+		}
+		entry.ws = newAuditWriteSyncer(entry.ws, key, lastAuditHash(cfg.FileName))
+	}
 
-	options := []zap.Option{}
-	if cfg.ShowCaller {
-		options = append(options, zap.AddCaller())
+	if cfg.RingBuffer > 0 {
+		entry.ring = newRingBuffer(cfg.RingBuffer)
 	}
-	if cfg.Development {
-		options = append(options, zap.Development())
+	entry.tail = newTailHub()
+
+	matchers, err := newDropMatchers(cfg.DropIf)
+	if err != nil {
+		return nil, err
+	}
+	var annotator *alertAnnotator
+	if cfg.AlertAnnotation.URL != "" {
+		annotator = newAlertAnnotator(cfg.AlertAnnotation, cfg.Name)
+		entry.annotator = annotator
+	}
+	build := func(level zapcore.LevelEnabler, ws zapcore.WriteSyncer) zapcore.Core {
+		var core zapcore.Core
+		if out, ok := ws.(*shardedOutput); ok {
+			core = out.core(encoder, silenceEnabler{level, &entry.silenced})
+		} else {
+			core = zapcore.NewCore(encoder, ws, silenceEnabler{level, &entry.silenced})
+		}
+		core = newWriteErrorCore(core, &entry.writeErrors)
+		if numbers.group != "" || numbers.precision > 0 {
+			core = newNumberCore(core, numbers)
+		}
+		if cfg.MaxEntryBytes > 0 {
+			core = newSizeCore(core, cfg.MaxEntryBytes)
+		}
+		if cfg.Sanitize {
+			core = newSanitizeCore(core)
+		}
+		if b != nil {
+			core = newBudgetCore(core, b)
+		}
+		if cfg.GoroutineID {
+			core = newGoroutineCore(core)
+		}
+		if cfg.ErrorFingerprint {
+			core = newFingerprintCore(core)
+		}
+		if cfg.StacktraceMaxFrames > 0 || cfg.CallerTrimPrefix != "" {
+			core = newStackCore(core, cfg.StacktraceMaxFrames, cfg.CallerTrimPrefix)
+		}
+		if annotator != nil {
+			core = newAlertCore(core, annotator)
+		}
+		if cfg.ReentrancyGuard {
+			core = newGuardCore(core, cfg.Name)
+		}
+		return zapcore.NewTee(core, newPanicCore())
+	}
+	teams := teams
+	entry.teams = teams
+	// wrap 附加与输出无关的处理：事件时间、顺序号、过滤和采样
+	wrap := func(core zapcore.Core) zapcore.Core {
+		core = newEventTimeCore(core)
+		if ordered != nil {
+			core = newSeqCore(core, ordered)
+		}
+		if len(matchers) > 0 {
+			core = newFilterCore(core, matchers)
+		}
+		// 采样器在Check中决定是否写入，须位于只在Write中处理的filterCore之外
+		if cfg.Sampling.enabled() {
+			core = newSamplingCore(core, cfg.Sampling, &entry.sampled)
+		}
+		return core
+	}
+	entry.newCore = func(level zapcore.LevelEnabler, ws zapcore.WriteSyncer) zapcore.Core {
+		core := build(level, ws)
+		if len(teams) > 0 {
+			core = newTeamCore(core, cfg.Name, teams, func(ws zapcore.WriteSyncer) zapcore.Core {
+				return build(level, ws)
+			})
+		}
+		if entry.ring != nil {
+			core = zapcore.NewTee(core, newRingCore(entry.ring, silenceEnabler{level, &entry.silenced}))
+		}
+		core = zapcore.NewTee(core, newTailCore(entry.tail, silenceEnabler{level, &entry.silenced}))
+		return wrap(core)
+	}
+	entry.newBatchCore = func(level zapcore.LevelEnabler, ws zapcore.WriteSyncer) zapcore.Core {
+		return wrap(build(level, ws))
+	}
+
+	if cfg.ErrorBurst.Threshold > 0 {
+		entry.hooks.add(newBurstDetector(cfg.ErrorBurst, cfg.Name, nil).hook)
 	}
 
-	return zap.New(core, options...), nil
+	entry.options = entry.loggerOptions(cfg)
+	entry.core = entry.newCore(entry.level, entry.ws)
+	entry.logger = zap.New(entry.core, entry.options...)
+	refreshLink(entry)
+	if cfg.Encryption.enabled() {
+		if entry.encryptor, err = newEncryptor(cfg, entry.files()...); err != nil {
+			return nil, err
+		}
+		entry.encryptor.start()
+	}
+	if cfg.Archive.Bucket != "" {
+		entry.archiver = newArchiver(cfg.Archive, cfg.Name, entry.fileNames()...)
+		if entry.encryptor != nil {
+			entry.archiver.suffix = ".gz" + encryptedSuffix
+		}
+		entry.archiver.start()
+	}
+	if entry.annotator != nil {
+		entry.annotator.start()
+	}
+	return entry, nil
 }
 
-// InitFromLocalFileConfig 初始化日志
-func InitFromLocalFileConfig(configPath string) error {
-	cfg, err := LoadConfig(configPath)
+// Init 按选项初始化日志，WithConfig与WithConfigFile同时指定时以WithConfig为准
+func Init(opts ...InitOption) error {
+	var o initOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	var (
+		cfg Config
+		err error
+	)
+	switch {
+	case o.config != nil:
+		cfg = *o.config
+		migrateConfig(&cfg)
+		applyDefaults(&cfg)
+		err = validateConfig(&cfg)
+	case o.configPath != "":
+		cfg, err = LoadConfigFormat(o.configPath, o.format)
+	default:
+		err = fmt.Errorf("no config provided")
+	}
 	if err != nil {
 		return err
 	}
+	// 配置校验通过后才切换到配置的崩溃日志文件，此前使用临时目录下的fallbackPanicFile
+	if err := initPanicFile(panicFilePath(cfg)); err != nil {
+		return err
+	}
 
 	metux.Lock()
 	defer metux.Unlock()
 
+	logDefaults = cfg.Defaults
+	setTemplates(cfg.Templates)
+	setModuleLevels(cfg.ModuleLevels)
+	setTeams(cfg.Teams)
 	for _, lc := range cfg.Zaplog {
-		logger, err := newLogger(lc)
+		entry, err := newLogger(lc)
 		if err != nil {
 			return fmt.Errorf("failed to create logger %s: %w", lc.Name, err)
 
 		}
-		loggers[lc.Name] = logger
+		loggers[lc.Name] = entry
 
 		if lc.Name == "default" {
-			zap.ReplaceGlobals(logger)
+			zap.ReplaceGlobals(entry.logger)
 		}
 	}
+	startSilenceWindows(cfg.SilenceWindows)
+	startDiskQuota(cfg.DiskQuota)
+	if cfg.ReopenOnSIGHUP {
+		startSIGHUPHandler()
+	}
+	if cfg.DebugSignals {
+		startDebugSignalHandler()
+	}
 	return nil
 }
 
+// loggerOptions 根据调用者、堆栈等配置生成zap的options，SetCaller等运行时修改后重新生成
+func (entry *logEntry) loggerOptions(cfg LogConfig) []zap.Option {
+	options := append([]zap.Option{zap.Hooks(entry.stats.hook, entry.hooks.run)}, flushOptions()...)
+	if cfg.ShowCaller {
+		options = append(options, zap.AddCaller())
+	}
+	if cfg.CallerSkip > 0 {
+		options = append(options, zap.AddCallerSkip(cfg.CallerSkip))
+	}
+	if cfg.Development {
+		options = append(options, zap.Development())
+	}
+	if cfg.StacktraceLevel != "" && !strings.EqualFold(cfg.StacktraceLevel, "none") {
+		options = append(options, zap.AddStacktrace(getLevel(cfg.StacktraceLevel)))
+	}
+	if cfg.K8sMetadata {
+		options = append(options, zap.Fields(k8sFields()...))
+	}
+	if cfg.BuildInfo {
+		options = append(options, zap.Fields(buildInfoFields()...))
+	}
+	return options
+}
+
 // GetLogger 获取指定名称的logger，如果不存在，则返回全局Default logger
 func GetLogger(name string) *zap.Logger {
 	metux.RLock()
 	defer metux.RUnlock()
 
-	logger, ok := loggers[name]
+	entry, ok := loggers[name]
 	if !ok || name == "default" {
-		logger = zap.L()
+		return zap.L()
 	}
-	return logger
+	return entry.logger
 }
 
 // GetDefaultLogger 返回全局Default logger
@@ -229,13 +878,183 @@ func GetDefaultLogger() *zap.Logger {
 	return GetLogger("default")
 }
 
-// Close 关闭所有的logger
+// Check 级别开启时返回指定名称logger待写入的日志，否则返回nil，级别未开启时不产生内存分配，用法见Logger.Check
+func Check(name string, level zapcore.Level, msg string) *zapcore.CheckedEntry {
+	logger := GetLogger(name)
+	if !logger.Core().Enabled(level) {
+		return nil
+	}
+	return logger.WithOptions(zap.AddCallerSkip(1)).Check(level, msg)
+}
+
+// closeTimeout Close等待各logger落盘的最长时间
+const closeTimeout = 5 * time.Second
+
+// Close 关闭所有的logger，同步超时或失败时忽略错误，需要错误信息时使用 CloseContext
 func Close() {
+	ctx, cancel := context.WithTimeout(context.Background(), closeTimeout)
+	defer cancel()
+	_ = CloseContext(ctx)
+}
+
+// CloseContext 关闭所有的logger：在ctx截止前同步各logger并关闭日志文件和sink，
+// 返回汇总后的错误。超时未完成同步的logger不再等待，其余资源照常释放
+func CloseContext(ctx context.Context) error {
 	metux.Lock()
 	defer metux.Unlock()
 
-	for name, logger := range loggers {
-		_ = logger.Sync()
+	// 关闭期间及之后的日志改写stderr
+	zap.ReplaceGlobals(fallback)
+
+	names := make([]string, 0, len(loggers))
+	for name := range loggers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var errs []error
+	for _, name := range names {
+		errs = append(errs, closeEntry(ctx, name, loggers[name])...)
 		delete(loggers, name)
 	}
+	logDefaults = LogDefaults{}
+	setTemplates(nil)
+	setModuleLevels(nil)
+	closeTeams()
+	stopSilenceWindows()
+	stopDiskQuota()
+	stopRemoteConfig()
+	stopSIGHUPHandler()
+	stopDebugSignalHandler()
+	return errors.Join(errs...)
+}
+
+// closeEntry 在ctx截止前同步logger，并关闭日志文件和sink
+func closeEntry(ctx context.Context, name string, entry *logEntry) []error {
+	var errs []error
+	if entry.archiver != nil {
+		entry.archiver.stop()
+	}
+	if entry.encryptor != nil {
+		entry.encryptor.stop()
+	}
+	if entry.annotator != nil {
+		entry.annotator.stop()
+	}
+	if err := syncContext(ctx, entry.logger.Sync); err != nil {
+		errs = append(errs, fmt.Errorf("logger %s: sync: %w", name, err))
+	}
+	// 团队文件由其他logger共用，关闭后在下次写入时重新打开
+	for _, file := range append(entry.files(), entry.teamFiles()...) {
+		if err := file.Close(); err != nil {
+			errs = append(errs, fmt.Errorf("logger %s: close file: %w", name, err))
+		}
+	}
+	for _, sink := range entry.sinks {
+		if c, ok := sink.(io.Closer); ok {
+			if err := c.Close(); err != nil {
+				errs = append(errs, fmt.Errorf("logger %s: close sink: %w", name, err))
+			}
+		}
+	}
+	return errs
+}
+
+// syncContext 执行sync，ctx先结束时返回ctx的错误，sync在后台继续完成
+func syncContext(ctx context.Context, sync func() error) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	done := make(chan error, 1)
+	go func() { done <- sync() }()
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// SetLevel 动态调整指定logger的日志级别，EnableDebugFor的调试窗口期间在窗口结束后生效
+func SetLevel(name, level string) error {
+	if !isValidLevel(level) {
+		return invalidLevel(level)
+	}
+
+	metux.RLock()
+	defer metux.RUnlock()
+
+	entry, ok := loggers[name]
+	if !ok {
+		return fmt.Errorf("logger %s not found", name)
+	}
+	setBaseLevel(entry, getLevel(level))
+	return nil
+}
+
+// SetCaller 动态开启或关闭指定logger的调用者信息。
+// 修改后GetLogger、New、Ctx等返回新的logger，此前取得并保存的*zap.Logger保持原有设置
+func SetCaller(name string, enabled bool) error {
+	return updateOptions(name, func(cfg *LogConfig) { cfg.ShowCaller = enabled })
+}
+
+// SetStacktraceLevel 动态调整指定logger附加堆栈的级别，为空或none时不附加，生效范围同SetCaller
+func SetStacktraceLevel(name, level string) error {
+	if level != "" && !strings.EqualFold(level, "none") && !isValidLevel(level) {
+		return invalidLevel(level)
+	}
+	return updateOptions(name, func(cfg *LogConfig) { cfg.StacktraceLevel = level })
+}
+
+// updateOptions 修改配置后以原有的core和新的options重建logger，core中的级别、输出端和计数等状态不变
+func updateOptions(name string, update func(*LogConfig)) error {
+	metux.Lock()
+	defer metux.Unlock()
+
+	entry, ok := loggers[name]
+	if !ok {
+		return fmt.Errorf("logger %s not found", name)
+	}
+	update(&entry.cfg)
+	entry.options = entry.loggerOptions(entry.cfg)
+	entry.logger = zap.New(entry.core, entry.options...)
+	if name == "default" {
+		zap.ReplaceGlobals(entry.logger)
+		// 模块logger派生自default，需重新创建
+		modules = make(map[string]*module)
+	}
+	return nil
+}
+
+// Rotate 立即切分指定logger的日志文件，name为空时切分所有logger
+func Rotate(name string) error {
+	metux.RLock()
+	defer metux.RUnlock()
+
+	if name != "" {
+		entry, ok := loggers[name]
+		if !ok {
+			return fmt.Errorf("logger %s not found", name)
+		}
+		for _, file := range append(entry.files(), entry.teamFiles()...) {
+			if err := file.Rotate(); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	for name, entry := range loggers {
+		// 已禁用或测试替换的logger没有日志文件
+		for _, file := range entry.files() {
+			if err := file.Rotate(); err != nil {
+				return fmt.Errorf("failed to rotate logger %s: %w", name, err)
+			}
+		}
+	}
+	for _, file := range teamFiles(teams) {
+		if err := file.Rotate(); err != nil {
+			return fmt.Errorf("failed to rotate team file: %w", err)
+		}
+	}
+	return nil
 }
@@ -1,20 +1,425 @@
 package log
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
 	"testing"
+	"time"
 
 	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
 )
 
 func TestLoadConfig(t *testing.T) {
-	err := InitFromLocalFileConfig("./log_config.yaml")
+	dir := t.TempDir()
+	data, err := os.ReadFile("./log_config.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	configPath := filepath.Join(dir, "log_config.yaml")
+	if err := os.WriteFile(configPath, []byte(strings.ReplaceAll(string(data), "./logs", dir)), 0644); err != nil {
+		t.Fatal(err)
+	}
+	err = InitFromLocalFileConfig(configPath)
 	if err != nil {
 		panic(err)
 	}
+	t.Cleanup(Close)
 	defaultLog := GetDefaultLogger()
 	defaultLog.Debug("aa", zapcore.Field{Key: "firstname", String: "chen", Type: zapcore.StringType}, zapcore.Field{Key: "age", Integer: 40, Type: zapcore.Int32Type})
 
 	errorLog := GetLogger("error")
 	errorLog.Error("bb", zapcore.Field{Key: "error", Interface: fmt.Errorf("divided by zero"), Type: zapcore.ErrorType})
 }
+
+// initTestLoggers 在临时目录下初始化default及指定名称的logger
+func initTestLoggers(t *testing.T, names ...string) string {
+	t.Helper()
+
+	dir := t.TempDir()
+	var b strings.Builder
+	b.WriteString("zaplog:\n")
+	for _, name := range append([]string{"default"}, names...) {
+		fmt.Fprintf(&b, "  - name: %s\n    level: info\n    file_name: %s\n    encoder: json\n",
+			name, filepath.Join(dir, name+".log"))
+	}
+	configPath := filepath.Join(dir, "log_config.yaml")
+	if err := os.WriteFile(configPath, []byte(b.String()), 0644); err != nil {
+		t.Fatal(err)
+	}
+	Close()
+	if err := Init(WithConfigFile(configPath)); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(Close)
+	return dir
+}
+
+// observeLogger 以observer替换指定名称的logger，测试结束时恢复
+func observeLogger(t *testing.T, name string) *observer.ObservedLogs {
+	t.Helper()
+
+	logs, restore := installObserver(name, nil)
+	t.Cleanup(restore)
+	return logs
+}
+
+func TestStacktraceLevel(t *testing.T) {
+	dir := t.TempDir()
+	entry, err := newLogger(LogConfig{
+		Name:            "stack",
+		FileName:        filepath.Join(dir, "stack.log"),
+		JsonEncoder:     true,
+		StacktraceLevel: "error",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	entry.logger.Warn("warn")
+	entry.logger.Error("error")
+	_ = entry.logger.Sync()
+	entry.writer.Close()
+
+	data, err := os.ReadFile(filepath.Join(dir, "stack.log"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected 2 lines, got %d", len(lines))
+	}
+	if strings.Contains(lines[0], `"stacktrace"`) || !strings.Contains(lines[1], `"stacktrace"`) {
+		t.Fatalf("stacktrace should only be attached at error, got %s", data)
+	}
+
+	cfg := Config{Zaplog: []LogConfig{{Name: "default", FileName: "a.log", StacktraceLevel: "verbose"}}}
+	if err := validateConfig(&cfg); err == nil {
+		t.Fatal("expected error for invalid stacktrace_level")
+	}
+}
+
+func TestCallerOptions(t *testing.T) {
+	dir := t.TempDir()
+	_, file, _, _ := runtime.Caller(0)
+	entry, err := newLogger(LogConfig{
+		Name:             "caller",
+		FileName:         filepath.Join(dir, "caller.log"),
+		JsonEncoder:      true,
+		ShowCaller:       true,
+		CallerSkip:       1,
+		CallerTrimPrefix: filepath.Dir(filepath.Dir(file)),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	logVia := func() { entry.logger.Info("wrapped") }
+	logVia()
+	_, _, line, _ := runtime.Caller(0)
+	_ = entry.logger.Sync()
+	entry.writer.Close()
+
+	data, err := os.ReadFile(filepath.Join(dir, "caller.log"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got struct {
+		Caller string `json:"caller"`
+	}
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatal(err)
+	}
+	// caller_skip跳过logVia，报告其调用处
+	if want := fmt.Sprintf("log/logger_test.go:%d", line-1); got.Caller != want {
+		t.Fatalf("expected caller %q, got %q", want, got.Caller)
+	}
+}
+
+func TestTimeEncoder(t *testing.T) {
+	ts := time.Date(2024, 5, 6, 7, 8, 9, 0, time.FixedZone("CST", 8*3600))
+	cases := []struct {
+		format, timezone string
+		want             any
+	}{
+		{"", "UTC", "2024-05-05T23:08:09.000Z"},
+		{"rfc3339", "UTC", "2024-05-05T23:08:09Z"},
+		{"epoch_ms", "", float64(ts.UnixMilli())},
+		{"2006-01-02 15:04:05", "Asia/Shanghai", "2024-05-06 07:08:09"},
+	}
+	for _, c := range cases {
+		enc := zapcore.NewMapObjectEncoder()
+		_ = enc.AddArray("t", zapcore.ArrayMarshalerFunc(func(ae zapcore.ArrayEncoder) error {
+			getTimeEncoder(c.format, c.timezone)(ts, ae)
+			return nil
+		}))
+		got := enc.Fields["t"].([]any)[0]
+		if got != c.want {
+			t.Errorf("format %q timezone %q: expected %v, got %v", c.format, c.timezone, c.want, got)
+		}
+	}
+
+	cfg := Config{Zaplog: []LogConfig{{Name: "default", FileName: "a.log", Timezone: "Mars/Olympus"}}}
+	if err := validateConfig(&cfg); err == nil {
+		t.Fatal("expected error for invalid timezone")
+	}
+}
+
+func TestEncoderKeys(t *testing.T) {
+	enc, _ := getEncoder(LogConfig{
+		JsonEncoder: true,
+		MessageKey:  "message",
+		LevelKey:    "severity",
+		TimeKey:     "@timestamp",
+		CallerKey:   "source",
+	})
+	buf, err := enc.EncodeEntry(zapcore.Entry{
+		Level:   zapcore.WarnLevel,
+		Time:    time.Now(),
+		Message: "hello",
+		Caller:  zapcore.NewEntryCaller(0, "/src/app/main.go", 10, true),
+	}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatal(err)
+	}
+	for _, key := range []string{"message", "severity", "@timestamp", "source"} {
+		if _, ok := got[key]; !ok {
+			t.Errorf("missing key %q in %s", key, buf.String())
+		}
+	}
+}
+
+func TestLoadConfigFormats(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"log.json": `{"zaplog": [{"name": "default", "level": "warn", "file_name": "./logs/app.log"}], "module_levels": {"dao": "debug"}}`,
+		"log.toml": "[[zaplog]]\nname = \"default\"\nlevel = \"warn\"\nfile_name = \"./logs/app.log\"\n\n[module_levels]\ndao = \"debug\"\n",
+		// 扩展名无法识别时显式指定格式
+		"log.conf": `{"zaplog": [{"name": "default", "level": "warn", "file_name": "./logs/app.log"}], "module_levels": {"dao": "debug"}}`,
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		format := ""
+		if name == "log.conf" {
+			format = "json"
+		}
+		cfg, err := LoadConfigFormat(path, format)
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		if len(cfg.Zaplog) != 1 || cfg.Zaplog[0].Level != "warn" || cfg.ModuleLevels["dao"] != "debug" {
+			t.Fatalf("%s: unexpected config %+v", name, cfg)
+		}
+	}
+
+	if _, err := LoadConfigFormat(filepath.Join(dir, "log.json"), "ini"); err == nil {
+		t.Fatal("expected error for unsupported format")
+	}
+}
+
+func TestConfigDefaults(t *testing.T) {
+	dir := t.TempDir()
+	configPath := filepath.Join(dir, "log_config.yaml")
+	config := `defaults:
+  level: warn
+  max_size: 50
+  encoder: logfmt
+  directory: /var/log/app
+zaplog:
+  - name: default
+  - name: access
+    level: info
+    file_name: access/access.log
+    encoder: json
+  - name: audit
+    file_name: /data/audit.log
+`
+	if err := os.WriteFile(configPath, []byte(config), 0644); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := LoadConfig(configPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []struct {
+		level, encoder, file string
+	}{
+		{"warn", "logfmt", "/var/log/app/default.log"},
+		{"info", "json", "/var/log/app/access/access.log"},
+		{"warn", "logfmt", "/data/audit.log"},
+	}
+	for i, w := range want {
+		lc := cfg.Zaplog[i]
+		if lc.Level != w.level || lc.Encoder != w.encoder || lc.FileName != w.file || lc.MaxSize != 50 {
+			t.Errorf("%s: unexpected config %+v", lc.Name, lc)
+		}
+	}
+}
+
+func TestValidateConfigAggregatesErrors(t *testing.T) {
+	dir := t.TempDir()
+	notDir := filepath.Join(dir, "file")
+	if err := os.WriteFile(notDir, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	cfg := Config{
+		Zaplog: []LogConfig{
+			{Name: "access", Level: "verbose", FileName: filepath.Join(dir, "access.log")},
+			{Name: "access", FileName: filepath.Join(dir, "access2.log")},
+			{FileName: filepath.Join(dir, "noname.log")},
+			{Name: "broken", FileName: filepath.Join(notDir, "broken.log")},
+		},
+		ModuleLevels: map[string]string{"dao": "loud"},
+	}
+
+	err := validateConfig(&cfg)
+	if err == nil {
+		t.Fatal("expected validation errors")
+	}
+	errs := err.(interface{ Unwrap() []error }).Unwrap()
+	for _, want := range []string{
+		`logger access: invalid level "verbose"`,
+		"logger access: duplicate name",
+		"logger 2: name is required",
+		"logger broken: " + notDir + " is not a directory",
+		"no default logger configuration found",
+		`module dao: invalid level "loud"`,
+	} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("missing error %q in:\n%v", want, err)
+		}
+	}
+	if len(errs) != 6 {
+		t.Fatalf("expected 6 errors, got %d:\n%v", len(errs), err)
+	}
+}
+
+// syncSink Sync按配置阻塞或返回错误的测试输出端
+type syncSink struct {
+	block chan struct{}
+	err   error
+}
+
+func (s *syncSink) Write(p []byte) (int, error) { return len(p), nil }
+
+func (s *syncSink) Sync() error {
+	if s.block != nil {
+		<-s.block
+	}
+	return s.err
+}
+
+func TestCloseContext(t *testing.T) {
+	sinks := map[string]*syncSink{
+		"slow":   {block: make(chan struct{})},
+		"broken": {err: errors.New("disk gone")},
+	}
+	defer close(sinks["slow"].block)
+	RegisterSink("test-sync", func(logger string, options map[string]any) (zapcore.WriteSyncer, error) {
+		return sinks[fmt.Sprint(options["kind"])], nil
+	})
+
+	dir := t.TempDir()
+	var b strings.Builder
+	b.WriteString("zaplog:\n")
+	for _, name := range []string{"default", "slow", "broken"} {
+		fmt.Fprintf(&b, "  - name: %s\n    file_name: %s\n", name, filepath.Join(dir, name+".log"))
+		if name != "default" {
+			fmt.Fprintf(&b, "    sinks:\n      - type: test-sync\n        options:\n          kind: %s\n", name)
+		}
+	}
+	configPath := filepath.Join(dir, "log_config.yaml")
+	if err := os.WriteFile(configPath, []byte(b.String()), 0644); err != nil {
+		t.Fatal(err)
+	}
+	Close()
+	if err := Init(WithConfigFile(configPath)); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(Close)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	err := CloseContext(ctx)
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Fatalf("CloseContext should honour the deadline, took %s", elapsed)
+	}
+	if !errors.Is(err, context.DeadlineExceeded) || !strings.Contains(err.Error(), "logger slow: sync") {
+		t.Fatalf("expected slow logger to time out, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "logger broken: sync: disk gone") {
+		t.Fatalf("expected broken logger sync error, got %v", err)
+	}
+	if strings.Contains(err.Error(), "logger default") {
+		t.Fatalf("default logger should sync cleanly, got %v", err)
+	}
+	if _, ok := loggers["slow"]; ok {
+		t.Fatal("loggers should be released even when sync times out")
+	}
+	if err := CloseContext(context.Background()); err != nil {
+		t.Fatalf("closing twice should be a no-op, got %v", err)
+	}
+}
+
+func TestSetCallerAndStacktraceLevel(t *testing.T) {
+	dir := initTestLoggers(t, "access")
+	logger := New("access")
+	if err := SetLevel("access", "warn"); err != nil {
+		t.Fatal(err)
+	}
+
+	logger.Warn("before")
+	if err := SetCaller("access", true); err != nil {
+		t.Fatal(err)
+	}
+	if err := SetStacktraceLevel("access", "warn"); err != nil {
+		t.Fatal(err)
+	}
+	logger.Info("filtered")
+	logger.Warn("enabled")
+	if err := SetCaller("access", false); err != nil {
+		t.Fatal(err)
+	}
+	if err := SetStacktraceLevel("access", "none"); err != nil {
+		t.Fatal(err)
+	}
+	GetLogger("access").Error("disabled")
+
+	data, err := os.ReadFile(filepath.Join(dir, "access.log"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("level should be kept, got %s", data)
+	}
+	if strings.Contains(lines[0], `"caller"`) || strings.Contains(lines[0], `"stacktrace"`) {
+		t.Fatalf("unexpected caller or stacktrace: %s", lines[0])
+	}
+	if !strings.Contains(lines[1], `"caller":"log/logger_test.go:`) || !strings.Contains(lines[1], `"stacktrace"`) {
+		t.Fatalf("expected caller and stacktrace: %s", lines[1])
+	}
+	if strings.Contains(lines[2], `"caller"`) || strings.Contains(lines[2], `"stacktrace"`) {
+		t.Fatalf("unexpected caller or stacktrace: %s", lines[2])
+	}
+
+	if err := SetStacktraceLevel("access", "loud"); err == nil {
+		t.Fatal("expected invalid level error")
+	}
+	if err := SetCaller("missing", true); err == nil {
+		t.Fatal("expected unknown logger error")
+	}
+}
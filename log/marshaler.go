@@ -0,0 +1,47 @@
+package log
+
+import (
+	"reflect"
+	"sync"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// marshalers 类型到序列化函数的映射，key为reflect.Type
+var marshalers sync.Map
+
+// RegisterMarshaler 注册类型T的日志序列化函数，之后通过Any记录T类型的值时自动使用该函数，
+// 重复注册时覆盖之前的函数
+func RegisterMarshaler[T any](fn func(T) []zap.Field) {
+	t := reflect.TypeOf((*T)(nil)).Elem()
+	marshalers.Store(t, func(v interface{}) []zap.Field {
+		return fn(v.(T))
+	})
+}
+
+// Any 与zap.Any相同，但优先使用RegisterMarshaler注册的序列化函数
+func Any(key string, value interface{}) zap.Field {
+	if value != nil {
+		if fn, ok := marshalers.Load(reflect.TypeOf(value)); ok {
+			return zap.Object(key, registeredObject{
+				value: value,
+				fn:    fn.(func(interface{}) []zap.Field),
+			})
+		}
+	}
+	return zap.Any(key, value)
+}
+
+// registeredObject 在编码时才调用序列化函数，日志级别未开启时没有额外开销
+type registeredObject struct {
+	value interface{}
+	fn    func(interface{}) []zap.Field
+}
+
+func (o registeredObject) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	for _, f := range o.fn(o.value) {
+		f.AddTo(enc)
+	}
+	return nil
+}
@@ -0,0 +1,38 @@
+package log
+
+import (
+	"testing"
+
+	"go.uber.org/zap"
+)
+
+type testUser struct {
+	ID       int
+	Name     string
+	Password string
+}
+
+func TestRegisterMarshaler(t *testing.T) {
+	logs := observeLogger(t, "marshaler")
+	RegisterMarshaler(func(u testUser) []zap.Field {
+		return []zap.Field{zap.Int("id", u.ID), zap.String("name", u.Name)}
+	})
+	RegisterMarshaler(func(u *testUser) []zap.Field {
+		return []zap.Field{zap.Int("id", u.ID)}
+	})
+
+	u := testUser{ID: 1, Name: "chen", Password: "secret"}
+	GetLogger("marshaler").Info("login", Any("user", u), Any("ptr", &u), Any("other", 42))
+
+	fields := logs.All()[0].ContextMap()
+	user, ok := fields["user"].(map[string]interface{})
+	if !ok || user["id"] != int64(1) || user["name"] != "chen" || user["Password"] != nil {
+		t.Fatalf("unexpected user field: %#v", fields["user"])
+	}
+	if ptr, ok := fields["ptr"].(map[string]interface{}); !ok || len(ptr) != 1 {
+		t.Fatalf("unexpected ptr field: %#v", fields["ptr"])
+	}
+	if fields["other"] != int64(42) {
+		t.Fatalf("unexpected other field: %#v", fields["other"])
+	}
+}
@@ -0,0 +1,77 @@
+package log
+
+import (
+	"context"
+	"slices"
+	"sync"
+
+	"go.uber.org/zap"
+)
+
+// mdc 请求级的可变字段表（mapped diagnostic context），同一请求的各层共享，按首次设置的顺序输出
+type mdc struct {
+	mu     sync.Mutex
+	keys   []string
+	fields map[string]zap.Field
+}
+
+type mdcKey struct{}
+
+// WithMDC 在ctx上开启MDC，之后通过MDCSet设置的字段对该ctx及其派生ctx上的所有日志生效，
+// 即使设置发生在更深的调用层。HTTPMiddleware、GinLogger与UnaryServerInterceptor已自动开启，ctx上已有MDC时原样返回
+func WithMDC(ctx context.Context) context.Context {
+	if _, ok := ctx.Value(mdcKey{}).(*mdc); ok {
+		return ctx
+	}
+	return context.WithValue(ctx, mdcKey{}, &mdc{fields: make(map[string]zap.Field)})
+}
+
+// MDCSet 设置MDC字段，之后由Ctx、FromContext及Logger.Ctx输出的日志都附加该字段，
+// 重复设置时覆盖原值。ctx上未通过WithMDC开启MDC时返回false
+func MDCSet(ctx context.Context, key string, value any) bool {
+	m, ok := ctx.Value(mdcKey{}).(*mdc)
+	if !ok {
+		return false
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, exists := m.fields[key]; !exists {
+		m.keys = append(m.keys, key)
+	}
+	m.fields[key] = Any(key, value)
+	return true
+}
+
+// MDCRemove 删除MDC字段
+func MDCRemove(ctx context.Context, key string) {
+	m, ok := ctx.Value(mdcKey{}).(*mdc)
+	if !ok {
+		return
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, exists := m.fields[key]; exists {
+		delete(m.fields, key)
+		m.keys = slices.DeleteFunc(m.keys, func(k string) bool { return k == key })
+	}
+}
+
+// mdcFields 返回ctx上MDC字段的快照
+func mdcFields(ctx context.Context) []zap.Field {
+	m, ok := ctx.Value(mdcKey{}).(*mdc)
+	if !ok {
+		return nil
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	fields := make([]zap.Field, 0, len(m.keys))
+	for _, key := range m.keys {
+		fields = append(fields, m.fields[key])
+	}
+	return fields
+}
+
+// Ctx 返回附加了ctx上字段与MDC字段的default logger，等同于FromContext(ctx, "default")
+func Ctx(ctx context.Context) *zap.Logger {
+	return FromContext(ctx, "default")
+}
@@ -0,0 +1,62 @@
+package log
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+
+	"go.uber.org/zap"
+)
+
+func TestMDC(t *testing.T) {
+	if MDCSet(context.Background(), "user", "u1") {
+		t.Fatal("MDCSet without WithMDC should report false")
+	}
+
+	ctx := WithMDC(context.Background())
+	if WithMDC(ctx) != ctx {
+		t.Fatal("WithMDC should reuse an existing MDC")
+	}
+	// 派生ctx上的设置对原ctx可见
+	child := WithFields(ctx, zap.String("service", "api"))
+	MDCSet(child, "user", "u1")
+	MDCSet(child, "session", "s1")
+	MDCSet(ctx, "user", "u2")
+
+	if got := fieldKeys(ContextFields(ctx)); !slices.Equal(got, []string{"user", "session"}) {
+		t.Fatalf("unexpected MDC fields %v", got)
+	}
+	if got := fieldKeys(ContextFields(child)); !slices.Equal(got, []string{"service", "user", "session"}) {
+		t.Fatalf("MDC fields should follow context fields, got %v", got)
+	}
+	MDCRemove(ctx, "user")
+	if got := fieldKeys(ContextFields(ctx)); !slices.Equal(got, []string{"session"}) {
+		t.Fatalf("unexpected fields after remove %v", got)
+	}
+
+	logs := observeLogger(t, "default")
+	Ctx(ctx).Info("hello")
+	if fields := logs.All()[0].ContextMap(); fields["session"] != "s1" {
+		t.Fatalf("Ctx should include MDC fields, got %v", fields)
+	}
+}
+
+func TestHTTPMiddlewareMDC(t *testing.T) {
+	access := observeLogger(t, "access")
+	app := observeLogger(t, "default")
+
+	handler := HTTPMiddleware("access")(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		MDCSet(r.Context(), "user_id", "42")
+		Ctx(r.Context()).Info("loaded profile")
+	}))
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/profile", nil))
+
+	if fields := app.All()[0].ContextMap(); fields["user_id"] != "42" {
+		t.Fatalf("handler log should include MDC fields, got %v", fields)
+	}
+	if fields := access.All()[0].ContextMap(); fields["user_id"] != "42" {
+		t.Fatalf("access log should include MDC fields set by the handler, got %v", fields)
+	}
+}
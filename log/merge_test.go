@@ -0,0 +1,75 @@
+package log
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoadConfigMerge(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"00-base.yaml": `
+module_levels: {dao: info}
+zaplog:
+  - name: default
+    level: info
+    file_name: ./logs/app.log
+    sampling: {initial: 100, thereafter: 100}
+    sinks: [{type: journald}]
+  - name: access
+    file_name: ./logs/access.log
+`,
+		"10-prod.yaml": `
+module_levels: {cache: warn}
+zaplog:
+  - name: default
+    level: warn
+    sampling: {thereafter: 10}
+    sinks: []
+  - name: audit
+    file_name: ./logs/audit.log
+`,
+		"20-local.json": `{"zaplog": [{"name": "access", "level": "debug"}]}`,
+		"README.md":     "not a config file",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	cfg, err := LoadConfig(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cfg.Zaplog) != 3 || cfg.Zaplog[0].Name != "default" || cfg.Zaplog[1].Name != "access" || cfg.Zaplog[2].Name != "audit" {
+		t.Fatalf("unexpected loggers %+v", cfg.Zaplog)
+	}
+	def := cfg.Zaplog[0]
+	if def.Level != "warn" || def.FileName != "./logs/app.log" || def.Sampling.Initial != 100 || def.Sampling.Thereafter != 10 || len(def.Sinks) != 0 {
+		t.Fatalf("unexpected default logger %+v", def)
+	}
+	if cfg.Zaplog[1].Level != "debug" || cfg.Zaplog[1].FileName != "./logs/access.log" {
+		t.Fatalf("unexpected access logger %+v", cfg.Zaplog[1])
+	}
+	if cfg.ModuleLevels["dao"] != "info" || cfg.ModuleLevels["cache"] != "warn" {
+		t.Fatalf("unexpected module levels %v", cfg.ModuleLevels)
+	}
+
+	// glob只合并匹配的文件
+	cfg, err = LoadConfig(filepath.Join(dir, "*.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cfg.Zaplog) != 3 || cfg.Zaplog[1].Level == "debug" {
+		t.Fatalf("unexpected loggers %+v", cfg.Zaplog)
+	}
+
+	if _, err := LoadConfig(filepath.Join(dir, "*.toml")); err == nil {
+		t.Fatal("expected error when no files match")
+	}
+	if _, err := LoadConfig(t.TempDir()); err == nil {
+		t.Fatal("expected error for empty dir")
+	}
+}
@@ -0,0 +1,79 @@
+package log
+
+import (
+	"sync/atomic"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/zap/zapcore"
+)
+
+// writeErrorCore 统计写入输出端失败的次数
+type writeErrorCore struct {
+	zapcore.Core
+	errors *atomic.Uint64
+}
+
+func newWriteErrorCore(core zapcore.Core, errors *atomic.Uint64) zapcore.Core {
+	return &writeErrorCore{Core: core, errors: errors}
+}
+
+func (c *writeErrorCore) With(fields []zapcore.Field) zapcore.Core {
+	return &writeErrorCore{Core: c.Core.With(fields), errors: c.errors}
+}
+
+func (c *writeErrorCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+func (c *writeErrorCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	err := c.Core.Write(ent, fields)
+	if err != nil {
+		c.errors.Add(1)
+	}
+	return err
+}
+
+var (
+	entriesDesc = prometheus.NewDesc(
+		"goeasy_log_entries_total",
+		"Number of log entries written, by logger and level.",
+		[]string{"logger", "level"}, nil,
+	)
+	writeErrorsDesc = prometheus.NewDesc(
+		"goeasy_log_write_errors_total",
+		"Number of failed writes to log outputs, by logger.",
+		[]string{"logger"}, nil,
+	)
+)
+
+// metricsCollector 导出各logger的日志条数和写入失败次数
+type metricsCollector struct{}
+
+// NewMetricsCollector 返回导出日志量指标的Prometheus collector，需自行注册：
+//
+//	prometheus.MustRegister(log.NewMetricsCollector())
+//
+// 指标在重新初始化后从零开始计数
+func NewMetricsCollector() prometheus.Collector {
+	return metricsCollector{}
+}
+
+func (metricsCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- entriesDesc
+	ch <- writeErrorsDesc
+}
+
+func (metricsCollector) Collect(ch chan<- prometheus.Metric) {
+	metux.RLock()
+	defer metux.RUnlock()
+
+	for name, entry := range loggers {
+		for level, n := range entry.stats.snapshot() {
+			ch <- prometheus.MustNewConstMetric(entriesDesc, prometheus.CounterValue, float64(n), name, level)
+		}
+		ch <- prometheus.MustNewConstMetric(writeErrorsDesc, prometheus.CounterValue, float64(entry.writeErrors.Load()), name)
+	}
+}
@@ -0,0 +1,48 @@
+package log
+
+import (
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+func TestMetricsCollector(t *testing.T) {
+	initTestLoggers(t, "access")
+	reg := prometheus.NewPedanticRegistry()
+	reg.MustRegister(NewMetricsCollector())
+
+	logger := GetLogger("access")
+	logger.Info("a")
+	logger.Error("b")
+	restore, err := SimulateDiskFaults("access", DiskFaults{ErrorRate: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	logger.Error("c")
+	restore()
+
+	families, err := reg.Gather()
+	if err != nil {
+		t.Fatal(err)
+	}
+	values := make(map[string]float64)
+	for _, mf := range families {
+		for _, m := range mf.GetMetric() {
+			key := mf.GetName()
+			for _, l := range m.GetLabel() {
+				key += "," + l.GetName() + "=" + l.GetValue()
+			}
+			values[key] = m.GetCounter().GetValue()
+		}
+	}
+
+	if got := values["goeasy_log_entries_total,level=error,logger=access"]; got != 2 {
+		t.Fatalf("expected 2 error entries, got %v", got)
+	}
+	if got := values["goeasy_log_entries_total,level=info,logger=access"]; got != 1 {
+		t.Fatalf("expected 1 info entry, got %v", got)
+	}
+	if got := values["goeasy_log_write_errors_total,logger=access"]; got != 1 {
+		t.Fatalf("expected 1 write error, got %v", got)
+	}
+}
@@ -0,0 +1,105 @@
+package log
+
+import (
+	"strings"
+	"sync/atomic"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// moduleLevel 模块级别，未设置覆盖时跟随default logger的级别
+type moduleLevel struct {
+	overridden atomic.Bool
+	level      zap.AtomicLevel
+	parent     zap.AtomicLevel
+}
+
+func (l *moduleLevel) Enabled(level zapcore.Level) bool {
+	if l.overridden.Load() {
+		return l.level.Enabled(level)
+	}
+	return l.parent.Enabled(level)
+}
+
+func (l *moduleLevel) set(level string) {
+	l.level.SetLevel(getLevel(level))
+	l.overridden.Store(true)
+}
+
+// module 模块logger
+type module struct {
+	level  *moduleLevel
+	logger *zap.Logger
+}
+
+var (
+	moduleLevels = make(map[string]string)
+	modules      = make(map[string]*module)
+)
+
+// setModuleLevels 替换模块级别配置并清空已创建的模块logger，调用方需持有metux写锁
+func setModuleLevels(levels map[string]string) {
+	moduleLevels = make(map[string]string, len(levels))
+	for name, level := range levels {
+		moduleLevels[strings.ToLower(name)] = level
+	}
+	modules = make(map[string]*module)
+}
+
+// Module 返回default logger名为name的子logger，级别取自配置中的 module_levels，
+// 未配置时跟随default logger：
+//
+//	module_levels:
+//	  dao: debug
+//	  cron: warn
+func Module(name string) *zap.Logger {
+	key := strings.ToLower(name)
+
+	metux.RLock()
+	m, ok := modules[key]
+	metux.RUnlock()
+	if ok {
+		return m.logger
+	}
+
+	metux.Lock()
+	defer metux.Unlock()
+
+	if m, ok := modules[key]; ok {
+		return m.logger
+	}
+	entry, ok := loggers["default"]
+	if !ok {
+		return zap.L().Named(name)
+	}
+	if entry.newCore == nil {
+		// 如测试中以observer替换的logger
+		return entry.logger.Named(name)
+	}
+
+	m = &module{level: &moduleLevel{level: zap.NewAtomicLevel(), parent: entry.level}}
+	if level, ok := moduleLevels[key]; ok {
+		m.level.set(level)
+	}
+	m.logger = zap.New(entry.newCore(m.level, entry.ws), entry.options...).Named(name)
+	modules[key] = m
+	return m.logger
+}
+
+// SetModuleLevel 动态调整模块日志级别
+func SetModuleLevel(name, level string) error {
+	if !isValidLevel(level) {
+		return invalidLevel(level)
+	}
+	key := strings.ToLower(name)
+
+	metux.Lock()
+	defer metux.Unlock()
+
+	moduleLevels[key] = level
+	if m, ok := modules[key]; ok {
+		m.level.set(level)
+	}
+	return nil
+}
@@ -0,0 +1,51 @@
+package log
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"go.uber.org/zap/zapcore"
+)
+
+func TestModule(t *testing.T) {
+	dir := t.TempDir()
+	config := "zaplog:\n  - name: default\n    level: info\n    file_name: " + filepath.Join(dir, "app.log") +
+		"\nmodule_levels:\n  dao: debug\n  cron: warn\n"
+	configPath := filepath.Join(dir, "log_config.yaml")
+	if err := os.WriteFile(configPath, []byte(config), 0644); err != nil {
+		t.Fatal(err)
+	}
+	Close()
+	if err := Init(WithConfigFile(configPath)); err != nil {
+		t.Fatal(err)
+	}
+	defer Close()
+
+	if !Module("dao").Core().Enabled(zapcore.DebugLevel) {
+		t.Fatal("dao should be at debug")
+	}
+	if Module("cron").Core().Enabled(zapcore.InfoLevel) {
+		t.Fatal("cron should be at warn")
+	}
+	api := Module("api")
+	if api.Core().Enabled(zapcore.DebugLevel) || !api.Core().Enabled(zapcore.InfoLevel) {
+		t.Fatal("api should follow the default level")
+	}
+	if Module("dao") != Module("DAO") {
+		t.Fatal("module loggers should be cached case-insensitively")
+	}
+
+	if err := SetLevel("default", "debug"); err != nil {
+		t.Fatal(err)
+	}
+	if !api.Core().Enabled(zapcore.DebugLevel) {
+		t.Fatal("api should follow default level changes")
+	}
+	if err := SetModuleLevel("api", "error"); err != nil {
+		t.Fatal(err)
+	}
+	if api.Core().Enabled(zapcore.WarnLevel) {
+		t.Fatal("api should be at error after override")
+	}
+}
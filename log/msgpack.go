@@ -0,0 +1,575 @@
+package log
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"go.uber.org/zap/buffer"
+	"go.uber.org/zap/zapcore"
+)
+
+var (
+	msgpackPool        = buffer.NewPool()
+	msgpackEncoderPool = sync.Pool{New: func() any { return &msgpackEncoder{} }}
+)
+
+// msgpackTimeExt MessagePack规范中timestamp扩展类型的编号-1
+const msgpackTimeExt byte = 0xff
+
+// msgpackEncoder 将每条日志编码为一个MessagePack map，条目之间不加分隔符(map本身可确定边界)。
+// 相比json省去转义与数字格式化，适合网络sinks等高吞吐管道，消费端使用MsgpackDecoder或goeasy decode解码
+type msgpackEncoder struct {
+	cfg *zapcore.EncoderConfig
+	buf *buffer.Buffer
+	n   int                // buf中已写入的键值对数
+	ns  []msgpackNamespace // OpenNamespace打开的外层
+
+	scratch msgpackArrayEncoder // addEncoded复用，避免每个时间、级别字段分配一次
+}
+
+// msgpackNamespace 保存OpenNamespace之前外层的内容，关闭时将内层作为map值写回
+type msgpackNamespace struct {
+	key string
+	buf *buffer.Buffer
+	n   int
+}
+
+func newMsgpackEncoder(cfg zapcore.EncoderConfig) zapcore.Encoder {
+	return &msgpackEncoder{cfg: &cfg, buf: msgpackPool.Get()}
+}
+
+func (enc *msgpackEncoder) Clone() zapcore.Encoder {
+	clone := &msgpackEncoder{cfg: enc.cfg, buf: msgpackPool.Get(), n: enc.n}
+	_, _ = clone.buf.Write(enc.buf.Bytes())
+	for _, ns := range enc.ns {
+		buf := msgpackPool.Get()
+		_, _ = buf.Write(ns.buf.Bytes())
+		clone.ns = append(clone.ns, msgpackNamespace{key: ns.key, buf: buf, n: ns.n})
+	}
+	return clone
+}
+
+func (enc *msgpackEncoder) EncodeEntry(ent zapcore.Entry, fields []zapcore.Field) (*buffer.Buffer, error) {
+	final := msgpackEncoderPool.Get().(*msgpackEncoder)
+	final.cfg, final.buf = enc.cfg, msgpackPool.Get()
+
+	if final.cfg.TimeKey != "" && final.cfg.EncodeTime != nil {
+		final.addEncoded(final.cfg.TimeKey, func(arr zapcore.PrimitiveArrayEncoder) { final.cfg.EncodeTime(ent.Time, arr) })
+	}
+	if final.cfg.LevelKey != "" && final.cfg.EncodeLevel != nil {
+		final.addEncoded(final.cfg.LevelKey, func(arr zapcore.PrimitiveArrayEncoder) { final.cfg.EncodeLevel(ent.Level, arr) })
+	}
+	if ent.LoggerName != "" && final.cfg.NameKey != "" {
+		final.AddString(final.cfg.NameKey, ent.LoggerName)
+	}
+	if ent.Caller.Defined && final.cfg.CallerKey != "" && final.cfg.EncodeCaller != nil {
+		final.addEncoded(final.cfg.CallerKey, func(arr zapcore.PrimitiveArrayEncoder) { final.cfg.EncodeCaller(ent.Caller, arr) })
+	}
+	if final.cfg.MessageKey != "" {
+		final.AddString(final.cfg.MessageKey, ent.Message)
+	}
+
+	// With添加的字段及命名空间，有命名空间时需复制各层
+	if len(enc.ns) == 0 {
+		_, _ = final.buf.Write(enc.buf.Bytes())
+		final.n += enc.n
+	} else {
+		ctx := enc.Clone().(*msgpackEncoder)
+		outer := ctx.ns[0]
+		_, _ = final.buf.Write(outer.buf.Bytes())
+		final.n += outer.n
+		outer.buf.Free()
+		ctx.ns[0].buf = final.buf
+		ctx.ns[0].n = final.n
+		final.buf, final.n, final.ns = ctx.buf, ctx.n, ctx.ns
+	}
+
+	for _, f := range fields {
+		f.AddTo(final)
+	}
+	for len(final.ns) > 0 {
+		final.closeNamespace()
+	}
+	if ent.Stack != "" && final.cfg.StacktraceKey != "" {
+		final.AddString(final.cfg.StacktraceKey, ent.Stack)
+	}
+
+	out := msgpackPool.Get()
+	appendMsgpackMapHeader(out, final.n)
+	_, _ = out.Write(final.buf.Bytes())
+	// scratch的buffer随encoder留在池中复用
+	final.buf.Free()
+	*final = msgpackEncoder{scratch: msgpackArrayEncoder{buf: final.scratch.buf}}
+	msgpackEncoderPool.Put(final)
+	return out, nil
+}
+
+func (enc *msgpackEncoder) free() {
+	enc.buf.Free()
+	if enc.scratch.buf != nil {
+		enc.scratch.buf.Free()
+	}
+}
+
+// closeNamespace 将当前层作为map写入外层
+func (enc *msgpackEncoder) closeNamespace() {
+	last := len(enc.ns) - 1
+	outer := enc.ns[last]
+	enc.ns = enc.ns[:last]
+	inner, n := enc.buf, enc.n
+	enc.buf, enc.n = outer.buf, outer.n
+	enc.addKey(outer.key)
+	appendMsgpackMapHeader(enc.buf, n)
+	_, _ = enc.buf.Write(inner.Bytes())
+	inner.Free()
+}
+
+// addEncoded 以EncodeTime/EncodeLevel等编码器输出的第一个值作为值，未输出时不写入该键
+func (enc *msgpackEncoder) addEncoded(key string, encode func(zapcore.PrimitiveArrayEncoder)) {
+	arr := &enc.scratch
+	if arr.buf == nil {
+		arr.buf = msgpackPool.Get()
+	}
+	arr.buf.Reset()
+	arr.cfg, arr.n, arr.first = enc.cfg, 0, 0
+	encode(arr)
+	if arr.n == 0 {
+		return
+	}
+	enc.addKey(key)
+	_, _ = enc.buf.Write(arr.buf.Bytes()[:arr.first])
+}
+
+func (enc *msgpackEncoder) addKey(key string) {
+	enc.n++
+	appendMsgpackString(enc.buf, key)
+}
+
+func (enc *msgpackEncoder) AddArray(key string, marshaler zapcore.ArrayMarshaler) error {
+	arr := &msgpackArrayEncoder{cfg: enc.cfg, buf: msgpackPool.Get()}
+	defer arr.buf.Free()
+	err := marshaler.MarshalLogArray(arr)
+	enc.addKey(key)
+	appendMsgpackArrayHeader(enc.buf, arr.n)
+	_, _ = enc.buf.Write(arr.buf.Bytes())
+	return err
+}
+
+func (enc *msgpackEncoder) AddObject(key string, marshaler zapcore.ObjectMarshaler) error {
+	inner := &msgpackEncoder{cfg: enc.cfg, buf: msgpackPool.Get()}
+	err := marshaler.MarshalLogObject(inner)
+	for len(inner.ns) > 0 {
+		inner.closeNamespace()
+	}
+	enc.addKey(key)
+	appendMsgpackMapHeader(enc.buf, inner.n)
+	_, _ = enc.buf.Write(inner.buf.Bytes())
+	inner.free()
+	return err
+}
+
+func (enc *msgpackEncoder) AddBinary(key string, value []byte) {
+	enc.addKey(key)
+	appendMsgpackBinary(enc.buf, value)
+}
+
+func (enc *msgpackEncoder) AddByteString(key string, value []byte) {
+	enc.addKey(key)
+	appendMsgpackStringHeader(enc.buf, len(value))
+	_, _ = enc.buf.Write(value)
+}
+
+func (enc *msgpackEncoder) AddBool(key string, value bool) {
+	enc.addKey(key)
+	appendMsgpackBool(enc.buf, value)
+}
+
+func (enc *msgpackEncoder) AddComplex128(key string, value complex128) {
+	enc.AddString(key, strconv.FormatComplex(value, 'g', -1, 128))
+}
+
+func (enc *msgpackEncoder) AddComplex64(key string, value complex64) {
+	enc.AddString(key, strconv.FormatComplex(complex128(value), 'g', -1, 64))
+}
+
+func (enc *msgpackEncoder) AddDuration(key string, value time.Duration) {
+	enc.addEncoded(key, func(arr zapcore.PrimitiveArrayEncoder) { arr.(*msgpackArrayEncoder).AppendDuration(value) })
+}
+
+func (enc *msgpackEncoder) AddFloat64(key string, value float64) {
+	enc.addKey(key)
+	appendMsgpackFloat64(enc.buf, value)
+}
+
+func (enc *msgpackEncoder) AddFloat32(key string, value float32) {
+	enc.addKey(key)
+	appendMsgpackFloat32(enc.buf, value)
+}
+
+func (enc *msgpackEncoder) AddInt(key string, value int)     { enc.AddInt64(key, int64(value)) }
+func (enc *msgpackEncoder) AddInt32(key string, value int32) { enc.AddInt64(key, int64(value)) }
+func (enc *msgpackEncoder) AddInt16(key string, value int16) { enc.AddInt64(key, int64(value)) }
+func (enc *msgpackEncoder) AddInt8(key string, value int8)   { enc.AddInt64(key, int64(value)) }
+
+func (enc *msgpackEncoder) AddInt64(key string, value int64) {
+	enc.addKey(key)
+	appendMsgpackInt(enc.buf, value)
+}
+
+func (enc *msgpackEncoder) AddString(key, value string) {
+	enc.addKey(key)
+	appendMsgpackString(enc.buf, value)
+}
+
+func (enc *msgpackEncoder) AddTime(key string, value time.Time) {
+	enc.addEncoded(key, func(arr zapcore.PrimitiveArrayEncoder) { arr.(*msgpackArrayEncoder).AppendTime(value) })
+}
+
+func (enc *msgpackEncoder) AddUint(key string, value uint)       { enc.AddUint64(key, uint64(value)) }
+func (enc *msgpackEncoder) AddUint32(key string, value uint32)   { enc.AddUint64(key, uint64(value)) }
+func (enc *msgpackEncoder) AddUint16(key string, value uint16)   { enc.AddUint64(key, uint64(value)) }
+func (enc *msgpackEncoder) AddUint8(key string, value uint8)     { enc.AddUint64(key, uint64(value)) }
+func (enc *msgpackEncoder) AddUintptr(key string, value uintptr) { enc.AddUint64(key, uint64(value)) }
+
+func (enc *msgpackEncoder) AddUint64(key string, value uint64) {
+	enc.addKey(key)
+	appendMsgpackUint(enc.buf, value)
+}
+
+// AddReflected 经json转换后按结构编码，保留嵌套的对象和数组
+func (enc *msgpackEncoder) AddReflected(key string, value any) error {
+	v, err := reflectedValue(value)
+	if err != nil {
+		return err
+	}
+	enc.addKey(key)
+	appendMsgpackAny(enc.buf, v)
+	return nil
+}
+
+func (enc *msgpackEncoder) OpenNamespace(key string) {
+	enc.ns = append(enc.ns, msgpackNamespace{key: key, buf: enc.buf, n: enc.n})
+	enc.buf, enc.n = msgpackPool.Get(), 0
+}
+
+// msgpackArrayEncoder 收集数组元素，first记录第一个元素的结束位置
+type msgpackArrayEncoder struct {
+	cfg   *zapcore.EncoderConfig
+	buf   *buffer.Buffer
+	n     int
+	first int
+}
+
+func (arr *msgpackArrayEncoder) done() {
+	arr.n++
+	if arr.n == 1 {
+		arr.first = arr.buf.Len()
+	}
+}
+
+func (arr *msgpackArrayEncoder) AppendArray(marshaler zapcore.ArrayMarshaler) error {
+	inner := &msgpackArrayEncoder{cfg: arr.cfg, buf: msgpackPool.Get()}
+	defer inner.buf.Free()
+	err := marshaler.MarshalLogArray(inner)
+	appendMsgpackArrayHeader(arr.buf, inner.n)
+	_, _ = arr.buf.Write(inner.buf.Bytes())
+	arr.done()
+	return err
+}
+
+func (arr *msgpackArrayEncoder) AppendObject(marshaler zapcore.ObjectMarshaler) error {
+	enc := &msgpackEncoder{cfg: arr.cfg, buf: msgpackPool.Get()}
+	err := marshaler.MarshalLogObject(enc)
+	for len(enc.ns) > 0 {
+		enc.closeNamespace()
+	}
+	appendMsgpackMapHeader(arr.buf, enc.n)
+	_, _ = arr.buf.Write(enc.buf.Bytes())
+	enc.free()
+	arr.done()
+	return err
+}
+
+func (arr *msgpackArrayEncoder) AppendReflected(value any) error {
+	v, err := reflectedValue(value)
+	if err != nil {
+		return err
+	}
+	appendMsgpackAny(arr.buf, v)
+	arr.done()
+	return nil
+}
+
+func (arr *msgpackArrayEncoder) AppendBool(v bool) { appendMsgpackBool(arr.buf, v); arr.done() }
+func (arr *msgpackArrayEncoder) AppendByteString(v []byte) {
+	appendMsgpackStringHeader(arr.buf, len(v))
+	_, _ = arr.buf.Write(v)
+	arr.done()
+}
+func (arr *msgpackArrayEncoder) AppendComplex128(v complex128) {
+	arr.AppendString(strconv.FormatComplex(v, 'g', -1, 128))
+}
+func (arr *msgpackArrayEncoder) AppendComplex64(v complex64) {
+	arr.AppendString(strconv.FormatComplex(complex128(v), 'g', -1, 64))
+}
+func (arr *msgpackArrayEncoder) AppendFloat64(v float64) {
+	appendMsgpackFloat64(arr.buf, v)
+	arr.done()
+}
+func (arr *msgpackArrayEncoder) AppendFloat32(v float32) {
+	appendMsgpackFloat32(arr.buf, v)
+	arr.done()
+}
+func (arr *msgpackArrayEncoder) AppendInt(v int)         { arr.AppendInt64(int64(v)) }
+func (arr *msgpackArrayEncoder) AppendInt64(v int64)     { appendMsgpackInt(arr.buf, v); arr.done() }
+func (arr *msgpackArrayEncoder) AppendInt32(v int32)     { arr.AppendInt64(int64(v)) }
+func (arr *msgpackArrayEncoder) AppendInt16(v int16)     { arr.AppendInt64(int64(v)) }
+func (arr *msgpackArrayEncoder) AppendInt8(v int8)       { arr.AppendInt64(int64(v)) }
+func (arr *msgpackArrayEncoder) AppendString(v string)   { appendMsgpackString(arr.buf, v); arr.done() }
+func (arr *msgpackArrayEncoder) AppendUint(v uint)       { arr.AppendUint64(uint64(v)) }
+func (arr *msgpackArrayEncoder) AppendUint64(v uint64)   { appendMsgpackUint(arr.buf, v); arr.done() }
+func (arr *msgpackArrayEncoder) AppendUint32(v uint32)   { arr.AppendUint64(uint64(v)) }
+func (arr *msgpackArrayEncoder) AppendUint16(v uint16)   { arr.AppendUint64(uint64(v)) }
+func (arr *msgpackArrayEncoder) AppendUint8(v uint8)     { arr.AppendUint64(uint64(v)) }
+func (arr *msgpackArrayEncoder) AppendUintptr(v uintptr) { arr.AppendUint64(uint64(v)) }
+
+// AppendDuration 未配置EncodeDuration时编码为纳秒数
+func (arr *msgpackArrayEncoder) AppendDuration(v time.Duration) {
+	n := arr.n
+	if arr.cfg.EncodeDuration != nil {
+		arr.cfg.EncodeDuration(v, arr)
+	}
+	if arr.n == n {
+		arr.AppendInt64(int64(v))
+	}
+}
+
+// AppendTimeLayout 供zapcore的时间编码器调用，格式化时不分配内存
+func (arr *msgpackArrayEncoder) AppendTimeLayout(t time.Time, layout string) {
+	var b [64]byte
+	arr.AppendByteString(t.AppendFormat(b[:0], layout))
+}
+
+// AppendTime 未配置EncodeTime时使用MessagePack的timestamp扩展类型
+func (arr *msgpackArrayEncoder) AppendTime(v time.Time) {
+	n := arr.n
+	if arr.cfg.EncodeTime != nil {
+		arr.cfg.EncodeTime(v, arr)
+	}
+	if arr.n == n {
+		appendMsgpackTime(arr.buf, v)
+		arr.done()
+	}
+}
+
+// reflectedValue 将任意值经json转换为map[string]any、[]any等基本结构，数字保留为json.Number
+func reflectedValue(value any) (any, error) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	var v any
+	d := json.NewDecoder(bytes.NewReader(data))
+	d.UseNumber()
+	if err := d.Decode(&v); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+func appendMsgpackAny(buf *buffer.Buffer, v any) {
+	switch v := v.(type) {
+	case nil:
+		buf.AppendByte(0xc0)
+	case bool:
+		appendMsgpackBool(buf, v)
+	case string:
+		appendMsgpackString(buf, v)
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			appendMsgpackInt(buf, i)
+		} else if u, err := strconv.ParseUint(string(v), 10, 64); err == nil {
+			appendMsgpackUint(buf, u)
+		} else if f, err := v.Float64(); err == nil {
+			appendMsgpackFloat64(buf, f)
+		} else {
+			appendMsgpackString(buf, string(v))
+		}
+	case []any:
+		appendMsgpackArrayHeader(buf, len(v))
+		for _, e := range v {
+			appendMsgpackAny(buf, e)
+		}
+	case map[string]any:
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		appendMsgpackMapHeader(buf, len(v))
+		for _, k := range keys {
+			appendMsgpackString(buf, k)
+			appendMsgpackAny(buf, v[k])
+		}
+	default:
+		appendMsgpackString(buf, fmt.Sprint(v))
+	}
+}
+
+func appendMsgpackBool(buf *buffer.Buffer, v bool) {
+	if v {
+		buf.AppendByte(0xc3)
+	} else {
+		buf.AppendByte(0xc2)
+	}
+}
+
+func appendMsgpackInt(buf *buffer.Buffer, v int64) {
+	switch {
+	case v >= 0:
+		appendMsgpackUint(buf, uint64(v))
+	case v >= -32:
+		buf.AppendByte(byte(v))
+	case v >= math.MinInt8:
+		buf.AppendByte(0xd0)
+		buf.AppendByte(byte(v))
+	case v >= math.MinInt16:
+		buf.AppendByte(0xd1)
+		appendUint16(buf, uint16(v))
+	case v >= math.MinInt32:
+		buf.AppendByte(0xd2)
+		appendUint32(buf, uint32(v))
+	default:
+		buf.AppendByte(0xd3)
+		appendUint64(buf, uint64(v))
+	}
+}
+
+func appendMsgpackUint(buf *buffer.Buffer, v uint64) {
+	switch {
+	case v <= 0x7f:
+		buf.AppendByte(byte(v))
+	case v <= math.MaxUint8:
+		buf.AppendByte(0xcc)
+		buf.AppendByte(byte(v))
+	case v <= math.MaxUint16:
+		buf.AppendByte(0xcd)
+		appendUint16(buf, uint16(v))
+	case v <= math.MaxUint32:
+		buf.AppendByte(0xce)
+		appendUint32(buf, uint32(v))
+	default:
+		buf.AppendByte(0xcf)
+		appendUint64(buf, v)
+	}
+}
+
+func appendMsgpackFloat64(buf *buffer.Buffer, v float64) {
+	buf.AppendByte(0xcb)
+	appendUint64(buf, math.Float64bits(v))
+}
+
+func appendMsgpackFloat32(buf *buffer.Buffer, v float32) {
+	buf.AppendByte(0xca)
+	appendUint32(buf, math.Float32bits(v))
+}
+
+func appendMsgpackString(buf *buffer.Buffer, s string) {
+	appendMsgpackStringHeader(buf, len(s))
+	buf.AppendString(s)
+}
+
+func appendMsgpackStringHeader(buf *buffer.Buffer, n int) {
+	switch {
+	case n < 32:
+		buf.AppendByte(0xa0 | byte(n))
+	case n <= math.MaxUint8:
+		buf.AppendByte(0xd9)
+		buf.AppendByte(byte(n))
+	case n <= math.MaxUint16:
+		buf.AppendByte(0xda)
+		appendUint16(buf, uint16(n))
+	default:
+		buf.AppendByte(0xdb)
+		appendUint32(buf, uint32(n))
+	}
+}
+
+func appendMsgpackBinary(buf *buffer.Buffer, b []byte) {
+	n := len(b)
+	switch {
+	case n <= math.MaxUint8:
+		buf.AppendByte(0xc4)
+		buf.AppendByte(byte(n))
+	case n <= math.MaxUint16:
+		buf.AppendByte(0xc5)
+		appendUint16(buf, uint16(n))
+	default:
+		buf.AppendByte(0xc6)
+		appendUint32(buf, uint32(n))
+	}
+	_, _ = buf.Write(b)
+}
+
+func appendMsgpackArrayHeader(buf *buffer.Buffer, n int) {
+	switch {
+	case n < 16:
+		buf.AppendByte(0x90 | byte(n))
+	case n <= math.MaxUint16:
+		buf.AppendByte(0xdc)
+		appendUint16(buf, uint16(n))
+	default:
+		buf.AppendByte(0xdd)
+		appendUint32(buf, uint32(n))
+	}
+}
+
+func appendMsgpackMapHeader(buf *buffer.Buffer, n int) {
+	switch {
+	case n < 16:
+		buf.AppendByte(0x80 | byte(n))
+	case n <= math.MaxUint16:
+		buf.AppendByte(0xde)
+		appendUint16(buf, uint16(n))
+	default:
+		buf.AppendByte(0xdf)
+		appendUint32(buf, uint32(n))
+	}
+}
+
+// appendMsgpackTime 按timestamp扩展类型编码：秒数在34位以内时用8字节格式，否则用12字节格式
+func appendMsgpackTime(buf *buffer.Buffer, t time.Time) {
+	sec, nsec := t.Unix(), uint32(t.Nanosecond())
+	if sec >= 0 && sec < 1<<34 {
+		buf.AppendByte(0xd7)
+		buf.AppendByte(msgpackTimeExt)
+		appendUint64(buf, uint64(nsec)<<34|uint64(sec))
+		return
+	}
+	buf.AppendByte(0xc7)
+	buf.AppendByte(12)
+	buf.AppendByte(msgpackTimeExt)
+	appendUint32(buf, nsec)
+	appendUint64(buf, uint64(sec))
+}
+
+func appendUint16(buf *buffer.Buffer, v uint16) {
+	buf.AppendByte(byte(v >> 8))
+	buf.AppendByte(byte(v))
+}
+
+func appendUint32(buf *buffer.Buffer, v uint32) {
+	appendUint16(buf, uint16(v>>16))
+	appendUint16(buf, uint16(v))
+}
+
+func appendUint64(buf *buffer.Buffer, v uint64) {
+	appendUint32(buf, uint32(v>>32))
+	appendUint32(buf, uint32(v))
+}
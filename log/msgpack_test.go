@@ -0,0 +1,119 @@
+package log
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"math"
+	"reflect"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+func TestMsgpackEncoder(t *testing.T) {
+	enc, err := getEncoder(LogConfig{Encoder: "msgpack", TimeFormat: "epoch_ms"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	enc.AddString("svc", "api")
+	enc.OpenNamespace("req")
+	enc.AddString("id", "r1")
+
+	ts := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	fields := []zapcore.Field{
+		zap.Int("rows", -42),
+		zap.Uint64("big", math.MaxUint64),
+		zap.Float64("ratio", 0.5),
+		zap.Bool("cached", true),
+		zap.Duration("took", 1500*time.Millisecond),
+		zap.Time("at", ts),
+		zap.Strings("tags", []string{"a", "b"}),
+		zap.Dict("user", zap.String("id", "u1"), zap.Int("age", 300)),
+		zap.Binary("raw", []byte{0, 1}),
+		zap.Any("meta", map[string]any{"n": 70000, "list": []int{1}}),
+		zap.Error(errors.New("timeout")),
+	}
+	var stream bytes.Buffer
+	for i := 0; i < 2; i++ {
+		buf, err := enc.EncodeEntry(zapcore.Entry{Level: zapcore.WarnLevel, Time: ts, Message: "slow query"}, fields)
+		if err != nil {
+			t.Fatal(err)
+		}
+		stream.Write(buf.Bytes())
+		buf.Free()
+	}
+
+	dec := NewMsgpackDecoder(&stream)
+	for i := 0; i < 2; i++ {
+		got, err := dec.Decode()
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := map[string]any{
+			"ts":    float64(ts.UnixMilli()),
+			"level": "WARN",
+			"msg":   "slow query",
+			"svc":   "api",
+			"req": map[string]any{
+				"id":     "r1",
+				"rows":   int64(-42),
+				"big":    uint64(math.MaxUint64),
+				"ratio":  0.5,
+				"cached": true,
+				"took":   1.5,
+				"at":     float64(ts.UnixMilli()),
+				"tags":   []any{"a", "b"},
+				"user":   map[string]any{"id": "u1", "age": int64(300)},
+				"raw":    []byte{0, 1},
+				"meta":   map[string]any{"n": int64(70000), "list": []any{int64(1)}},
+				"error":  "timeout",
+			},
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Fatalf("unexpected entry\n got: %#v\nwant: %#v", got, want)
+		}
+	}
+	if _, err := dec.Decode(); !errors.Is(err, io.EOF) {
+		t.Fatalf("expected io.EOF, got %v", err)
+	}
+
+	buf, _ := enc.EncodeEntry(zapcore.Entry{Level: zapcore.InfoLevel, Time: ts, Message: "x"}, nil)
+	defer buf.Free()
+	_, err = NewMsgpackDecoder(bytes.NewReader(buf.Bytes()[:buf.Len()-1])).Decode()
+	if !errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Fatalf("expected io.ErrUnexpectedEOF, got %v", err)
+	}
+}
+
+func TestMsgpackTimeAndSize(t *testing.T) {
+	// 未配置EncodeTime时使用timestamp扩展类型
+	enc := newMsgpackEncoder(zapcore.EncoderConfig{MessageKey: "msg"})
+	for _, ts := range []time.Time{
+		time.Date(2024, 1, 2, 3, 4, 5, 123456789, time.UTC),
+		time.Date(1960, 1, 1, 0, 0, 0, 1, time.UTC),
+	} {
+		buf, _ := enc.EncodeEntry(zapcore.Entry{Message: "m"}, []zapcore.Field{zap.Time("at", ts)})
+		got, err := NewMsgpackDecoder(bytes.NewReader(buf.Bytes())).Decode()
+		buf.Free()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if at, ok := got["at"].(time.Time); !ok || !at.Equal(ts) {
+			t.Fatalf("unexpected time %v, want %v", got["at"], ts)
+		}
+	}
+
+	// 与json相比输出更小
+	jsonEnc, _ := getEncoder(LogConfig{Encoder: "json"})
+	msgpackEnc, _ := getEncoder(LogConfig{Encoder: "msgpack"})
+	ent := zapcore.Entry{Level: zapcore.InfoLevel, Time: time.Now(), Message: "http request"}
+	fields := []zapcore.Field{zap.String("path", "/api/orders"), zap.Int("status", 200), zap.Float64("latency", 1.25)}
+	j, _ := jsonEnc.EncodeEntry(ent, fields)
+	m, _ := msgpackEnc.EncodeEntry(ent, fields)
+	if m.Len() >= j.Len() {
+		t.Fatalf("msgpack %d bytes, json %d bytes", m.Len(), j.Len())
+	}
+}
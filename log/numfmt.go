@@ -0,0 +1,181 @@
+package log
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// getDurationEncoder 按duration_format返回时长编码器
+func getDurationEncoder(format string) zapcore.DurationEncoder {
+	switch strings.ToLower(format) {
+	case "ms":
+		return zapcore.MillisDurationEncoder
+	case "ns":
+		return zapcore.NanosDurationEncoder
+	case "human":
+		return humanDurationEncoder
+	default:
+		return zapcore.SecondsDurationEncoder
+	}
+}
+
+func isValidDurationFormat(format string) bool {
+	switch strings.ToLower(format) {
+	case "", "s", "ms", "ns", "human":
+		return true
+	}
+	return false
+}
+
+// humanDurationEncoder 按量级取整后输出可读的时长，如 1m32s、1.234s、12.5ms
+func humanDurationEncoder(d time.Duration, enc zapcore.PrimitiveArrayEncoder) {
+	abs := d.Abs()
+	switch {
+	case abs >= time.Minute:
+		d = d.Round(time.Second)
+	case abs >= time.Second:
+		d = d.Round(time.Millisecond)
+	case abs >= time.Millisecond:
+		d = d.Round(time.Microsecond)
+	}
+	enc.AppendString(d.String())
+}
+
+// numberSeparators 各地区的千分位与小数点分隔符
+var numberSeparators = map[string][2]string{
+	"en":    {",", "."},
+	"zh":    {",", "."},
+	"ja":    {",", "."},
+	"ko":    {",", "."},
+	"de":    {".", ","},
+	"es":    {".", ","},
+	"it":    {".", ","},
+	"pt-br": {".", ","},
+	"fr":    {" ", ","},
+	"ru":    {" ", ","},
+	"de-ch": {"'", "."},
+}
+
+// numberFormat 数值字段的格式化方式
+type numberFormat struct {
+	group, decimal string // 千分位与小数点分隔符，group为空时保持数值类型
+	precision      int    // 浮点数保留的小数位数，0表示不处理
+}
+
+func newNumberFormat(locale string, precision int) (numberFormat, error) {
+	f := numberFormat{precision: precision}
+	if locale == "" {
+		return f, nil
+	}
+	seps, ok := numberSeparators[strings.ToLower(locale)]
+	if !ok {
+		return f, fmt.Errorf("unknown number_locale %q", locale)
+	}
+	f.group, f.decimal = seps[0], seps[1]
+	return f, nil
+}
+
+// field 按格式转换数值字段，非数值字段原样返回
+func (f numberFormat) field(field zap.Field) zap.Field {
+	switch field.Type {
+	case zapcore.Int64Type, zapcore.Int32Type, zapcore.Int16Type, zapcore.Int8Type:
+		if f.group != "" {
+			return zap.String(field.Key, f.group3(strconv.FormatInt(field.Integer, 10)))
+		}
+	case zapcore.Uint64Type, zapcore.Uint32Type, zapcore.Uint16Type, zapcore.Uint8Type:
+		if f.group != "" {
+			return zap.String(field.Key, f.group3(strconv.FormatUint(uint64(field.Integer), 10)))
+		}
+	case zapcore.Float64Type:
+		return f.float(field.Key, math.Float64frombits(uint64(field.Integer)), 64)
+	case zapcore.Float32Type:
+		return f.float(field.Key, float64(math.Float32frombits(uint32(field.Integer))), 32)
+	}
+	return field
+}
+
+func (f numberFormat) float(key string, v float64, bitSize int) zap.Field {
+	if math.IsNaN(v) || math.IsInf(v, 0) {
+		return zap.Float64(key, v)
+	}
+	prec := -1
+	if f.precision > 0 {
+		prec = f.precision
+	}
+	if f.group == "" {
+		if prec >= 0 {
+			v, _ = strconv.ParseFloat(strconv.FormatFloat(v, 'f', prec, bitSize), 64)
+		}
+		return zap.Float64(key, v)
+	}
+	s := strconv.FormatFloat(v, 'f', prec, bitSize)
+	intPart, frac, hasFrac := strings.Cut(s, ".")
+	s = f.group3(intPart)
+	if hasFrac {
+		s += f.decimal + frac
+	}
+	return zap.String(key, s)
+}
+
+// group3 为整数字符串添加千分位分隔符
+func (f numberFormat) group3(s string) string {
+	sign := ""
+	if strings.HasPrefix(s, "-") {
+		sign, s = "-", s[1:]
+	}
+	if len(s) <= 3 {
+		return sign + s
+	}
+	var b strings.Builder
+	b.WriteString(sign)
+	head := len(s) % 3
+	if head > 0 {
+		b.WriteString(s[:head])
+	}
+	for i := head; i < len(s); i += 3 {
+		if i > 0 {
+			b.WriteString(f.group)
+		}
+		b.WriteString(s[i : i+3])
+	}
+	return b.String()
+}
+
+// numberCore 按number_locale和float_precision格式化数值字段
+type numberCore struct {
+	zapcore.Core
+	format numberFormat
+}
+
+func newNumberCore(core zapcore.Core, format numberFormat) zapcore.Core {
+	return &numberCore{Core: core, format: format}
+}
+
+func (c *numberCore) fields(fields []zap.Field) []zap.Field {
+	out := make([]zap.Field, len(fields))
+	for i, field := range fields {
+		out[i] = c.format.field(field)
+	}
+	return out
+}
+
+func (c *numberCore) With(fields []zap.Field) zapcore.Core {
+	return &numberCore{Core: c.Core.With(c.fields(fields)), format: c.format}
+}
+
+func (c *numberCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+func (c *numberCore) Write(ent zapcore.Entry, fields []zap.Field) error {
+	return c.Core.Write(ent, c.fields(fields))
+}
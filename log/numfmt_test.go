@@ -0,0 +1,103 @@
+package log
+
+import (
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+func TestDurationEncoder(t *testing.T) {
+	d := 92*time.Second + 345*time.Millisecond
+	cases := []struct {
+		format string
+		d      time.Duration
+		want   any
+	}{
+		{"", d, 92.345},
+		{"ms", d, int64(92345)},
+		{"ns", d, int64(d)},
+		{"human", d, "1m32s"},
+		{"human", 1234567 * time.Microsecond, "1.235s"},
+		{"human", 12500 * time.Microsecond, "12.5ms"},
+	}
+	for _, c := range cases {
+		enc := zapcore.NewMapObjectEncoder()
+		_ = enc.AddArray("d", zapcore.ArrayMarshalerFunc(func(ae zapcore.ArrayEncoder) error {
+			getDurationEncoder(c.format)(c.d, ae)
+			return nil
+		}))
+		if got := enc.Fields["d"].([]any)[0]; got != c.want {
+			t.Errorf("format %q: expected %v (%T), got %v (%T)", c.format, c.want, c.want, got, got)
+		}
+	}
+}
+
+func TestNumberFormat(t *testing.T) {
+	cases := []struct {
+		locale    string
+		precision int
+		field     zap.Field
+		want      string
+	}{
+		{"en", 0, zap.Int("n", 1234567), `"n":"1,234,567"`},
+		{"en", 0, zap.Int("n", -1234), `"n":"-1,234"`},
+		{"en", 0, zap.Int("n", 123), `"n":"123"`},
+		{"de", 2, zap.Float64("n", 1234567.891), `"n":"1.234.567,89"`},
+		{"fr", 0, zap.Uint64("n", 1000000), `"n":"1 000 000"`},
+		{"de-ch", 1, zap.Float32("n", 9876.54), `"n":"9'876.5"`},
+		{"", 2, zap.Float64("n", 3.14159), `"n":3.14`},
+		{"", 2, zap.Int("n", 1234567), `"n":1234567`},
+		{"en", 0, zap.String("n", "1234"), `"n":"1234"`},
+	}
+	for _, c := range cases {
+		f, err := newNumberFormat(c.locale, c.precision)
+		if err != nil {
+			t.Fatal(err)
+		}
+		enc := zapcore.NewJSONEncoder(zapcore.EncoderConfig{})
+		buf, err := enc.EncodeEntry(zapcore.Entry{}, []zap.Field{f.field(c.field)})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := strings.TrimSpace(buf.String()); got != "{"+c.want+"}" {
+			t.Errorf("locale %q precision %d: expected {%s}, got %s", c.locale, c.precision, c.want, got)
+		}
+	}
+
+	if _, err := newNumberFormat("tlh", 0); err == nil {
+		t.Fatal("expected error for unknown locale")
+	}
+}
+
+func TestNumberCore(t *testing.T) {
+	dir := t.TempDir()
+	cfg := LogConfig{Name: "default", FileName: dir + "/app.log", Encoder: "json", NumberLocale: "en", DurationFormat: "human"}
+	entry, err := newLogger(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	entry.logger.With(zap.Int("rows", 12345)).Info("done", zap.Int64("bytes", 1048576), zap.Duration("took", 92*time.Second))
+	_ = entry.logger.Sync()
+	_ = entry.writer.Close()
+
+	raw, err := os.ReadFile(cfg.FileName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data := string(raw)
+	for _, want := range []string{`"rows":"12,345"`, `"bytes":"1,048,576"`, `"took":"1m32s"`} {
+		if !strings.Contains(data, want) {
+			t.Fatalf("expected %s in %s", want, data)
+		}
+	}
+
+	bad := Config{Zaplog: []LogConfig{{Name: "default", FileName: "a.log", DurationFormat: "fortnights", NumberLocale: "xx"}}}
+	err = validateConfig(&bad)
+	if err == nil || !strings.Contains(err.Error(), "duration_format") || !strings.Contains(err.Error(), "number_locale") {
+		t.Fatalf("expected duration_format and number_locale errors, got %v", err)
+	}
+}
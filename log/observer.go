@@ -0,0 +1,82 @@
+package log
+
+import (
+	"testing"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+// InstallObserver 以内存observer替换指定名称的logger（不存在时新增），级别为debug，
+// 用于在单元测试中断言输出的日志和字段，不写文件也不输出到stdout。
+// 替换在下次Init或Close时失效，需要自动恢复时使用NewTestLogger
+func InstallObserver(name string) *observer.ObservedLogs {
+	logs, _ := installObserver(name, nil)
+	return logs
+}
+
+// NewTestLogger 以observer替换default logger，日志同时通过t.Log输出，测试结束时恢复原来的logger。
+// 返回的logger即替换后的default logger
+func NewTestLogger(t testing.TB) (*zap.Logger, *observer.ObservedLogs) {
+	t.Helper()
+
+	core := zaptest.NewLogger(t, zaptest.Level(zapcore.DebugLevel)).Core()
+	logs, restore := installObserver("default", core)
+	t.Cleanup(restore)
+	return GetDefaultLogger(), logs
+}
+
+// installObserver 替换logger并返回恢复函数，extra不为nil时同时写入extra
+func installObserver(name string, extra zapcore.Core) (*observer.ObservedLogs, func()) {
+	level := zap.NewAtomicLevelAt(zapcore.DebugLevel)
+	core, logs := observer.New(level)
+	if extra != nil {
+		core = zapcore.NewTee(core, extra)
+	}
+	entry := &logEntry{
+		cfg:   LogConfig{Name: name, Level: "debug"},
+		level: level,
+		stats: newLevelCounter(),
+		usage: newUsageCounter(),
+		hooks: &hookList{},
+	}
+	entry.options = []zap.Option{zap.Hooks(entry.stats.hook, entry.hooks.run)}
+	entry.core = core
+	entry.logger = zap.New(core, entry.options...)
+
+	metux.Lock()
+	prev, hadPrev := loggers[name]
+	loggers[name] = entry
+	if name == "default" {
+		zap.ReplaceGlobals(entry.logger)
+		// 模块logger派生自default，需重新创建
+		modules = make(map[string]*module)
+	}
+	metux.Unlock()
+
+	restore := func() {
+		metux.Lock()
+		defer metux.Unlock()
+
+		// 期间已被Init或Close替换时不再恢复
+		if loggers[name] != entry {
+			return
+		}
+		if hadPrev {
+			loggers[name] = prev
+		} else {
+			delete(loggers, name)
+		}
+		if name == "default" {
+			modules = make(map[string]*module)
+			if hadPrev {
+				zap.ReplaceGlobals(prev.logger)
+			} else {
+				zap.ReplaceGlobals(fallback)
+			}
+		}
+	}
+	return logs, restore
+}
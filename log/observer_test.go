@@ -0,0 +1,45 @@
+package log
+
+import (
+	"testing"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+func TestInstallObserver(t *testing.T) {
+	initTestLoggers(t)
+
+	logs := InstallObserver("payment")
+	GetLogger("payment").Debug("charged", zap.String("order", "A1"), zap.Int("cents", 1999))
+	Module("payment").Info("ignored")
+
+	entries := logs.FilterMessage("charged").FilterField(zap.String("order", "A1")).All()
+	if len(entries) != 1 || entries[0].Level != zapcore.DebugLevel || entries[0].ContextMap()["cents"] != int64(1999) {
+		t.Fatalf("unexpected observed entries: %+v", logs.All())
+	}
+	if logs.Len() != 1 {
+		t.Fatalf("module loggers derive from default, got %d entries", logs.Len())
+	}
+}
+
+func TestNewTestLogger(t *testing.T) {
+	initTestLoggers(t)
+	original := GetDefaultLogger()
+	Module("dao").Info("before")
+
+	t.Run("observe", func(t *testing.T) {
+		logger, logs := NewTestLogger(t)
+		if GetDefaultLogger() != logger || zap.L() != logger {
+			t.Fatal("test logger should replace the default and global logger")
+		}
+		Module("dao").Warn("slow query", zap.Duration("took", 0))
+		if logs.FilterMessage("slow query").Len() != 1 {
+			t.Fatalf("module entries should be observed, got %+v", logs.All())
+		}
+	})
+
+	if GetDefaultLogger() != original || zap.L() != original {
+		t.Fatal("original default logger should be restored after the test")
+	}
+}
@@ -0,0 +1,91 @@
+package log
+
+import (
+	"net/http"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// Option 中间件、拦截器与Timer的可选配置
+type Option func(*options)
+
+type options struct {
+	logPayload    bool
+	levels        map[string]zapcore.Level
+	requestIDKey  string
+	slowThreshold time.Duration
+	dumpHeader    string
+}
+
+func newOptions(opts []Option) *options {
+	o := &options{
+		levels:       make(map[string]zapcore.Level),
+		requestIDKey: "x-request-id",
+	}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+// levelFor 返回请求成功时使用的日志级别
+func (o *options) levelFor(method string) zapcore.Level {
+	if level, ok := o.levels[method]; ok {
+		return level
+	}
+	return zapcore.InfoLevel
+}
+
+// isSlow 请求耗时是否超过慢请求阈值
+func (o *options) isSlow(latency time.Duration) bool {
+	return o.slowThreshold > 0 && latency >= o.slowThreshold
+}
+
+// WithPayload 是否记录请求与响应内容
+func WithPayload(enabled bool) Option {
+	return func(o *options) {
+		o.logPayload = enabled
+	}
+}
+
+// WithMethodLevel 指定方法（gRPC全方法名或HTTP路径）成功时的日志级别，如把健康检查降为debug
+func WithMethodLevel(method, level string) Option {
+	return func(o *options) {
+		o.levels[method] = getLevel(level)
+	}
+}
+
+// WithRequestIDKey 指定读取请求ID的metadata或header名称，默认 x-request-id
+func WithRequestIDKey(key string) Option {
+	return func(o *options) {
+		o.requestIDKey = key
+	}
+}
+
+// WithDumpHeader 带有该header(值不为空、0或false)的请求以ForceDump标记，
+// 处理过程中DumpContext始终记录，用于在生产环境按需抓取指定请求的请求体、响应体
+func WithDumpHeader(header string) Option {
+	return func(o *options) {
+		o.dumpHeader = header
+	}
+}
+
+// dumpRequested 请求是否通过header要求记录Dump
+func (o *options) dumpRequested(header http.Header) bool {
+	if o.dumpHeader == "" {
+		return false
+	}
+	switch header.Get(o.dumpHeader) {
+	case "", "0", "false":
+		return false
+	}
+	return true
+}
+
+// WithSlowThreshold 耗时超过阈值的请求以warn级别记录并标记 slow=true，0表示不检查
+func WithSlowThreshold(d time.Duration) Option {
+	return func(o *options) {
+		o.slowThreshold = d
+	}
+}
@@ -0,0 +1,75 @@
+package log
+
+import (
+	"errors"
+	"sync"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// orderedOutput ordered_tee开启时logger共享的顺序状态
+type orderedOutput struct {
+	mu  sync.Mutex
+	seq uint64
+	key string
+}
+
+func newOrderedOutput(key string) *orderedOutput {
+	if key == "" {
+		key = "seq"
+	}
+	return &orderedOutput{key: key}
+}
+
+// orderedWriteSyncer 先写本地文件，成功后才写入标准输出和sink，
+// 保证本地文件始终是各输出端的超集，可作为对账依据
+type orderedWriteSyncer struct {
+	file zapcore.WriteSyncer
+	rest zapcore.WriteSyncer
+}
+
+func (w *orderedWriteSyncer) Write(p []byte) (int, error) {
+	n, err := w.file.Write(p)
+	if err != nil {
+		return n, err
+	}
+	if _, err := w.rest.Write(p); err != nil {
+		return n, err
+	}
+	return n, nil
+}
+
+func (w *orderedWriteSyncer) Sync() error {
+	return errors.Join(w.file.Sync(), w.rest.Sync())
+}
+
+// seqCore 为每条日志附加递增的序号，并串行化编码与写入，使序号、文件和sink中的顺序一致
+type seqCore struct {
+	zapcore.Core
+	out *orderedOutput
+}
+
+func newSeqCore(core zapcore.Core, out *orderedOutput) zapcore.Core {
+	return &seqCore{Core: core, out: out}
+}
+
+func (c *seqCore) With(fields []zapcore.Field) zapcore.Core {
+	return &seqCore{Core: c.Core.With(fields), out: c.out}
+}
+
+func (c *seqCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+func (c *seqCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	c.out.mu.Lock()
+	defer c.out.mu.Unlock()
+
+	c.out.seq++
+	fields = append(fields[:len(fields):len(fields)], zap.Uint64(c.out.key, c.out.seq))
+	return c.Core.Write(ent, fields)
+}
@@ -0,0 +1,97 @@
+package log
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+func TestOrderedTee(t *testing.T) {
+	var sink *memorySink
+	RegisterSink("test-ordered", func(logger string, options map[string]any) (zapcore.WriteSyncer, error) {
+		sink = &memorySink{}
+		return sink, nil
+	})
+
+	dir := t.TempDir()
+	logPath := filepath.Join(dir, "app.log")
+	config := fmt.Sprintf(`zaplog:
+  - name: default
+    file_name: %s
+    encoder: json
+    ordered_tee: true
+    sinks:
+      - type: test-ordered
+`, logPath)
+	configPath := filepath.Join(dir, "log_config.yaml")
+	if err := os.WriteFile(configPath, []byte(config), 0644); err != nil {
+		t.Fatal(err)
+	}
+	Close()
+	if err := Init(WithConfigFile(configPath)); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(Close)
+
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 50; i++ {
+				GetDefaultLogger().Info("tick", zap.Int("i", i))
+			}
+		}()
+	}
+	wg.Wait()
+
+	// 文件写入失败的日志不应出现在sink中
+	restore, err := SimulateDiskFaults("default", DiskFaults{ErrorRate: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	GetDefaultLogger().Info("lost")
+	restore()
+	GetDefaultLogger().Info("after")
+
+	data, err := os.ReadFile(logPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	file := strings.Split(strings.TrimSpace(string(data)), "\n")
+	sink.mu.Lock()
+	remote := strings.Split(strings.TrimSpace(sink.buf.String()), "\n")
+	sink.mu.Unlock()
+
+	if len(file) != 401 || strings.Join(file, "\n") != strings.Join(remote, "\n") {
+		t.Fatalf("sink should receive exactly the file entries in the same order, got %d file and %d sink lines", len(file), len(remote))
+	}
+	var prev uint64
+	for _, line := range file {
+		var rec struct {
+			Msg string `json:"msg"`
+			Seq uint64 `json:"seq"`
+		}
+		if err := json.Unmarshal([]byte(line), &rec); err != nil {
+			t.Fatal(err)
+		}
+		if rec.Seq <= prev {
+			t.Fatalf("sequence numbers should increase in file order: %d after %d", rec.Seq, prev)
+		}
+		prev = rec.Seq
+		if rec.Msg == "lost" {
+			t.Fatal("entry that failed to reach the file should not be written")
+		}
+	}
+	// 写入失败的日志也占用序号，对账时可以发现缺口
+	if prev != 402 {
+		t.Fatalf("expected last seq 402, got %d", prev)
+	}
+}
@@ -0,0 +1,174 @@
+package log
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime/debug"
+	"strings"
+	"sync"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+const (
+	defaultPanicFile = "panic.log"
+	maxPanicFileSize = 10 << 20
+)
+
+// panicWriter 独立的崩溃日志文件，不经过缓冲，每次写入后立即fsync
+type panicWriter struct {
+	mu   sync.Mutex
+	path string
+	file *os.File
+}
+
+// fallbackPanicFile 配置生效前以及default logger不写文件时的崩溃日志文件，位于临时目录
+var fallbackPanicFile = filepath.Join(os.TempDir(),
+	fmt.Sprintf("%s-%d-panic.log", strings.TrimSuffix(filepath.Base(os.Args[0]), ".exe"), os.Getpid()))
+
+var panicSink = &panicWriter{}
+
+func init() {
+	// 包初始化时即打开，Init之前或配置无效时发生的panic同样有记录
+	if err := panicSink.open(""); err != nil {
+		fmt.Fprintf(os.Stderr, "goeasy/log: failed to open panic file: %v\n", err)
+	}
+}
+
+// open 打开崩溃日志文件，同时作为运行时未捕获panic的输出，path为空时使用fallbackPanicFile。
+// 从fallbackPanicFile切换到配置的文件时，已记录的内容移入新文件
+func (w *panicWriter) open(path string) error {
+	if path == "" {
+		path = fallbackPanicFile
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.file != nil && w.path == path {
+		return nil
+	}
+	prev, prevPath := w.file, w.path
+	w.path, w.file = path, nil
+	if err := w.openLocked(); err != nil {
+		w.path, w.file = prevPath, prev
+		return err
+	}
+	if prev == nil {
+		return nil
+	}
+	_ = prev.Close()
+	if prevPath == fallbackPanicFile {
+		if data, err := os.ReadFile(prevPath); err == nil && len(data) > 0 {
+			if _, err := w.file.Write(data); err != nil {
+				return err
+			}
+		}
+		_ = os.Remove(prevPath)
+	}
+	return nil
+}
+
+func (w *panicWriter) openLocked() error {
+	if err := os.MkdirAll(filepath.Dir(w.path), 0755); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(w.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return err
+	}
+	if w.file != nil {
+		_ = w.file.Close()
+	}
+	w.file = f
+	return debug.SetCrashOutput(f, debug.CrashOptions{})
+}
+
+func (w *panicWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.file == nil {
+		if err := w.openLocked(); err != nil {
+			return 0, err
+		}
+	}
+	// 超出大小上限时保留一个备份
+	if fi, err := w.file.Stat(); err == nil && fi.Size() > maxPanicFileSize {
+		_ = os.Rename(w.path, w.path+".1")
+		if err := w.openLocked(); err != nil {
+			return 0, err
+		}
+	}
+	n, err := w.file.Write(p)
+	if err != nil {
+		return n, err
+	}
+	return n, w.file.Sync()
+}
+
+func (w *panicWriter) Sync() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.file == nil {
+		return nil
+	}
+	return w.file.Sync()
+}
+
+// panicCore 记录DPanic及以上级别的日志到崩溃日志文件，不受logger级别、预算等配置影响
+type panicCore struct {
+	zapcore.Core
+}
+
+func newPanicCore() zapcore.Core {
+	encoderConfig := zap.NewProductionEncoderConfig()
+	encoderConfig.EncodeTime = zapcore.ISO8601TimeEncoder
+	return &panicCore{zapcore.NewCore(zapcore.NewJSONEncoder(encoderConfig), panicSink, zapcore.DPanicLevel)}
+}
+
+func (c *panicCore) With(fields []zapcore.Field) zapcore.Core {
+	return &panicCore{c.Core.With(fields)}
+}
+
+func (c *panicCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+// Write 外层core通过Tee写入时不经过本core的Check，需自行检查级别，否则每条日志都会附加堆栈写入崩溃日志
+func (c *panicCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	if !c.Enabled(ent.Level) {
+		return nil
+	}
+	if ent.Stack == "" {
+		ent.Stack = string(debug.Stack())
+	}
+	return c.Core.Write(ent, fields)
+}
+
+// panicFilePath 返回崩溃日志文件路径，未配置panic_file时放在default logger的日志目录下，
+// default logger不写文件时返回空，继续使用fallbackPanicFile
+func panicFilePath(cfg Config) string {
+	if cfg.PanicFile != "" {
+		return cfg.PanicFile
+	}
+	for _, lc := range cfg.Zaplog {
+		if lc.Name == "default" && lc.FileName != "" {
+			return filepath.Join(filepath.Dir(lc.FileName), defaultPanicFile)
+		}
+	}
+	return ""
+}
+
+// initPanicFile 设置崩溃日志文件路径，为空时使用fallbackPanicFile
+func initPanicFile(path string) error {
+	if err := panicSink.open(path); err != nil {
+		return fmt.Errorf("failed to open panic file: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,74 @@
+package log
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestPanicFile(t *testing.T) {
+	dir := initTestLoggers(t, "access")
+	path := filepath.Join(dir, "panic.log")
+	if err := initPanicFile(path); err != nil {
+		t.Fatal(err)
+	}
+	defer initPanicFile("")
+
+	// 即使logger级别为error也会写入崩溃日志
+	if err := SetLevel("access", "fatal"); err != nil {
+		t.Fatal(err)
+	}
+	GetLogger("access").DPanic("something impossible happened")
+	func() {
+		defer func() { recover() }()
+		GetLogger("access").Panic("boom")
+	}()
+	GetLogger("access").Error("not a panic")
+	// 正常输出的日志不写入崩溃日志
+	if err := SetLevel("access", "info"); err != nil {
+		t.Fatal(err)
+	}
+	GetLogger("access").Info("not a panic")
+	New("access").Error("not a panic")
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) != 2 || !strings.Contains(lines[1], `"msg":"boom"`) || !strings.Contains(lines[1], `"stacktrace"`) {
+		t.Fatalf("unexpected panic file content:\n%s", data)
+	}
+}
+
+func TestPanicFileDefault(t *testing.T) {
+	dir := t.TempDir()
+	defer initPanicFile("")
+	if err := initPanicFile(""); err != nil {
+		t.Fatal(err)
+	}
+
+	// 配置无效时继续写入临时目录下的崩溃日志文件
+	err := Init(WithConfig(Config{Zaplog: []LogConfig{{Name: "default", Level: "verbose", FileName: filepath.Join(dir, "app.log")}}}))
+	if err == nil {
+		t.Fatal("expected invalid level error")
+	}
+	if _, err := os.Stat(filepath.Join(dir, "panic.log")); !os.IsNotExist(err) {
+		t.Fatalf("panic file should not be created for invalid config: %v", err)
+	}
+	Fallback().DPanic("before init")
+	if data, err := os.ReadFile(fallbackPanicFile); err != nil || !strings.Contains(string(data), "before init") {
+		t.Fatalf("fallback panic file should record panics before Init: %q, %v", data, err)
+	}
+
+	// 未配置panic_file时放在default logger的日志目录下，此前的记录一并移入
+	dir = initTestLoggers(t)
+	data, err := os.ReadFile(filepath.Join(dir, "panic.log"))
+	if err != nil || !strings.Contains(string(data), "before init") {
+		t.Fatalf("panic file should be moved next to the default log file: %q, %v", data, err)
+	}
+	if _, err := os.Stat(fallbackPanicFile); !os.IsNotExist(err) {
+		t.Fatalf("fallback panic file should be removed: %v", err)
+	}
+}
@@ -0,0 +1,120 @@
+package log
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/user"
+	"path/filepath"
+	"runtime"
+	"strconv"
+
+	"go.uber.org/zap"
+)
+
+// defaultDirMode 未设置dir_mode时创建日志目录的权限
+const defaultDirMode = 0o755
+
+// validatePermissions 检查file_mode、dir_mode为有效的权限位，owner、group存在
+func validatePermissions(lc LogConfig) []error {
+	var errs []error
+	if lc.FileMode < 0 || lc.FileMode > 0o777 {
+		errs = append(errs, fmt.Errorf("logger %s: invalid file_mode %#o, use an octal string such as \"0640\"", lc.Name, lc.FileMode))
+	}
+	if lc.DirMode < 0 || lc.DirMode > 0o777 {
+		errs = append(errs, fmt.Errorf("logger %s: invalid dir_mode %#o, use an octal string such as \"0750\"", lc.Name, lc.DirMode))
+	}
+	if lc.Owner != "" || lc.Group != "" {
+		if runtime.GOOS == "windows" {
+			errs = append(errs, fmt.Errorf("logger %s: owner and group are not supported on windows", lc.Name))
+		} else if _, _, err := lookupOwner(lc.Owner, lc.Group); err != nil {
+			errs = append(errs, fmt.Errorf("logger %s: %w", lc.Name, err))
+		}
+	}
+	return errs
+}
+
+// lookupOwner 将用户名、组名或数字ID解析为uid、gid，未设置的返回-1表示不修改
+func lookupOwner(owner, group string) (uid, gid int, err error) {
+	uid, gid = -1, -1
+	if owner != "" {
+		if uid, err = strconv.Atoi(owner); err != nil {
+			u, err := user.Lookup(owner)
+			if err != nil {
+				return -1, -1, fmt.Errorf("unknown owner %q: %w", owner, err)
+			}
+			if uid, err = strconv.Atoi(u.Uid); err != nil {
+				return -1, -1, fmt.Errorf("owner %q has non-numeric uid %q", owner, u.Uid)
+			}
+		}
+	}
+	if group != "" {
+		if gid, err = strconv.Atoi(group); err != nil {
+			g, err := user.LookupGroup(group)
+			if err != nil {
+				return -1, -1, fmt.Errorf("unknown group %q: %w", group, err)
+			}
+			if gid, err = strconv.Atoi(g.Gid); err != nil {
+				return -1, -1, fmt.Errorf("group %q has non-numeric gid %q", group, g.Gid)
+			}
+		}
+	}
+	return uid, gid, nil
+}
+
+// prepareLogDir 以dir_mode创建日志目录，设置dir_mode时同时修正已存在目录的权限，不受umask影响
+func prepareLogDir(cfg LogConfig) error {
+	dir := filepath.Dir(cfg.FileName)
+	mode := os.FileMode(defaultDirMode)
+	if cfg.DirMode != 0 {
+		mode = os.FileMode(cfg.DirMode)
+	}
+	if err := os.MkdirAll(dir, mode); err != nil {
+		return err
+	}
+	if cfg.DirMode != 0 {
+		return os.Chmod(dir, mode)
+	}
+	return nil
+}
+
+// applyFilePermissions 预先创建日志文件并设置权限与属主，目录的属主不修改。
+// lumberjack切割时新文件沿用当前文件的权限与属主，因此之后切割出的文件和压缩备份保持一致
+func applyFilePermissions(cfg LogConfig) error {
+	if cfg.FileMode == 0 && cfg.Owner == "" && cfg.Group == "" {
+		return nil
+	}
+	mode := os.FileMode(0o600)
+	if cfg.FileMode != 0 {
+		mode = os.FileMode(cfg.FileMode)
+	}
+	f, err := os.OpenFile(cfg.FileName, os.O_CREATE|os.O_WRONLY|os.O_APPEND, mode)
+	if err != nil {
+		return err
+	}
+	f.Close()
+
+	var errs []error
+	if cfg.FileMode != 0 {
+		errs = append(errs, os.Chmod(cfg.FileName, mode))
+	}
+	if cfg.Owner != "" || cfg.Group != "" {
+		uid, gid, err := lookupOwner(cfg.Owner, cfg.Group)
+		if err == nil {
+			err = os.Chown(cfg.FileName, uid, gid)
+		}
+		errs = append(errs, err)
+	}
+	return errors.Join(errs...)
+}
+
+// prepareLogFile 创建日志目录并设置文件权限，权限设置失败时输出诊断信息，日志仍可写入
+func prepareLogFile(cfg LogConfig) {
+	if err := prepareLogDir(cfg); err != nil {
+		panic(err)
+	}
+	if err := applyFilePermissions(cfg); err != nil {
+		diagnostics.Warn("failed to apply log file permissions", zap.String("logger", cfg.Name),
+			zap.String("file", cfg.FileName), zap.Error(err))
+	}
+}
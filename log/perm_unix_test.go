@@ -0,0 +1,73 @@
+//go:build unix
+
+package log
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+	"testing"
+)
+
+func TestFilePermissions(t *testing.T) {
+	dir := t.TempDir()
+	logDir := filepath.Join(dir, "logs")
+	configPath := filepath.Join(dir, "log_config.yaml")
+	config := "defaults:\n  file_mode: \"0640\"\n  group: \"" + strconv.Itoa(os.Getgid()) + "\"\n" +
+		"zaplog:\n  - name: default\n    file_name: " + filepath.Join(logDir, "app.log") + "\n    dir_mode: \"0750\"\n"
+	if err := os.WriteFile(configPath, []byte(config), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := LoadConfig(configPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	Close()
+	if err := Init(WithConfig(cfg)); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(Close)
+
+	check := func(path string, want os.FileMode) {
+		t.Helper()
+		info, err := os.Stat(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := info.Mode().Perm(); got != want {
+			t.Fatalf("%s mode = %#o, want %#o", path, got, want)
+		}
+		if gid := info.Sys().(*syscall.Stat_t).Gid; int(gid) != os.Getgid() {
+			t.Fatalf("%s gid = %d, want %d", path, gid, os.Getgid())
+		}
+	}
+	check(logDir, 0o750)
+	check(filepath.Join(logDir, "app.log"), 0o640)
+
+	// 切割后的新文件沿用权限
+	GetDefaultLogger().Info("before rotate")
+	if err := Rotate("default"); err != nil {
+		t.Fatal(err)
+	}
+	GetDefaultLogger().Info("after rotate")
+	check(filepath.Join(logDir, "app.log"), 0o640)
+}
+
+func TestValidatePermissions(t *testing.T) {
+	for _, lc := range []LogConfig{
+		{Name: "decimal", FileMode: 640},
+		{Name: "dir", DirMode: 0o1777},
+		{Name: "owner", Owner: "no-such-user-goeasy"},
+		{Name: "group", Group: "no-such-group-goeasy"},
+	} {
+		errs := validatePermissions(lc)
+		if len(errs) != 1 || !strings.Contains(errs[0].Error(), "logger "+lc.Name) {
+			t.Errorf("expected one error for %+v, got %v", lc, errs)
+		}
+	}
+	if errs := validatePermissions(LogConfig{Name: "ok", FileMode: 0o640, DirMode: 0o750, Owner: strconv.Itoa(os.Getuid())}); len(errs) != 0 {
+		t.Fatalf("unexpected errors %v", errs)
+	}
+}
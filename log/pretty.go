@@ -0,0 +1,220 @@
+package log
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"go.uber.org/zap/buffer"
+	"go.uber.org/zap/zapcore"
+)
+
+var prettyPool = buffer.NewPool()
+
+// ANSI颜色
+const (
+	colorReset  = "\x1b[0m"
+	colorDim    = "\x1b[2m"
+	colorRed    = "\x1b[31m"
+	colorYellow = "\x1b[33m"
+	colorBlue   = "\x1b[34m"
+	colorPurple = "\x1b[35m"
+	colorCyan   = "\x1b[36m"
+)
+
+// prettyIndent 字段与堆栈行的缩进
+const prettyIndent = "    "
+
+type prettyPair struct {
+	key, value string
+}
+
+// prettyEncoder 开发模式使用的多行输出：首行为时间、彩色级别、logger名称与消息，调用者在行尾，
+// 之后每个字段一行且键对齐，嵌套对象展开为parent.child，时长以1m32s、12.5ms等可读形式输出
+type prettyEncoder struct {
+	cfg    *zapcore.EncoderConfig
+	pairs  []prettyPair
+	prefix string
+}
+
+func newPrettyEncoder(cfg zapcore.EncoderConfig) zapcore.Encoder {
+	cfg.EncodeDuration = humanDurationEncoder
+	return &prettyEncoder{cfg: &cfg}
+}
+
+func (enc *prettyEncoder) Clone() zapcore.Encoder {
+	return &prettyEncoder{cfg: enc.cfg, pairs: append([]prettyPair(nil), enc.pairs...), prefix: enc.prefix}
+}
+
+func (enc *prettyEncoder) EncodeEntry(ent zapcore.Entry, fields []zapcore.Field) (*buffer.Buffer, error) {
+	final := enc.Clone().(*prettyEncoder)
+	for _, f := range fields {
+		f.AddTo(final)
+	}
+
+	buf := prettyPool.Get()
+	var head []string
+	if final.cfg.TimeKey != "" && final.cfg.EncodeTime != nil {
+		arr := &logfmtArrayEncoder{cfg: final.cfg}
+		arr.AppendTime(ent.Time)
+		head = append(head, colorDim+strings.Join(arr.elems, " ")+colorReset)
+	}
+	if final.cfg.LevelKey != "" {
+		head = append(head, levelColor(ent.Level)+padRight(ent.Level.CapitalString(), 5)+colorReset)
+	}
+	if ent.LoggerName != "" && final.cfg.NameKey != "" {
+		head = append(head, colorPurple+ent.LoggerName+colorReset)
+	}
+	if final.cfg.MessageKey != "" {
+		head = append(head, ent.Message)
+	}
+	buf.AppendString(strings.Join(head, " "))
+	if ent.Caller.Defined && final.cfg.CallerKey != "" && final.cfg.EncodeCaller != nil {
+		arr := &logfmtArrayEncoder{cfg: final.cfg}
+		final.cfg.EncodeCaller(ent.Caller, arr)
+		buf.AppendString("  " + colorDim + strings.Join(arr.elems, " ") + colorReset)
+	}
+	buf.AppendByte('\n')
+
+	width := 0
+	for _, p := range final.pairs {
+		width = max(width, utf8.RuneCountInString(p.key))
+	}
+	for _, p := range final.pairs {
+		buf.AppendString(prettyIndent + colorCyan + padRight(p.key, width) + colorReset + " = ")
+		buf.AppendString(strings.ReplaceAll(p.value, "\n", "\n"+prettyIndent+strings.Repeat(" ", width+3)))
+		buf.AppendByte('\n')
+	}
+	if ent.Stack != "" && final.cfg.StacktraceKey != "" {
+		buf.AppendString(colorDim + prettyIndent + strings.ReplaceAll(ent.Stack, "\n", "\n"+prettyIndent) + colorReset + "\n")
+	}
+	if final.cfg.LineEnding != "" && final.cfg.LineEnding != zapcore.DefaultLineEnding {
+		buf.TrimNewline()
+		buf.AppendString(final.cfg.LineEnding)
+	}
+	return buf, nil
+}
+
+func levelColor(level zapcore.Level) string {
+	switch {
+	case level >= zapcore.ErrorLevel:
+		return colorRed
+	case level == zapcore.WarnLevel:
+		return colorYellow
+	case level == zapcore.InfoLevel:
+		return colorBlue
+	}
+	return colorPurple
+}
+
+func padRight(s string, width int) string {
+	if n := utf8.RuneCountInString(s); n < width {
+		return s + strings.Repeat(" ", width-n)
+	}
+	return s
+}
+
+func (enc *prettyEncoder) add(key, value string) {
+	enc.pairs = append(enc.pairs, prettyPair{key: enc.prefix + key, value: value})
+}
+
+// addEncoded 以EncodeTime/EncodeDuration等编码器的输出作为值
+func (enc *prettyEncoder) addEncoded(key string, encode func(*logfmtArrayEncoder)) {
+	arr := &logfmtArrayEncoder{cfg: enc.cfg}
+	encode(arr)
+	enc.add(key, strings.Join(arr.elems, " "))
+}
+
+func (enc *prettyEncoder) AddArray(key string, marshaler zapcore.ArrayMarshaler) error {
+	arr := &logfmtArrayEncoder{cfg: enc.cfg}
+	err := marshaler.MarshalLogArray(arr)
+	enc.add(key, "["+strings.Join(arr.elems, ", ")+"]")
+	return err
+}
+
+func (enc *prettyEncoder) AddObject(key string, marshaler zapcore.ObjectMarshaler) error {
+	prefix := enc.prefix
+	enc.prefix += key + "."
+	err := marshaler.MarshalLogObject(enc)
+	enc.prefix = prefix
+	return err
+}
+
+func (enc *prettyEncoder) AddBinary(key string, value []byte) {
+	enc.add(key, base64.StdEncoding.EncodeToString(value))
+}
+
+func (enc *prettyEncoder) AddByteString(key string, value []byte) {
+	enc.AddString(key, string(value))
+}
+
+func (enc *prettyEncoder) AddBool(key string, value bool) {
+	enc.add(key, strconv.FormatBool(value))
+}
+
+func (enc *prettyEncoder) AddComplex128(key string, value complex128) {
+	enc.add(key, strconv.FormatComplex(value, 'g', -1, 128))
+}
+
+func (enc *prettyEncoder) AddComplex64(key string, value complex64) {
+	enc.add(key, strconv.FormatComplex(complex128(value), 'g', -1, 64))
+}
+
+func (enc *prettyEncoder) AddDuration(key string, value time.Duration) {
+	enc.addEncoded(key, func(arr *logfmtArrayEncoder) { arr.AppendDuration(value) })
+}
+
+func (enc *prettyEncoder) AddFloat64(key string, value float64) {
+	enc.add(key, formatFloat(value, 64))
+}
+
+func (enc *prettyEncoder) AddFloat32(key string, value float32) {
+	enc.add(key, formatFloat(float64(value), 32))
+}
+
+func (enc *prettyEncoder) AddInt(key string, value int)     { enc.AddInt64(key, int64(value)) }
+func (enc *prettyEncoder) AddInt32(key string, value int32) { enc.AddInt64(key, int64(value)) }
+func (enc *prettyEncoder) AddInt16(key string, value int16) { enc.AddInt64(key, int64(value)) }
+func (enc *prettyEncoder) AddInt8(key string, value int8)   { enc.AddInt64(key, int64(value)) }
+
+func (enc *prettyEncoder) AddInt64(key string, value int64) {
+	enc.add(key, strconv.FormatInt(value, 10))
+}
+
+// AddString 字符串原样输出，为空时输出""，多行内容在EncodeEntry中对齐缩进
+func (enc *prettyEncoder) AddString(key, value string) {
+	if value == "" {
+		value = `""`
+	}
+	enc.add(key, value)
+}
+
+func (enc *prettyEncoder) AddTime(key string, value time.Time) {
+	enc.addEncoded(key, func(arr *logfmtArrayEncoder) { arr.AppendTime(value) })
+}
+
+func (enc *prettyEncoder) AddUint(key string, value uint)       { enc.AddUint64(key, uint64(value)) }
+func (enc *prettyEncoder) AddUint32(key string, value uint32)   { enc.AddUint64(key, uint64(value)) }
+func (enc *prettyEncoder) AddUint16(key string, value uint16)   { enc.AddUint64(key, uint64(value)) }
+func (enc *prettyEncoder) AddUint8(key string, value uint8)     { enc.AddUint64(key, uint64(value)) }
+func (enc *prettyEncoder) AddUintptr(key string, value uintptr) { enc.AddUint64(key, uint64(value)) }
+
+func (enc *prettyEncoder) AddUint64(key string, value uint64) {
+	enc.add(key, strconv.FormatUint(value, 10))
+}
+
+func (enc *prettyEncoder) AddReflected(key string, value any) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	enc.add(key, string(data))
+	return nil
+}
+
+func (enc *prettyEncoder) OpenNamespace(key string) {
+	enc.prefix += key + "."
+}
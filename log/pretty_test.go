@@ -0,0 +1,70 @@
+package log
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+func TestPrettyEncoder(t *testing.T) {
+	enc, err := getEncoder(LogConfig{Encoder: "pretty", TimeFormat: "15:04:05", Timezone: "UTC"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := enc.(*prettyEncoder); !ok {
+		t.Fatalf("expected the pretty encoder, got %T", enc)
+	}
+	enc.AddString("svc", "api")
+
+	ent := zapcore.Entry{
+		Level:   zapcore.WarnLevel,
+		Time:    time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+		Message: "slow query",
+		Caller:  zapcore.NewEntryCaller(0, "/src/app/dao/user.go", 42, true),
+	}
+	buf, err := enc.EncodeEntry(ent, []zapcore.Field{
+		zap.Duration("took", 92*time.Second+300*time.Millisecond),
+		zap.Dict("user", zap.String("id", "u1")),
+		zap.Strings("tags", []string{"a", "b"}),
+		zap.Error(errors.New("timeout")),
+		zap.String("sql", "select *\nfrom t"),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := colorDim + "03:04:05" + colorReset + " " + colorYellow + "WARN " + colorReset + " slow query  " +
+		colorDim + "dao/user.go:42" + colorReset + "\n" +
+		"    " + colorCyan + "svc    " + colorReset + " = api\n" +
+		"    " + colorCyan + "took   " + colorReset + " = 1m32s\n" +
+		"    " + colorCyan + "user.id" + colorReset + " = u1\n" +
+		"    " + colorCyan + "tags   " + colorReset + " = [a, b]\n" +
+		"    " + colorCyan + "error  " + colorReset + " = timeout\n" +
+		"    " + colorCyan + "sql    " + colorReset + " = select *\n" +
+		"              from t\n"
+	if got := buf.String(); got != want {
+		t.Fatalf("unexpected output\n got: %q\nwant: %q", got, want)
+	}
+
+	// With添加的字段不影响原编码器
+	clone := enc.Clone()
+	clone.AddInt("attempt", 2)
+	buf, _ = enc.EncodeEntry(ent, nil)
+	if strings.Contains(buf.String(), "attempt") {
+		t.Fatal("clone should not share fields with the original encoder")
+	}
+
+	// development不改变默认编码格式，避免多行彩色输出写入文件和sink
+	if enc, _ := getEncoder(LogConfig{Development: true}); enc == nil {
+		t.Fatal("expected console encoder")
+	} else if _, ok := enc.(*prettyEncoder); ok {
+		t.Fatal("development should keep the console encoder")
+	}
+	if got := encoderName(LogConfig{Development: true}); got != "console" {
+		t.Fatalf("encoderName = %q, want console", got)
+	}
+}
@@ -0,0 +1,300 @@
+package log
+
+import (
+	"bufio"
+	"bytes"
+	"cmp"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// defaultQueryLimit 未设置Limit时最多返回的条数
+const defaultQueryLimit = 1000
+
+// QueryFilter 本地日志的查询条件，设置的条件需同时满足
+type QueryFilter struct {
+	Since    time.Time         // 不早于该时间
+	Until    time.Time         // 早于该时间
+	Level    string            // 最低级别
+	Contains string            // 日志行包含的文本
+	Fields   map[string]string // 字段值等于，数值等非字符串字段按fmt.Sprint比较
+	Limit    int               // 最多返回的条数，超出时保留最新的，默认1000
+}
+
+// Entry Query返回的一条日志
+type Entry struct {
+	Time    time.Time      `json:"time"`
+	Level   string         `json:"level"`
+	Logger  string         `json:"logger,omitempty"` // Named设置的子logger名称
+	Message string         `json:"msg"`
+	Caller  string         `json:"caller,omitempty"`
+	Fields  map[string]any `json:"fields,omitempty"`
+	File    string         `json:"file"` // 所在的日志文件
+}
+
+// Query 在logger当前的日志文件及切割出的备份(包括gzip压缩的备份)中查找日志，按时间排序返回。
+// 只支持json编码的logger，加密的备份不检索。用于没有集中日志系统时的排查，会读取全部相关文件，不宜频繁调用
+func Query(name string, q QueryFilter) ([]Entry, error) {
+	metux.RLock()
+	entry, ok := loggers[name]
+	var cfg LogConfig
+	if ok {
+		cfg = entry.cfg
+	}
+	metux.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("logger %s not found", name)
+	}
+	if encoderName(cfg) != "json" {
+		return nil, fmt.Errorf("logger %s: query requires encoder: json", name)
+	}
+	m, err := newQueryMatcher(cfg, q)
+	if err != nil {
+		return nil, fmt.Errorf("logger %s: %w", name, err)
+	}
+
+	var out []Entry
+	for _, filename := range entry.fileNames() {
+		files := append(rotatedFiles(filename, ""), filename)
+		for _, path := range files {
+			if strings.HasSuffix(path, ".enc") || !m.mayContain(filename, path) {
+				continue
+			}
+			entries, err := m.scanFile(path)
+			if err != nil && !os.IsNotExist(err) {
+				return nil, fmt.Errorf("logger %s: %w", name, err)
+			}
+			out = append(out, entries...)
+		}
+	}
+	sort.SliceStable(out, func(i, j int) bool { return out[i].Time.Before(out[j].Time) })
+	if len(out) > m.limit {
+		out = out[len(out)-m.limit:]
+	}
+	return out, nil
+}
+
+// queryMatcher 解析并匹配日志行
+type queryMatcher struct {
+	q        QueryFilter
+	minLevel zapcore.Level
+	limit    int
+	keys     map[string]string // 时间、级别等字段在日志中的键名
+	epochMs  bool
+}
+
+func newQueryMatcher(cfg LogConfig, q QueryFilter) (*queryMatcher, error) {
+	m := &queryMatcher{
+		q:        q,
+		minLevel: zapcore.DebugLevel,
+		limit:    q.Limit,
+		epochMs:  strings.EqualFold(cfg.TimeFormat, "epoch_ms"),
+		keys: map[string]string{
+			"time":   cmp.Or(cfg.TimeKey, "ts"),
+			"level":  cmp.Or(cfg.LevelKey, "level"),
+			"msg":    cmp.Or(cfg.MessageKey, "msg"),
+			"caller": cmp.Or(cfg.CallerKey, "caller"),
+			"logger": "logger",
+		},
+	}
+	if q.Level != "" {
+		if !isValidLevel(q.Level) {
+			return nil, invalidLevel(q.Level)
+		}
+		m.minLevel = getLevel(q.Level)
+	}
+	if m.limit <= 0 {
+		m.limit = defaultQueryLimit
+	}
+	return m, nil
+}
+
+// mayContain 按备份文件名中的切割时间跳过早于Since的备份，文件中的日志都早于切割时间
+func (m *queryMatcher) mayContain(filename, path string) bool {
+	if m.q.Since.IsZero() || path == filename {
+		return true
+	}
+	rotated, ok := backupTime(filename, filepath.Base(path))
+	if !ok {
+		return true
+	}
+	// lumberjack默认以UTC命名，本地时间命名时最多相差一天
+	return !rotated.Add(24 * time.Hour).Before(m.q.Since)
+}
+
+// scanFile 返回文件中匹配的日志，超出limit时保留最新的
+func (m *queryMatcher) scanFile(path string) ([]Entry, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var r io.Reader = f
+	if strings.HasSuffix(path, ".gz") {
+		gz, err := gzip.NewReader(f)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		defer gz.Close()
+		r = gz
+	}
+
+	var out []Entry
+	br := bufio.NewReader(r)
+	for {
+		line, err := br.ReadBytes('\n')
+		if e, ok := m.match(bytes.TrimSpace(line)); ok {
+			e.File = path
+			out = append(out, e)
+			if len(out) > 2*m.limit {
+				out = append(out[:0], out[len(out)-m.limit:]...)
+			}
+		}
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return out, fmt.Errorf("%s: %w", path, err)
+		}
+	}
+	if len(out) > m.limit {
+		out = out[len(out)-m.limit:]
+	}
+	return out, nil
+}
+
+// match 解析一行json日志并检查查询条件，无法解析的行(如堆栈)不匹配
+func (m *queryMatcher) match(line []byte) (Entry, bool) {
+	if len(line) == 0 || line[0] != '{' {
+		return Entry{}, false
+	}
+	if m.q.Contains != "" && !bytes.Contains(line, []byte(m.q.Contains)) {
+		return Entry{}, false
+	}
+	var fields map[string]any
+	if json.Unmarshal(line, &fields) != nil {
+		return Entry{}, false
+	}
+
+	e := Entry{}
+	e.Level, _ = fields[m.keys["level"]].(string)
+	var level zapcore.Level
+	if level.UnmarshalText([]byte(strings.ToLower(e.Level))) == nil && level < m.minLevel {
+		return Entry{}, false
+	}
+	e.Time = m.parseTime(fields[m.keys["time"]])
+	if !m.q.Since.IsZero() && e.Time.Before(m.q.Since) {
+		return Entry{}, false
+	}
+	if !m.q.Until.IsZero() && !e.Time.Before(m.q.Until) {
+		return Entry{}, false
+	}
+	for key, want := range m.q.Fields {
+		v, ok := fields[key]
+		if !ok || fmt.Sprint(v) != want {
+			return Entry{}, false
+		}
+	}
+
+	e.Message, _ = fields[m.keys["msg"]].(string)
+	e.Caller, _ = fields[m.keys["caller"]].(string)
+	e.Logger, _ = fields[m.keys["logger"]].(string)
+	for _, key := range m.keys {
+		delete(fields, key)
+	}
+	if len(fields) > 0 {
+		e.Fields = fields
+	}
+	return e, true
+}
+
+// parseTime 解析iso8601、rfc3339等字符串时间和epoch数值时间，自定义layout的时间无法解析时为零值
+func (m *queryMatcher) parseTime(v any) time.Time {
+	switch t := v.(type) {
+	case string:
+		for _, layout := range []string{time.RFC3339Nano, "2006-01-02T15:04:05.000Z0700"} {
+			if parsed, err := time.Parse(layout, t); err == nil {
+				return parsed
+			}
+		}
+	case float64:
+		if m.epochMs {
+			return time.UnixMilli(int64(t))
+		}
+		sec := int64(t)
+		return time.Unix(sec, int64((t-float64(sec))*1e9))
+	}
+	return time.Time{}
+}
+
+// handleSearch 在日志文件中查询，参数：name logger名称(默认default)，since、until为RFC3339时间或距今的时长如1h，
+// level 最低级别，q 包含的文本，field.<key>=<value> 字段值，limit 最多返回的条数
+func handleSearch(w http.ResponseWriter, r *http.Request) {
+	params := r.URL.Query()
+	name := cmp.Or(params.Get("name"), "default")
+
+	q := QueryFilter{Level: params.Get("level"), Contains: params.Get("q")}
+	for key, target := range map[string]*time.Time{"since": &q.Since, "until": &q.Until} {
+		s := params.Get(key)
+		if s == "" {
+			continue
+		}
+		t, err := parseQueryTime(s)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, fmt.Errorf("invalid %s %q", key, s))
+			return
+		}
+		*target = t
+	}
+	if s := params.Get("limit"); s != "" {
+		n, err := strconv.Atoi(s)
+		if err != nil || n <= 0 {
+			writeError(w, http.StatusBadRequest, fmt.Errorf("invalid limit %q", s))
+			return
+		}
+		q.Limit = n
+	}
+	for key, values := range params {
+		if field, ok := strings.CutPrefix(key, "field."); ok && len(values) > 0 {
+			if q.Fields == nil {
+				q.Fields = make(map[string]string)
+			}
+			q.Fields[field] = values[0]
+		}
+	}
+
+	metux.RLock()
+	_, ok := loggers[name]
+	metux.RUnlock()
+	if !ok {
+		writeError(w, http.StatusNotFound, fmt.Errorf("logger %s not found", name))
+		return
+	}
+	entries, err := Query(name, q)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, entries)
+}
+
+// parseQueryTime 解析RFC3339时间或距今的时长
+func parseQueryTime(s string) (time.Time, error) {
+	if d, err := time.ParseDuration(s); err == nil {
+		return time.Now().Add(-d), nil
+	}
+	return time.Parse(time.RFC3339, s)
+}
@@ -0,0 +1,92 @@
+package log
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+func TestQuery(t *testing.T) {
+	dir := initTestLoggers(t, "access")
+	logger := GetLogger("access")
+
+	// 压缩备份中的旧日志，时间需在max_age默认的7天之内，否则切割后会被lumberjack删除
+	rotated := time.Now().UTC().Add(-time.Hour)
+	gzPath := filepath.Join(dir, "access-"+rotated.Format("2006-01-02T15-04-05.000")+".log.gz")
+	f, err := os.Create(gzPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	gz := gzip.NewWriter(f)
+	gz.Write([]byte(`{"level":"ERROR","ts":"` + rotated.Add(-time.Minute).Format(time.RFC3339) + `","msg":"db down","trace_id":"t1"}` + "\n"))
+	gz.Close()
+	f.Close()
+
+	logger.Info("request", zap.String("trace_id", "t1"), zap.Int("status", 200))
+	logger.Warn("slow request", zap.String("trace_id", "t2"))
+	if err := Rotate("access"); err != nil {
+		t.Fatal(err)
+	}
+	logger.Error("request failed", zap.String("trace_id", "t1"), zap.Int("status", 500))
+
+	entries, err := Query("access", QueryFilter{Fields: map[string]string{"trace_id": "t1"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 3 || entries[0].Message != "db down" || entries[0].File != gzPath ||
+		entries[1].Message != "request" || entries[2].Message != "request failed" {
+		t.Fatalf("unexpected entries %+v", entries)
+	}
+	if entries[2].Fields["status"] != float64(500) || entries[2].Level != "ERROR" {
+		t.Fatalf("unexpected fields %+v", entries[2])
+	}
+
+	entries, err = Query("access", QueryFilter{Level: "warn", Since: time.Now().Add(-time.Minute)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 2 || entries[0].Message != "slow request" {
+		t.Fatalf("unexpected entries %+v", entries)
+	}
+
+	entries, err = Query("access", QueryFilter{Fields: map[string]string{"status": "500"}, Contains: "failed", Limit: 1})
+	if err != nil || len(entries) != 1 {
+		t.Fatalf("unexpected entries %+v, err %v", entries, err)
+	}
+
+	if _, err := Query("access", QueryFilter{Level: "loud"}); err == nil {
+		t.Fatal("expected invalid level error")
+	}
+	if _, err := Query("missing", QueryFilter{}); err == nil {
+		t.Fatal("expected unknown logger error")
+	}
+}
+
+func TestSearchHandler(t *testing.T) {
+	initTestLoggers(t)
+	GetDefaultLogger().Info("login", zap.String("user", "bob"))
+	GetDefaultLogger().Info("login", zap.String("user", "alice"))
+
+	rec := httptest.NewRecorder()
+	AdminHandler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/debug/log/search?since=1h&field.user=bob", nil))
+	var entries []Entry
+	if err := json.Unmarshal(rec.Body.Bytes(), &entries); err != nil || rec.Code != http.StatusOK {
+		t.Fatalf("unexpected response %d %s", rec.Code, rec.Body)
+	}
+	if len(entries) != 1 || entries[0].Fields["user"] != "bob" {
+		t.Fatalf("unexpected entries %+v", entries)
+	}
+
+	rec = httptest.NewRecorder()
+	AdminHandler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/debug/log/search?since=yesterday", nil))
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400, got %d", rec.Code)
+	}
+}
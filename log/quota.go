@@ -0,0 +1,226 @@
+package log
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// DiskQuotaConfig 日志目录的磁盘配额，超出时删除最旧的备份文件，
+// 仍然超出则提升所有logger的最低级别并输出一条告警，恢复后自动还原
+type DiskQuotaConfig struct {
+	MaxDirMB  int           `yaml:"max_dir_mb" mapstructure:"max_dir_mb"`   // 各日志目录总大小上限（MB），0表示不检查
+	MinFreeMB int           `yaml:"min_free_mb" mapstructure:"min_free_mb"` // 日志所在磁盘的最小剩余空间（MB），0表示不检查
+	Interval  time.Duration `yaml:"interval" mapstructure:"interval"`       // 检查间隔，默认1m
+	Level     string        `yaml:"level" mapstructure:"level"`             // 超出配额时的最低级别，默认error
+}
+
+func (c DiskQuotaConfig) enabled() bool {
+	return c.MaxDirMB > 0 || c.MinFreeMB > 0
+}
+
+func validateDiskQuota(c DiskQuotaConfig) []error {
+	var errs []error
+	if c.MaxDirMB < 0 || c.MinFreeMB < 0 {
+		errs = append(errs, fmt.Errorf("disk_quota: max_dir_mb and min_free_mb must not be negative"))
+	}
+	if c.Interval < 0 {
+		errs = append(errs, fmt.Errorf("disk_quota: interval must not be negative"))
+	}
+	if c.Level != "" && !isValidLevel(c.Level) {
+		errs = append(errs, fmt.Errorf("disk_quota: %w", invalidLevel(c.Level)))
+	}
+	return errs
+}
+
+// quotaLevel 超出配额时所有logger的最低级别，未超出时低于所有级别
+var quotaLevel atomic.Int32
+
+const quotaOff = int32(zapcore.DebugLevel) - 1
+
+func init() {
+	quotaLevel.Store(quotaOff)
+}
+
+// quotaAllows 磁盘配额是否放行该级别
+func quotaAllows(level zapcore.Level) bool {
+	return int32(level) >= quotaLevel.Load()
+}
+
+var quotaStop chan struct{}
+
+// startDiskQuota 启动磁盘配额检查，调用方需持有metux写锁
+func startDiskQuota(cfg DiskQuotaConfig) {
+	stopDiskQuota()
+	if !cfg.enabled() {
+		return
+	}
+	if cfg.Interval == 0 {
+		cfg.Interval = time.Minute
+	}
+	if cfg.Level == "" {
+		cfg.Level = "error"
+	}
+	stop := make(chan struct{})
+	quotaStop = stop
+	w := &quotaWatcher{cfg: cfg, level: getLevel(cfg.Level), stop: stop}
+	go func() {
+		ticker := time.NewTicker(cfg.Interval)
+		defer ticker.Stop()
+		w.check()
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+				w.check()
+			}
+		}
+	}()
+}
+
+// stopDiskQuota 停止检查并恢复级别，调用方需持有metux写锁
+func stopDiskQuota() {
+	if quotaStop != nil {
+		close(quotaStop)
+		quotaStop = nil
+	}
+	quotaLevel.Store(quotaOff)
+}
+
+type quotaWatcher struct {
+	cfg      DiskQuotaConfig
+	level    zapcore.Level
+	stop     chan struct{}
+	exceeded bool
+}
+
+// logFile 日志目录中的文件
+type logFile struct {
+	path    string
+	size    int64
+	modTime time.Time
+	backup  bool
+}
+
+// check 统计日志目录，超出配额时按修改时间从旧到新删除备份文件，仍超出则提升级别
+func (w *quotaWatcher) check() {
+	metux.RLock()
+	dirs, prefixes := logDirs()
+	metux.RUnlock()
+
+	var (
+		files []logFile
+		total int64
+	)
+	for dir := range dirs {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+		for _, e := range entries {
+			info, err := e.Info()
+			if err != nil || !info.Mode().IsRegular() {
+				continue
+			}
+			path := filepath.Join(dir, e.Name())
+			files = append(files, logFile{path: path, size: info.Size(), modTime: info.ModTime(), backup: isBackup(path, prefixes)})
+			total += info.Size()
+		}
+	}
+	free := int64(-1)
+	for dir := range dirs {
+		if f, err := freeSpace(dir); err == nil && (free < 0 || f < free) {
+			free = f
+		}
+	}
+
+	sort.Slice(files, func(i, j int) bool { return files[i].modTime.Before(files[j].modTime) })
+	var deleted []string
+	for _, f := range files {
+		if !w.over(total, free) {
+			break
+		}
+		if !f.backup {
+			continue
+		}
+		if err := os.Remove(f.path); err != nil {
+			continue
+		}
+		deleted = append(deleted, f.path)
+		total -= f.size
+		if free >= 0 {
+			free += f.size
+		}
+	}
+
+	switch over := w.over(total, free); {
+	case over && !w.exceeded:
+		// 先输出告警，提升级别后告警本身可能被过滤
+		GetDefaultLogger().Error("log disk quota exceeded",
+			zap.Int64("dir_bytes", total),
+			zap.Int64("free_bytes", free),
+			zap.Strings("deleted", deleted),
+			zap.Stringer("min_level", w.level))
+		w.exceeded = w.setLevel(int32(w.level))
+	case !over && w.exceeded:
+		w.exceeded = !w.setLevel(quotaOff)
+		GetDefaultLogger().Warn("log disk quota recovered",
+			zap.Int64("dir_bytes", total),
+			zap.Int64("free_bytes", free))
+	case len(deleted) > 0:
+		diagnostics.Info("deleted log backups over disk quota", zap.Strings("deleted", deleted))
+	}
+}
+
+// setLevel 设置配额级别，检查已停止时不再修改，避免覆盖stopDiskQuota的恢复
+func (w *quotaWatcher) setLevel(level int32) bool {
+	metux.RLock()
+	defer metux.RUnlock()
+
+	select {
+	case <-w.stop:
+		return false
+	default:
+	}
+	quotaLevel.Store(level)
+	return true
+}
+
+func (w *quotaWatcher) over(total, free int64) bool {
+	if w.cfg.MaxDirMB > 0 && total > int64(w.cfg.MaxDirMB)<<20 {
+		return true
+	}
+	return w.cfg.MinFreeMB > 0 && free >= 0 && free < int64(w.cfg.MinFreeMB)<<20
+}
+
+// logDirs 返回各logger文件所在的目录，以及文件名去掉扩展名后的前缀，用于识别备份文件。
+// 调用方需持有metux读锁
+func logDirs() (map[string]bool, []string) {
+	dirs := make(map[string]bool)
+	var prefixes []string
+	for _, entry := range loggers {
+		for _, name := range entry.fileNames() {
+			dirs[filepath.Dir(name)] = true
+			prefixes = append(prefixes, strings.TrimSuffix(name, filepath.Ext(name))+"-")
+		}
+	}
+	return dirs, prefixes
+}
+
+// isBackup 是否为lumberjack切割出的备份文件，格式为 name-2006-01-02T15-04-05.000.ext[.gz]
+func isBackup(path string, prefixes []string) bool {
+	for _, prefix := range prefixes {
+		if strings.HasPrefix(path, prefix) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,10 @@
+//go:build !unix
+
+package log
+
+import "errors"
+
+// freeSpace 非unix平台不检查剩余空间
+func freeSpace(string) (int64, error) {
+	return 0, errors.ErrUnsupported
+}
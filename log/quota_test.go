@@ -0,0 +1,109 @@
+package log
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+func writeSized(t *testing.T, path string, size int, modTime time.Time) {
+	t.Helper()
+
+	if err := os.WriteFile(path, make([]byte, size), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chtimes(path, modTime, modTime); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestDiskQuotaDeletesOldestBackups(t *testing.T) {
+	dir := initTestLoggers(t)
+	now := time.Now()
+	oldest := filepath.Join(dir, "default-2024-01-01T00-00-00.000.log.gz")
+	newer := filepath.Join(dir, "default-2024-01-02T00-00-00.000.log.gz")
+	other := filepath.Join(dir, "notes.txt")
+	writeSized(t, oldest, 600<<10, now.Add(-2*time.Hour))
+	writeSized(t, newer, 600<<10, now.Add(-time.Hour))
+	writeSized(t, other, 10, now.Add(-3*time.Hour))
+
+	w := &quotaWatcher{cfg: DiskQuotaConfig{MaxDirMB: 1}, level: zapcore.ErrorLevel, stop: make(chan struct{})}
+	w.check()
+
+	if _, err := os.Stat(oldest); !os.IsNotExist(err) {
+		t.Fatal("oldest backup should be deleted")
+	}
+	for _, path := range []string{newer, other} {
+		if _, err := os.Stat(path); err != nil {
+			t.Fatalf("%s should be kept: %v", path, err)
+		}
+	}
+	if w.exceeded || !quotaAllows(zapcore.DebugLevel) {
+		t.Fatal("quota should not be exceeded after deleting backups")
+	}
+}
+
+func TestDiskQuotaRaisesLevel(t *testing.T) {
+	dir := initTestLoggers(t)
+	t.Cleanup(func() { quotaLevel.Store(quotaOff) })
+	dump := filepath.Join(dir, "heap.dump")
+	writeSized(t, dump, 2<<20, time.Now())
+
+	w := &quotaWatcher{cfg: DiskQuotaConfig{MaxDirMB: 1}, level: zapcore.ErrorLevel, stop: make(chan struct{})}
+	w.check()
+	w.check()
+	GetDefaultLogger().Warn("dropped while over quota")
+	GetDefaultLogger().Error("kept while over quota")
+
+	if !w.exceeded || quotaAllows(zapcore.WarnLevel) || !quotaAllows(zapcore.ErrorLevel) {
+		t.Fatal("level should be raised to error while over quota")
+	}
+	if err := os.Remove(dump); err != nil {
+		t.Fatal(err)
+	}
+	w.check()
+	if w.exceeded || !quotaAllows(zapcore.DebugLevel) {
+		t.Fatal("level should be restored after recovery")
+	}
+	GetDefaultLogger().Info("after recovery")
+	Close()
+
+	data, err := os.ReadFile(filepath.Join(dir, "default.log"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := string(data)
+	if n := strings.Count(got, "log disk quota exceeded"); n != 1 {
+		t.Fatalf("expected a single alert, got %d in %s", n, got)
+	}
+	for _, want := range []string{"kept while over quota", "log disk quota recovered", "after recovery"} {
+		if !strings.Contains(got, want) {
+			t.Fatalf("missing %q in %s", want, got)
+		}
+	}
+	if strings.Contains(got, "dropped while over quota") {
+		t.Fatalf("warn should be dropped while over quota: %s", got)
+	}
+}
+
+func TestDiskQuotaStop(t *testing.T) {
+	initTestLoggers(t)
+	w := &quotaWatcher{cfg: DiskQuotaConfig{MaxDirMB: 1}, level: zapcore.ErrorLevel, stop: make(chan struct{})}
+	close(w.stop)
+	if w.setLevel(int32(zapcore.ErrorLevel)) || !quotaAllows(zapcore.DebugLevel) {
+		t.Fatal("stopped watcher should not change the level")
+	}
+
+	cfg := Config{
+		Zaplog:    []LogConfig{{Name: "default", FileName: "a.log"}},
+		DiskQuota: DiskQuotaConfig{MaxDirMB: -1, Level: "loud"},
+	}
+	err := validateConfig(&cfg)
+	if err == nil || !strings.Contains(err.Error(), "must not be negative") || !strings.Contains(err.Error(), `invalid level "loud"`) {
+		t.Fatalf("expected disk_quota errors, got %v", err)
+	}
+}
@@ -0,0 +1,14 @@
+//go:build unix
+
+package log
+
+import "syscall"
+
+// freeSpace 返回dir所在文件系统对非特权用户可用的字节数
+func freeSpace(dir string) (int64, error) {
+	var st syscall.Statfs_t
+	if err := syscall.Statfs(dir, &st); err != nil {
+		return 0, err
+	}
+	return int64(st.Bavail) * int64(st.Bsize), nil
+}
@@ -0,0 +1,132 @@
+package log
+
+import (
+	"fmt"
+	"runtime"
+	"strings"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// RecoverOption Recover与Go的可选配置
+type RecoverOption func(*recoverOptions)
+
+type recoverOptions struct {
+	repanic bool
+	onPanic func(any)
+}
+
+// WithRePanic 记录后重新panic，用于希望进程照常崩溃、只是不丢失现场的场景
+func WithRePanic() RecoverOption {
+	return func(o *recoverOptions) {
+		o.repanic = true
+	}
+}
+
+// WithPanicHandler 记录后调用fn，如上报监控或通知调用方
+func WithPanicHandler(fn func(any)) RecoverOption {
+	return func(o *recoverOptions) {
+		o.onPanic = fn
+	}
+}
+
+// stackFrame 解析后的堆栈帧
+type stackFrame struct {
+	Function string
+	File     string
+	Line     int
+}
+
+func (f stackFrame) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	enc.AddString("func", f.Function)
+	enc.AddString("file", f.File)
+	enc.AddInt("line", f.Line)
+	return nil
+}
+
+type stackFrames []stackFrame
+
+func (s stackFrames) MarshalLogArray(enc zapcore.ArrayEncoder) error {
+	for _, f := range s {
+		if err := enc.AppendObject(f); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// panicStack 返回panic发生处的堆栈，跳过runtime.gopanic及之前的帧
+func panicStack() stackFrames {
+	pcs := make([]uintptr, 64)
+	n := runtime.Callers(3, pcs)
+	frames := runtime.CallersFrames(pcs[:n])
+
+	var out stackFrames
+	for {
+		frame, more := frames.Next()
+		if frame.Function == "runtime.gopanic" {
+			out = out[:0]
+		} else if !strings.HasPrefix(frame.Function, "runtime.") {
+			out = append(out, stackFrame{Function: frame.Function, File: frame.File, Line: frame.Line})
+		}
+		if !more {
+			break
+		}
+	}
+	return out
+}
+
+// Recover 恢复panic并以error级别记录panic值和结构化的堆栈，需直接defer调用：
+//
+//	defer log.Recover(logger)
+//
+// logger为nil时使用default logger
+func Recover(logger *zap.Logger, opts ...RecoverOption) {
+	r := recover()
+	if r == nil {
+		return
+	}
+	handlePanic(logger, r, opts)
+}
+
+// handlePanic 记录panic并按选项处理
+func handlePanic(logger *zap.Logger, r any, opts []RecoverOption) {
+	var o recoverOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if logger == nil {
+		logger = GetDefaultLogger()
+	}
+
+	fields := []zap.Field{
+		zap.String("panic", fmt.Sprint(r)),
+		zap.String("panic_type", fmt.Sprintf("%T", r)),
+		zap.Array("stack", panicStack()),
+	}
+	if err, ok := r.(error); ok {
+		fields = append(fields, zap.Error(err))
+	}
+	logger.Error("panic recovered", fields...)
+	_ = logger.Sync()
+
+	if o.onPanic != nil {
+		o.onPanic(r)
+	}
+	if o.repanic {
+		panic(r)
+	}
+}
+
+// Go 在新的goroutine中执行fn，fn中的panic被恢复并记录到default logger，避免后台goroutine崩溃时没有任何记录
+func Go(fn func(), opts ...RecoverOption) {
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				handlePanic(nil, r, opts)
+			}
+		}()
+		fn()
+	}()
+}
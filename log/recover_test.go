@@ -0,0 +1,73 @@
+package log
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"go.uber.org/zap/zapcore"
+)
+
+func TestRecover(t *testing.T) {
+	initTestLoggers(t)
+	logs := observeLogger(t, "default")
+
+	boom := errors.New("boom")
+	func() {
+		defer Recover(nil)
+		panic(boom)
+	}()
+
+	entries := logs.FilterMessage("panic recovered").All()
+	if len(entries) != 1 || entries[0].Level != zapcore.ErrorLevel {
+		t.Fatalf("unexpected entries: %+v", logs.All())
+	}
+	fields := entries[0].ContextMap()
+	if fields["panic"] != "boom" || fields["error"] != "boom" || fields["panic_type"] != "*errors.errorString" {
+		t.Fatalf("unexpected fields: %+v", fields)
+	}
+	stack, ok := fields["stack"].([]any)
+	if !ok || len(stack) == 0 {
+		t.Fatalf("stack should be parsed into frames, got %#v", fields["stack"])
+	}
+	top := stack[0].(map[string]any)
+	if !strings.Contains(top["func"].(string), "TestRecover") || !strings.HasSuffix(top["file"].(string), "recover_test.go") {
+		t.Fatalf("top frame should be the panicking function, got %+v", top)
+	}
+}
+
+func TestRecoverRePanic(t *testing.T) {
+	initTestLoggers(t)
+	logs := observeLogger(t, "default")
+
+	var handled any
+	defer func() {
+		if r := recover(); r != "again" {
+			t.Fatalf("expected re-panic, got %v", r)
+		}
+		if handled != "again" || logs.FilterMessage("panic recovered").Len() != 1 {
+			t.Fatalf("panic should be logged and handled before re-panic, got %v %+v", handled, logs.All())
+		}
+	}()
+	func() {
+		defer Recover(nil, WithRePanic(), WithPanicHandler(func(r any) { handled = r }))
+		panic("again")
+	}()
+}
+
+func TestGo(t *testing.T) {
+	initTestLoggers(t)
+	logs := observeLogger(t, "default")
+
+	done := make(chan any, 1)
+	Go(func() {
+		panic("worker crashed")
+	}, WithPanicHandler(func(r any) { done <- r }))
+
+	if r := <-done; r != "worker crashed" {
+		t.Fatalf("unexpected panic value %v", r)
+	}
+	if logs.FilterMessage("panic recovered").FilterFieldKey("stack").Len() != 1 {
+		t.Fatalf("goroutine panic should be logged, got %+v", logs.All())
+	}
+}
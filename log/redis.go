@@ -0,0 +1,106 @@
+package log
+
+import (
+	"context"
+	"errors"
+	"net"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+// RedisHook go-redis钩子，记录慢命令与执行错误，redis.Nil不视为错误
+//
+//	rdb.AddHook(log.NewRedisHook("redis", log.WithSlowThreshold(50*time.Millisecond)))
+type RedisHook struct {
+	name string
+	opts *options
+}
+
+var _ redis.Hook = (*RedisHook)(nil)
+
+// NewRedisHook 创建写入指定名称logger的go-redis钩子，WithPayload(true)时记录命令参数
+func NewRedisHook(name string, opts ...Option) *RedisHook {
+	return &RedisHook{name: name, opts: newOptions(opts)}
+}
+
+// DialHook 实现redis.Hook，记录建连失败
+func (h *RedisHook) DialHook(next redis.DialHook) redis.DialHook {
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		conn, err := next(ctx, network, addr)
+		if err != nil {
+			GetLogger(h.name).Error("redis dial failed", zap.String("addr", addr), zap.Error(err))
+		}
+		return conn, err
+	}
+}
+
+// ProcessHook 实现redis.Hook
+func (h *RedisHook) ProcessHook(next redis.ProcessHook) redis.ProcessHook {
+	return func(ctx context.Context, cmd redis.Cmder) error {
+		start := time.Now()
+		err := next(ctx, cmd)
+		latency := time.Since(start)
+
+		failed := isRedisError(err)
+		if !failed && !h.opts.isSlow(latency) {
+			return err
+		}
+		fields := []zap.Field{
+			zap.String("cmd", cmd.FullName()),
+			zap.Duration("latency", latency),
+		}
+		if h.opts.logPayload {
+			fields = append(fields, Any("args", cmd.Args()))
+		}
+		if failed {
+			GetLogger(h.name).Error("redis command failed", append(fields, zap.Error(err))...)
+		} else {
+			GetLogger(h.name).Warn("slow redis command", fields...)
+		}
+		return err
+	}
+}
+
+// ProcessPipelineHook 实现redis.Hook，按整个pipeline的耗时判断是否为慢请求，并列出出错的命令
+func (h *RedisHook) ProcessPipelineHook(next redis.ProcessPipelineHook) redis.ProcessPipelineHook {
+	return func(ctx context.Context, cmds []redis.Cmder) error {
+		start := time.Now()
+		err := next(ctx, cmds)
+		latency := time.Since(start)
+
+		var failed []string
+		for _, cmd := range cmds {
+			if isRedisError(cmd.Err()) {
+				failed = append(failed, cmd.FullName()+": "+cmd.Err().Error())
+			}
+		}
+		if len(failed) == 0 && !isRedisError(err) && !h.opts.isSlow(latency) {
+			return err
+		}
+
+		names := make([]string, len(cmds))
+		for i, cmd := range cmds {
+			names[i] = cmd.FullName()
+		}
+		fields := []zap.Field{
+			zap.Strings("cmds", names),
+			zap.Duration("latency", latency),
+		}
+		if len(failed) > 0 || isRedisError(err) {
+			fields = append(fields, zap.Strings("failed", failed))
+			if err != nil {
+				fields = append(fields, zap.Error(err))
+			}
+			GetLogger(h.name).Error("redis pipeline failed", fields...)
+		} else {
+			GetLogger(h.name).Warn("slow redis pipeline", fields...)
+		}
+		return err
+	}
+}
+
+func isRedisError(err error) bool {
+	return err != nil && !errors.Is(err, redis.Nil)
+}
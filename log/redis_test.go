@@ -0,0 +1,53 @@
+package log
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap/zapcore"
+)
+
+func TestRedisHook(t *testing.T) {
+	logs := observeLogger(t, "redis")
+	hook := NewRedisHook("redis", WithSlowThreshold(10*time.Millisecond), WithPayload(true))
+	ctx := context.Background()
+
+	process := hook.ProcessHook(func(ctx context.Context, cmd redis.Cmder) error {
+		switch cmd.Name() {
+		case "get":
+			return redis.Nil
+		case "set":
+			time.Sleep(20 * time.Millisecond)
+			return nil
+		default:
+			cmd.SetErr(errors.New("WRONGTYPE"))
+			return cmd.Err()
+		}
+	})
+	_ = process(ctx, redis.NewStringCmd(ctx, "get", "k"))
+	_ = process(ctx, redis.NewStatusCmd(ctx, "set", "k", "v"))
+	_ = process(ctx, redis.NewIntCmd(ctx, "incr", "k"))
+
+	pipeline := hook.ProcessPipelineHook(func(ctx context.Context, cmds []redis.Cmder) error {
+		cmds[1].SetErr(errors.New("ERR"))
+		return cmds[1].Err()
+	})
+	_ = pipeline(ctx, []redis.Cmder{redis.NewStringCmd(ctx, "get", "a"), redis.NewIntCmd(ctx, "incr", "b")})
+
+	entries := logs.All()
+	if len(entries) != 3 {
+		t.Fatalf("expected 3 entries, got %d", len(entries))
+	}
+	if entries[0].Level != zapcore.WarnLevel || entries[0].ContextMap()["cmd"] != "set" || entries[0].ContextMap()["args"] == nil {
+		t.Fatalf("unexpected slow entry: %v %v", entries[0].Level, entries[0].ContextMap())
+	}
+	if entries[1].Level != zapcore.ErrorLevel || entries[1].ContextMap()["cmd"] != "incr" {
+		t.Fatalf("unexpected error entry: %v %v", entries[1].Level, entries[1].ContextMap())
+	}
+	if entries[2].Message != "redis pipeline failed" {
+		t.Fatalf("unexpected pipeline entry: %v", entries[2].Message)
+	}
+}
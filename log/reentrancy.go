@@ -0,0 +1,43 @@
+package log
+
+import (
+	"sync"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// guardCore 检测同一goroutine在写入过程中再次写入同一logger（如输出端自身出错时又通过该logger记录日志），
+// 此类日志转交内部诊断日志输出，避免死锁或无限递归
+type guardCore struct {
+	zapcore.Core
+	name    string
+	writing *sync.Map
+}
+
+func newGuardCore(core zapcore.Core, name string) zapcore.Core {
+	return &guardCore{Core: core, name: name, writing: &sync.Map{}}
+}
+
+func (c *guardCore) With(fields []zapcore.Field) zapcore.Core {
+	return &guardCore{Core: c.Core.With(fields), name: c.name, writing: c.writing}
+}
+
+func (c *guardCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+func (c *guardCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	id := goid()
+	if _, busy := c.writing.LoadOrStore(id, struct{}{}); busy {
+		if ce := diagnostics.Check(ent.Level, ent.Message); ce != nil {
+			ce.Write(append(fields, zap.String("logger", c.name), zap.Bool("reentrant", true))...)
+		}
+		return nil
+	}
+	defer c.writing.Delete(id)
+	return c.Core.Write(ent, fields)
+}
@@ -0,0 +1,35 @@
+package log
+
+import (
+	"bytes"
+	"testing"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+type writerFunc func([]byte) (int, error)
+
+func (f writerFunc) Write(p []byte) (int, error) { return f(p) }
+
+func TestGuardCore(t *testing.T) {
+	var buf bytes.Buffer
+	var logger *zap.Logger
+	sink := writerFunc(func(p []byte) (int, error) {
+		// 模拟输出端内部又通过同一个logger记录日志
+		logger.Warn("sink is slow")
+		return buf.Write(p)
+	})
+	core := zapcore.NewCore(zapcore.NewJSONEncoder(zap.NewProductionEncoderConfig()), zapcore.AddSync(sink), zapcore.DebugLevel)
+	logger = zap.New(newGuardCore(core, "kafka"))
+
+	logger.Info("hello")
+	logger.With(zap.String("k", "v")).Info("world")
+
+	if n := bytes.Count(buf.Bytes(), []byte("\n")); n != 2 {
+		t.Fatalf("expected 2 entries in sink, got %d:\n%s", n, buf.String())
+	}
+	if bytes.Contains(buf.Bytes(), []byte("sink is slow")) {
+		t.Fatal("reentrant entry should not reach the sink")
+	}
+}
@@ -0,0 +1,119 @@
+package log
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// EncoderFactory 根据编码配置创建编码器，配置中已应用time_format、各字段名等选项
+type EncoderFactory func(cfg zapcore.EncoderConfig) (zapcore.Encoder, error)
+
+// SinkFactory 根据logger名称和sinks中的options创建输出端，
+// 返回的WriteSyncer如果实现了io.Closer，会在Close时关闭
+type SinkFactory func(logger string, options map[string]any) (zapcore.WriteSyncer, error)
+
+// SinkConfig 额外输出端配置
+type SinkConfig struct {
+	Type        string         `yaml:"type" mapstructure:"type"`                 // 内置的journald、eventlog、cloudwatch、sls、cls或通过RegisterSink注册的名称
+	Options     map[string]any `yaml:"options" mapstructure:"options"`           // 传给SinkFactory的参数
+	SyncTimeout time.Duration  `yaml:"sync_timeout" mapstructure:"sync_timeout"` // Sync和Close的最长等待时间，超时后不再等待并输出到stderr，默认5s
+}
+
+var (
+	encoderFactories = map[string]EncoderFactory{
+		"json": func(cfg zapcore.EncoderConfig) (zapcore.Encoder, error) {
+			return zapcore.NewJSONEncoder(cfg), nil
+		},
+		"console": func(cfg zapcore.EncoderConfig) (zapcore.Encoder, error) {
+			cfg.EncodeLevel = zapcore.CapitalColorLevelEncoder
+			return zapcore.NewConsoleEncoder(cfg), nil
+		},
+		"logfmt": func(cfg zapcore.EncoderConfig) (zapcore.Encoder, error) {
+			cfg.EncodeLevel = zapcore.LowercaseLevelEncoder
+			return newLogfmtEncoder(cfg), nil
+		},
+		"pretty": func(cfg zapcore.EncoderConfig) (zapcore.Encoder, error) {
+			return newPrettyEncoder(cfg), nil
+		},
+		"msgpack": func(cfg zapcore.EncoderConfig) (zapcore.Encoder, error) {
+			return newMsgpackEncoder(cfg), nil
+		},
+	}
+	sinkFactories = map[string]SinkFactory{
+		"journald":   newJournaldSink,
+		"eventlog":   newEventLogSink,
+		"cloudwatch": newCloudWatchSink,
+		"sls":        newSLSSink,
+		"cls":        newCLSSink,
+	}
+	registryMu sync.RWMutex
+)
+
+// RegisterEncoder 注册自定义编码器，配置中通过encoder: <name>引用。
+// 需在InitFromLocalFileConfig之前调用，重复注册时覆盖原有实现
+func RegisterEncoder(name string, factory EncoderFactory) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	encoderFactories[strings.ToLower(name)] = factory
+}
+
+// RegisterSink 注册自定义输出端，配置中通过sinks: [{type: <name>}]引用。
+// 需在InitFromLocalFileConfig之前调用，重复注册时覆盖原有实现
+func RegisterSink(name string, factory SinkFactory) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	sinkFactories[strings.ToLower(name)] = factory
+}
+
+func lookupEncoder(name string) (EncoderFactory, bool) {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	f, ok := encoderFactories[strings.ToLower(name)]
+	return f, ok
+}
+
+func lookupSink(name string) (SinkFactory, bool) {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	f, ok := sinkFactories[strings.ToLower(name)]
+	return f, ok
+}
+
+// registeredList 返回已注册的名称，按字母排序
+func registeredList[T any](m map[string]T) []string {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	names := make([]string, 0, len(m))
+	for name := range m {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// registeredNames 返回已注册的名称，用于错误提示
+func registeredNames[T any](m map[string]T) string {
+	return strings.Join(registeredList(m), ", ")
+}
+
+// newSinks 按配置创建额外输出端
+func newSinks(cfg LogConfig) ([]zapcore.WriteSyncer, error) {
+	sinks := make([]zapcore.WriteSyncer, 0, len(cfg.Sinks))
+	for _, sc := range cfg.Sinks {
+		factory, ok := lookupSink(sc.Type)
+		if !ok {
+			return nil, fmt.Errorf("unknown sink %q", sc.Type)
+		}
+		ws, err := factory(cfg.Name, sc.Options)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create sink %s: %w", sc.Type, err)
+		}
+		sinks = append(sinks, newTimeoutSyncer(cfg.Name, sc, ws))
+	}
+	return sinks, nil
+}
@@ -0,0 +1,87 @@
+package log
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// memorySink 记录写入内容的测试输出端
+type memorySink struct {
+	mu     sync.Mutex
+	buf    bytes.Buffer
+	prefix string
+	closed bool
+}
+
+func (s *memorySink) Write(p []byte) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.buf.WriteString(s.prefix)
+	return s.buf.Write(p)
+}
+
+func (s *memorySink) Sync() error { return nil }
+
+func (s *memorySink) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.closed = true
+	return nil
+}
+
+func TestRegisterEncoderAndSink(t *testing.T) {
+	var sink *memorySink
+	RegisterEncoder("test-json", func(cfg zapcore.EncoderConfig) (zapcore.Encoder, error) {
+		cfg.MessageKey = "custom_msg"
+		return zapcore.NewJSONEncoder(cfg), nil
+	})
+	RegisterSink("test-memory", func(logger string, options map[string]any) (zapcore.WriteSyncer, error) {
+		sink = &memorySink{prefix: fmt.Sprint(options["prefix"])}
+		return sink, nil
+	})
+
+	dir := t.TempDir()
+	config := fmt.Sprintf(`zaplog:
+  - name: default
+    file_name: %s
+    encoder: test-json
+    sinks:
+      - type: test-memory
+        options:
+          prefix: "> "
+`, filepath.Join(dir, "app.log"))
+	configPath := filepath.Join(dir, "log_config.yaml")
+	if err := os.WriteFile(configPath, []byte(config), 0644); err != nil {
+		t.Fatal(err)
+	}
+	Close()
+	if err := Init(WithConfigFile(configPath)); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(Close)
+
+	GetDefaultLogger().Info("hello")
+	sink.mu.Lock()
+	got := sink.buf.String()
+	sink.mu.Unlock()
+	if !strings.HasPrefix(got, `> {`) || !strings.Contains(got, `"custom_msg":"hello"`) {
+		t.Fatalf("unexpected sink output %q", got)
+	}
+
+	Close()
+	if !sink.closed {
+		t.Fatal("sink should be closed on Close")
+	}
+
+	cfg := Config{Zaplog: []LogConfig{{Name: "default", FileName: "a.log", Sinks: []SinkConfig{{Type: "missing"}}}}}
+	if err := validateConfig(&cfg); err == nil || !strings.Contains(err.Error(), "unknown sink") {
+		t.Fatalf("expected unknown sink error, got %v", err)
+	}
+}
@@ -0,0 +1,177 @@
+package log
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"reflect"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/allanchen1214/goeasy/config"
+)
+
+// remotePollInterval 重新读取远程配置的间隔
+var remotePollInterval = 10 * time.Second
+
+var remoteCancel context.CancelFunc
+
+// InitFromRemoteConfig 从配置中心读取配置并初始化日志，之后定期重新读取并在配置变化时运行时生效：
+// 仅级别变化的logger直接调整级别，其他配置变化的logger重新创建，新增或删除的logger随之增删，
+// GetOrCreate创建的logger保留至RemoveLogger。
+// panic_file、silence_windows、disk_quota等进程级配置只在初始化时生效。
+//
+// provider为config.NewRemote支持的consul、etcd、nacos、apollo或通过config.RegisterProvider注册的名称，
+// endpoint为服务地址，如http://127.0.0.1:8848，可通过namespace、app_id、cluster、token查询参数
+// 指定config.RemoteOptions中的对应字段；path为配置的键，nacos时为[group/]dataId。
+// 配置格式由path的扩展名决定，默认yaml
+func InitFromRemoteConfig(provider, endpoint, path string) error {
+	opts, err := remoteOptions(provider, endpoint, path)
+	if err != nil {
+		return err
+	}
+	r, err := config.NewRemote(provider, opts)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	var cfg Config
+	w, err := config.WatchRemote(r, &cfg, func(_, cfg Config) {
+		// 无效的配置被忽略并保留当前配置
+		err := finishConfig(&cfg)
+		if err == nil {
+			err = reconfigure(ctx, cfg)
+		}
+		if err != nil {
+			diagnostics.Warn("failed to apply remote config", zap.String("path", path), zap.Error(err))
+		}
+	}, append(configOptions(""), config.WithErrorHandler(func(err error) {
+		diagnostics.Warn("failed to read remote config", zap.String("path", path), zap.Error(err))
+	}))...)
+	if err == nil {
+		err = finishConfig(&cfg)
+	}
+	if err == nil {
+		err = Init(WithConfig(cfg))
+	}
+	if err != nil {
+		cancel()
+		if w != nil {
+			w.Close()
+		}
+		return err
+	}
+
+	metux.Lock()
+	stopRemoteConfig()
+	remoteCancel = func() {
+		cancel()
+		// 回调可能正等待metux，Close等待回调结束，不能在持有metux时同步调用
+		go w.Close()
+	}
+	metux.Unlock()
+	return nil
+}
+
+// remoteOptions 将endpoint的查询参数与path转换为config.RemoteOptions
+func remoteOptions(provider, endpoint, path string) (config.RemoteOptions, error) {
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return config.RemoteOptions{}, fmt.Errorf("invalid remote endpoint %q: %w", endpoint, err)
+	}
+	q := u.Query()
+	u.RawQuery = ""
+	opts := config.RemoteOptions{
+		Endpoint:     u.String(),
+		Key:          path,
+		Namespace:    q.Get("namespace"),
+		AppID:        q.Get("app_id"),
+		Cluster:      q.Get("cluster"),
+		Token:        q.Get("token"),
+		PollInterval: remotePollInterval,
+	}
+	if strings.EqualFold(provider, "nacos") {
+		// 兼容以/nacos结尾的服务地址
+		opts.Endpoint = strings.TrimSuffix(strings.TrimSuffix(opts.Endpoint, "/"), "/nacos")
+		if i := strings.LastIndex(path, "/"); i >= 0 {
+			opts.Group, opts.Key = path[:i], path[i+1:]
+		}
+		if opts.Group == "" && strings.Contains(path, "/") || opts.Key == "" {
+			return opts, fmt.Errorf("nacos: invalid path %q, expected [group/]dataId", path)
+		}
+	}
+	return opts, nil
+}
+
+// stopRemoteConfig 停止监听远程配置，调用方需持有metux写锁
+func stopRemoteConfig() {
+	if remoteCancel != nil {
+		remoteCancel()
+		remoteCancel = nil
+	}
+}
+
+// reconfigure 按新配置调整已初始化的logger，ctx取消后不再生效
+func reconfigure(ctx context.Context, cfg Config) error {
+	metux.Lock()
+	defer metux.Unlock()
+	if ctx.Err() != nil {
+		return nil
+	}
+
+	logDefaults = cfg.Defaults
+	setTemplates(cfg.Templates)
+	setModuleLevels(cfg.ModuleLevels)
+
+	var (
+		errs    []error
+		retired = make(map[string]*logEntry)
+		seen    = make(map[string]bool)
+	)
+	for _, lc := range cfg.Zaplog {
+		seen[lc.Name] = true
+		old, ok := loggers[lc.Name]
+		if ok && sameExceptLevel(old.cfg, lc) {
+			old.cfg.Level = lc.Level
+			setBaseLevel(old, getLevel(lc.Level))
+			continue
+		}
+		entry, err := newLogger(lc)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to create logger %s: %w", lc.Name, err))
+			continue
+		}
+		loggers[lc.Name] = entry
+		if lc.Name == "default" {
+			zap.ReplaceGlobals(entry.logger)
+		}
+		if ok {
+			retired[lc.Name] = old
+		}
+	}
+	for name, entry := range loggers {
+		if !seen[name] && name != "default" && entry.template == "" {
+			delete(loggers, name)
+			retired[name] = entry
+		}
+	}
+
+	closeCtx, cancel := context.WithTimeout(context.Background(), closeTimeout)
+	defer cancel()
+	for name, entry := range retired {
+		stopDebugWindow(entry)
+		errs = append(errs, closeEntry(closeCtx, name, entry)...)
+	}
+	return errors.Join(errs...)
+}
+
+// sameExceptLevel 比较已创建logger的配置与新配置，新配置先补全newLogger中的默认值
+func sameExceptLevel(a, b LogConfig) bool {
+	setDefault(&b)
+	a.Level = b.Level
+	return reflect.DeepEqual(a, b)
+}
@@ -0,0 +1,130 @@
+package log
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+
+	"github.com/allanchen1214/goeasy/config"
+)
+
+// memProvider 测试用的配置中心，Get返回最近set的内容
+type memProvider struct {
+	mu   sync.Mutex
+	data []byte
+}
+
+func (p *memProvider) Get(context.Context) ([]byte, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.data, nil
+}
+
+func (p *memProvider) set(data string) {
+	p.mu.Lock()
+	p.data = []byte(data)
+	p.mu.Unlock()
+}
+
+func waitFor(t *testing.T, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatal("condition not met before deadline")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestInitFromRemoteConfig(t *testing.T) {
+	dir := t.TempDir()
+	base := "defaults:\n  directory: " + dir + "\nzaplog:\n  - name: default\n    level: info\n"
+	p := &memProvider{data: []byte(base + "  - name: access\n    level: info\n")}
+	config.RegisterProvider("test-remote", func(config.RemoteOptions) (config.Provider, error) { return p, nil })
+	defer func(d time.Duration) { remotePollInterval = d }(remotePollInterval)
+	remotePollInterval = 10 * time.Millisecond
+
+	Close()
+	if err := InitFromRemoteConfig("test-remote", "", "app/log.yaml"); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(Close)
+
+	access := GetLogger("access")
+	if access.Core().Enabled(zapcore.DebugLevel) {
+		t.Fatal("access should start at info")
+	}
+
+	// 仅级别变化时保留原logger
+	p.set(base + "  - name: access\n    level: debug\n")
+	waitFor(t, func() bool { return GetLogger("access").Core().Enabled(zapcore.DebugLevel) })
+	if GetLogger("access") != access {
+		t.Fatal("a level change should not recreate the logger")
+	}
+
+	// 无效配置被忽略
+	p.set(base + "  - name: access\n    level: loud\n")
+	time.Sleep(5 * remotePollInterval)
+	if GetLogger("access") != access {
+		t.Fatal("an invalid config should be ignored")
+	}
+	// 其他配置变化时重新创建，新增logger随之创建
+	p.set(base + "  - name: access\n    level: debug\n    encoder: console\n  - name: audit\n")
+	waitFor(t, func() bool {
+		metux.RLock()
+		defer metux.RUnlock()
+		_, ok := loggers["audit"]
+		return ok
+	})
+	if GetLogger("access") == access || !GetLogger("access").Core().Enabled(zapcore.DebugLevel) {
+		t.Fatal("access should be recreated with the new encoder")
+	}
+
+	// 删除的logger被移除
+	p.set(base)
+	waitFor(t, func() bool { return GetLogger("access") == GetDefaultLogger() })
+}
+
+func TestInitFromRemoteConfigErrors(t *testing.T) {
+	if err := InitFromRemoteConfig("zookeeper", "localhost:2181", "/log.yaml"); err == nil || !strings.Contains(err.Error(), "unknown provider") {
+		t.Fatalf("expected unknown provider error, got %v", err)
+	}
+	if err := InitFromRemoteConfig("nacos", "http://127.0.0.1:8848", "infra/"); err == nil || !strings.Contains(err.Error(), "[group/]dataId") {
+		t.Fatalf("expected invalid path error, got %v", err)
+	}
+}
+
+func TestInitFromNacos(t *testing.T) {
+	dir := t.TempDir()
+	content := "defaults:\n  directory: " + dir + "\nzaplog:\n  - name: default\n    level: warn\n"
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if r.URL.Path != "/nacos/v1/cs/configs" || q.Get("dataId") != "log.yaml" || q.Get("group") != "infra" || q.Get("tenant") != "prod" {
+			http.NotFound(w, r)
+			return
+		}
+		io.WriteString(w, content)
+	}))
+	defer srv.Close()
+
+	Close()
+	// 兼容以/nacos结尾的服务地址
+	if err := InitFromRemoteConfig("nacos", srv.URL+"/nacos?namespace=prod", "infra/log.yaml"); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(Close)
+	if GetDefaultLogger().Core().Enabled(zapcore.InfoLevel) {
+		t.Fatal("default should use the level from nacos")
+	}
+	if err := InitFromRemoteConfig("nacos", srv.URL, "other/log.yaml"); err == nil {
+		t.Fatal("expected error for missing config")
+	}
+}
@@ -0,0 +1,64 @@
+package log
+
+import (
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"go.uber.org/zap"
+)
+
+var sighupStop chan struct{}
+
+// Reopen 关闭所有logger的日志文件，下次写入时按原路径重新打开。
+// 配合系统logrotate使用：文件被改名后调用Reopen，避免继续写入已改名的文件
+func Reopen() error {
+	metux.RLock()
+	defer metux.RUnlock()
+
+	for name, entry := range loggers {
+		for _, file := range entry.files() {
+			if err := file.Close(); err != nil {
+				return fmt.Errorf("failed to reopen logger %s: %w", name, err)
+			}
+		}
+	}
+	for _, file := range teamFiles(teams) {
+		if err := file.Close(); err != nil {
+			return fmt.Errorf("failed to reopen team file: %w", err)
+		}
+	}
+	return nil
+}
+
+// startSIGHUPHandler 收到SIGHUP时调用Reopen，调用方需持有metux写锁
+func startSIGHUPHandler() {
+	stopSIGHUPHandler()
+
+	ch := make(chan os.Signal, 1)
+	signal.Notify(ch, syscall.SIGHUP)
+	stop := make(chan struct{})
+	sighupStop = stop
+	go func() {
+		defer signal.Stop(ch)
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ch:
+				if err := Reopen(); err != nil {
+					diagnostics.Error("failed to reopen log files", zap.Error(err))
+				}
+			}
+		}
+	}()
+}
+
+// stopSIGHUPHandler 调用方需持有metux写锁
+func stopSIGHUPHandler() {
+	if sighupStop != nil {
+		close(sighupStop)
+		sighupStop = nil
+	}
+}
@@ -0,0 +1,45 @@
+//go:build unix
+
+package log
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestReopenOnSIGHUP(t *testing.T) {
+	dir := initTestLoggers(t, "access")
+	path := filepath.Join(dir, "access.log")
+
+	metux.Lock()
+	startSIGHUPHandler()
+	metux.Unlock()
+
+	GetLogger("access").Info("before rotate")
+	// 模拟logrotate改名
+	if err := os.Rename(path, path+".1"); err != nil {
+		t.Fatal(err)
+	}
+	if err := syscall.Kill(os.Getpid(), syscall.SIGHUP); err != nil {
+		t.Fatal(err)
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for {
+		GetLogger("access").Info("after rotate")
+		if data, err := os.ReadFile(path); err == nil && strings.Contains(string(data), "after rotate") {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("log file was not reopened after SIGHUP")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if data, _ := os.ReadFile(path + ".1"); !strings.Contains(string(data), "before rotate") {
+		t.Fatalf("rotated file should keep old entries: %s", data)
+	}
+}
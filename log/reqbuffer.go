@@ -0,0 +1,167 @@
+package log
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// requestBufferMax 单个请求最多缓存的日志条数，超出后丢弃最早的
+const requestBufferMax = 1000
+
+// bufferedEntry 缓存的日志及写入时使用的core
+type bufferedEntry struct {
+	core   zapcore.Core
+	ent    zapcore.Entry
+	fields []zapcore.Field
+}
+
+// requestBuffer 一个请求内缓存的debug/info日志
+type requestBuffer struct {
+	mu      sync.Mutex
+	entries []bufferedEntry
+	dropped int
+	failed  bool // 请求期间输出过error及以上级别的日志
+	done    bool
+}
+
+// add 缓存日志，请求已结束时返回false
+func (b *requestBuffer) add(e bufferedEntry) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.done {
+		return false
+	}
+	if len(b.entries) == requestBufferMax {
+		b.entries = b.entries[1:]
+		b.dropped++
+	}
+	b.entries = append(b.entries, e)
+	return true
+}
+
+func (b *requestBuffer) markFailed() {
+	b.mu.Lock()
+	b.failed = true
+	b.mu.Unlock()
+}
+
+// finish 结束请求，返回需要输出的日志
+func (b *requestBuffer) finish(flush bool) ([]bufferedEntry, int) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.done = true
+	entries, dropped := b.entries, b.dropped
+	b.entries = nil
+	if !flush && !b.failed {
+		return nil, 0
+	}
+	return entries, dropped
+}
+
+// bufferCore 请求结束前缓存debug/info日志，warn及以上级别直接输出
+type bufferCore struct {
+	inner zapcore.Core         // 不做级别过滤的core
+	level zapcore.LevelEnabler // logger当前的级别，用于warn及以上级别和请求结束后的日志
+	buf   *requestBuffer
+}
+
+func (c *bufferCore) Enabled(level zapcore.Level) bool {
+	return level < zapcore.WarnLevel || c.level.Enabled(level)
+}
+
+func (c *bufferCore) With(fields []zapcore.Field) zapcore.Core {
+	return &bufferCore{inner: c.inner.With(fields), level: c.level, buf: c.buf}
+}
+
+func (c *bufferCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+func (c *bufferCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	if ent.Level >= zapcore.WarnLevel {
+		if ent.Level >= zapcore.ErrorLevel {
+			c.buf.markFailed()
+		}
+		return c.inner.Write(ent, fields)
+	}
+	if c.buf.add(bufferedEntry{core: c.inner, ent: ent, fields: fields}) {
+		return nil
+	}
+	// 请求结束后按logger级别正常输出
+	if c.level.Enabled(ent.Level) {
+		return c.inner.Write(ent, fields)
+	}
+	return nil
+}
+
+func (c *bufferCore) Sync() error {
+	return c.inner.Sync()
+}
+
+type requestBufferKey struct{}
+
+// requestBuffered ctx上的缓冲logger
+type requestBuffered struct {
+	name   string
+	logger *zap.Logger
+}
+
+// BufferRequest 为一个请求开启日志缓冲：请求期间通过FromContext(ctx, name)记录的debug/info日志暂存在内存中，
+// 不受logger级别限制；warn及以上级别照常输出。调用返回的函数结束请求：
+// err不为nil、耗时不低于slow（0表示不检查）或请求期间出现过error日志时，按记录顺序输出缓存的日志，否则丢弃。
+// 适用于只在失败时需要完整上下文的请求，成功请求不产生debug日志量
+func BufferRequest(ctx context.Context, name string, slow time.Duration) (context.Context, func(err error)) {
+	metux.RLock()
+	entry, ok := loggers[name]
+	if !ok {
+		entry, ok = loggers["default"]
+	}
+	metux.RUnlock()
+	if !ok || entry.newCore == nil {
+		return ctx, func(error) {}
+	}
+
+	start := time.Now()
+	buf := &requestBuffer{}
+	core := &bufferCore{
+		inner: entry.newCore(zapcore.DebugLevel, entry.ws),
+		level: entry.level,
+		buf:   buf,
+	}
+	logger := zap.New(core, entry.options...)
+	ctx = context.WithValue(ctx, requestBufferKey{}, &requestBuffered{name: name, logger: logger})
+
+	return ctx, func(err error) {
+		elapsed := time.Since(start)
+		entries, dropped := buf.finish(err != nil || slow > 0 && elapsed >= slow)
+		if dropped > 0 && len(entries) > 0 {
+			overflow := bufferedEntry{
+				core:   entries[0].core,
+				ent:    zapcore.Entry{Level: zapcore.WarnLevel, Time: time.Now(), LoggerName: entries[0].ent.LoggerName, Message: "request log buffer overflowed"},
+				fields: []zapcore.Field{zap.Int("dropped", dropped)},
+			}
+			entries = append([]bufferedEntry{overflow}, entries...)
+		}
+		for _, e := range entries {
+			_ = e.core.Write(e.ent, e.fields)
+		}
+	}
+}
+
+// bufferedLogger 返回ctx上名为name的缓冲logger
+func bufferedLogger(ctx context.Context, name string) (*zap.Logger, bool) {
+	b, ok := ctx.Value(requestBufferKey{}).(*requestBuffered)
+	if !ok || b.name != name {
+		return nil, false
+	}
+	return b.logger, true
+}
@@ -0,0 +1,94 @@
+package log
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+func TestBufferRequest(t *testing.T) {
+	dir := initTestLoggers(t, "api")
+	path := filepath.Join(dir, "api.log")
+	read := func() string {
+		t.Helper()
+		_ = GetLogger("api").Sync()
+		data, err := os.ReadFile(path)
+		if err != nil && !os.IsNotExist(err) {
+			t.Fatal(err)
+		}
+		return string(data)
+	}
+
+	// 成功的请求丢弃缓存的日志
+	ctx, end := BufferRequest(context.Background(), "api", time.Hour)
+	FromContext(ctx, "api").Debug("ok-debug")
+	FromContext(ctx, "api").Info("ok-info")
+	end(nil)
+	if got := read(); got != "" {
+		t.Fatalf("successful request should not be logged, got %q", got)
+	}
+
+	// 失败的请求按顺序输出，包括低于logger级别的debug日志
+	ctx, end = BufferRequest(context.Background(), "api", time.Hour)
+	ctx = WithFields(ctx, zap.String("request_id", "r2"))
+	FromContext(ctx, "api").Debug("fail-debug")
+	FromContext(ctx, "api").Warn("fail-warn")
+	FromContext(ctx, "api").Info("fail-info")
+	if got := read(); !strings.Contains(got, "fail-warn") || strings.Contains(got, "fail-debug") {
+		t.Fatalf("warn should be written immediately and debug held back, got %q", got)
+	}
+	end(errors.New("boom"))
+	got := read()
+	debugAt, infoAt := strings.Index(got, "fail-debug"), strings.Index(got, "fail-info")
+	if debugAt < 0 || infoAt < debugAt || !strings.Contains(got, `"request_id":"r2"`) {
+		t.Fatalf("failed request should flush buffered entries in order, got %q", got)
+	}
+
+	// 慢请求同样输出
+	ctx, end = BufferRequest(context.Background(), "api", time.Nanosecond)
+	FromContext(ctx, "api").Info("slow-info")
+	time.Sleep(time.Millisecond)
+	end(nil)
+	if !strings.Contains(read(), "slow-info") {
+		t.Fatal("slow request should flush buffered entries")
+	}
+
+	// 请求期间的error日志同样触发输出，结束后按logger级别正常输出
+	ctx, end = BufferRequest(context.Background(), "api", 0)
+	FromContext(ctx, "api").Info("err-info")
+	FromContext(ctx, "api").Error("err-error")
+	end(nil)
+	FromContext(ctx, "api").Debug("late-debug")
+	FromContext(ctx, "api").Info("late-info")
+	got = read()
+	if !strings.Contains(got, "err-info") || strings.Contains(got, "late-debug") || !strings.Contains(got, "late-info") {
+		t.Fatalf("unexpected output after error request: %q", got)
+	}
+}
+
+func TestBufferRequestOverflow(t *testing.T) {
+	dir := initTestLoggers(t, "api")
+
+	ctx, end := BufferRequest(context.Background(), "api", 0)
+	for i := 0; i < requestBufferMax+3; i++ {
+		FromContext(ctx, "api").Debug("step", zap.Int("i", i))
+	}
+	end(errors.New("boom"))
+	_ = GetLogger("api").Sync()
+
+	data, err := os.ReadFile(filepath.Join(dir, "api.log"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) != requestBufferMax+1 || !strings.Contains(lines[0], `"msg":"request log buffer overflowed","dropped":3`) ||
+		!strings.Contains(lines[1], `"i":3`) {
+		t.Fatalf("expected overflow notice followed by the last %d entries, got %d lines starting %q", requestBufferMax, len(lines), lines[0])
+	}
+}
@@ -0,0 +1,168 @@
+package log
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// RingEntry 内存环形缓冲中的一条日志
+type RingEntry struct {
+	Time    time.Time      `json:"time"`
+	Level   string         `json:"level"`
+	Logger  string         `json:"logger,omitempty"` // Named设置的子logger名称
+	Message string         `json:"msg"`
+	Caller  string         `json:"caller,omitempty"`
+	Fields  map[string]any `json:"fields,omitempty"`
+}
+
+// ringBuffer 保留最近size条日志
+type ringBuffer struct {
+	mu      sync.Mutex
+	entries []RingEntry
+	next    int
+	full    bool
+}
+
+func newRingBuffer(size int) *ringBuffer {
+	return &ringBuffer{entries: make([]RingEntry, size)}
+}
+
+func (r *ringBuffer) add(e RingEntry) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.entries[r.next] = e
+	r.next++
+	if r.next == len(r.entries) {
+		r.next, r.full = 0, true
+	}
+}
+
+// snapshot 按时间顺序返回缓冲中的日志
+func (r *ringBuffer) snapshot() []RingEntry {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if !r.full {
+		return append([]RingEntry(nil), r.entries[:r.next]...)
+	}
+	out := make([]RingEntry, 0, len(r.entries))
+	out = append(out, r.entries[r.next:]...)
+	return append(out, r.entries[:r.next]...)
+}
+
+// ringCore 把日志写入环形缓冲
+type ringCore struct {
+	zapcore.LevelEnabler
+	ring   *ringBuffer
+	fields []zapcore.Field
+}
+
+func newRingCore(ring *ringBuffer, level zapcore.LevelEnabler) zapcore.Core {
+	return &ringCore{LevelEnabler: level, ring: ring}
+}
+
+func (c *ringCore) With(fields []zapcore.Field) zapcore.Core {
+	return &ringCore{
+		LevelEnabler: c.LevelEnabler,
+		ring:         c.ring,
+		fields:       append(c.fields[:len(c.fields):len(c.fields)], fields...),
+	}
+}
+
+func (c *ringCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+func (c *ringCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	c.ring.add(c.entry(ent, fields))
+	return nil
+}
+
+// entry 把日志转换为RingEntry
+func (c *ringCore) entry(ent zapcore.Entry, fields []zapcore.Field) RingEntry {
+	e := RingEntry{
+		Time:    ent.Time,
+		Level:   ent.Level.String(),
+		Logger:  ent.LoggerName,
+		Message: ent.Message,
+	}
+	if ent.Caller.Defined {
+		e.Caller = ent.Caller.TrimmedPath()
+	}
+	if len(c.fields)+len(fields) > 0 {
+		enc := zapcore.NewMapObjectEncoder()
+		for _, f := range c.fields {
+			f.AddTo(enc)
+		}
+		for _, f := range fields {
+			f.AddTo(enc)
+		}
+		e.Fields = enc.Fields
+	}
+	return e
+}
+
+func (c *ringCore) Sync() error {
+	return nil
+}
+
+// RingDump 单个logger环形缓冲中的日志
+type RingDump struct {
+	Name    string      `json:"name"`
+	Entries []RingEntry `json:"entries"`
+}
+
+// DumpRing 返回开启了ring_buffer的logger在内存中保留的最近日志，name为空时返回所有logger
+func DumpRing(name string) []RingDump {
+	metux.RLock()
+	defer metux.RUnlock()
+
+	var dumps []RingDump
+	for n, entry := range loggers {
+		if entry.ring == nil || name != "" && n != name {
+			continue
+		}
+		dumps = append(dumps, RingDump{Name: n, Entries: entry.ring.snapshot()})
+	}
+	sort.Slice(dumps, func(i, j int) bool { return dumps[i].Name < dumps[j].Name })
+	return dumps
+}
+
+// handleDump 以JSON输出环形缓冲中的日志，参数：name logger名称，limit 每个logger最多返回的条数
+func handleDump(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	limit := 0
+	if s := query.Get("limit"); s != "" {
+		n, err := strconv.Atoi(s)
+		if err != nil || n <= 0 {
+			writeError(w, http.StatusBadRequest, fmt.Errorf("invalid limit %q", s))
+			return
+		}
+		limit = n
+	}
+
+	dumps := DumpRing(query.Get("name"))
+	if name := query.Get("name"); name != "" && len(dumps) == 0 {
+		writeError(w, http.StatusNotFound, fmt.Errorf("logger %s not found or ring_buffer disabled", name))
+		return
+	}
+	for i := range dumps {
+		if limit > 0 && len(dumps[i].Entries) > limit {
+			dumps[i].Entries = dumps[i].Entries[len(dumps[i].Entries)-limit:]
+		}
+	}
+	if dumps == nil {
+		dumps = []RingDump{}
+	}
+	writeJSON(w, http.StatusOK, dumps)
+}
@@ -0,0 +1,106 @@
+package log
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"go.uber.org/zap"
+)
+
+func TestRingBuffer(t *testing.T) {
+	r := newRingBuffer(3)
+	for i := 0; i < 5; i++ {
+		r.add(RingEntry{Message: fmt.Sprint(i)})
+	}
+	var got []string
+	for _, e := range r.snapshot() {
+		got = append(got, e.Message)
+	}
+	if strings.Join(got, ",") != "2,3,4" {
+		t.Fatalf("expected the last 3 entries in order, got %v", got)
+	}
+}
+
+func TestDumpRing(t *testing.T) {
+	dir := t.TempDir()
+	config := fmt.Sprintf(`zaplog:
+  - name: default
+    file_name: %s
+  - name: access
+    level: info
+    ring_buffer: 2
+    file_name: %s
+`, filepath.Join(dir, "app.log"), filepath.Join(dir, "access.log"))
+	configPath := filepath.Join(dir, "log_config.yaml")
+	if err := os.WriteFile(configPath, []byte(config), 0644); err != nil {
+		t.Fatal(err)
+	}
+	Close()
+	if err := Init(WithConfigFile(configPath)); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(Close)
+
+	logger := GetLogger("access").With(zap.String("request_id", "r1"))
+	logger.Debug("filtered")
+	logger.Info("first")
+	logger.Named("api").Warn("second", zap.Int("status", 404))
+	logger.Error("third", zap.Error(errors.New("boom")))
+
+	dumps := DumpRing("")
+	if len(dumps) != 1 || dumps[0].Name != "access" || len(dumps[0].Entries) != 2 {
+		t.Fatalf("unexpected dump: %+v", dumps)
+	}
+	second := dumps[0].Entries[0]
+	if second.Message != "second" || second.Level != "warn" || second.Logger != "api" ||
+		second.Fields["request_id"] != "r1" || second.Fields["status"] != int64(404) {
+		t.Fatalf("unexpected entry: %+v", second)
+	}
+	if dumps[0].Entries[1].Fields["error"] != "boom" {
+		t.Fatalf("unexpected entry: %+v", dumps[0].Entries[1])
+	}
+
+	srv := httptest.NewServer(AdminHandler())
+	defer srv.Close()
+	resp, err := http.Get(srv.URL + "/debug/log/dump?name=access&limit=1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	var got []RingDump
+	if err := json.NewDecoder(resp.Body).Decode(&got); err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 1 || len(got[0].Entries) != 1 || got[0].Entries[0].Message != "third" {
+		t.Fatalf("unexpected response: %+v", got)
+	}
+
+	resp, err = http.Get(srv.URL + "/debug/log/dump?name=default")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusNotFound {
+		t.Fatalf("expected 404 for logger without ring buffer, got %d", resp.StatusCode)
+	}
+
+	resp, err = http.Get(srv.URL + "/debug/log/recent?name=access&source=memory&level=error")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	var recent recentResult
+	if err := json.NewDecoder(resp.Body).Decode(&recent); err != nil {
+		t.Fatal(err)
+	}
+	if recent.File != "memory" || len(recent.Lines) != 1 || !strings.Contains(recent.Lines[0], `"msg":"third"`) {
+		t.Fatalf("unexpected recent response: %+v", recent)
+	}
+}
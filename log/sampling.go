@@ -0,0 +1,71 @@
+package log
+
+import (
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// SamplingConfig 日志采样配置，每个tick内相同级别和消息的日志先输出initial条，之后每thereafter条输出1条。
+// 与zap内置的采样不同，只对max_level及以下的级别采样，warn及以上的日志始终输出
+type SamplingConfig struct {
+	Initial    int           `yaml:"initial" mapstructure:"initial"`       // 每个tick内先输出的条数，0表示不采样
+	Thereafter int           `yaml:"thereafter" mapstructure:"thereafter"` // 超过initial后每thereafter条输出1条，0表示全部丢弃
+	Tick       time.Duration `yaml:"tick" mapstructure:"tick"`             // 计数周期，默认1s
+	MaxLevel   string        `yaml:"max_level" mapstructure:"max_level"`   // 采样的最高级别，默认info
+}
+
+func (c SamplingConfig) enabled() bool {
+	return c.Initial > 0
+}
+
+func validateSampling(lc LogConfig) []error {
+	s := lc.Sampling
+	var errs []error
+	if s.Initial < 0 || s.Thereafter < 0 || s.Tick < 0 {
+		errs = append(errs, fmt.Errorf("logger %s: sampling: initial, thereafter and tick must not be negative", lc.Name))
+	}
+	if s.MaxLevel != "" && !isValidLevel(s.MaxLevel) {
+		errs = append(errs, fmt.Errorf("logger %s: sampling: max_level: %w", lc.Name, invalidLevel(s.MaxLevel)))
+	}
+	return errs
+}
+
+// samplingCore max_level及以下的日志经过zap的采样器，更高级别的日志直接写入
+type samplingCore struct {
+	zapcore.Core
+	sampled  zapcore.Core
+	maxLevel zapcore.Level
+}
+
+// newSamplingCore dropped记录被采样丢弃的日志条数
+func newSamplingCore(core zapcore.Core, cfg SamplingConfig, dropped *atomic.Uint64) zapcore.Core {
+	tick := cfg.Tick
+	if tick <= 0 {
+		tick = time.Second
+	}
+	maxLevel := zapcore.InfoLevel
+	if cfg.MaxLevel != "" {
+		maxLevel = getLevel(cfg.MaxLevel)
+	}
+	sampled := zapcore.NewSamplerWithOptions(core, tick, cfg.Initial, cfg.Thereafter,
+		zapcore.SamplerHook(func(_ zapcore.Entry, dec zapcore.SamplingDecision) {
+			if dec&zapcore.LogDropped != 0 {
+				dropped.Add(1)
+			}
+		}))
+	return &samplingCore{Core: core, sampled: sampled, maxLevel: maxLevel}
+}
+
+func (c *samplingCore) With(fields []zapcore.Field) zapcore.Core {
+	return &samplingCore{Core: c.Core.With(fields), sampled: c.sampled.With(fields), maxLevel: c.maxLevel}
+}
+
+func (c *samplingCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if ent.Level <= c.maxLevel {
+		return c.sampled.Check(ent, ce)
+	}
+	return c.Core.Check(ent, ce)
+}
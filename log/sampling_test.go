@@ -0,0 +1,100 @@
+package log
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"go.uber.org/zap"
+)
+
+func TestSamplingKeepsWarnings(t *testing.T) {
+	dir := t.TempDir()
+	entry, err := newLogger(LogConfig{
+		Name:     "access",
+		Level:    "debug",
+		FileName: filepath.Join(dir, "access.log"),
+		Encoder:  "json",
+		Sampling: SamplingConfig{Initial: 2, Thereafter: 5},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer entry.writer.Close()
+	for i := 0; i < 12; i++ {
+		entry.logger.Debug("cache miss")
+		entry.logger.Info("request")
+		entry.logger.Warn("slow request")
+		entry.logger.Error("request failed")
+	}
+
+	data, _ := os.ReadFile(filepath.Join(dir, "access.log"))
+	// initial 2条，之后第5、10条，共4条
+	for msg, want := range map[string]int{"cache miss": 4, "request": 4, "slow request": 12, "request failed": 12} {
+		if got := strings.Count(string(data), `"msg":"`+msg+`"`); got != want {
+			t.Errorf("%s: got %d entries, want %d", msg, got, want)
+		}
+	}
+	if got := entry.sampled.Load(); got != 16 {
+		t.Fatalf("sampled = %d, want 16", got)
+	}
+}
+
+func TestSamplingMaxLevel(t *testing.T) {
+	dir := t.TempDir()
+	entry, err := newLogger(LogConfig{
+		Name:     "access",
+		FileName: filepath.Join(dir, "access.log"),
+		Encoder:  "json",
+		Sampling: SamplingConfig{Initial: 1, MaxLevel: "warn"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer entry.writer.Close()
+	child := entry.logger.With()
+	for i := 0; i < 3; i++ {
+		child.Warn("slow request")
+		child.Error("request failed")
+	}
+	data, _ := os.ReadFile(filepath.Join(dir, "access.log"))
+	if strings.Count(string(data), "slow request") != 1 || strings.Count(string(data), "request failed") != 3 {
+		t.Fatalf("unexpected log %q", data)
+	}
+
+	errs := validateSampling(LogConfig{Name: "access", Sampling: SamplingConfig{Initial: -1, MaxLevel: "loud"}})
+	if len(errs) != 2 {
+		t.Fatalf("expected 2 errors, got %v", errs)
+	}
+	if !(SamplingConfig{Initial: 1}).enabled() || (SamplingConfig{Thereafter: 1}).enabled() {
+		t.Fatal("sampling should be enabled only when initial is set")
+	}
+}
+
+func TestSamplingWithDropIf(t *testing.T) {
+	dir := t.TempDir()
+	entry, err := newLogger(LogConfig{
+		Name:     "access",
+		FileName: filepath.Join(dir, "access.log"),
+		Encoder:  "json",
+		DropIf:   []DropRule{{Field: "path", Equals: "/healthz"}},
+		Sampling: SamplingConfig{Initial: 1},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer entry.writer.Close()
+	for i := 0; i < 10; i++ {
+		entry.logger.Info("request")
+	}
+	entry.logger.Info("probe", zap.String("path", "/healthz"))
+
+	data, _ := os.ReadFile(filepath.Join(dir, "access.log"))
+	if got := strings.Count(string(data), `"msg":"request"`); got != 1 {
+		t.Fatalf("got %d sampled entries, want 1", got)
+	}
+	if strings.Contains(string(data), "probe") {
+		t.Fatal("drop_if should still apply with sampling")
+	}
+}
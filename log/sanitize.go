@@ -0,0 +1,92 @@
+package log
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// sanitizeCore 在编码前转义消息和字符串字段中的控制字符与无效UTF-8，
+// 防止用户输入中的CRLF伪造日志行(log injection)，以及console、logfmt输出破坏下游解析器
+type sanitizeCore struct {
+	zapcore.Core
+}
+
+func newSanitizeCore(core zapcore.Core) zapcore.Core {
+	return &sanitizeCore{Core: core}
+}
+
+func (c *sanitizeCore) With(fields []zap.Field) zapcore.Core {
+	return &sanitizeCore{Core: c.Core.With(sanitizeFields(fields))}
+}
+
+func (c *sanitizeCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+func (c *sanitizeCore) Write(ent zapcore.Entry, fields []zap.Field) error {
+	ent.Message = sanitizeString(ent.Message)
+	return c.Core.Write(ent, sanitizeFields(fields))
+}
+
+// sanitizeFields 需要转义时返回副本，不修改调用方的字段
+func sanitizeFields(fields []zap.Field) []zap.Field {
+	var out []zap.Field
+	for i, f := range fields {
+		s := stringValue(f)
+		clean := sanitizeString(s)
+		if clean == s {
+			continue
+		}
+		if out == nil {
+			out = append([]zap.Field(nil), fields...)
+		}
+		out[i] = zap.String(f.Key, clean)
+	}
+	if out == nil {
+		return fields
+	}
+	return out
+}
+
+// sanitizeString 将控制字符按Go字符串字面量的规则转义为\n、\x1b、\u0085等形式，无效UTF-8的字节转义为\xNN，
+// 不含需转义字符时原样返回，不分配内存
+func sanitizeString(s string) string {
+	i := 0
+	for i < len(s) {
+		r, size := utf8.DecodeRuneInString(s[i:])
+		if (r == utf8.RuneError && size == 1) || unicode.IsControl(r) {
+			break
+		}
+		i += size
+	}
+	if i == len(s) {
+		return s
+	}
+
+	var b strings.Builder
+	b.Grow(len(s) + 8)
+	b.WriteString(s[:i])
+	for i < len(s) {
+		r, size := utf8.DecodeRuneInString(s[i:])
+		switch {
+		case r == utf8.RuneError && size == 1:
+			fmt.Fprintf(&b, `\x%02x`, s[i])
+		case unicode.IsControl(r):
+			q := strconv.QuoteRune(r)
+			b.WriteString(q[1 : len(q)-1])
+		default:
+			b.WriteString(s[i : i+size])
+		}
+		i += size
+	}
+	return b.String()
+}
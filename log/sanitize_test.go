@@ -0,0 +1,59 @@
+package log
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"go.uber.org/zap"
+)
+
+func TestSanitizeString(t *testing.T) {
+	for in, want := range map[string]string{
+		"plain 中文":                  "plain 中文",
+		"user=admin\r\nlevel=error": `user=admin\r\nlevel=error`,
+		"tab\there":                 `tab\there`,
+		"\x1b[31mred":               `\x1b[31mred`,
+		"bad\xffutf8":               `bad\xffutf8`,
+		"next\u0085line":            `next\u0085line`,
+	} {
+		if got := sanitizeString(in); got != want {
+			t.Errorf("sanitizeString(%q) = %q, want %q", in, got, want)
+		}
+	}
+	if n := testing.AllocsPerRun(100, func() { sanitizeString("clean message") }); n != 0 {
+		t.Fatalf("expected no allocations for clean strings, got %v", n)
+	}
+}
+
+func TestSanitizeOption(t *testing.T) {
+	dir := t.TempDir()
+	entry, err := newLogger(LogConfig{
+		Name:     "access",
+		FileName: filepath.Join(dir, "access.log"),
+		Encoder:  "console",
+		Sanitize: true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer entry.writer.Close()
+	fields := []zap.Field{zap.String("user", "bob\n2026-10-01T00:00:00Z\tINFO\tforged")}
+	entry.logger.With(zap.String("ua", "curl\r\n")).Info("login\nfailed", append(fields, zap.Error(errors.New("bad\x00input")))...)
+
+	data, _ := os.ReadFile(filepath.Join(dir, "access.log"))
+	if lines := strings.Count(string(data), "\n"); lines != 1 {
+		t.Fatalf("expected a single line, got %d: %q", lines, data)
+	}
+	// console编码的字段部分为JSON，转义符本身再次转义
+	for _, want := range []string{`login\nfailed`, `bob\\n2026-10-01T00:00:00Z\\tINFO\\tforged`, `curl\\r\\n`, `bad\\x00input`} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("missing %q in %q", want, data)
+		}
+	}
+	if fields[0].String != "bob\n2026-10-01T00:00:00Z\tINFO\tforged" {
+		t.Fatal("caller's fields should not be modified")
+	}
+}
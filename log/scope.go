@@ -0,0 +1,94 @@
+package log
+
+import (
+	"context"
+	"slices"
+	"sync/atomic"
+
+	"go.uber.org/zap"
+)
+
+// scope 字段的作用域，关闭后其上的字段不再输出
+type scope struct {
+	name   string
+	parent *scope
+	closed atomic.Bool
+}
+
+// active 作用域及其所有上级都未关闭
+func (s *scope) active() bool {
+	for ; s != nil; s = s.parent {
+		if s.closed.Load() {
+			return false
+		}
+	}
+	return true
+}
+
+// ctxFields ctx上的字段链表，后添加的在前
+type ctxFields struct {
+	fields []zap.Field
+	scope  *scope
+	prev   *ctxFields
+}
+
+type (
+	scopeKey  struct{}
+	fieldsKey struct{}
+)
+
+// OpenScope 在ctx上开启名为name的作用域（如request、transaction），返回的函数用于关闭。
+// 作用域关闭后，在其中添加的字段不再出现在FromContext返回的logger中，即使ctx仍被其他goroutine持有；
+// 关闭上级作用域同时关闭所有下级作用域
+func OpenScope(ctx context.Context, name string) (context.Context, func()) {
+	parent, _ := ctx.Value(scopeKey{}).(*scope)
+	s := &scope{name: name, parent: parent}
+	return context.WithValue(ctx, scopeKey{}, s), func() { s.closed.Store(true) }
+}
+
+// WithFields 在ctx上添加字段，字段归属于最内层的作用域，没有作用域时一直有效
+func WithFields(ctx context.Context, fields ...zap.Field) context.Context {
+	s, _ := ctx.Value(scopeKey{}).(*scope)
+	return withFields(ctx, s, fields)
+}
+
+// WithScopeFields 在ctx上添加字段，字段归属于名为name的最内层作用域，找不到时归属于最内层作用域。
+// 用于在事务等内层作用域中添加应随外层请求作用域存续的字段
+func WithScopeFields(ctx context.Context, name string, fields ...zap.Field) context.Context {
+	inner, _ := ctx.Value(scopeKey{}).(*scope)
+	for s := inner; s != nil; s = s.parent {
+		if s.name == name {
+			return withFields(ctx, s, fields)
+		}
+	}
+	return withFields(ctx, inner, fields)
+}
+
+func withFields(ctx context.Context, s *scope, fields []zap.Field) context.Context {
+	prev, _ := ctx.Value(fieldsKey{}).(*ctxFields)
+	return context.WithValue(ctx, fieldsKey{}, &ctxFields{fields: fields, scope: s, prev: prev})
+}
+
+// ContextFields 返回ctx上仍处于有效作用域内的字段，按添加顺序排列，之后是MDC字段
+func ContextFields(ctx context.Context) []zap.Field {
+	var sets [][]zap.Field
+	for f, _ := ctx.Value(fieldsKey{}).(*ctxFields); f != nil; f = f.prev {
+		if f.scope.active() {
+			sets = append(sets, f.fields)
+		}
+	}
+	slices.Reverse(sets)
+	return slices.Concat(append(sets, mdcFields(ctx))...)
+}
+
+// FromContext 返回附加了ctx上有效字段的指定logger，ctx上有BufferRequest开启的缓冲时返回缓冲logger
+func FromContext(ctx context.Context, name string) *zap.Logger {
+	logger, ok := bufferedLogger(ctx, name)
+	if !ok {
+		logger = GetLogger(name)
+	}
+	if fields := ContextFields(ctx); len(fields) > 0 {
+		return logger.With(fields...)
+	}
+	return logger
+}
@@ -0,0 +1,49 @@
+package log
+
+import (
+	"context"
+	"testing"
+
+	"go.uber.org/zap"
+)
+
+func fieldKeys(fields []zap.Field) []string {
+	keys := make([]string, len(fields))
+	for i, f := range fields {
+		keys[i] = f.Key
+	}
+	return keys
+}
+
+func TestScopedFields(t *testing.T) {
+	ctx := WithFields(context.Background(), zap.String("service", "api"))
+
+	reqCtx, endRequest := OpenScope(ctx, "request")
+	reqCtx = WithFields(reqCtx, zap.String("request_id", "r1"))
+
+	txCtx, endTx := OpenScope(reqCtx, "transaction")
+	txCtx = WithFields(txCtx, zap.Int("attempt", 2))
+	txCtx = WithScopeFields(txCtx, "request", zap.String("user", "u1"))
+
+	if got := fieldKeys(ContextFields(txCtx)); len(got) != 4 {
+		t.Fatalf("unexpected fields %v", got)
+	}
+
+	endTx()
+	got := fieldKeys(ContextFields(txCtx))
+	if len(got) != 3 || got[0] != "service" || got[1] != "request_id" || got[2] != "user" {
+		t.Fatalf("transaction fields should fall away, got %v", got)
+	}
+
+	endRequest()
+	got = fieldKeys(ContextFields(txCtx))
+	if len(got) != 1 || got[0] != "service" {
+		t.Fatalf("request fields should fall away, got %v", got)
+	}
+
+	logs := observeLogger(t, "scope")
+	FromContext(txCtx, "scope").Info("after request")
+	if fields := logs.AllUntimed()[0].ContextMap(); len(fields) != 1 || fields["service"] != "api" {
+		t.Fatalf("unexpected fields %v", fields)
+	}
+}
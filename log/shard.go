@@ -0,0 +1,170 @@
+package log
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+
+	"go.uber.org/zap/zapcore"
+	"gopkg.in/natefinch/lumberjack.v2"
+)
+
+// shardLevels 按级别分文件时的分片，error分片同时包含dpanic、panic和fatal
+var shardLevels = []zapcore.Level{zapcore.DebugLevel, zapcore.InfoLevel, zapcore.WarnLevel, zapcore.ErrorLevel}
+
+// shardFileName 返回分片的文件名，如 app.log 的info分片为 app.info.log
+func shardFileName(filename string, level zapcore.Level) string {
+	ext := filepath.Ext(filename)
+	return strings.TrimSuffix(filename, ext) + "." + level.String() + ext
+}
+
+// levelShard 一个级别的分片文件
+type levelShard struct {
+	level  zapcore.Level
+	writer *lumberjack.Logger
+	ws     zapcore.WriteSyncer
+}
+
+// shardedOutput 按级别分文件的logger的输出，日志写入所属级别的分片文件，同时写入标准输出和sink。
+// 直接调用Write时无法区分级别，只写入标准输出和sink
+type shardedOutput struct {
+	zapcore.WriteSyncer
+	shards []*levelShard
+}
+
+// newShardedOutput 创建各级别的分片文件，track记录分片文件的健康状态，sinks返回同样记录了状态的sink
+func newShardedOutput(cfg LogConfig, faults *atomic.Pointer[DiskFaults],
+	track func(string, zapcore.WriteSyncer) zapcore.WriteSyncer, sinks func() []zapcore.WriteSyncer) *shardedOutput {
+	out := &shardedOutput{}
+	for _, level := range shardLevels {
+		lc := cfg
+		lc.FileName = shardFileName(cfg.FileName, level)
+		if days, ok := cfg.ShardMaxAge[level.String()]; ok {
+			lc.MaxAge = days
+		}
+		writer := newFileWriter(lc)
+		out.shards = append(out.shards, &levelShard{
+			level:  level,
+			writer: writer,
+			ws:     track("file:"+lc.FileName, zapcore.AddSync(&faultWriter{Writer: writer, path: lc.FileName, faults: faults})),
+		})
+	}
+	out.WriteSyncer = getStdoutWriteSyncer(sinks()...)
+	return out
+}
+
+func (o *shardedOutput) Sync() error {
+	errs := []error{o.WriteSyncer.Sync()}
+	for _, s := range o.shards {
+		errs = append(errs, s.ws.Sync())
+	}
+	return errors.Join(errs...)
+}
+
+// core 为每个分片创建core，共用编码器和级别
+func (o *shardedOutput) core(encoder zapcore.Encoder, level zapcore.LevelEnabler) zapcore.Core {
+	c := &shardCore{LevelEnabler: level}
+	for _, s := range o.shards {
+		c.cores = append(c.cores, zapcore.NewCore(encoder, zapcore.NewMultiWriteSyncer(s.ws, o.WriteSyncer), level))
+	}
+	return c
+}
+
+// shardCore 按日志级别写入对应分片的core。外层core包装后会直接调用Write，因此在Write中选择分片
+type shardCore struct {
+	zapcore.LevelEnabler
+	cores []zapcore.Core // 与shardLevels一一对应
+}
+
+// shard 返回级别所属分片的core，error及以上级别都属于error分片
+func (c *shardCore) shard(level zapcore.Level) zapcore.Core {
+	for i := len(shardLevels) - 1; i > 0; i-- {
+		if level >= shardLevels[i] {
+			return c.cores[i]
+		}
+	}
+	return c.cores[0]
+}
+
+func (c *shardCore) With(fields []zapcore.Field) zapcore.Core {
+	clone := &shardCore{LevelEnabler: c.LevelEnabler, cores: make([]zapcore.Core, len(c.cores))}
+	for i, core := range c.cores {
+		clone.cores[i] = core.With(fields)
+	}
+	return clone
+}
+
+func (c *shardCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+func (c *shardCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	return c.shard(ent.Level).Write(ent, fields)
+}
+
+func (c *shardCore) Sync() error {
+	var errs []error
+	for _, core := range c.cores {
+		errs = append(errs, core.Sync())
+	}
+	return errors.Join(errs...)
+}
+
+func validateShards(lc LogConfig) []error {
+	var errs []error
+	if !lc.ShardByLevel {
+		if len(lc.ShardMaxAge) > 0 {
+			errs = append(errs, fmt.Errorf("logger %s: shard_max_age requires shard_by_level", lc.Name))
+		}
+		return errs
+	}
+	if lc.OrderedTee || lc.Audit {
+		errs = append(errs, fmt.Errorf("logger %s: shard_by_level cannot be combined with ordered_tee or audit", lc.Name))
+	}
+	for level, days := range lc.ShardMaxAge {
+		switch level {
+		case "debug", "info", "warn", "error":
+		default:
+			errs = append(errs, fmt.Errorf("logger %s: invalid shard_max_age level %q", lc.Name, level))
+		}
+		if days < 0 {
+			errs = append(errs, fmt.Errorf("logger %s: shard_max_age must not be negative", lc.Name))
+		}
+	}
+	return errs
+}
+
+// isSharded logger是否按级别分文件
+func isSharded(e *logEntry) bool {
+	_, ok := e.ws.(*shardedOutput)
+	return ok
+}
+
+// files 返回logger的所有日志文件，按级别分文件时为各分片，禁用或测试替换的logger没有日志文件
+func (e *logEntry) files() []*lumberjack.Logger {
+	if out, ok := e.ws.(*shardedOutput); ok {
+		files := make([]*lumberjack.Logger, 0, len(out.shards))
+		for _, s := range out.shards {
+			files = append(files, s.writer)
+		}
+		return files
+	}
+	if e.writer == nil {
+		return nil
+	}
+	return []*lumberjack.Logger{e.writer}
+}
+
+// fileNames 返回logger所有日志文件的路径
+func (e *logEntry) fileNames() []string {
+	var names []string
+	for _, f := range e.files() {
+		names = append(names, f.Filename)
+	}
+	return names
+}
@@ -0,0 +1,82 @@
+package log
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"go.uber.org/zap"
+)
+
+func TestShardByLevel(t *testing.T) {
+	dir := t.TempDir()
+	entry, err := newLogger(LogConfig{
+		Name:         "app",
+		Level:        "debug",
+		FileName:     filepath.Join(dir, "app.log"),
+		Encoder:      "json",
+		MaxAge:       7,
+		ShardByLevel: true,
+		ShardMaxAge:  map[string]int{"debug": 1, "error": 90},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	logger := entry.logger.With(zap.String("svc", "checkout"))
+	logger.Debug("cache miss")
+	logger.Info("order created")
+	logger.Warn("slow payment")
+	logger.Error("charge failed")
+	logger.DPanic("invariant broken")
+	Module("unused") // 确保未初始化的注册表不影响分片logger
+
+	files := entry.files()
+	if len(files) != 4 || files[0].MaxAge != 1 || files[1].MaxAge != 7 || files[3].MaxAge != 90 {
+		t.Fatalf("unexpected shard retention %+v", files)
+	}
+	for _, f := range files {
+		_ = f.Close()
+	}
+
+	want := map[string][]string{
+		"app.debug.log": {"cache miss"},
+		"app.info.log":  {"order created"},
+		"app.warn.log":  {"slow payment"},
+		"app.error.log": {"charge failed", "invariant broken"},
+	}
+	for name, msgs := range want {
+		data, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+		if len(lines) != len(msgs) {
+			t.Fatalf("%s: expected %d lines, got %q", name, len(msgs), data)
+		}
+		for i, msg := range msgs {
+			if !strings.Contains(lines[i], `"msg":"`+msg+`"`) || !strings.Contains(lines[i], `"svc":"checkout"`) {
+				t.Fatalf("%s: unexpected line %s", name, lines[i])
+			}
+		}
+	}
+	if _, err := os.Stat(filepath.Join(dir, "app.log")); !os.IsNotExist(err) {
+		t.Fatal("unsharded file should not be created")
+	}
+}
+
+func TestShardConfig(t *testing.T) {
+	if got := shardFileName("/var/log/app.log", zap.WarnLevel); got != "/var/log/app.warn.log" {
+		t.Fatalf("unexpected shard name %s", got)
+	}
+	cfg := Config{Zaplog: []LogConfig{
+		{Name: "default", FileName: "a.log", ShardByLevel: true, OrderedTee: true, ShardMaxAge: map[string]int{"trace": 1}},
+		{Name: "other", FileName: "b.log", ShardMaxAge: map[string]int{"debug": 1}},
+	}}
+	err := validateConfig(&cfg)
+	for _, want := range []string{"cannot be combined", `invalid shard_max_age level "trace"`, "shard_max_age requires shard_by_level"} {
+		if err == nil || !strings.Contains(err.Error(), want) {
+			t.Fatalf("expected %q, got %v", want, err)
+		}
+	}
+}
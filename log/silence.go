@@ -0,0 +1,120 @@
+package log
+
+import (
+	"sync/atomic"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// SilenceWindow 静默时段，时段内相关logger只输出error及以上级别，结束后自动恢复
+type SilenceWindow struct {
+	Cron     string        `yaml:"cron" mapstructure:"cron"`         // 开始时间，标准5段cron表达式，如 "0 2 * * *"
+	Duration time.Duration `yaml:"duration" mapstructure:"duration"` // 持续时长，如 2h
+	Loggers  []string      `yaml:"loggers" mapstructure:"loggers"`   // 生效的logger，为空时对所有logger生效
+}
+
+// silenceEnabler 静默时只放行error及以上级别，同时受磁盘配额限制
+type silenceEnabler struct {
+	zapcore.LevelEnabler
+	silenced *atomic.Bool
+}
+
+func (e silenceEnabler) Enabled(level zapcore.Level) bool {
+	if level < zapcore.ErrorLevel && e.silenced.Load() {
+		return false
+	}
+	if !quotaAllows(level) {
+		return false
+	}
+	return e.LevelEnabler.Enabled(level)
+}
+
+type silenceSchedule struct {
+	window   SilenceWindow
+	schedule *cronSchedule
+}
+
+var (
+	silenceStop    chan struct{}
+	silenceWindows []SilenceWindow
+)
+
+// startSilenceWindows 启动静默时段调度，调用方需持有metux写锁
+func startSilenceWindows(windows []SilenceWindow) {
+	stopSilenceWindows()
+	if len(windows) == 0 {
+		return
+	}
+	silenceWindows = windows
+
+	schedules := make([]silenceSchedule, 0, len(windows))
+	for _, w := range windows {
+		s, err := parseCron(w.Cron)
+		if err != nil {
+			continue
+		}
+		schedules = append(schedules, silenceSchedule{window: w, schedule: s})
+	}
+	applySilence(schedules, time.Now())
+
+	stop := make(chan struct{})
+	silenceStop = stop
+	go func() {
+		ticker := time.NewTicker(time.Minute)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-stop:
+				return
+			case now := <-ticker.C:
+				metux.RLock()
+				applySilence(schedules, now)
+				metux.RUnlock()
+			}
+		}
+	}()
+}
+
+// stopSilenceWindows 停止调度并恢复所有logger，调用方需持有metux写锁
+func stopSilenceWindows() {
+	if silenceStop != nil {
+		close(silenceStop)
+		silenceStop = nil
+	}
+	silenceWindows = nil
+	for _, entry := range loggers {
+		entry.silenced.Store(false)
+	}
+}
+
+// applySilence 按当前时间设置各logger的静默状态，调用方需持有metux读锁
+func applySilence(schedules []silenceSchedule, now time.Time) {
+	silenced := make(map[string]bool)
+	all := false
+	for _, s := range schedules {
+		if !s.active(now) {
+			continue
+		}
+		if len(s.window.Loggers) == 0 {
+			all = true
+		}
+		for _, name := range s.window.Loggers {
+			silenced[name] = true
+		}
+	}
+	for name, entry := range loggers {
+		entry.silenced.Store(all || silenced[name])
+	}
+}
+
+// active now是否处于某次开始后的duration之内
+func (s silenceSchedule) active(now time.Time) bool {
+	start := now.Truncate(time.Minute)
+	for t := start; now.Sub(t) < s.window.Duration; t = t.Add(-time.Minute) {
+		if s.schedule.matches(t) {
+			return true
+		}
+	}
+	return false
+}
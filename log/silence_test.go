@@ -0,0 +1,40 @@
+package log
+
+import (
+	"testing"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+func TestSilenceWindows(t *testing.T) {
+	initTestLoggers(t, "access", "cron")
+
+	s, err := parseCron("0 2 * * *")
+	if err != nil {
+		t.Fatal(err)
+	}
+	schedules := []silenceSchedule{{
+		window:   SilenceWindow{Cron: "0 2 * * *", Duration: 2 * time.Hour, Loggers: []string{"access"}},
+		schedule: s,
+	}}
+	day := time.Date(2026, 10, 16, 0, 0, 0, 0, time.Local)
+
+	metux.RLock()
+	applySilence(schedules, day.Add(3*time.Hour))
+	metux.RUnlock()
+	access := GetLogger("access").Core()
+	if access.Enabled(zapcore.WarnLevel) || !access.Enabled(zapcore.ErrorLevel) {
+		t.Fatal("access should be error-only during the window")
+	}
+	if !GetLogger("cron").Core().Enabled(zapcore.InfoLevel) {
+		t.Fatal("cron should not be silenced")
+	}
+
+	metux.RLock()
+	applySilence(schedules, day.Add(4*time.Hour))
+	metux.RUnlock()
+	if !access.Enabled(zapcore.InfoLevel) {
+		t.Fatal("access should be restored after the window")
+	}
+}
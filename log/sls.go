@@ -0,0 +1,178 @@
+package log
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/md5"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// PostLogStoreLogs的限制：单次最多4096条、5MB
+const (
+	slsMaxBatchLogs  = 4096
+	slsMaxBatchBytes = 5 * 1024 * 1024
+	slsAPIVersion    = "0.6.0"
+)
+
+// slsSink 通过PostLogStoreLogs接口以protobuf批量写入阿里云日志服务SLS，json日志行的各字段对应SLS的字段。
+// options：endpoint(如cn-hangzhou.log.aliyuncs.com，必填)、project、logstore(必填)、topic、source(默认主机名)、
+// access_key_id、access_key_secret、security_token(默认读取ALIBABA_CLOUD_ACCESS_KEY_ID等环境变量)、
+// flush_interval(默认5s)、max_buffer(默认100000条)
+type slsSink struct {
+	*cloudBatcher
+
+	baseURL       string // https://<project>.<endpoint>
+	logstore      string
+	topic, source string
+	logger        string
+	accessKeyID   string
+	accessSecret  string
+	securityToken string
+	client        *http.Client
+}
+
+func newSLSSink(logger string, options map[string]any) (zapcore.WriteSyncer, error) {
+	project, logstore := sinkString(options, "project", ""), sinkString(options, "logstore", "")
+	if project == "" || logstore == "" {
+		return nil, errors.New("sls: project and logstore are required")
+	}
+	u, err := cloudEndpoint(sinkString(options, "endpoint", ""))
+	if err != nil {
+		return nil, fmt.Errorf("sls: %w", err)
+	}
+	u.Host = project + "." + u.Host
+
+	s := &slsSink{
+		baseURL:       u.String(),
+		logstore:      logstore,
+		topic:         sinkString(options, "topic", ""),
+		logger:        logger,
+		accessKeyID:   envOr(sinkString(options, "access_key_id", ""), "ALIBABA_CLOUD_ACCESS_KEY_ID", "ALICLOUD_ACCESS_KEY"),
+		accessSecret:  envOr(sinkString(options, "access_key_secret", ""), "ALIBABA_CLOUD_ACCESS_KEY_SECRET", "ALICLOUD_SECRET_KEY"),
+		securityToken: envOr(sinkString(options, "security_token", ""), "ALIBABA_CLOUD_SECURITY_TOKEN"),
+		client:        &http.Client{Timeout: cloudRequestTimeout},
+	}
+	hostname, _ := os.Hostname()
+	s.source = sinkString(options, "source", hostname)
+	if s.accessKeyID == "" || s.accessSecret == "" {
+		return nil, errors.New("sls: access_key_id and access_key_secret are required")
+	}
+	opts, err := parseCloudBatchOptions("sls", options)
+	if err != nil {
+		return nil, err
+	}
+	s.cloudBatcher = newCloudBatcher("sls", logger, slsMaxBatchLogs, slsMaxBatchBytes, opts, s.send)
+	return s, nil
+}
+
+// encodeSLSLogGroup 按SLS的LogGroup定义编码：
+// Log{Time=1 uint32, Contents=2 {Key=1, Value=2}, TimeNs=4 fixed32}，LogGroup{Logs=1, Topic=3, Source=4, LogTags=6 {Key=1, Value=2}}
+func encodeSLSLogGroup(logs []cloudLog, topic, source string, tags [][2]string) []byte {
+	var group []byte
+	for _, l := range logs {
+		var entry []byte
+		entry = protowire.AppendTag(entry, 1, protowire.VarintType)
+		entry = protowire.AppendVarint(entry, uint64(l.time.Unix()))
+		for _, c := range l.contents {
+			entry = protowire.AppendTag(entry, 2, protowire.BytesType)
+			entry = protowire.AppendBytes(entry, encodeProtoPair(c))
+		}
+		entry = protowire.AppendTag(entry, 4, protowire.Fixed32Type)
+		entry = protowire.AppendFixed32(entry, uint32(l.time.Nanosecond()))
+		group = protowire.AppendTag(group, 1, protowire.BytesType)
+		group = protowire.AppendBytes(group, entry)
+	}
+	if topic != "" {
+		group = protowire.AppendTag(group, 3, protowire.BytesType)
+		group = protowire.AppendString(group, topic)
+	}
+	if source != "" {
+		group = protowire.AppendTag(group, 4, protowire.BytesType)
+		group = protowire.AppendString(group, source)
+	}
+	for _, tag := range tags {
+		group = protowire.AppendTag(group, 6, protowire.BytesType)
+		group = protowire.AppendBytes(group, encodeProtoPair(tag))
+	}
+	return group
+}
+
+// encodeProtoPair 编码{key=1, value=2}形式的消息
+func encodeProtoPair(kv [2]string) []byte {
+	var b []byte
+	b = protowire.AppendTag(b, 1, protowire.BytesType)
+	b = protowire.AppendString(b, kv[0])
+	b = protowire.AppendTag(b, 2, protowire.BytesType)
+	b = protowire.AppendString(b, kv[1])
+	return b
+}
+
+func (s *slsSink) send(ctx context.Context, logs []cloudLog) error {
+	body := encodeSLSLogGroup(logs, s.topic, s.source, [][2]string{{"__logger__", s.logger}})
+	resource := "/logstores/" + s.logstore + "/shards/lb"
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.baseURL+resource, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	sum := md5.Sum(body)
+	req.Header.Set("Content-Type", "application/x-protobuf")
+	req.Header.Set("Content-MD5", strings.ToUpper(hex.EncodeToString(sum[:])))
+	req.Header.Set("Date", time.Now().UTC().Format(http.TimeFormat))
+	req.Header.Set("x-log-apiversion", slsAPIVersion)
+	req.Header.Set("x-log-signaturemethod", "hmac-sha1")
+	req.Header.Set("x-log-bodyrawsize", strconv.Itoa(len(body)))
+	if s.securityToken != "" {
+		req.Header.Set("x-acs-security-token", s.securityToken)
+	}
+	req.Header.Set("Authorization", "LOG "+s.accessKeyID+":"+signSLS(req, resource, s.accessSecret))
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("sls: post logs to %s: unexpected status %s: %s", s.logstore, resp.Status, bytes.TrimSpace(msg))
+	}
+	return nil
+}
+
+// signSLS 计算SLS请求签名：
+// base64(hmac-sha1(VERB\nContent-MD5\nContent-Type\nDate\n排序后的x-log-、x-acs-头\n资源路径))
+func signSLS(req *http.Request, resource, secret string) string {
+	var headers []string
+	for key, values := range req.Header {
+		key = strings.ToLower(key)
+		if strings.HasPrefix(key, "x-log-") || strings.HasPrefix(key, "x-acs-") {
+			headers = append(headers, key+":"+values[0])
+		}
+	}
+	sort.Strings(headers)
+	toSign := strings.Join([]string{
+		req.Method,
+		req.Header.Get("Content-MD5"),
+		req.Header.Get("Content-Type"),
+		req.Header.Get("Date"),
+		strings.Join(headers, "\n"),
+		resource,
+	}, "\n")
+	mac := hmac.New(sha1.New, []byte(secret))
+	mac.Write([]byte(toSign))
+	return base64.StdEncoding.EncodeToString(mac.Sum(nil))
+}
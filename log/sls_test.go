@@ -0,0 +1,89 @@
+package log
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSLSSink(t *testing.T) {
+	var body []byte
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		resource := "/logstores/app/shards/lb"
+		if r.URL.Path != resource {
+			http.NotFound(w, r)
+			return
+		}
+		if got, want := r.Header.Get("Authorization"), "LOG ak:"+signSLS(r, resource, "secret"); got != want {
+			t.Errorf("Authorization = %q, want %q", got, want)
+		}
+		if r.Header.Get("x-acs-security-token") != "sts" || r.Header.Get("Content-MD5") == "" {
+			t.Errorf("missing headers: %v", r.Header)
+		}
+		body, _ = io.ReadAll(r.Body)
+	}))
+	defer srv.Close()
+
+	s := &slsSink{
+		baseURL:       srv.URL,
+		logstore:      "app",
+		topic:         "orders",
+		source:        "10.0.0.1",
+		logger:        "access",
+		accessKeyID:   "ak",
+		accessSecret:  "secret",
+		securityToken: "sts",
+		client:        srv.Client(),
+	}
+	s.cloudBatcher = newCloudBatcher("sls", "access", slsMaxBatchLogs, slsMaxBatchBytes, cloudBatchOptions{flushInterval: time.Hour, maxBuffer: 10}, s.send)
+	s.Write([]byte(`{"msg":"ok","status":200}` + "\n"))
+	if err := s.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	group := protoFields(t, body)
+	if len(group[1]) != 1 || string(group[3][0]) != "orders" || string(group[4][0]) != "10.0.0.1" {
+		t.Fatalf("unexpected log group %v", group)
+	}
+	if tag := protoFields(t, group[6][0]); string(tag[1][0]) != "__logger__" || string(tag[2][0]) != "access" {
+		t.Fatalf("unexpected tag %v", tag)
+	}
+	entry := protoFields(t, group[1][0])
+	if sec := protoVarint(t, entry[1][0]); time.Since(time.Unix(int64(sec), 0)) > time.Minute {
+		t.Fatalf("unexpected time %d", sec)
+	}
+	if len(entry[2]) != 2 || len(entry[4]) != 1 {
+		t.Fatalf("unexpected log %v", entry)
+	}
+	if c := protoFields(t, entry[2][1]); string(c[1][0]) != "status" || string(c[2][0]) != "200" {
+		t.Fatalf("unexpected content %v", c)
+	}
+}
+
+func TestSLSSinkErrors(t *testing.T) {
+	t.Setenv("ALIBABA_CLOUD_ACCESS_KEY_ID", "")
+	t.Setenv("ALIBABA_CLOUD_ACCESS_KEY_SECRET", "")
+	t.Setenv("ALICLOUD_ACCESS_KEY", "")
+	t.Setenv("ALICLOUD_SECRET_KEY", "")
+	for _, options := range []map[string]any{
+		{"endpoint": "cn-hangzhou.log.aliyuncs.com", "project": "p"},
+		{"endpoint": "cn-hangzhou.log.aliyuncs.com", "project": "p", "logstore": "app"},
+	} {
+		if _, err := newSLSSink("access", options); err == nil || !strings.HasPrefix(err.Error(), "sls:") {
+			t.Errorf("expected error for %v, got %v", options, err)
+		}
+	}
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, `{"errorCode":"Unauthorized"}`, http.StatusUnauthorized)
+	}))
+	defer srv.Close()
+	s := &slsSink{baseURL: srv.URL, logstore: "app", client: srv.Client()}
+	if err := s.send(context.Background(), []cloudLog{parseCloudLog([]byte("x"), time.Now())}); err == nil || !strings.Contains(err.Error(), "Unauthorized") {
+		t.Fatalf("expected server error, got %v", err)
+	}
+}
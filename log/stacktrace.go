@@ -0,0 +1,64 @@
+package log
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// trimStack 限制堆栈的帧数并去除文件路径前缀。
+// zap的堆栈每帧两行：函数名和以制表符开头的"文件:行号"
+func trimStack(stack string, maxFrames int, prefix string) string {
+	if stack == "" {
+		return stack
+	}
+	if prefix != "" {
+		prefix = strings.TrimSuffix(filepath.ToSlash(prefix), "/") + "/"
+	}
+
+	lines := strings.Split(stack, "\n")
+	frames := (len(lines) + 1) / 2
+	if maxFrames > 0 && frames > maxFrames {
+		lines = lines[:maxFrames*2]
+	}
+	if prefix != "" {
+		for i, line := range lines {
+			if strings.HasPrefix(line, "\t") {
+				lines[i] = "\t" + strings.TrimPrefix(line[1:], prefix)
+			}
+		}
+	}
+	if maxFrames > 0 && frames > maxFrames {
+		lines = append(lines, fmt.Sprintf("\t... %d more frames", frames-maxFrames))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// stackCore 截断并精简Entry.Stack
+type stackCore struct {
+	zapcore.Core
+	maxFrames int
+	prefix    string
+}
+
+func newStackCore(core zapcore.Core, maxFrames int, prefix string) zapcore.Core {
+	return &stackCore{Core: core, maxFrames: maxFrames, prefix: prefix}
+}
+
+func (c *stackCore) With(fields []zapcore.Field) zapcore.Core {
+	return &stackCore{Core: c.Core.With(fields), maxFrames: c.maxFrames, prefix: c.prefix}
+}
+
+func (c *stackCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+func (c *stackCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	ent.Stack = trimStack(ent.Stack, c.maxFrames, c.prefix)
+	return c.Core.Write(ent, fields)
+}
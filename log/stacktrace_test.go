@@ -0,0 +1,32 @@
+package log
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestTrimStack(t *testing.T) {
+	stack := strings.Join([]string{
+		"github.com/acme/app/svc.(*Server).handle",
+		"\t/home/ci/src/acme/app/svc/server.go:42",
+		"github.com/acme/app/svc.Run",
+		"\t/home/ci/src/acme/app/svc/run.go:10",
+		"main.main",
+		"\t/home/ci/src/acme/app/main.go:7",
+	}, "\n")
+
+	got := trimStack(stack, 2, "/home/ci/src/acme/")
+	want := strings.Join([]string{
+		"github.com/acme/app/svc.(*Server).handle",
+		"\tapp/svc/server.go:42",
+		"github.com/acme/app/svc.Run",
+		"\tapp/svc/run.go:10",
+		"\t... 1 more frames",
+	}, "\n")
+	if got != want {
+		t.Fatalf("unexpected stack\n got: %s\nwant: %s", got, want)
+	}
+	if got := trimStack(stack, 0, ""); got != stack {
+		t.Fatalf("stack should be unchanged, got %s", got)
+	}
+}
@@ -0,0 +1,58 @@
+package log
+
+import (
+	"sort"
+	"sync/atomic"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// LoggerStats logger的运行统计
+type LoggerStats struct {
+	Name    string            `json:"name"`    // 日志名称
+	Level   string            `json:"level"`   // 当前日志级别
+	Entries map[string]uint64 `json:"entries"` // 各级别已输出的日志条数
+	Sampled uint64            `json:"sampled"` // 被采样丢弃的日志条数
+}
+
+// levelCounter 按级别统计日志条数
+type levelCounter struct {
+	counts [zapcore.FatalLevel - zapcore.DebugLevel + 1]atomic.Uint64
+}
+
+func newLevelCounter() *levelCounter {
+	return &levelCounter{}
+}
+
+func (c *levelCounter) hook(e zapcore.Entry) error {
+	if e.Level >= zapcore.DebugLevel && e.Level <= zapcore.FatalLevel {
+		c.counts[e.Level-zapcore.DebugLevel].Add(1)
+	}
+	return nil
+}
+
+func (c *levelCounter) snapshot() map[string]uint64 {
+	m := make(map[string]uint64, len(c.counts))
+	for i := range c.counts {
+		m[(zapcore.DebugLevel + zapcore.Level(i)).String()] = c.counts[i].Load()
+	}
+	return m
+}
+
+// Stats 返回所有logger的运行统计，按名称排序
+func Stats() []LoggerStats {
+	metux.RLock()
+	defer metux.RUnlock()
+
+	stats := make([]LoggerStats, 0, len(loggers))
+	for name, entry := range loggers {
+		stats = append(stats, LoggerStats{
+			Name:    name,
+			Level:   entry.level.Level().String(),
+			Entries: entry.stats.snapshot(),
+			Sampled: entry.sampled.Load(),
+		})
+	}
+	sort.Slice(stats, func(i, j int) bool { return stats[i].Name < stats[j].Name })
+	return stats
+}
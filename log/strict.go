@@ -0,0 +1,141 @@
+package log
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+)
+
+// checkUnknownKeys 检查配置中无法对应到Config字段的键，如将file_name写成filename，
+// 这类拼写错误不会导致解析失败，而是静默使用默认值。sinks、encryption的options等自由格式的参数不检查
+func checkUnknownKeys(settings map[string]any) []error {
+	return unknownKeys(settings, reflect.TypeOf(Config{}), "")
+}
+
+func unknownKeys(settings map[string]any, typ reflect.Type, path string) []error {
+	fields := configFields(typ)
+	keys := make([]string, 0, len(settings))
+	for key := range settings {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var errs []error
+	for _, key := range keys {
+		ft, ok := fields[strings.ToLower(key)]
+		if !ok {
+			names := make([]string, 0, len(fields))
+			for name := range fields {
+				names = append(names, name)
+			}
+			errs = append(errs, fmt.Errorf("unknown key %q%s", path+key, didYouMean(key, names)))
+			continue
+		}
+		errs = append(errs, unknownNested(settings[key], ft, path+key)...)
+	}
+	return errs
+}
+
+// unknownNested 检查结构体与结构体切片类型字段的下一层
+func unknownNested(value any, typ reflect.Type, path string) []error {
+	switch {
+	case typ.Kind() == reflect.Struct && typ != reflect.TypeOf(time.Time{}):
+		if m, ok := value.(map[string]any); ok {
+			return unknownKeys(m, typ, path+".")
+		}
+	case typ.Kind() == reflect.Slice && typ.Elem().Kind() == reflect.Struct:
+		var errs []error
+		for i, item := range sliceItems(value) {
+			m, ok := item.(map[string]any)
+			if !ok {
+				continue
+			}
+			// 以name标识列表项，便于定位
+			index := fmt.Sprint(i)
+			if name, ok := m["name"].(string); ok && name != "" {
+				index = name
+			}
+			errs = append(errs, unknownKeys(m, typ.Elem(), fmt.Sprintf("%s[%s].", path, index))...)
+		}
+		return errs
+	}
+	return nil
+}
+
+func sliceItems(value any) []any {
+	switch v := value.(type) {
+	case []any:
+		return v
+	case []map[string]any:
+		items := make([]any, len(v))
+		for i, m := range v {
+			items[i] = m
+		}
+		return items
+	}
+	return nil
+}
+
+// configFields 返回结构体字段的配置键与类型，键为mapstructure标签，未设置时为小写的字段名
+func configFields(typ reflect.Type) map[string]reflect.Type {
+	fields := make(map[string]reflect.Type, typ.NumField())
+	for i := 0; i < typ.NumField(); i++ {
+		f := typ.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+		key := f.Tag.Get("mapstructure")
+		if key == "-" {
+			continue
+		}
+		if key == "" {
+			key = strings.ToLower(f.Name)
+		}
+		fields[key] = f.Type
+	}
+	return fields
+}
+
+// didYouMean 返回与input最接近的候选项提示，没有足够接近的候选项时返回空字符串
+func didYouMean(input string, candidates []string) string {
+	if s := suggest(input, candidates); s != "" {
+		return fmt.Sprintf(", did you mean %q?", s)
+	}
+	return ""
+}
+
+// suggest 忽略大小写、下划线与连字符后比较编辑距离，距离不超过名称长度的三分之一(至少2)时视为拼写错误；
+// 一方是另一方的前缀时同样视为匹配，如warning与warn
+func suggest(input string, candidates []string) string {
+	normalize := func(s string) string {
+		return strings.NewReplacer("_", "", "-", "").Replace(strings.ToLower(s))
+	}
+	in := normalize(input)
+	if in == "" {
+		return ""
+	}
+	best, bestDist := "", -1
+	sorted := append([]string(nil), candidates...)
+	sort.Strings(sorted)
+	for _, c := range sorted {
+		cn := normalize(c)
+		d := editDistance(in, cn)
+		if d > max(2, len(cn)/3) && !strings.HasPrefix(in, cn) && !strings.HasPrefix(cn, in) {
+			continue
+		}
+		if bestDist < 0 || d < bestDist {
+			best, bestDist = c, d
+		}
+	}
+	return best
+}
+
+// levelNames 配置中可用的级别
+var levelNames = []string{"debug", "info", "warn", "error", "panic", "fatal"}
+
+// invalidLevel 返回无效级别的错误，附带最接近的级别提示
+func invalidLevel(level string) error {
+	return fmt.Errorf("invalid level %q%s", level, didYouMean(level, levelNames))
+}
@@ -0,0 +1,64 @@
+package log
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestUnknownConfigKeys(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "log_config.yaml")
+	config := `zaplog:
+  - name: default
+    filename: app.log
+    level: info
+    sinks:
+      - type: journald
+        options: {anything: goes}
+    archive:
+      bukket: logs
+  - name: access
+    maxsize: 10
+panicfile: ./panic.log
+colour: true
+`
+	if err := os.WriteFile(path, []byte(config), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	_, err := LoadConfig(path)
+	if err == nil {
+		t.Fatal("expected unknown key errors")
+	}
+	for _, want := range []string{
+		`unknown key "zaplog[default].filename", did you mean "file_name"?`,
+		`unknown key "zaplog[default].archive.bukket", did you mean "bucket"?`,
+		`unknown key "zaplog[access].maxsize", did you mean "max_size"?`,
+		`unknown key "panicfile", did you mean "panic_file"?`,
+		`unknown key "colour"`,
+	} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("missing %q in:\n%v", want, err)
+		}
+	}
+	if strings.Contains(err.Error(), "anything") || strings.Contains(err.Error(), `"colour", did you mean`) {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestInvalidLevelSuggestion(t *testing.T) {
+	for level, want := range map[string]string{
+		"warning": `invalid level "warning", did you mean "warn"?`,
+		"eror":    `invalid level "eror", did you mean "error"?`,
+		"loud":    `invalid level "loud"`,
+	} {
+		if got := invalidLevel(level).Error(); got != want {
+			t.Errorf("invalidLevel(%q) = %q, want %q", level, got, want)
+		}
+	}
+	if err := validateConfig(&Config{Zaplog: []LogConfig{{Name: "default", Level: "debgu"}}}); err == nil ||
+		!strings.Contains(err.Error(), `logger default: invalid level "debgu", did you mean "debug"?`) {
+		t.Fatalf("unexpected error %v", err)
+	}
+}
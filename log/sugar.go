@@ -0,0 +1,98 @@
+package log
+
+import (
+	"fmt"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// Debugw 以debug级别记录到指定名称的logger，keysAndValues为交替的键值对，
+// 与zap.SugaredLogger.Debugw相同，也可以直接传入zap.Field
+func Debugw(name, msg string, keysAndValues ...interface{}) {
+	logw(GetLogger(name), zapcore.DebugLevel, msg, keysAndValues)
+}
+
+// Infow 以info级别记录到指定名称的logger，参数同Debugw
+func Infow(name, msg string, keysAndValues ...interface{}) {
+	logw(GetLogger(name), zapcore.InfoLevel, msg, keysAndValues)
+}
+
+// Warnw 以warn级别记录到指定名称的logger，参数同Debugw
+func Warnw(name, msg string, keysAndValues ...interface{}) {
+	logw(GetLogger(name), zapcore.WarnLevel, msg, keysAndValues)
+}
+
+// Errorw 以error级别记录到指定名称的logger，参数同Debugw
+func Errorw(name, msg string, keysAndValues ...interface{}) {
+	logw(GetLogger(name), zapcore.ErrorLevel, msg, keysAndValues)
+}
+
+// logw 级别未开启时不转换键值对，调用方位于两层之上
+func logw(logger *zap.Logger, level zapcore.Level, msg string, keysAndValues []interface{}) {
+	if !logger.Core().Enabled(level) {
+		return
+	}
+	if ce := logger.WithOptions(zap.AddCallerSkip(2)).Check(level, msg); ce != nil {
+		ce.Write(kvFields(keysAndValues)...)
+	}
+}
+
+// kvFields 将交替的键值对转换为字段，值按Any的规则编码，非字符串的键转为字符串，
+// 缺少值的键记录为 !BADKEY
+func kvFields(keysAndValues []interface{}) []zap.Field {
+	fields := make([]zap.Field, 0, (len(keysAndValues)+1)/2)
+	for i := 0; i < len(keysAndValues); {
+		if f, ok := keysAndValues[i].(zap.Field); ok {
+			fields = append(fields, f)
+			i++
+			continue
+		}
+		if i == len(keysAndValues)-1 {
+			fields = append(fields, Any("!BADKEY", keysAndValues[i]))
+			break
+		}
+		key, ok := keysAndValues[i].(string)
+		if !ok {
+			key = fmt.Sprint(keysAndValues[i])
+		}
+		fields = append(fields, Any(key, keysAndValues[i+1]))
+		i += 2
+	}
+	return fields
+}
+
+// KV 按名称选择logger的键值对风格记录器，调用方无需直接使用zap的类型
+type KV struct {
+	logger *zap.Logger
+}
+
+// Sugar 返回指定名称logger的键值对风格记录器，如果不存在，则使用default logger
+func Sugar(name string) KV {
+	return KV{logger: GetLogger(name)}
+}
+
+// With 返回附加了键值对的记录器
+func (k KV) With(keysAndValues ...interface{}) KV {
+	return KV{logger: k.logger.With(kvFields(keysAndValues)...)}
+}
+
+// Debugw 以debug级别记录
+func (k KV) Debugw(msg string, keysAndValues ...interface{}) {
+	logw(k.logger, zapcore.DebugLevel, msg, keysAndValues)
+}
+
+// Infow 以info级别记录
+func (k KV) Infow(msg string, keysAndValues ...interface{}) {
+	logw(k.logger, zapcore.InfoLevel, msg, keysAndValues)
+}
+
+// Warnw 以warn级别记录
+func (k KV) Warnw(msg string, keysAndValues ...interface{}) {
+	logw(k.logger, zapcore.WarnLevel, msg, keysAndValues)
+}
+
+// Errorw 以error级别记录
+func (k KV) Errorw(msg string, keysAndValues ...interface{}) {
+	logw(k.logger, zapcore.ErrorLevel, msg, keysAndValues)
+}
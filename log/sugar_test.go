@@ -0,0 +1,67 @@
+package log
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"go.uber.org/zap"
+)
+
+func TestInfow(t *testing.T) {
+	initTestLoggers(t, "order")
+	logs := observeLogger(t, "order")
+
+	Infow("order", "created", "id", 42, "amount", 9.5, zap.String("currency", "CNY"), 7, "seven", "dangling")
+	Sugar("order").With("tenant", "acme").Warnw("retrying", "attempt", 2)
+	Debugw("missing", "falls back to default")
+
+	entries := logs.All()
+	if len(entries) != 2 {
+		t.Fatalf("expected 2 entries, got %+v", entries)
+	}
+	fields := entries[0].ContextMap()
+	if fields["id"] != int64(42) || fields["amount"] != 9.5 || fields["currency"] != "CNY" ||
+		fields["7"] != "seven" || fields["!BADKEY"] != "dangling" {
+		t.Fatalf("unexpected fields %+v", fields)
+	}
+	fields = entries[1].ContextMap()
+	if entries[1].Message != "retrying" || fields["tenant"] != "acme" || fields["attempt"] != int64(2) {
+		t.Fatalf("unexpected entry %+v", entries[1])
+	}
+}
+
+func TestInfowCaller(t *testing.T) {
+	dir := t.TempDir()
+	config := fmt.Sprintf("zaplog:\n  - name: default\n    file_name: %s\n    encoder: json\n    show_caller: true\n",
+		filepath.Join(dir, "app.log"))
+	configPath := filepath.Join(dir, "log_config.yaml")
+	if err := os.WriteFile(configPath, []byte(config), 0644); err != nil {
+		t.Fatal(err)
+	}
+	Close()
+	if err := Init(WithConfigFile(configPath)); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(Close)
+
+	Infow("default", "package func")
+	Sugar("default").Infow("wrapper")
+	Close()
+
+	data, err := os.ReadFile(filepath.Join(dir, "app.log"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected 2 lines, got %q", data)
+	}
+	for _, line := range lines {
+		if !strings.Contains(line, `"caller":"log/sugar_test.go:`) {
+			t.Fatalf("caller should point to the call site, got %s", line)
+		}
+	}
+}
@@ -0,0 +1,50 @@
+package log
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"go.uber.org/zap"
+)
+
+// updateSymlink 使link指向target，已指向target时不做修改。
+// 先创建临时链接再改名，替换过程中link始终可用；link为普通文件时不覆盖
+func updateSymlink(link, target string) error {
+	abs, err := filepath.Abs(target)
+	if err != nil {
+		return err
+	}
+	if info, err := os.Lstat(link); err == nil {
+		if info.Mode()&os.ModeSymlink == 0 {
+			return fmt.Errorf("%s exists and is not a symlink", link)
+		}
+		if dest, err := os.Readlink(link); err == nil && dest == abs {
+			return nil
+		}
+	}
+	if err := os.MkdirAll(filepath.Dir(link), 0755); err != nil {
+		return err
+	}
+	tmp := link + ".tmp"
+	_ = os.Remove(tmp)
+	if err := os.Symlink(abs, tmp); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, link); err != nil {
+		_ = os.Remove(tmp)
+		return err
+	}
+	return nil
+}
+
+// refreshLink 按配置创建logger的link_name，失败时只记录诊断日志
+func refreshLink(entry *logEntry) {
+	if entry.cfg.LinkName == "" || entry.writer == nil {
+		return
+	}
+	if err := updateSymlink(entry.cfg.LinkName, entry.writer.Filename); err != nil {
+		diagnostics.Warn("failed to update log symlink", zap.String("logger", entry.cfg.Name),
+			zap.String("link", entry.cfg.LinkName), zap.Error(err))
+	}
+}
@@ -0,0 +1,68 @@
+package log
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLinkName(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "app", "app.log")
+	link := filepath.Join(dir, "current", "app.log")
+	config := "zaplog:\n  - name: default\n    file_name: " + file + "\n    encoder: json\n    link_name: " + link + "\n"
+	configPath := filepath.Join(dir, "log_config.yaml")
+	if err := os.WriteFile(configPath, []byte(config), 0644); err != nil {
+		t.Fatal(err)
+	}
+	Close()
+	if err := Init(WithConfigFile(configPath)); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(Close)
+
+	GetDefaultLogger().Info("before rotate")
+	if dest, err := os.Readlink(link); err != nil || dest != file {
+		t.Fatalf("link should point at the active file, got %q: %v", dest, err)
+	}
+
+	// 切割后file_name仍是当前文件，链接保持不变
+	if err := Rotate("default"); err != nil {
+		t.Fatal(err)
+	}
+	GetDefaultLogger().Info("after rotate")
+	data, err := os.ReadFile(link)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := string(data); !strings.Contains(got, "after rotate") || strings.Contains(got, "before rotate") {
+		t.Fatalf("link should follow the new active file, got %s", got)
+	}
+}
+
+func TestUpdateSymlink(t *testing.T) {
+	dir := t.TempDir()
+	regular := filepath.Join(dir, "regular.log")
+	if err := os.WriteFile(regular, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := updateSymlink(regular, filepath.Join(dir, "app.log")); err == nil {
+		t.Fatal("regular files should not be replaced")
+	}
+
+	link := filepath.Join(dir, "link.log")
+	for _, target := range []string{"a.log", "b.log", "b.log"} {
+		if err := updateSymlink(link, filepath.Join(dir, target)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if dest, _ := os.Readlink(link); dest != filepath.Join(dir, "b.log") {
+		t.Fatalf("link should be switched to the new target, got %s", dest)
+	}
+
+	cfg := Config{Zaplog: []LogConfig{{Name: "default", FileName: "a.log", LinkName: "./a.log"}}}
+	if err := validateConfig(&cfg); err == nil || !strings.Contains(err.Error(), "link_name must differ") {
+		t.Fatalf("expected link_name error, got %v", err)
+	}
+}
@@ -0,0 +1,63 @@
+package log
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sync/atomic"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// defaultSinkSyncTimeout 未设置sync_timeout时sink的Sync、Close最长等待时间
+const defaultSinkSyncTimeout = 5 * time.Second
+
+// timeoutSyncer 限制sink的Sync与Close的等待时间，网络输出端不可达时不会阻塞logger.Sync和Close。
+// 超时后不再等待并输出诊断信息，底层调用在后台继续，完成前的Sync直接返回错误，避免堆积goroutine
+type timeoutSyncer struct {
+	zapcore.WriteSyncer
+	logger, sink string
+	timeout      time.Duration
+	pending      atomic.Bool
+}
+
+func newTimeoutSyncer(logger string, sc SinkConfig, ws zapcore.WriteSyncer) *timeoutSyncer {
+	timeout := sc.SyncTimeout
+	if timeout <= 0 {
+		timeout = defaultSinkSyncTimeout
+	}
+	return &timeoutSyncer{WriteSyncer: ws, logger: logger, sink: sc.Type, timeout: timeout}
+}
+
+func (s *timeoutSyncer) Sync() error {
+	return s.call("sync", s.WriteSyncer.Sync)
+}
+
+// Close 底层sink实现io.Closer时关闭，关闭前通常需要提交缓冲的数据，同样受超时限制
+func (s *timeoutSyncer) Close() error {
+	c, ok := s.WriteSyncer.(io.Closer)
+	if !ok {
+		return nil
+	}
+	return s.call("close", c.Close)
+}
+
+func (s *timeoutSyncer) call(op string, fn func() error) error {
+	if !s.pending.CompareAndSwap(false, true) {
+		return fmt.Errorf("sink %s: %s skipped, previous sync or close has not finished", s.sink, op)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), s.timeout)
+	defer cancel()
+	err := syncContext(ctx, func() error {
+		defer s.pending.Store(false)
+		return fn()
+	})
+	if ctx.Err() != nil && err == ctx.Err() {
+		diagnostics.Warn("log sink "+op+" timed out",
+			zap.String("logger", s.logger), zap.String("sink", s.sink), zap.Duration("timeout", s.timeout))
+		return fmt.Errorf("sink %s: %s timed out after %s", s.sink, op, s.timeout)
+	}
+	return err
+}
@@ -0,0 +1,67 @@
+package log
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+func TestSinkSyncTimeout(t *testing.T) {
+	sink := &syncSink{block: make(chan struct{})}
+	ws := newTimeoutSyncer("access", SinkConfig{Type: "kafka", SyncTimeout: 20 * time.Millisecond}, sink)
+
+	start := time.Now()
+	err := ws.Sync()
+	if err == nil || !strings.Contains(err.Error(), "sink kafka: sync timed out after 20ms") {
+		t.Fatalf("expected timeout error, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Fatalf("Sync should honour the timeout, took %s", elapsed)
+	}
+	if err := ws.Sync(); err == nil || !strings.Contains(err.Error(), "previous sync or close has not finished") {
+		t.Fatalf("expected pending sync error, got %v", err)
+	}
+
+	close(sink.block)
+	deadline := time.Now().Add(time.Second)
+	for ws.pending.Load() && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	if err := ws.Sync(); err != nil {
+		t.Fatalf("sync should succeed after the sink recovers, got %v", err)
+	}
+	if err := ws.Close(); err != nil {
+		t.Fatalf("closing a sink without Close should be a no-op, got %v", err)
+	}
+	if newTimeoutSyncer("access", SinkConfig{}, zapcore.AddSync(nil)).timeout != defaultSinkSyncTimeout {
+		t.Fatal("expected default sync timeout")
+	}
+}
+
+func TestSinkSyncTimeoutLogger(t *testing.T) {
+	sink := &syncSink{block: make(chan struct{})}
+	defer close(sink.block)
+	RegisterSink("test-sync-timeout", func(string, map[string]any) (zapcore.WriteSyncer, error) {
+		return sink, nil
+	})
+	entry, err := newLogger(LogConfig{
+		Name:     "access",
+		FileName: t.TempDir() + "/access.log",
+		Sinks:    []SinkConfig{{Type: "test-sync-timeout", SyncTimeout: 20 * time.Millisecond}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer entry.writer.Close()
+	entry.logger.Info("request")
+	if err := entry.logger.Sync(); err == nil || !strings.Contains(err.Error(), "timed out") {
+		t.Fatalf("expected logger Sync to time out, got %v", err)
+	}
+
+	errs := validateLogConfig(LogConfig{Name: "access", FileName: "access.log", Sinks: []SinkConfig{{Type: "test-sync-timeout", SyncTimeout: -1}}}, map[string]bool{".": true})
+	if len(errs) != 1 || !strings.Contains(errs[0].Error(), "sync_timeout") {
+		t.Fatalf("expected sync_timeout error, got %v", errs)
+	}
+}
@@ -0,0 +1,138 @@
+package log
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// sysEntry 系统日志sink从json编码的日志行中解析出的级别、消息与其余字段
+type sysEntry struct {
+	level   zapcore.Level
+	message string
+	fields  map[string]json.RawMessage
+}
+
+// sysEntryKeys 解析日志行使用的字段名，与logger的level_key、message_key等一致
+type sysEntryKeys struct {
+	level, message, time string
+}
+
+func newSysEntryKeys(options map[string]any) sysEntryKeys {
+	return sysEntryKeys{
+		level:   sinkString(options, "level_key", "level"),
+		message: sinkString(options, "message_key", "msg"),
+		time:    sinkString(options, "time_key", "ts"),
+	}
+}
+
+// parseSysEntry 解析json编码的日志行，非json时整行作为消息，级别为info
+func parseSysEntry(p []byte, keys sysEntryKeys) sysEntry {
+	line := trimLineEnding(p)
+	e := sysEntry{level: zapcore.InfoLevel, message: string(line)}
+	var fields map[string]json.RawMessage
+	if json.Unmarshal(line, &fields) != nil {
+		return e
+	}
+	if raw, ok := fields[keys.level]; ok {
+		var level string
+		if json.Unmarshal(raw, &level) == nil {
+			_ = e.level.UnmarshalText([]byte(level))
+		}
+		delete(fields, keys.level)
+	}
+	if raw, ok := fields[keys.message]; ok {
+		e.message = jsonText(raw)
+		delete(fields, keys.message)
+	}
+	delete(fields, keys.time)
+	e.fields = fields
+	return e
+}
+
+func trimLineEnding(p []byte) []byte {
+	return bytes.TrimRight(p, "\r\n")
+}
+
+// jsonText 字符串返回其内容，其他类型返回json文本
+func jsonText(raw json.RawMessage) string {
+	var s string
+	if json.Unmarshal(raw, &s) == nil {
+		return s
+	}
+	return string(raw)
+}
+
+func sinkString(options map[string]any, key, def string) string {
+	if v, ok := options[key]; ok && v != nil {
+		if s := fmt.Sprint(v); s != "" {
+			return s
+		}
+	}
+	return def
+}
+
+// programName 当前程序名，不含扩展名
+func programName() string {
+	name := filepath.Base(os.Args[0])
+	return strings.TrimSuffix(name, filepath.Ext(name))
+}
+
+// journalPriority 日志级别对应的syslog优先级
+func journalPriority(level zapcore.Level) int {
+	switch {
+	case level >= zapcore.DPanicLevel:
+		return 2 // crit
+	case level == zapcore.ErrorLevel:
+		return 3 // err
+	case level == zapcore.WarnLevel:
+		return 4 // warning
+	case level == zapcore.InfoLevel:
+		return 6 // info
+	}
+	return 7 // debug
+}
+
+// journalFieldName 转换为journald字段名：大写字母、数字和下划线，不以下划线或数字开头，最长64字符
+func journalFieldName(key string) string {
+	name := strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z':
+			return r - 'a' + 'A'
+		case r >= 'A' && r <= 'Z' || r >= '0' && r <= '9':
+			return r
+		}
+		return '_'
+	}, key)
+	name = strings.TrimLeft(name, "_")
+	if name == "" || name[0] >= '0' && name[0] <= '9' {
+		name = "F_" + name
+	}
+	if len(name) > 64 {
+		name = name[:64]
+	}
+	return name
+}
+
+// Windows事件类型
+const (
+	eventLogError       = 0x0001
+	eventLogWarning     = 0x0002
+	eventLogInformation = 0x0004
+)
+
+// eventLogType 日志级别对应的Windows事件类型，debug与info均为信息
+func eventLogType(level zapcore.Level) uint16 {
+	switch {
+	case level >= zapcore.ErrorLevel:
+		return eventLogError
+	case level == zapcore.WarnLevel:
+		return eventLogWarning
+	}
+	return eventLogInformation
+}
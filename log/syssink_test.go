@@ -0,0 +1,58 @@
+package log
+
+import (
+	"testing"
+
+	"go.uber.org/zap/zapcore"
+)
+
+func TestParseSysEntry(t *testing.T) {
+	keys := newSysEntryKeys(map[string]any{"message_key": "message"})
+	e := parseSysEntry([]byte(`{"level":"WARN","ts":"2024-01-02T03:04:05Z","message":"slow","user_id":42,"tags":["a"]}`+"\n"), keys)
+	if e.level != zapcore.WarnLevel || e.message != "slow" || len(e.fields) != 2 {
+		t.Fatalf("unexpected entry %+v", e)
+	}
+	if jsonText(e.fields["user_id"]) != "42" || jsonText(e.fields["tags"]) != `["a"]` {
+		t.Fatalf("unexpected fields %v", e.fields)
+	}
+
+	e = parseSysEntry([]byte("2024-01-02 INFO plain text\n"), keys)
+	if e.level != zapcore.InfoLevel || e.message != "2024-01-02 INFO plain text" || e.fields != nil {
+		t.Fatalf("non-json lines should be kept as the message, got %+v", e)
+	}
+}
+
+func TestJournalFieldName(t *testing.T) {
+	for key, want := range map[string]string{
+		"user_id":     "USER_ID",
+		"http.status": "HTTP_STATUS",
+		"_private":    "PRIVATE",
+		"1st":         "F_1ST",
+		"请求":          "F_",
+	} {
+		if got := journalFieldName(key); got != want {
+			t.Errorf("journalFieldName(%q) = %q, want %q", key, got, want)
+		}
+	}
+}
+
+func TestSysLevelMapping(t *testing.T) {
+	priorities := map[zapcore.Level]int{
+		zapcore.DebugLevel: 7, zapcore.InfoLevel: 6, zapcore.WarnLevel: 4,
+		zapcore.ErrorLevel: 3, zapcore.PanicLevel: 2, zapcore.FatalLevel: 2,
+	}
+	for level, want := range priorities {
+		if got := journalPriority(level); got != want {
+			t.Errorf("journalPriority(%s) = %d, want %d", level, got, want)
+		}
+	}
+	types := map[zapcore.Level]uint16{
+		zapcore.DebugLevel: eventLogInformation, zapcore.InfoLevel: eventLogInformation,
+		zapcore.WarnLevel: eventLogWarning, zapcore.ErrorLevel: eventLogError, zapcore.FatalLevel: eventLogError,
+	}
+	for level, want := range types {
+		if got := eventLogType(level); got != want {
+			t.Errorf("eventLogType(%s) = %d, want %d", level, got, want)
+		}
+	}
+}
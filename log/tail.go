@@ -0,0 +1,175 @@
+package log
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// tailBuffer 每个订阅者缓存的日志条数，订阅者读取过慢时丢弃新日志
+const tailBuffer = 256
+
+// tailHub 向实时订阅者广播日志
+type tailHub struct {
+	mu   sync.Mutex
+	subs map[*tailSub]struct{}
+	n    atomic.Int32
+}
+
+// tailSub 一个实时订阅
+type tailSub struct {
+	level   zapcore.Level
+	ch      chan []byte
+	dropped atomic.Uint64
+}
+
+func newTailHub() *tailHub {
+	return &tailHub{subs: make(map[*tailSub]struct{})}
+}
+
+func (h *tailHub) subscribe(level zapcore.Level) *tailSub {
+	s := &tailSub{level: level, ch: make(chan []byte, tailBuffer)}
+	h.mu.Lock()
+	h.subs[s] = struct{}{}
+	h.n.Store(int32(len(h.subs)))
+	h.mu.Unlock()
+	return s
+}
+
+func (h *tailHub) unsubscribe(s *tailSub) {
+	h.mu.Lock()
+	delete(h.subs, s)
+	h.n.Store(int32(len(h.subs)))
+	h.mu.Unlock()
+}
+
+// active 是否有订阅者，没有时不编码日志
+func (h *tailHub) active() bool {
+	return h.n.Load() > 0
+}
+
+func (h *tailHub) publish(level zapcore.Level, line []byte) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for s := range h.subs {
+		if level < s.level {
+			continue
+		}
+		select {
+		case s.ch <- line:
+		default:
+			s.dropped.Add(1)
+		}
+	}
+}
+
+// tailCore 有订阅者时把日志广播给订阅者，复用ringCore的字段处理
+type tailCore struct {
+	*ringCore
+	hub *tailHub
+}
+
+func newTailCore(hub *tailHub, level zapcore.LevelEnabler) zapcore.Core {
+	return &tailCore{ringCore: &ringCore{LevelEnabler: level}, hub: hub}
+}
+
+func (c *tailCore) Enabled(level zapcore.Level) bool {
+	return c.hub.active() && c.ringCore.Enabled(level)
+}
+
+func (c *tailCore) With(fields []zapcore.Field) zapcore.Core {
+	return &tailCore{ringCore: c.ringCore.With(fields).(*ringCore), hub: c.hub}
+}
+
+func (c *tailCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+func (c *tailCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	line, err := json.Marshal(c.entry(ent, fields))
+	if err != nil {
+		return err
+	}
+	c.hub.publish(ent.Level, line)
+	return nil
+}
+
+// TailHandler 返回以SSE(text/event-stream)实时推送新日志的处理器，参数：
+// name logger名称（默认default），level 最低级别。每条日志为一个data事件，内容同/debug/log/dump中的条目；
+// 客户端读取过慢时丢弃的条数以dropped事件通知。浏览器中可直接使用EventSource订阅
+func TailHandler() http.Handler {
+	return http.HandlerFunc(handleTail)
+}
+
+func handleTail(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	name := query.Get("name")
+	if name == "" {
+		name = "default"
+	}
+	level := zapcore.DebugLevel
+	if s := query.Get("level"); s != "" {
+		if !isValidLevel(s) {
+			writeError(w, http.StatusBadRequest, fmt.Errorf("invalid level %q", s))
+			return
+		}
+		level = getLevel(s)
+	}
+
+	metux.RLock()
+	entry, ok := loggers[name]
+	metux.RUnlock()
+	if !ok || entry.tail == nil {
+		writeError(w, http.StatusNotFound, fmt.Errorf("logger %s not found", name))
+		return
+	}
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		writeError(w, http.StatusInternalServerError, fmt.Errorf("streaming unsupported"))
+		return
+	}
+
+	sub := entry.tail.subscribe(level)
+	defer entry.tail.unsubscribe(sub)
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+	// 先发送注释行，让客户端尽快确认连接建立
+	fmt.Fprint(w, ": tailing "+name+"\n\n")
+	flusher.Flush()
+
+	keepalive := time.NewTicker(30 * time.Second)
+	defer keepalive.Stop()
+	var reported uint64
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case line := <-sub.ch:
+			if dropped := sub.dropped.Load(); dropped > reported {
+				fmt.Fprintf(w, "event: dropped\ndata: %d\n\n", dropped-reported)
+				reported = dropped
+			}
+			if _, err := fmt.Fprintf(w, "data: %s\n\n", line); err != nil {
+				return
+			}
+			flusher.Flush()
+		case <-keepalive.C:
+			if _, err := fmt.Fprint(w, ": keepalive\n\n"); err != nil {
+				return
+			}
+			flusher.Flush()
+		}
+	}
+}
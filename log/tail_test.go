@@ -0,0 +1,84 @@
+package log
+
+import (
+	"bufio"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+func TestTailHandler(t *testing.T) {
+	initTestLoggers(t, "access")
+	srv := httptest.NewServer(TailHandler())
+	defer srv.Close()
+
+	if GetLogger("access").Core().Enabled(zapcore.DebugLevel) {
+		t.Fatal("tail should not enable levels below the logger level")
+	}
+
+	resp, err := http.Get(srv.URL + "?name=access&level=warn")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if ct := resp.Header.Get("Content-Type"); ct != "text/event-stream" {
+		t.Fatalf("unexpected content type %q", ct)
+	}
+	reader := bufio.NewReader(resp.Body)
+	if line, _ := reader.ReadString('\n'); !strings.HasPrefix(line, ": tailing access") {
+		t.Fatalf("unexpected first line %q", line)
+	}
+
+	logger := GetLogger("access")
+	logger.Info("ignored")
+	logger.Warn("slow request", zap.String("path", "/orders"))
+
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !strings.HasPrefix(line, "data: ") {
+			continue
+		}
+		if strings.Contains(line, "ignored") {
+			t.Fatalf("entries below the requested level should be skipped: %q", line)
+		}
+		if !strings.Contains(line, `"msg":"slow request"`) || !strings.Contains(line, `"path":"/orders"`) {
+			t.Fatalf("unexpected event %q", line)
+		}
+		break
+	}
+
+	resp, err = http.Get(srv.URL + "?name=missing")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusNotFound {
+		t.Fatalf("expected 404 for unknown logger, got %d", resp.StatusCode)
+	}
+}
+
+func TestTailHubDropsForSlowSubscribers(t *testing.T) {
+	hub := newTailHub()
+	if hub.active() {
+		t.Fatal("hub without subscribers should be inactive")
+	}
+	sub := hub.subscribe(zapcore.InfoLevel)
+	for i := 0; i < tailBuffer+5; i++ {
+		hub.publish(zapcore.InfoLevel, []byte("x"))
+	}
+	hub.publish(zapcore.DebugLevel, []byte("debug"))
+	if sub.dropped.Load() != 5 || len(sub.ch) != tailBuffer {
+		t.Fatalf("expected 5 dropped entries, got %d (buffered %d)", sub.dropped.Load(), len(sub.ch))
+	}
+	hub.unsubscribe(sub)
+	if hub.active() {
+		t.Fatal("hub should be inactive after the last subscriber leaves")
+	}
+}
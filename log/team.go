@@ -0,0 +1,186 @@
+package log
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"strings"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"gopkg.in/natefinch/lumberjack.v2"
+)
+
+// TeamConfig 团队归属配置，用于多个团队的代码共用一个进程时区分日志归属
+type TeamConfig struct {
+	Name     string   `yaml:"name" mapstructure:"name"`           // 团队名称，写入team字段
+	Loggers  []string `yaml:"loggers" mapstructure:"loggers"`     // 匹配的logger或模块名称，支持path.Match通配符
+	Packages []string `yaml:"packages" mapstructure:"packages"`   // 匹配的调用者包路径前缀，需开启show_caller
+	FileName string   `yaml:"file_name" mapstructure:"file_name"` // 设置后该团队的日志只写入此文件，而不是原logger的文件
+}
+
+// teamKey 团队归属字段名
+const teamKey = "team"
+
+// team 运行中的团队配置
+type team struct {
+	cfg    TeamConfig
+	writer *lumberjack.Logger
+	ws     zapcore.WriteSyncer
+}
+
+var teams []*team
+
+func validateTeams(cfgs []TeamConfig) error {
+	var errs []error
+	seen := make(map[string]bool)
+	for i, tc := range cfgs {
+		if tc.Name == "" {
+			errs = append(errs, fmt.Errorf("team %d: name is required", i))
+		} else if seen[tc.Name] {
+			errs = append(errs, fmt.Errorf("team %s: duplicate name", tc.Name))
+		}
+		seen[tc.Name] = true
+		for _, pattern := range tc.Loggers {
+			if _, err := path.Match(pattern, ""); err != nil {
+				errs = append(errs, fmt.Errorf("team %s: invalid logger pattern %q", tc.Name, pattern))
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// setTeams 替换团队配置并打开团队日志文件，调用方需持有metux写锁
+func setTeams(cfgs []TeamConfig) {
+	closeTeams()
+	for _, tc := range cfgs {
+		t := &team{cfg: tc}
+		if tc.FileName != "" {
+			lc := LogConfig{Name: tc.Name, FileName: tc.FileName}
+			setDefault(&lc)
+			t.writer = newFileWriter(lc)
+			t.ws = zapcore.AddSync(t.writer)
+		}
+		teams = append(teams, t)
+	}
+}
+
+// closeTeams 关闭团队日志文件，调用方需持有metux写锁
+func closeTeams() {
+	for _, t := range teams {
+		if t.writer != nil {
+			_ = t.writer.Close()
+		}
+	}
+	teams = nil
+}
+
+// teamFiles 返回配置了file_name的团队文件
+func teamFiles(teams []*team) []*lumberjack.Logger {
+	var files []*lumberjack.Logger
+	for _, t := range teams {
+		if t.writer != nil {
+			files = append(files, t.writer)
+		}
+	}
+	return files
+}
+
+// teamFiles 返回logger可能写入的团队文件，Rotate、Reopen和关闭logger时与logger的文件一并处理
+func (e *logEntry) teamFiles() []*lumberjack.Logger {
+	return teamFiles(e.teams)
+}
+
+// matchLogger 返回名称匹配的团队
+func matchLogger(teams []*team, name string) *team {
+	if name == "" {
+		return nil
+	}
+	for _, t := range teams {
+		for _, pattern := range t.cfg.Loggers {
+			if ok, _ := path.Match(pattern, name); ok {
+				return t
+			}
+		}
+	}
+	return nil
+}
+
+// matchPackage 返回调用者包路径匹配的团队
+func matchPackage(teams []*team, caller zapcore.EntryCaller) *team {
+	if !caller.Defined || caller.Function == "" {
+		return nil
+	}
+	for _, t := range teams {
+		for _, pkg := range t.cfg.Packages {
+			rest, ok := strings.CutPrefix(caller.Function, pkg)
+			if ok && (rest == "" || rest[0] == '.' || rest[0] == '/') {
+				return t
+			}
+		}
+	}
+	return nil
+}
+
+// teamCore 为日志附加team字段，团队配置了file_name时改写到团队文件
+type teamCore struct {
+	zapcore.Core
+	teams  []*team
+	owner  *team // 按logger名称确定的归属，优先于调用者包路径
+	routes map[*team]zapcore.Core
+}
+
+// newTeamCore newRoute以指定输出构建与core相同配置的core
+func newTeamCore(core zapcore.Core, name string, teams []*team, newRoute func(zapcore.WriteSyncer) zapcore.Core) zapcore.Core {
+	routes := make(map[*team]zapcore.Core)
+	for _, t := range teams {
+		if t.ws != nil {
+			routes[t] = newRoute(t.ws)
+		}
+	}
+	return &teamCore{Core: core, teams: teams, owner: matchLogger(teams, name), routes: routes}
+}
+
+func (c *teamCore) With(fields []zapcore.Field) zapcore.Core {
+	routes := make(map[*team]zapcore.Core, len(c.routes))
+	for t, route := range c.routes {
+		routes[t] = route.With(fields)
+	}
+	return &teamCore{Core: c.Core.With(fields), teams: c.teams, owner: c.owner, routes: routes}
+}
+
+func (c *teamCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+func (c *teamCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	owner := c.owner
+	if owner == nil {
+		owner = matchLogger(c.teams, ent.LoggerName)
+	}
+	if owner == nil {
+		owner = matchPackage(c.teams, ent.Caller)
+	}
+	if owner == nil {
+		return c.Core.Write(ent, fields)
+	}
+
+	fields = append(fields[:len(fields):len(fields)], zap.String(teamKey, owner.cfg.Name))
+	if route, ok := c.routes[owner]; ok {
+		return route.Write(ent, fields)
+	}
+	return c.Core.Write(ent, fields)
+}
+
+func (c *teamCore) Sync() error {
+	err := c.Core.Sync()
+	for _, route := range c.routes {
+		if e := route.Sync(); e != nil && err == nil {
+			err = e
+		}
+	}
+	return err
+}
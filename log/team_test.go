@@ -0,0 +1,77 @@
+package log
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestTeams(t *testing.T) {
+	dir := t.TempDir()
+	file := func(name string) string { return filepath.Join(dir, name) }
+	config := fmt.Sprintf(`zaplog:
+  - name: default
+    file_name: %s
+    encoder: json
+    show_caller: true
+  - name: payments-api
+    file_name: %s
+    encoder: json
+teams:
+  - name: payments
+    loggers: ["payments-*"]
+    file_name: %s
+  - name: search
+    packages: [github.com/allanchen1214/goeasy/log]
+`, file("app.log"), file("payments-api.log"), file("payments.log"))
+	configPath := file("log_config.yaml")
+	if err := os.WriteFile(configPath, []byte(config), 0644); err != nil {
+		t.Fatal(err)
+	}
+	Close()
+	if err := Init(WithConfigFile(configPath)); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(Close)
+
+	GetLogger("payments-api").Info("charge")
+	Module("payments-db").Info("query")
+	GetDefaultLogger().Info("index")
+	// 团队文件随logger一同切分
+	if err := Rotate("payments-api"); err != nil {
+		t.Fatal(err)
+	}
+	if backups := rotatedFiles(file("payments.log"), ""); len(backups) != 1 {
+		t.Fatalf("team file should be rotated, got %v", backups)
+	}
+	GetLogger("payments-api").Info("after rotate")
+	Close()
+
+	read := func(name string) string {
+		data, _ := os.ReadFile(file(name))
+		return string(data)
+	}
+	if got := read("payments-api.log"); got != "" {
+		t.Fatalf("payments records should be routed to team file, got %s", got)
+	}
+	payments := read("payments.log")
+	backups := rotatedFiles(file("payments.log"), "")
+	data, _ := os.ReadFile(backups[0])
+	payments = string(data) + payments
+	if strings.Count(payments, `"team":"payments"`) != 3 || !strings.Contains(payments, "charge") || !strings.Contains(payments, "query") {
+		t.Fatalf("unexpected team file content %s", payments)
+	}
+	if app := read("app.log"); !strings.Contains(app, `"team":"search"`) || !strings.Contains(app, "index") {
+		t.Fatalf("expected caller package match, got %s", app)
+	}
+
+	cfg := Config{
+		Zaplog: []LogConfig{{Name: "default", FileName: "a.log"}},
+		Teams:  []TeamConfig{{Name: "a"}, {Name: "a"}},
+	}
+	if err := validateConfig(&cfg); err == nil {
+		t.Fatal("expected error for duplicate team")
+	}
+}
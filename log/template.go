@@ -0,0 +1,99 @@
+package log
+
+import (
+	"fmt"
+	"strings"
+
+	"go.uber.org/zap"
+)
+
+// templateNamePlaceholder 模板file_name中替换为logger名称的占位符
+const templateNamePlaceholder = "{name}"
+
+// logTemplates Init时的templates配置，按模板名索引，受metux保护
+var logTemplates map[string]LogConfig
+
+// setTemplates 更新模板，调用方需持有metux写锁
+func setTemplates(templates []LogConfig) {
+	logTemplates = make(map[string]LogConfig, len(templates))
+	for _, t := range templates {
+		logTemplates[t.Name] = t
+	}
+}
+
+func validateTemplates(templates []LogConfig) []error {
+	var (
+		errs  []error
+		names = make(map[string]bool)
+	)
+	for i, t := range templates {
+		switch {
+		case t.Name == "":
+			errs = append(errs, fmt.Errorf("template %d: name is required", i))
+		case names[t.Name]:
+			errs = append(errs, fmt.Errorf("template %s: duplicate name", t.Name))
+		}
+		names[t.Name] = true
+		if t.Level != "" && !isValidLevel(t.Level) {
+			errs = append(errs, fmt.Errorf("template %s: %w", t.Name, invalidLevel(t.Level)))
+		}
+		// 未设置file_name时按defaults.directory生成<name>.log，设置时需区分各logger的文件
+		if t.FileName != "" && !strings.Contains(t.FileName, templateNamePlaceholder) {
+			errs = append(errs, fmt.Errorf("template %s: file_name must contain %s", t.Name, templateNamePlaceholder))
+		}
+	}
+	return errs
+}
+
+// fileSafeName 将logger名称中文件名不允许的字符替换为下划线，避免租户ID等外部输入逃逸出日志目录
+func fileSafeName(name string) string {
+	safe := strings.Map(func(r rune) rune {
+		if r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '-' || r == '_' || r == '.' {
+			return r
+		}
+		return '_'
+	}, name)
+	if strings.Trim(safe, ".") == "" {
+		safe = strings.Repeat("_", len(safe))
+	}
+	return safe
+}
+
+// GetOrCreate 返回指定名称的logger，不存在时以template模板创建，模板file_name中的{name}替换为名称，
+// 如 ./logs/tenants/{name}.log。适用于按租户或任务ID分文件，不再使用时通过RemoveLogger关闭。
+// 模板不存在或创建失败时输出诊断信息并返回全局Default logger
+func GetOrCreate(name, template string) *zap.Logger {
+	metux.RLock()
+	entry, ok := loggers[name]
+	tmpl, found := logTemplates[template]
+	dir := logDefaults.Directory
+	metux.RUnlock()
+	if ok {
+		return entry.logger
+	}
+	if !found {
+		diagnostics.Warn("logger template not found", zap.String("logger", name), zap.String("template", template))
+		return zap.L()
+	}
+
+	lc := tmpl
+	lc.Name = name
+	lc.FileName = strings.ReplaceAll(lc.FileName, templateNamePlaceholder, fileSafeName(name))
+	if lc.FileName == "" && dir != "" {
+		lc.FileName = fileSafeName(name) + ".log"
+	}
+	logger, err := addLogger(lc, template)
+	if err == nil {
+		return logger
+	}
+
+	// 并发创建时其他调用方已注册
+	metux.RLock()
+	entry, ok = loggers[name]
+	metux.RUnlock()
+	if ok {
+		return entry.logger
+	}
+	diagnostics.Warn("failed to create logger from template", zap.String("logger", name), zap.String("template", template), zap.Error(err))
+	return zap.L()
+}
@@ -0,0 +1,103 @@
+package log
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+func TestGetOrCreate(t *testing.T) {
+	dir := t.TempDir()
+	cfg := Config{
+		Defaults: LogDefaults{Directory: dir, Encoder: "json"},
+		Zaplog:   []LogConfig{{Name: "default"}},
+		Templates: []LogConfig{
+			{Name: "tenant", Level: "warn", FileName: "tenants/{name}.log"},
+			{Name: "job"},
+		},
+	}
+	Close()
+	if err := Init(WithConfig(cfg)); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(Close)
+
+	var (
+		wg      sync.WaitGroup
+		created = make([]*zap.Logger, 8)
+	)
+	for i := range created {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			created[i] = GetOrCreate("acme", "tenant")
+		}(i)
+	}
+	wg.Wait()
+	for _, logger := range created {
+		if logger != created[0] || logger == GetDefaultLogger() {
+			t.Fatal("concurrent calls should share one tenant logger")
+		}
+	}
+	logger := created[0]
+	if logger.Core().Enabled(zapcore.InfoLevel) {
+		t.Fatal("tenant logger should use the template level")
+	}
+	logger.Warn("over limit")
+
+	// 名称中的路径分隔符不能逃逸出日志目录
+	GetOrCreate("../evil", "tenant").Warn("escaped")
+	GetOrCreate("job/42", "job").Info("done")
+
+	if err := RemoveLogger("acme"); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(filepath.Join(dir, "tenants", "acme.log"))
+	if err != nil || !strings.Contains(string(data), `"msg":"over limit"`) {
+		t.Fatalf("unexpected tenant log %q, %v", data, err)
+	}
+	for _, name := range []string{"tenants/.._evil.log", "job_42.log"} {
+		if _, err := os.Stat(filepath.Join(dir, name)); err != nil {
+			t.Errorf("expected %s: %v", name, err)
+		}
+	}
+	if GetOrCreate("other", "missing") != GetDefaultLogger() {
+		t.Fatal("unknown template should fall back to default")
+	}
+}
+
+func TestValidateTemplates(t *testing.T) {
+	errs := validateTemplates([]LogConfig{
+		{Name: "tenant", FileName: "./logs/tenant.log"},
+		{Name: "tenant", Level: "loud"},
+		{},
+	})
+	msgs := []string{"must contain {name}", "duplicate name", "invalid level", "name is required"}
+	if len(errs) != len(msgs) {
+		t.Fatalf("expected %d errors, got %v", len(msgs), errs)
+	}
+	for i, msg := range msgs {
+		if !strings.Contains(errs[i].Error(), msg) {
+			t.Errorf("error %d: expected %q, got %v", i, msg, errs[i])
+		}
+	}
+}
+
+func TestFileSafeName(t *testing.T) {
+	for name, want := range map[string]string{
+		"acme":       "acme",
+		"tenant-1.2": "tenant-1.2",
+		"../x":       ".._x",
+		"..":         "__",
+		"a b/c":      "a_b_c",
+	} {
+		if got := fileSafeName(name); got != want {
+			t.Errorf("fileSafeName(%q) = %q, want %q", name, got, want)
+		}
+	}
+}
@@ -0,0 +1,52 @@
+package log
+
+import (
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// Timing 由Timer创建的计时器
+type Timing struct {
+	logger    *zap.Logger
+	operation string
+	start     time.Time
+	opts      *options
+}
+
+// Timer 开始计时，Stop时以operation为消息记录耗时elapsed，用于统一各处的耗时日志：
+//
+//	defer log.Timer(logger, "load config", log.WithSlowThreshold(time.Second)).Stop()
+//
+// 默认以info级别记录，可通过WithMethodLevel(operation, level)调整；
+// 通过WithSlowThreshold设置阈值后，超过阈值时升级为warn并标记 slow=true。
+// logger为nil时使用default logger
+func Timer(logger *zap.Logger, operation string, opts ...Option) *Timing {
+	if logger == nil {
+		logger = GetDefaultLogger()
+	}
+	return &Timing{
+		logger:    logger.WithOptions(zap.AddCallerSkip(1)),
+		operation: operation,
+		start:     time.Now(),
+		opts:      newOptions(opts),
+	}
+}
+
+// Stop 记录从Timer到现在的耗时并返回，fields附加到日志中
+func (t *Timing) Stop(fields ...zap.Field) time.Duration {
+	elapsed := time.Since(t.start)
+	level := t.opts.levelFor(t.operation)
+	fields = append(fields, zap.Duration("elapsed", elapsed))
+	if t.opts.isSlow(elapsed) {
+		fields = append(fields, zap.Bool("slow", true), zap.Duration("slow_threshold", t.opts.slowThreshold))
+		if level < zapcore.WarnLevel {
+			level = zapcore.WarnLevel
+		}
+	}
+	if ce := t.logger.Check(level, t.operation); ce != nil {
+		ce.Write(fields...)
+	}
+	return elapsed
+}
@@ -0,0 +1,38 @@
+package log
+
+import (
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+func TestTimer(t *testing.T) {
+	initTestLoggers(t)
+	logs := observeLogger(t, "default")
+
+	elapsed := Timer(nil, "load config").Stop(zap.String("source", "file"))
+	Timer(nil, "health check", WithMethodLevel("health check", "debug")).Stop()
+
+	slow := Timer(GetDefaultLogger(), "sync users", WithSlowThreshold(time.Millisecond))
+	time.Sleep(2 * time.Millisecond)
+	slow.Stop()
+
+	entries := logs.All()
+	if len(entries) != 3 {
+		t.Fatalf("expected 3 entries, got %+v", entries)
+	}
+	fields := entries[0].ContextMap()
+	if entries[0].Message != "load config" || entries[0].Level != zapcore.InfoLevel ||
+		fields["source"] != "file" || fields["elapsed"] != elapsed || fields["slow"] != nil {
+		t.Fatalf("unexpected timer entry %+v", entries[0])
+	}
+	if entries[1].Level != zapcore.DebugLevel {
+		t.Fatalf("method level should apply to the operation, got %v", entries[1].Level)
+	}
+	fields = entries[2].ContextMap()
+	if entries[2].Level != zapcore.WarnLevel || fields["slow"] != true || fields["slow_threshold"] != time.Millisecond {
+		t.Fatalf("slow operation should escalate to warn, got %+v", entries[2])
+	}
+}
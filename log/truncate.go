@@ -0,0 +1,131 @@
+package log
+
+import (
+	"unicode/utf8"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+const (
+	// fieldOverhead 非字符串字段及键名引号、分隔符的估算字节数
+	fieldOverhead = 16
+	// truncatedSuffix 截断后附加的标记
+	truncatedSuffix = "...(truncated)"
+)
+
+// sizeCore 限制单条日志的大小，超出max_entry_bytes时依次截断最长的字符串字段，
+// 并附加 truncated=true，避免意外输出的超大内容拖垮下游解析器和网络输出端
+type sizeCore struct {
+	zapcore.Core
+	limit int
+	// used With附加的字段已占用的字节数
+	used int
+}
+
+func newSizeCore(core zapcore.Core, limit int) zapcore.Core {
+	return &sizeCore{Core: core, limit: limit}
+}
+
+func (c *sizeCore) With(fields []zap.Field) zapcore.Core {
+	fields, _ = truncateFields(fields, c.limit-c.used)
+	return &sizeCore{Core: c.Core.With(fields), limit: c.limit, used: c.used + fieldsSize(fields)}
+}
+
+func (c *sizeCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+func (c *sizeCore) Write(ent zapcore.Entry, fields []zap.Field) error {
+	budget := c.limit - c.used - len(ent.Message) - len(ent.Stack) - len(ent.LoggerName) - len(ent.Caller.File)
+	fields, truncated := truncateFields(fields, budget)
+	if truncated {
+		fields = append(fields, zap.Bool("truncated", true))
+	}
+	return c.Core.Write(ent, fields)
+}
+
+// fieldSize 估算字段编码后的字节数
+func fieldSize(f zap.Field) int {
+	n := len(f.Key) + fieldOverhead
+	switch f.Type {
+	case zapcore.StringType:
+		n += len(f.String)
+	case zapcore.ByteStringType, zapcore.BinaryType:
+		if b, ok := f.Interface.([]byte); ok {
+			n += len(b)
+		}
+	case zapcore.ErrorType:
+		if err, ok := f.Interface.(error); ok && err != nil {
+			n += len(err.Error())
+		}
+	case zapcore.StringerType:
+		if s, ok := f.Interface.(interface{ String() string }); ok {
+			n += len(s.String())
+		}
+	}
+	return n
+}
+
+func fieldsSize(fields []zap.Field) int {
+	n := 0
+	for _, f := range fields {
+		n += fieldSize(f)
+	}
+	return n
+}
+
+// truncateFields 估算大小超过budget时，依次截断最长的字符串类字段直到不超过budget，
+// 返回的切片为副本，不修改调用方的字段
+func truncateFields(fields []zap.Field, budget int) ([]zap.Field, bool) {
+	over := fieldsSize(fields) - budget
+	if over <= 0 {
+		return fields, false
+	}
+	fields = append([]zap.Field(nil), fields...)
+	truncated := false
+	for over > 0 {
+		longest, size := -1, 0
+		for i, f := range fields {
+			if s := stringValue(f); len(s) > size && len(s) > len(truncatedSuffix) {
+				longest, size = i, len(s)
+			}
+		}
+		if longest < 0 {
+			break
+		}
+		keep := max(size-over-len(truncatedSuffix), 0)
+		s := stringValue(fields[longest])
+		for keep > 0 && !utf8.RuneStart(s[keep]) {
+			keep--
+		}
+		fields[longest] = zap.String(fields[longest].Key, s[:keep]+truncatedSuffix)
+		over -= size - keep - len(truncatedSuffix)
+		truncated = true
+	}
+	return fields, truncated
+}
+
+// stringValue 返回可截断字段的字符串值，其他字段返回空串
+func stringValue(f zap.Field) string {
+	switch f.Type {
+	case zapcore.StringType:
+		return f.String
+	case zapcore.ByteStringType:
+		if b, ok := f.Interface.([]byte); ok {
+			return string(b)
+		}
+	case zapcore.ErrorType:
+		if err, ok := f.Interface.(error); ok && err != nil {
+			return err.Error()
+		}
+	case zapcore.StringerType:
+		if s, ok := f.Interface.(interface{ String() string }); ok {
+			return s.String()
+		}
+	}
+	return ""
+}
@@ -0,0 +1,69 @@
+package log
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"go.uber.org/zap"
+)
+
+func TestTruncateFields(t *testing.T) {
+	fields := []zap.Field{zap.String("small", "ok"), zap.String("dump", strings.Repeat("x", 1000)), zap.Int("n", 1)}
+	got, truncated := truncateFields(fields, 200)
+	if !truncated || fieldsSize(got) > 200 {
+		t.Fatalf("fields should fit the budget, got %d bytes", fieldsSize(got))
+	}
+	if got[0].String != "ok" || !strings.HasSuffix(got[1].String, truncatedSuffix) || fields[1].String != strings.Repeat("x", 1000) {
+		t.Fatalf("only the largest field should be truncated on a copy, got %+v", got)
+	}
+
+	if _, truncated := truncateFields(fields[:1], 200); truncated {
+		t.Fatal("small entries should not be truncated")
+	}
+
+	got, _ = truncateFields([]zap.Field{zap.String("cjk", strings.Repeat("日志", 200))}, 100)
+	if !strings.HasSuffix(got[0].String, truncatedSuffix) || !json.Valid([]byte(`"`+got[0].String+`"`)) {
+		t.Fatalf("truncation should keep valid UTF-8, got %q", got[0].String)
+	}
+}
+
+func TestMaxEntryBytes(t *testing.T) {
+	dir := t.TempDir()
+	entry, err := newLogger(LogConfig{
+		Name:          "size",
+		FileName:      filepath.Join(dir, "size.log"),
+		Encoder:       "json",
+		MaxEntryBytes: 512,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	logger := entry.logger.With(zap.String("request", strings.Repeat("r", 300)))
+	logger.Info("dump", zap.String("body", strings.Repeat("b", 4000)), zap.Error(errors.New(strings.Repeat("e", 2000))))
+	logger.Info("small", zap.String("body", "tiny"))
+	_ = entry.writer.Close()
+
+	data, err := os.ReadFile(filepath.Join(dir, "size.log"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected 2 lines, got %d", len(lines))
+	}
+	if len(lines[0]) > 700 || !strings.Contains(lines[0], `"truncated":true`) {
+		t.Fatalf("large entry should be truncated, got %d bytes: %s", len(lines[0]), lines[0])
+	}
+	if strings.Contains(lines[1], "truncated") {
+		t.Fatalf("small entry should be untouched: %s", lines[1])
+	}
+
+	cfg := Config{Zaplog: []LogConfig{{Name: "default", FileName: "a.log", MaxEntryBytes: -1}}}
+	if err := validateConfig(&cfg); err == nil || !strings.Contains(err.Error(), "max_entry_bytes") {
+		t.Fatalf("expected max_entry_bytes error, got %v", err)
+	}
+}
@@ -0,0 +1,101 @@
+package log
+
+import (
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+const monthDuration = 30 * 24 * time.Hour
+
+// Usage 日志量报告，用于按logger核算日志成本
+type Usage struct {
+	Since   time.Time     `json:"since"`   // 统计窗口起始时间
+	Window  time.Duration `json:"window"`  // 统计窗口长度
+	Loggers []LoggerUsage `json:"loggers"` // 各logger用量，按名称排序
+}
+
+// LoggerUsage 单个logger的日志量
+type LoggerUsage struct {
+	Name                  string           `json:"name"`                    // 日志名称
+	Bytes                 map[string]int64 `json:"bytes"`                   // 各级别输出字节数
+	TotalBytes            int64            `json:"total_bytes"`             // 总字节数
+	ProjectedMonthlyBytes int64            `json:"projected_monthly_bytes"` // 按当前速率推算的月日志量
+}
+
+// usageCounter 按级别统计输出字节数
+type usageCounter struct {
+	mu     sync.Mutex
+	since  time.Time
+	counts [zapcore.FatalLevel - zapcore.DebugLevel + 1]atomic.Int64
+}
+
+func newUsageCounter() *usageCounter {
+	return &usageCounter{since: time.Now()}
+}
+
+func (c *usageCounter) add(level zapcore.Level, n int) {
+	if level >= zapcore.DebugLevel && level <= zapcore.FatalLevel {
+		c.counts[level-zapcore.DebugLevel].Add(int64(n))
+	}
+}
+
+func (c *usageCounter) reset() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for i := range c.counts {
+		c.counts[i].Store(0)
+	}
+	c.since = time.Now()
+}
+
+func (c *usageCounter) snapshot() (time.Time, map[string]int64, int64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	bytes := make(map[string]int64, len(c.counts))
+	var total int64
+	for i := range c.counts {
+		n := c.counts[i].Load()
+		bytes[(zapcore.DebugLevel + zapcore.Level(i)).String()] = n
+		total += n
+	}
+	return c.since, bytes, total
+}
+
+// UsageReport 返回各logger自启动（或上次ResetUsage）以来的日志量及月度推算值
+func UsageReport() Usage {
+	metux.RLock()
+	defer metux.RUnlock()
+
+	now := time.Now()
+	report := Usage{Since: now, Loggers: make([]LoggerUsage, 0, len(loggers))}
+	for name, entry := range loggers {
+		since, bytes, total := entry.usage.snapshot()
+		if since.Before(report.Since) {
+			report.Since = since
+		}
+		usage := LoggerUsage{Name: name, Bytes: bytes, TotalBytes: total}
+		if window := now.Sub(since); window > 0 {
+			usage.ProjectedMonthlyBytes = int64(float64(total) * float64(monthDuration) / float64(window))
+		}
+		report.Loggers = append(report.Loggers, usage)
+	}
+	report.Window = now.Sub(report.Since)
+	sort.Slice(report.Loggers, func(i, j int) bool { return report.Loggers[i].Name < report.Loggers[j].Name })
+	return report
+}
+
+// ResetUsage 清零所有logger的日志量统计，开始新的统计窗口
+func ResetUsage() {
+	metux.RLock()
+	defer metux.RUnlock()
+
+	for _, entry := range loggers {
+		entry.usage.reset()
+	}
+}
@@ -0,0 +1,31 @@
+package log
+
+import (
+	"testing"
+)
+
+func TestUsageReport(t *testing.T) {
+	initTestLoggers(t, "access")
+	ResetUsage()
+
+	GetLogger("access").Info("hello")
+	GetLogger("access").Warn("world")
+	GetLogger("access").Debug("filtered")
+
+	report := UsageReport()
+	if len(report.Loggers) != 2 || report.Window <= 0 {
+		t.Fatalf("unexpected report: %+v", report)
+	}
+	access := report.Loggers[0]
+	if access.Name != "access" || access.Bytes["info"] == 0 || access.Bytes["warn"] == 0 || access.Bytes["debug"] != 0 {
+		t.Fatalf("unexpected access usage: %+v", access)
+	}
+	if access.TotalBytes != access.Bytes["info"]+access.Bytes["warn"] || access.ProjectedMonthlyBytes < access.TotalBytes {
+		t.Fatalf("unexpected totals: %+v", access)
+	}
+
+	ResetUsage()
+	if UsageReport().Loggers[0].TotalBytes != 0 {
+		t.Fatal("usage should be reset")
+	}
+}
@@ -0,0 +1,213 @@
+package log
+
+import (
+	"bytes"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+
+	"go.uber.org/zap/zapcore"
+)
+
+//go:embed viewer/index.html
+var viewerPage []byte
+
+// recentMaxBytes 读取日志文件末尾的最大字节数
+const recentMaxBytes = 4 << 20
+
+// ViewerHandler 返回内置的日志查看页面，用于没有集中日志系统的私有化部署环境。
+// 页面通过管理接口读取数据，需与AdminHandler一起挂载在同一前缀下：
+//
+//	mux.Handle("/debug/log/", log.AdminHandler())
+//	mux.Handle("/debug/log/ui/", log.ViewerHandler())
+//
+// 页面可以查看任意logger的日志内容，只应在受信任的网络中开放
+func ViewerHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.Header().Set("Cache-Control", "no-store")
+		_, _ = w.Write(viewerPage)
+	})
+}
+
+// recentResult /debug/log/recent的响应
+type recentResult struct {
+	Name  string   `json:"name"`
+	File  string   `json:"file"` // 日志文件路径，来源为环形缓冲时为memory
+	Lines []string `json:"lines"`
+}
+
+// handleRecent 返回logger日志文件末尾的日志，参数：
+// name logger名称，source 数据来源file(默认)或memory（环形缓冲），lines 最多返回的行数（默认200），
+// level 最低级别，q 包含的文本
+func handleRecent(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	name := query.Get("name")
+	if name == "" {
+		name = "default"
+	}
+	limit := 200
+	if s := query.Get("lines"); s != "" {
+		n, err := strconv.Atoi(s)
+		if err != nil || n <= 0 {
+			writeError(w, http.StatusBadRequest, fmt.Errorf("invalid lines %q", s))
+			return
+		}
+		limit = n
+	}
+	minLevel := zapcore.DebugLevel
+	if s := query.Get("level"); s != "" {
+		if !isValidLevel(s) {
+			writeError(w, http.StatusBadRequest, fmt.Errorf("invalid level %q", s))
+			return
+		}
+		minLevel = getLevel(s)
+	}
+
+	metux.RLock()
+	entry, ok := loggers[name]
+	var cfg LogConfig
+	if ok {
+		cfg = entry.cfg
+	}
+	metux.RUnlock()
+	if !ok {
+		writeError(w, http.StatusNotFound, fmt.Errorf("logger %s not found", name))
+		return
+	}
+
+	var (
+		lines  []string
+		source = cfg.FileName
+	)
+	switch query.Get("source") {
+	case "", "file":
+		var err error
+		lines, err = recentLines(cfg.FileName, recentMaxBytes)
+		if err != nil && !os.IsNotExist(err) {
+			writeError(w, http.StatusInternalServerError, err)
+			return
+		}
+	case "memory":
+		if entry.ring == nil {
+			writeError(w, http.StatusBadRequest, fmt.Errorf("logger %s: ring_buffer disabled", name))
+			return
+		}
+		for _, e := range entry.ring.snapshot() {
+			data, err := json.Marshal(e)
+			if err != nil {
+				continue
+			}
+			lines = append(lines, string(data))
+		}
+		cfg, source = LogConfig{}, "memory"
+	default:
+		writeError(w, http.StatusBadRequest, fmt.Errorf("invalid source %q", query.Get("source")))
+		return
+	}
+	lines = filterLines(lines, cfg, minLevel, query.Get("q"))
+	if len(lines) > limit {
+		lines = lines[len(lines)-limit:]
+	}
+	writeJSON(w, http.StatusOK, recentResult{Name: name, File: source, Lines: lines})
+}
+
+// recentLines 读取文件末尾最多maxBytes字节，返回其中的完整行
+func recentLines(path string, maxBytes int64) ([]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+	offset := max(info.Size()-maxBytes, 0)
+	data, err := io.ReadAll(io.NewSectionReader(f, offset, info.Size()-offset))
+	if err != nil {
+		return nil, err
+	}
+	if offset > 0 {
+		// 丢弃被截断的第一行
+		if i := bytes.IndexByte(data, '\n'); i >= 0 {
+			data = data[i+1:]
+		}
+	}
+	data = bytes.TrimRight(data, "\n")
+	if len(data) == 0 {
+		return nil, nil
+	}
+	return strings.Split(string(data), "\n"), nil
+}
+
+// filterLines 保留级别不低于minLevel且包含q的行，无法识别级别的行（如堆栈）始终保留
+func filterLines(lines []string, cfg LogConfig, minLevel zapcore.Level, q string) []string {
+	out := lines[:0:0]
+	for _, line := range lines {
+		if q != "" && !strings.Contains(line, q) {
+			continue
+		}
+		if level, ok := lineLevel(line, cfg); ok && level < minLevel {
+			continue
+		}
+		out = append(out, line)
+	}
+	return out
+}
+
+// lineLevel 识别json、logfmt和console编码的日志行的级别
+func lineLevel(line string, cfg LogConfig) (zapcore.Level, bool) {
+	key := cfg.LevelKey
+	if key == "" {
+		key = "level"
+	}
+
+	var text string
+	switch {
+	case strings.HasPrefix(line, "{"):
+		var m map[string]any
+		if json.Unmarshal([]byte(line), &m) != nil {
+			return 0, false
+		}
+		text, _ = m[key].(string)
+	case strings.Contains(line, key+"="):
+		_, rest, _ := strings.Cut(line, key+"=")
+		text, _, _ = strings.Cut(rest, " ")
+		text = strings.Trim(text, `"`)
+	default:
+		// console: 时间\t级别\t...
+		fields := strings.SplitN(line, "\t", 3)
+		if len(fields) < 2 {
+			return 0, false
+		}
+		text = stripANSI(fields[1])
+	}
+
+	var level zapcore.Level
+	if level.UnmarshalText([]byte(strings.ToLower(text))) != nil {
+		return 0, false
+	}
+	return level, true
+}
+
+// stripANSI 去除终端颜色控制符
+func stripANSI(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] == 0x1b {
+			for i < len(s) && s[i] != 'm' {
+				i++
+			}
+			continue
+		}
+		b.WriteByte(s[i])
+	}
+	return b.String()
+}
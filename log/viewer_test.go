@@ -0,0 +1,104 @@
+package log
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"go.uber.org/zap/zapcore"
+)
+
+func TestViewer(t *testing.T) {
+	initTestLoggers(t, "access")
+	mux := http.NewServeMux()
+	mux.Handle("/debug/log/", AdminHandler())
+	mux.Handle("/debug/log/ui/", ViewerHandler())
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	resp, err := http.Get(srv.URL + "/debug/log/ui/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	page, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK || !strings.Contains(string(page), "goeasy log viewer") {
+		t.Fatalf("unexpected viewer page: %d %.80s", resp.StatusCode, page)
+	}
+
+	logger := GetLogger("access")
+	logger.Info("GET /orders")
+	logger.Warn("GET /orders slow")
+	logger.Error("GET /users failed")
+	_ = logger.Sync()
+
+	recent := func(query string) recentResult {
+		t.Helper()
+		resp, err := http.Get(srv.URL + "/debug/log/recent?" + query)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+		var r recentResult
+		if err := json.NewDecoder(resp.Body).Decode(&r); err != nil {
+			t.Fatal(err)
+		}
+		return r
+	}
+	if r := recent("name=access"); len(r.Lines) != 3 {
+		t.Fatalf("expected 3 lines, got %q", r.Lines)
+	}
+	if r := recent("name=access&level=warn&q=orders"); len(r.Lines) != 1 || !strings.Contains(r.Lines[0], "slow") {
+		t.Fatalf("expected only the slow warning, got %q", r.Lines)
+	}
+	if r := recent("name=access&lines=2"); len(r.Lines) != 2 || !strings.Contains(r.Lines[1], "failed") {
+		t.Fatalf("expected the last 2 lines, got %q", r.Lines)
+	}
+
+	resp, err = http.Get(srv.URL + "/debug/log/recent?name=missing")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusNotFound {
+		t.Fatalf("expected 404 for unknown logger, got %d", resp.StatusCode)
+	}
+}
+
+func TestRecentLines(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	if err := os.WriteFile(path, []byte("first line\nsecond\nthird\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	lines, err := recentLines(path, 14)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(lines, "|") != "second|third" {
+		t.Fatalf("truncated first line should be dropped, got %q", lines)
+	}
+}
+
+func TestLineLevel(t *testing.T) {
+	cases := []struct {
+		line string
+		want zapcore.Level
+		ok   bool
+	}{
+		{`{"level":"WARN","msg":"x"}`, zapcore.WarnLevel, true},
+		{`ts=1 level=error msg=x`, zapcore.ErrorLevel, true},
+		{"2024-01-01T00:00:00Z\t\x1b[34mINFO\x1b[0m\tx", zapcore.InfoLevel, true},
+		{"\tgoroutine 1 [running]:", 0, false},
+	}
+	for _, c := range cases {
+		got, ok := lineLevel(c.line, LogConfig{})
+		if got != c.want || ok != c.ok {
+			t.Errorf("%q: expected %v %v, got %v %v", c.line, c.want, c.ok, got, ok)
+		}
+	}
+}
@@ -0,0 +1,178 @@
+package log
+
+import (
+	"context"
+	"slices"
+	"sync/atomic"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// Field 日志字段，使用方无需直接引用zap
+type Field = zap.Field
+
+// Logger 绑定到注册表中logger名称的记录器。每次记录时按名称取当前的zap logger，
+// 因此重新Init、AddLogger、SetLevel等对已创建的Logger同样生效，下游代码只依赖此类型，
+// 之后更换输出端或实现不影响调用方
+type Logger struct {
+	name   string
+	names  []string
+	fields []Field
+	// buffered 由Ctx取得的BufferRequest缓冲logger，优先于注册表中的logger
+	buffered *zap.Logger
+	cache    atomic.Pointer[derivedLogger]
+}
+
+// derivedLogger 基于某个zap logger派生出的带名称和字段的logger
+type derivedLogger struct {
+	base, logger *zap.Logger
+}
+
+// New 返回指定名称logger的Logger，名称不存在时使用default logger
+func New(name string) *Logger {
+	return &Logger{name: name}
+}
+
+// base 返回注册表或缓冲中的zap logger，不附加名称和字段
+func (l *Logger) base() *zap.Logger {
+	if l.buffered != nil {
+		return l.buffered
+	}
+	return GetLogger(l.name)
+}
+
+// current 返回当前生效的zap logger，注册表中的logger被替换时重新派生
+func (l *Logger) current() *zap.Logger {
+	base := l.base()
+	if d := l.cache.Load(); d != nil && d.base == base {
+		return d.logger
+	}
+	logger := base.WithOptions(zap.AddCallerSkip(1))
+	for _, name := range l.names {
+		logger = logger.Named(name)
+	}
+	if len(l.fields) > 0 {
+		logger = logger.With(l.fields...)
+	}
+	l.cache.Store(&derivedLogger{base: base, logger: logger})
+	return logger
+}
+
+func (l *Logger) clone() *Logger {
+	return &Logger{name: l.name, names: l.names, fields: l.fields, buffered: l.buffered}
+}
+
+// With 返回附加了fields的Logger
+func (l *Logger) With(fields ...Field) *Logger {
+	c := l.clone()
+	c.fields = slices.Concat(l.fields, fields)
+	return c
+}
+
+// Named 返回追加了子名称的Logger，输出的logger字段为 原名称.name
+func (l *Logger) Named(name string) *Logger {
+	c := l.clone()
+	c.names = append(slices.Clip(l.names), name)
+	return c
+}
+
+// Ctx 返回附加了ctx上有效字段的Logger，ctx上有BufferRequest开启的缓冲时写入缓冲
+func (l *Logger) Ctx(ctx context.Context) *Logger {
+	c := l.clone()
+	if buffered, ok := bufferedLogger(ctx, l.name); ok {
+		c.buffered = buffered
+	}
+	if fields := ContextFields(ctx); len(fields) > 0 {
+		c.fields = slices.Concat(l.fields, fields)
+	}
+	return c
+}
+
+// Level 返回当前生效的最低日志级别
+func (l *Logger) Level() zapcore.Level {
+	return zapcore.LevelOf(l.base().Core())
+}
+
+// Enabled 指定级别的日志是否会输出。名称和字段不影响级别，因此按未派生的logger判断，
+// 级别未开启时无需派生附加了With字段的logger
+func (l *Logger) Enabled(level zapcore.Level) bool {
+	return l.base().Core().Enabled(level)
+}
+
+// Check 级别开启时返回待写入的日志，否则返回nil。字段在ce.Write时才构造，
+// 级别未开启时不产生内存分配，适合热路径上的debug日志：
+//
+//	if ce := logger.Check(zapcore.DebugLevel, "cache miss"); ce != nil {
+//		ce.Write(log.Lazy("snapshot", cache.Snapshot))
+//	}
+func (l *Logger) Check(level zapcore.Level, msg string) *zapcore.CheckedEntry {
+	if !l.Enabled(level) {
+		return nil
+	}
+	return l.current().Check(level, msg)
+}
+
+// DebugFunc 以debug级别记录，fields只在级别开启时调用。fn不会逃逸，
+// 捕获局部变量的闭包分配在栈上，级别未开启时不产生内存分配
+func (l *Logger) DebugFunc(msg string, fields func() []Field) {
+	if !l.Enabled(zapcore.DebugLevel) {
+		return
+	}
+	if ce := l.current().Check(zapcore.DebugLevel, msg); ce != nil {
+		ce.Write(fields()...)
+	}
+}
+
+// Zap 返回当前生效的zap logger，用于需要zap类型的第三方库
+func (l *Logger) Zap() *zap.Logger {
+	return l.current().WithOptions(zap.AddCallerSkip(-1))
+}
+
+// Debug 以debug级别记录
+func (l *Logger) Debug(msg string, fields ...Field) {
+	if l.Enabled(zapcore.DebugLevel) {
+		l.current().Debug(msg, fields...)
+	}
+}
+
+// Info 以info级别记录
+func (l *Logger) Info(msg string, fields ...Field) {
+	if l.Enabled(zapcore.InfoLevel) {
+		l.current().Info(msg, fields...)
+	}
+}
+
+// Warn 以warn级别记录
+func (l *Logger) Warn(msg string, fields ...Field) {
+	if l.Enabled(zapcore.WarnLevel) {
+		l.current().Warn(msg, fields...)
+	}
+}
+
+// Error 以error级别记录
+func (l *Logger) Error(msg string, fields ...Field) {
+	if l.Enabled(zapcore.ErrorLevel) {
+		l.current().Error(msg, fields...)
+	}
+}
+
+// DPanic 以dpanic级别记录，开发模式下随后panic
+func (l *Logger) DPanic(msg string, fields ...Field) {
+	l.current().DPanic(msg, fields...)
+}
+
+// Panic 以panic级别记录后panic
+func (l *Logger) Panic(msg string, fields ...Field) {
+	l.current().Panic(msg, fields...)
+}
+
+// Fatal 以fatal级别记录后退出进程
+func (l *Logger) Fatal(msg string, fields ...Field) {
+	l.current().Fatal(msg, fields...)
+}
+
+// Sync 同步输出端
+func (l *Logger) Sync() error {
+	return l.current().Sync()
+}
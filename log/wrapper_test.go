@@ -0,0 +1,98 @@
+package log
+
+import (
+	"context"
+	"testing"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+func TestLogger(t *testing.T) {
+	initTestLoggers(t, "order")
+	logger := New("order").With(zap.String("service", "checkout")).Named("payment")
+
+	logs := observeLogger(t, "order")
+	ctx := WithFields(context.Background(), zap.String("request_id", "r1"))
+	logger.Ctx(ctx).Info("charged", zap.Int("cents", 100))
+	logger.Debug("detail")
+
+	entries := logs.All()
+	if len(entries) != 2 {
+		t.Fatalf("logger should follow the replaced registry entry, got %+v", entries)
+	}
+	fields := entries[0].ContextMap()
+	if entries[0].LoggerName != "payment" || fields["service"] != "checkout" || fields["request_id"] != "r1" || fields["cents"] != int64(100) {
+		t.Fatalf("unexpected entry %+v", entries[0])
+	}
+	if _, ok := entries[1].ContextMap()["request_id"]; ok {
+		t.Fatal("Ctx should not modify the original logger")
+	}
+}
+
+func TestLoggerLevel(t *testing.T) {
+	initTestLoggers(t, "order")
+	logger := New("order")
+	if logger.Level() != zapcore.InfoLevel || logger.Enabled(zapcore.DebugLevel) {
+		t.Fatalf("unexpected level %v", logger.Level())
+	}
+	if err := SetLevel("order", "error"); err != nil {
+		t.Fatal(err)
+	}
+	if logger.Level() != zapcore.ErrorLevel || logger.Enabled(zapcore.WarnLevel) {
+		t.Fatalf("SetLevel should apply to existing loggers, got %v", logger.Level())
+	}
+	if New("missing").Level() != zapcore.InfoLevel {
+		t.Fatal("unknown names should fall back to the default logger")
+	}
+}
+
+func TestDisabledLevelZeroAlloc(t *testing.T) {
+	initTestLoggers(t, "order")
+	logger := New("order")
+	snapshot := map[string]int{"hits": 1}
+
+	for name, fn := range map[string]func(){
+		"Debug": func() { logger.Debug("cache miss") },
+		"Check": func() {
+			if ce := logger.Check(zapcore.DebugLevel, "cache miss"); ce != nil {
+				ce.Write(Lazy("snapshot", func() any { return snapshot }))
+			}
+		},
+		"DebugFunc": func() {
+			logger.DebugFunc("cache miss", func() []Field { return []Field{Any("snapshot", snapshot)} })
+		},
+		"package Check": func() {
+			if ce := Check("order", zapcore.DebugLevel, "cache miss"); ce != nil {
+				ce.Write(zap.Any("snapshot", snapshot))
+			}
+		},
+	} {
+		if n := testing.AllocsPerRun(100, fn); n != 0 {
+			t.Errorf("%s: %v allocs per disabled call, want 0", name, n)
+		}
+	}
+
+	// 级别未开启时不派生附加字段的logger
+	child := logger.With(zap.String("service", "checkout"))
+	child.Debug("skipped")
+	if child.cache.Load() != nil {
+		t.Fatal("disabled call should not derive the logger")
+	}
+}
+
+func TestCheckAndDebugFunc(t *testing.T) {
+	initTestLoggers(t, "order")
+	logs := observeLogger(t, "order")
+	logger := New("order").With(zap.String("service", "checkout"))
+	if ce := logger.Check(zapcore.InfoLevel, "charged"); ce != nil {
+		ce.Write(zap.Int("cents", 100))
+	}
+	logger.DebugFunc("cache miss", func() []Field { return []Field{zap.Int("hits", 1)} })
+
+	entries := logs.All()
+	if len(entries) != 2 || entries[0].Message != "charged" || entries[0].ContextMap()["cents"] != int64(100) ||
+		entries[1].ContextMap()["hits"] != int64(1) || entries[1].ContextMap()["service"] != "checkout" {
+		t.Fatalf("unexpected entries %+v", entries)
+	}
+}
@@ -0,0 +1,100 @@
+package integration
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"testing"
+)
+
+// Elasticsearch 模拟Elasticsearch的_bulk和_doc写入接口
+type Elasticsearch struct {
+	*httpFake
+}
+
+// NewElasticsearch 启动Elasticsearch模拟服务，测试结束时自动关闭
+func NewElasticsearch(t testing.TB) *Elasticsearch {
+	e := &Elasticsearch{}
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /{$}", e.handleInfo)
+	mux.HandleFunc("POST /_bulk", e.handleBulk)
+	mux.HandleFunc("POST /{index}/_bulk", e.handleBulk)
+	mux.HandleFunc("POST /{index}/_doc", e.handleDoc)
+	e.httpFake = newHTTPFake(t, mux)
+	return e
+}
+
+// handleInfo 部分客户端初始化时会检查版本
+func (e *Elasticsearch) handleInfo(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("X-Elastic-Product", "Elasticsearch")
+	_, _ = w.Write([]byte(`{"name":"fake","version":{"number":"8.15.0"},"tagline":"You Know, for Search"}`))
+}
+
+func (e *Elasticsearch) handleDoc(w http.ResponseWriter, r *http.Request) {
+	data, err := readBody(r)
+	if err != nil || !json.Valid(data) {
+		http.Error(w, "invalid document", http.StatusBadRequest)
+		return
+	}
+	index := r.PathValue("index")
+	e.add(Record{Sink: "elasticsearch", Labels: map[string]string{"index": index}, Line: string(data)})
+	writeESJSON(w, http.StatusCreated, map[string]any{"_index": index, "result": "created"})
+}
+
+func (e *Elasticsearch) handleBulk(w http.ResponseWriter, r *http.Request) {
+	data, err := readBody(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	var (
+		records []Record
+		items   []map[string]any
+	)
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 64*1024), 16<<20)
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		var action map[string]struct {
+			Index string `json:"_index"`
+		}
+		if err := json.Unmarshal(line, &action); err != nil || len(action) != 1 {
+			http.Error(w, "invalid bulk action line", http.StatusBadRequest)
+			return
+		}
+		for op, meta := range action {
+			index := meta.Index
+			if index == "" {
+				index = r.PathValue("index")
+			}
+			if op == "delete" {
+				continue
+			}
+			if !scanner.Scan() {
+				http.Error(w, "missing bulk document line", http.StatusBadRequest)
+				return
+			}
+			records = append(records, Record{
+				Sink:   "elasticsearch",
+				Labels: map[string]string{"index": index, "op": op},
+				Line:   string(bytes.TrimSpace(scanner.Bytes())),
+			})
+			items = append(items, map[string]any{op: map[string]any{"_index": index, "status": http.StatusCreated}})
+		}
+	}
+	e.add(records...)
+	writeESJSON(w, http.StatusOK, map[string]any{"errors": false, "items": items})
+}
+
+func writeESJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("X-Elastic-Product", "Elasticsearch")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
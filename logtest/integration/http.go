@@ -0,0 +1,62 @@
+package integration
+
+import (
+	"compress/gzip"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+)
+
+// httpFake 基于httptest.Server的模拟服务，支持注入失败
+type httpFake struct {
+	*recorder
+
+	server   *httptest.Server
+	failures atomic.Int64
+	requests atomic.Int64
+}
+
+func newHTTPFake(t testing.TB, handler http.Handler) *httpFake {
+	f := &httpFake{recorder: newRecorder()}
+	f.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		f.requests.Add(1)
+		if f.failures.Load() > 0 && f.failures.Add(-1) >= 0 {
+			http.Error(w, "injected failure", http.StatusServiceUnavailable)
+			return
+		}
+		handler.ServeHTTP(w, r)
+	}))
+	t.Cleanup(f.server.Close)
+	return f
+}
+
+// URL 返回模拟服务的根地址
+func (f *httpFake) URL() string {
+	return f.server.URL
+}
+
+// FailNext 接下来的n个请求返回503，用于测试重试逻辑
+func (f *httpFake) FailNext(n int) {
+	f.failures.Store(int64(n))
+}
+
+// Requests 返回收到的请求数，包括注入失败的请求
+func (f *httpFake) Requests() int {
+	return int(f.requests.Load())
+}
+
+// readBody 读取请求体，支持gzip压缩
+func readBody(r *http.Request) ([]byte, error) {
+	body := io.Reader(r.Body)
+	if r.Header.Get("Content-Encoding") == "gzip" {
+		gz, err := gzip.NewReader(r.Body)
+		if err != nil {
+			return nil, err
+		}
+		defer gz.Close()
+		body = gz
+	}
+	return io.ReadAll(body)
+}
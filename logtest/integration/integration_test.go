@@ -0,0 +1,119 @@
+package integration
+
+import (
+	"bytes"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func post(t *testing.T, url, contentType, body string) int {
+	t.Helper()
+	resp, err := http.Post(url, contentType, strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	return resp.StatusCode
+}
+
+func TestLoki(t *testing.T) {
+	loki := NewLoki(t)
+
+	loki.FailNext(1)
+	body := `{"streams":[{"stream":{"app":"api"},"values":[["1700000000000000000","hello"],["1700000000000000001","world"]]}]}`
+	if code := post(t, loki.PushURL(), "application/json", body); code != http.StatusServiceUnavailable {
+		t.Fatalf("expected injected failure, got %d", code)
+	}
+	if code := post(t, loki.PushURL(), "application/json", body); code != http.StatusNoContent {
+		t.Fatalf("unexpected status %d", code)
+	}
+	if code := post(t, loki.PushURL(), "application/x-protobuf", body); code != http.StatusUnsupportedMediaType {
+		t.Fatalf("unexpected status %d", code)
+	}
+
+	loki.WaitFor(t, 2, time.Second)
+	loki.AssertContains(t, "world")
+	loki.AssertLabel(t, "app", "api")
+	if loki.Requests() != 3 {
+		t.Fatalf("expected 3 requests, got %d", loki.Requests())
+	}
+}
+
+func TestElasticsearch(t *testing.T) {
+	es := NewElasticsearch(t)
+
+	bulk := `{"index":{"_index":"logs-a"}}
+{"msg":"first"}
+{"create":{}}
+{"msg":"second"}
+`
+	if code := post(t, es.URL()+"/logs-b/_bulk", "application/x-ndjson", bulk); code != http.StatusOK {
+		t.Fatalf("unexpected status %d", code)
+	}
+	if code := post(t, es.URL()+"/logs-c/_doc", "application/json", `{"msg":"third"}`); code != http.StatusCreated {
+		t.Fatalf("unexpected status %d", code)
+	}
+
+	records := es.WaitFor(t, 3, time.Second)
+	want := []string{"logs-a", "logs-b", "logs-c"}
+	for i, rec := range records {
+		if rec.Labels["index"] != want[i] {
+			t.Errorf("record %d: expected index %s, got %s", i, want[i], rec.Labels["index"])
+		}
+	}
+	es.AssertContains(t, `"second"`)
+}
+
+func TestKafka(t *testing.T) {
+	kafka := NewKafka(t)
+
+	body := `{"records":[{"key":"k1","value":"plain"},{"value":{"msg":"structured"}}]}`
+	if code := post(t, kafka.URL()+"/topics/app-logs", "application/vnd.kafka.json.v2+json", body); code != http.StatusOK {
+		t.Fatalf("unexpected status %d", code)
+	}
+
+	records := kafka.WaitFor(t, 2, time.Second)
+	if records[0].Line != "plain" || records[0].Labels["key"] != "k1" {
+		t.Fatalf("unexpected record %+v", records[0])
+	}
+	if records[1].Line != `{"msg":"structured"}` || records[1].Labels["topic"] != "app-logs" {
+		t.Fatalf("unexpected record %+v", records[1])
+	}
+}
+
+func TestSyslog(t *testing.T) {
+	for _, network := range []string{"udp", "tcp"} {
+		t.Run(network, func(t *testing.T) {
+			s := NewSyslog(t, network)
+			conn, err := net.Dial(s.Network(), s.Addr())
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer conn.Close()
+
+			rfc5424 := "<11>1 2024-01-02T03:04:05Z host1 api 123 - - disk full"
+			rfc3164 := "<30>Jan  2 03:04:05 host2 worker[42]: job done"
+			if network == "udp" {
+				fmt.Fprint(conn, rfc5424)
+				fmt.Fprint(conn, rfc3164)
+			} else {
+				var buf bytes.Buffer
+				fmt.Fprintf(&buf, "%d %s", len(rfc5424), rfc5424)
+				fmt.Fprintf(&buf, "%s\n", rfc3164)
+				conn.Write(buf.Bytes())
+			}
+
+			records := s.WaitFor(t, 2, time.Second)
+			if r := records[0]; r.Line != "disk full" || r.Labels["app"] != "api" || r.Labels["severity"] != "3" {
+				t.Fatalf("unexpected record %+v", r)
+			}
+			if r := records[1]; r.Line != "job done" || r.Labels["app"] != "worker" || r.Labels["facility"] != "3" {
+				t.Fatalf("unexpected record %+v", r)
+			}
+		})
+	}
+}
@@ -0,0 +1,64 @@
+package integration
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+)
+
+// Kafka 模拟Kafka REST Proxy(v2)的生产接口：POST /topics/{topic}
+type Kafka struct {
+	*httpFake
+}
+
+// NewKafka 启动Kafka REST Proxy模拟服务，测试结束时自动关闭
+func NewKafka(t testing.TB) *Kafka {
+	k := &Kafka{}
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /topics/{topic}", k.handleProduce)
+	k.httpFake = newHTTPFake(t, mux)
+	return k
+}
+
+func (k *Kafka) handleProduce(w http.ResponseWriter, r *http.Request) {
+	data, err := readBody(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	var req struct {
+		Records []struct {
+			Key   json.RawMessage `json:"key"`
+			Value json.RawMessage `json:"value"`
+		} `json:"records"`
+	}
+	if err := json.Unmarshal(data, &req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	topic := r.PathValue("topic")
+	records := make([]Record, 0, len(req.Records))
+	offsets := make([]map[string]int, 0, len(req.Records))
+	for i, rec := range req.Records {
+		labels := map[string]string{"topic": topic}
+		if len(rec.Key) > 0 && string(rec.Key) != "null" {
+			labels["key"] = rawString(rec.Key)
+		}
+		records = append(records, Record{Sink: "kafka", Labels: labels, Line: rawString(rec.Value)})
+		offsets = append(offsets, map[string]int{"partition": 0, "offset": len(k.Records()) + i})
+	}
+	k.add(records...)
+
+	w.Header().Set("Content-Type", "application/vnd.kafka.v2+json")
+	_ = json.NewEncoder(w).Encode(map[string]any{"offsets": offsets})
+}
+
+// rawString JSON字符串返回其内容，其他类型返回原始JSON
+func rawString(raw json.RawMessage) string {
+	var s string
+	if err := json.Unmarshal(raw, &s); err == nil {
+		return s
+	}
+	return string(raw)
+}
@@ -0,0 +1,65 @@
+package integration
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+// LokiPushPath Loki推送接口路径
+const LokiPushPath = "/loki/api/v1/push"
+
+// Loki 模拟Loki推送接口，只支持JSON格式
+type Loki struct {
+	*httpFake
+}
+
+// NewLoki 启动Loki模拟服务，测试结束时自动关闭
+func NewLoki(t testing.TB) *Loki {
+	l := &Loki{}
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST "+LokiPushPath, l.handlePush)
+	l.httpFake = newHTTPFake(t, mux)
+	return l
+}
+
+// PushURL 返回推送接口的完整地址
+func (l *Loki) PushURL() string {
+	return l.URL() + LokiPushPath
+}
+
+func (l *Loki) handlePush(w http.ResponseWriter, r *http.Request) {
+	if ct := r.Header.Get("Content-Type"); !strings.HasPrefix(ct, "application/json") {
+		http.Error(w, "only application/json is supported", http.StatusUnsupportedMediaType)
+		return
+	}
+	data, err := readBody(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	var req struct {
+		Streams []struct {
+			Stream map[string]string `json:"stream"`
+			Values [][]string        `json:"values"`
+		} `json:"streams"`
+	}
+	if err := json.Unmarshal(data, &req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	var records []Record
+	for _, s := range req.Streams {
+		for _, v := range s.Values {
+			if len(v) < 2 {
+				http.Error(w, "invalid value entry", http.StatusBadRequest)
+				return
+			}
+			records = append(records, Record{Sink: "loki", Labels: s.Stream, Line: v[1]})
+		}
+	}
+	l.add(records...)
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,200 @@
+package integration
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// Syslog 模拟syslog服务，支持udp和tcp，解析RFC5424和RFC3164格式。
+// tcp同时支持换行分隔和RFC6587的octet-counting分帧
+type Syslog struct {
+	*recorder
+
+	network string
+	addr    string
+	closer  io.Closer
+	quit    chan struct{}
+	wg      sync.WaitGroup
+}
+
+// NewSyslog 在本地随机端口启动syslog模拟服务，network为udp或tcp，测试结束时自动关闭
+func NewSyslog(t testing.TB, network string) *Syslog {
+	t.Helper()
+
+	s := &Syslog{recorder: newRecorder(), network: network, quit: make(chan struct{})}
+	switch network {
+	case "udp":
+		conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+		if err != nil {
+			t.Fatal(err)
+		}
+		s.addr, s.closer = conn.LocalAddr().String(), conn
+		s.wg.Add(1)
+		go s.serveUDP(conn)
+	case "tcp":
+		ln, err := net.Listen("tcp", "127.0.0.1:0")
+		if err != nil {
+			t.Fatal(err)
+		}
+		s.addr, s.closer = ln.Addr().String(), ln
+		s.wg.Add(1)
+		go s.serveTCP(ln)
+	default:
+		t.Fatalf("unsupported syslog network %q", network)
+	}
+	t.Cleanup(func() {
+		close(s.quit)
+		_ = s.closer.Close()
+		s.wg.Wait()
+	})
+	return s
+}
+
+// Network 返回监听的网络类型
+func (s *Syslog) Network() string {
+	return s.network
+}
+
+// Addr 返回监听地址
+func (s *Syslog) Addr() string {
+	return s.addr
+}
+
+func (s *Syslog) serveUDP(conn net.PacketConn) {
+	defer s.wg.Done()
+
+	buf := make([]byte, 64*1024)
+	for {
+		n, _, err := conn.ReadFrom(buf)
+		if err != nil {
+			return
+		}
+		s.add(parseSyslog(strings.TrimRight(string(buf[:n]), "\n")))
+	}
+}
+
+func (s *Syslog) serveTCP(ln net.Listener) {
+	defer s.wg.Done()
+
+	var conns sync.WaitGroup
+	defer conns.Wait()
+	for {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		conns.Add(1)
+		go func() {
+			defer conns.Done()
+			s.serveConn(conn)
+		}()
+	}
+}
+
+func (s *Syslog) serveConn(conn net.Conn) {
+	// 服务关闭时断开仍在读取的连接
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-done:
+		case <-s.quit:
+		}
+		conn.Close()
+	}()
+
+	r := bufio.NewReader(conn)
+	for {
+		msg, err := readFrame(r)
+		if msg != "" {
+			s.add(parseSyslog(msg))
+		}
+		if err != nil {
+			return
+		}
+	}
+}
+
+// readFrame 读取一帧消息，以数字开头时按octet-counting解析，否则按换行分隔
+func readFrame(r *bufio.Reader) (string, error) {
+	b, err := r.Peek(1)
+	if err != nil {
+		return "", err
+	}
+	if b[0] < '0' || b[0] > '9' {
+		line, err := r.ReadString('\n')
+		return strings.TrimRight(line, "\r\n"), err
+	}
+
+	length, err := r.ReadString(' ')
+	if err != nil {
+		return "", err
+	}
+	n, err := strconv.Atoi(strings.TrimSpace(length))
+	if err != nil || n <= 0 {
+		return "", errors.New("invalid octet count")
+	}
+	buf := make([]byte, n)
+	if _, err := io.ReadFull(r, buf); err != nil {
+		return "", err
+	}
+	return string(buf), nil
+}
+
+// parseSyslog 解析一条syslog消息，无法识别的部分原样保留在Line中
+func parseSyslog(msg string) Record {
+	rec := Record{Sink: "syslog", Labels: map[string]string{}, Line: msg}
+	if !strings.HasPrefix(msg, "<") {
+		return rec
+	}
+	end := strings.IndexByte(msg, '>')
+	pri, err := strconv.Atoi(msg[1:max(end, 1)])
+	if end < 0 || err != nil {
+		return rec
+	}
+	rec.Labels["facility"] = strconv.Itoa(pri / 8)
+	rec.Labels["severity"] = strconv.Itoa(pri % 8)
+	rest := msg[end+1:]
+	rec.Line = rest
+
+	if strings.HasPrefix(rest, "1 ") {
+		// RFC5424: VERSION TIMESTAMP HOSTNAME APP-NAME PROCID MSGID STRUCTURED-DATA MSG
+		parts := strings.SplitN(rest, " ", 7)
+		if len(parts) < 7 {
+			return rec
+		}
+		rec.Labels["hostname"] = parts[2]
+		rec.Labels["app"] = parts[3]
+		sd := parts[6]
+		if strings.HasPrefix(sd, "-") {
+			rec.Line = strings.TrimPrefix(strings.TrimPrefix(sd, "-"), " ")
+		} else if i := strings.Index(sd, "] "); i >= 0 {
+			rec.Labels["structured_data"] = sd[:i+1]
+			rec.Line = sd[i+2:]
+		}
+		rec.Line = strings.TrimPrefix(rec.Line, "\ufeff")
+		return rec
+	}
+
+	// RFC3164: Mmm dd hh:mm:ss HOSTNAME TAG: MSG
+	if len(rest) > 16 && rest[15] == ' ' {
+		parts := strings.SplitN(rest[16:], " ", 2)
+		if len(parts) == 2 {
+			rec.Labels["hostname"] = parts[0]
+			if tag, line, ok := strings.Cut(parts[1], ": "); ok {
+				if i := strings.IndexByte(tag, '['); i >= 0 {
+					tag = tag[:i]
+				}
+				rec.Labels["app"] = tag
+				rec.Line = line
+			}
+		}
+	}
+	return rec
+}